@@ -0,0 +1,205 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/audit"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/google/uuid"
+)
+
+// The channels the commands are received from, which are recorded in the audit log
+const (
+	CommandChannelRest       = "rest"
+	CommandChannelBatch      = "batch"
+	CommandChannelMessageBus = "messagebus"
+)
+
+// CommandCaller describes who issued a command and how, as recorded in the audit log
+type CommandCaller struct {
+	Identity      string
+	CorrelationId string
+	Channel       string
+}
+
+// commandAudit records a command in the audit log as pending before it is issued, and then its outcome once it is done
+type commandAudit struct {
+	log     *audit.Log
+	lc      logger.LoggingClient
+	started time.Time
+	entry   commandDTOs.CommandAuditEntry
+}
+
+// StartCommandAudit opens the audit log when the command audit is enabled by the configuration, and then purges the
+// entries beyond the retention in the background until ctx is done.
+func StartCommandAudit(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	info := commandContainer.ConfigurationFrom(dic.Get).CommandAudit
+	if !info.Enabled {
+		return nil
+	}
+	interval, edgeXerr := parseCommandAuditDuration("RetentionInterval", info.RetentionInterval)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	maxAge, edgeXerr := parseCommandAuditDuration("MaxAge", info.MaxAge)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	retained := maxAge > 0 || info.MaxCount > 0
+	if retained && interval == 0 {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "RetentionInterval is required by MaxAge or MaxCount", nil)
+	}
+
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	auditLog, edgeXerr := audit.Open(info.Path)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	dic.Update(di.ServiceConstructorMap{
+		commandContainer.CommandAuditLogName: func(get di.Get) interface{} {
+			return auditLog
+		},
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// the audit log is never purged without a retention
+		var purgeTicks <-chan time.Time
+		if retained {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			purgeTicks = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				auditLog.Close()
+				lc.Info("Exiting the command audit")
+				return
+			case <-purgeTicks:
+				var before int64
+				if maxAge > 0 {
+					before = time.Now().Add(-maxAge).UnixNano()
+				}
+				purged, err := auditLog.Purge(before, info.MaxCount)
+				if err != nil {
+					lc.Errorf("fail to purge the command audit log, %v", err)
+					continue
+				}
+				lc.Debugf("%d entries purged from the command audit log", purged)
+			}
+		}
+	}()
+
+	lc.Infof("Command audit log opened at %s", info.Path)
+	return nil
+}
+
+// parseCommandAuditDuration parses the duration string, and an empty string means zero
+func parseCommandAuditDuration(name string, value string) (time.Duration, errors.EdgeX) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("command audit %s %s is not a non-negative duration", name, value), err)
+	}
+	return d, nil
+}
+
+// startCommandAudit records the command as pending in the audit log and starts timing it, and returns nil when the
+// command audit is disabled.  The command must not be issued when it fails to be recorded, so that no command escapes
+// the audit.
+func startCommandAudit(caller CommandCaller, deviceName string, commandName string, method string, queryParams string, settings map[string]interface{}, dic *di.Container) (*commandAudit, errors.EdgeX) {
+	auditLog := commandContainer.CommandAuditLogFrom(dic.Get)
+	if auditLog == nil {
+		return nil, nil
+	}
+	started := time.Now()
+	a := &commandAudit{
+		log:     auditLog,
+		lc:      bootstrapContainer.LoggingClientFrom(dic.Get),
+		started: started,
+		entry: commandDTOs.CommandAuditEntry{
+			Id:              uuid.NewString(),
+			Timestamp:       started.UnixNano(),
+			DeviceName:      deviceName,
+			CommandName:     commandName,
+			Method:          method,
+			QueryParameters: queryParams,
+			Settings:        settings,
+			Caller:          caller.Identity,
+			CorrelationId:   caller.CorrelationId,
+			Channel:         caller.Channel,
+			Status:          commandDTOs.CommandAuditStatusPending,
+		},
+	}
+	if err := auditLog.Add(a.entry); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to record the command %s of device %s in the audit log", commandName, deviceName), err)
+	}
+	return a, nil
+}
+
+// done records the response status of the command in the audit log.  The command has already been issued, so a
+// failure to record it is only logged, and the entry is left pending.
+func (a *commandAudit) done(statusCode int, message string) {
+	if a == nil {
+		return
+	}
+	a.entry.Status = commandDTOs.CommandAuditStatusDone
+	a.entry.StatusCode = statusCode
+	a.entry.Message = message
+	a.entry.Latency = time.Since(a.started).String()
+	if err := a.log.Update(a.entry); err != nil {
+		a.lc.Errorf("fail to record the outcome of the command %s of device %s in the audit log, Correlation-id: %s, %v",
+			a.entry.CommandName, a.entry.DeviceName, a.entry.CorrelationId, err)
+	}
+}
+
+// failed records the command failed with err in the audit log
+func (a *commandAudit) failed(err errors.EdgeX) {
+	if a == nil {
+		return
+	}
+	a.done(err.Code(), err.Error())
+}
+
+// CommandAuditEntries returns the audit entries between start and end in nanoseconds, the newest first, and the total
+// count of the entries in range.  The entries of all the devices are returned when deviceName is empty.
+func CommandAuditEntries(deviceName string, start int64, end int64, offset int, limit int, dic *di.Container) ([]commandDTOs.CommandAuditEntry, uint32, errors.EdgeX) {
+	auditLog := commandContainer.CommandAuditLogFrom(dic.Get)
+	if auditLog == nil {
+		return nil, 0, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "the command audit is disabled", nil)
+	}
+
+	var entries []commandDTOs.CommandAuditEntry
+	var totalCount uint32
+	var err errors.EdgeX
+	if deviceName == "" {
+		entries, totalCount, err = auditLog.Entries(start, end, offset, limit)
+	} else {
+		entries, totalCount, err = auditLog.EntriesByDevice(deviceName, start, end, offset, limit)
+	}
+	if err != nil {
+		return nil, 0, errors.NewCommonEdgeXWrapper(err)
+	}
+	return entries, totalCount, nil
+}
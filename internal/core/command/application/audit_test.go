@@ -0,0 +1,153 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/audit"
+	"github.com/edgexfoundry/edgex-go/internal/core/command/config"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testCaller = "testUser"
+
+func TestStartCommandAudit(t *testing.T) {
+	dic := mockCommandDic(&messageBusStub{})
+	configuration := commandContainer.ConfigurationFrom(dic.Get)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	require.NoError(t, StartCommandAudit(ctx, wg, dic))
+	assert.Nil(t, commandContainer.CommandAuditLogFrom(dic.Get), "the disabled command audit should not be opened")
+
+	tests := []struct {
+		name string
+		info config.CommandAuditInfo
+	}{
+		{"Invalid - malformed retention interval", config.CommandAuditInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.db"), RetentionInterval: "hourly"}},
+		{"Invalid - negative max age", config.CommandAuditInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.db"), RetentionInterval: "1h", MaxAge: "-1h"}},
+		{"Invalid - max count without retention interval", config.CommandAuditInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.db"), MaxCount: 10}},
+		{"Invalid - no path", config.CommandAuditInfo{Enabled: true}},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			configuration.CommandAudit = testCase.info
+			err := StartCommandAudit(ctx, wg, dic)
+			require.Error(t, err)
+			assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+		})
+	}
+
+	configuration.CommandAudit = config.CommandAuditInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.db"), RetentionInterval: "1h", MaxAge: "720h"}
+	require.NoError(t, StartCommandAudit(ctx, wg, dic))
+	require.NotNil(t, commandContainer.CommandAuditLogFrom(dic.Get))
+	cancel()
+	wg.Wait()
+}
+
+func TestCommandAudit(t *testing.T) {
+	dic := mockCommandDic(&messageBusStub{})
+	_, _, err := CommandAuditEntries("", 0, math.MaxInt64, 0, -1, dic)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindServiceUnavailable, errors.Kind(err), "the entries should be unavailable when the command audit is disabled")
+
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	defer auditLog.Close()
+	dic.Update(di.ServiceConstructorMap{
+		commandContainer.CommandAuditLogName: func(get di.Get) interface{} {
+			return auditLog
+		},
+	})
+
+	caller := CommandCaller{Identity: testCaller, CorrelationId: testCorrelationId, Channel: CommandChannelRest}
+	_, err = IssueGetCommandByName(testDeviceName, testCommandName, "ds-pushevent=yes", caller, dic)
+	require.NoError(t, err)
+	_, err = IssueSetCommandByName(testDeviceName, testCommandName, "", map[string]interface{}{"testResource": "1.5"}, caller, dic)
+	require.Error(t, err)
+	_, _, err = handleCommandRequest(types.MessageEnvelope{
+		CorrelationID: testCorrelationId,
+		ReceivedTopic: testRequestTopic + "/" + unknownDeviceName + "/" + testCommandName + "/" + commandMethodGet,
+		ContentType:   common.ContentTypeJSON,
+	}, testResponsePrefix, dic)
+	require.NoError(t, err)
+
+	entries, totalCount, err := CommandAuditEntries("", 0, math.MaxInt64, 0, -1, dic)
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), totalCount)
+
+	// the entries are returned the newest first
+	messageBusEntry, setEntry, getEntry := entries[0], entries[1], entries[2]
+	assert.Equal(t, testDeviceName, getEntry.DeviceName)
+	assert.Equal(t, testCommandName, getEntry.CommandName)
+	assert.Equal(t, commandMethodGet, getEntry.Method)
+	assert.Equal(t, "ds-pushevent=yes", getEntry.QueryParameters)
+	assert.Equal(t, testCaller, getEntry.Caller)
+	assert.Equal(t, testCorrelationId, getEntry.CorrelationId)
+	assert.Equal(t, CommandChannelRest, getEntry.Channel)
+	assert.Equal(t, commandDTOs.CommandAuditStatusDone, getEntry.Status, "the pending entry should be updated once the command is done")
+	assert.Equal(t, http.StatusOK, getEntry.StatusCode)
+	assert.NotEmpty(t, getEntry.Id)
+	assert.NotEmpty(t, getEntry.Latency)
+
+	assert.Equal(t, commandMethodSet, setEntry.Method)
+	assert.Equal(t, commandDTOs.CommandAuditStatusDone, setEntry.Status)
+	assert.Equal(t, map[string]interface{}{"testResource": "1.5"}, setEntry.Settings)
+	assert.Equal(t, http.StatusBadRequest, setEntry.StatusCode, "the rejected set command should be recorded")
+	assert.Contains(t, setEntry.Message, "testResource")
+
+	assert.Equal(t, unknownDeviceName, messageBusEntry.DeviceName)
+	assert.Equal(t, CommandChannelMessageBus, messageBusEntry.Channel)
+	assert.Equal(t, testCorrelationId, messageBusEntry.CorrelationId)
+	assert.Empty(t, messageBusEntry.Caller)
+	assert.Equal(t, http.StatusNotFound, messageBusEntry.StatusCode)
+
+	entries, totalCount, err = CommandAuditEntries(testDeviceName, getEntry.Timestamp, getEntry.Timestamp, 0, -1, dic)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), totalCount)
+	require.Len(t, entries, 1)
+	assert.Equal(t, getEntry.Id, entries[0].Id)
+}
+
+func TestCommandAudit_PendingNotRecorded(t *testing.T) {
+	dic := mockCommandDic(&messageBusStub{})
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	auditLog.Close()
+	dic.Update(di.ServiceConstructorMap{
+		commandContainer.CommandAuditLogName: func(get di.Get) interface{} {
+			return auditLog
+		},
+	})
+
+	caller := CommandCaller{Identity: testCaller, CorrelationId: testCorrelationId, Channel: CommandChannelRest}
+	_, err = IssueGetCommandByName(testDeviceName, testCommandName, "ds-pushevent=yes", caller, dic)
+	require.Error(t, err, "the command should not be issued when it fails to be recorded as pending")
+	_, err = IssueSetCommandByName(testDeviceName, testCommandName, "", map[string]interface{}{"testResource": "1"}, caller, dic)
+	require.Error(t, err, "the command should not be issued when it fails to be recorded as pending")
+
+	dscc := bootstrapContainer.DeviceServiceCommandClientFrom(dic.Get).(*mocks.DeviceServiceCommandClient)
+	dscc.AssertNotCalled(t, "GetCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	dscc.AssertNotCalled(t, "SetCommandWithObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,264 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// batchCommandService is a device service the commands of a batch are issued to, tokens bounds the number of the
+// commands issued to it at the same time
+type batchCommandService struct {
+	baseAddress string
+	err         errors.EdgeX
+	tokens      chan struct{}
+}
+
+// batchCommandTargets looks up the devices, the device profiles and the device services of a batch, each of them only
+// once
+type batchCommandTargets struct {
+	dic            *di.Container
+	maxConcurrency int
+	devices        map[string]dtos.Device
+	deviceErrors   map[string]errors.EdgeX
+	profiles       map[string]dtos.DeviceProfile
+	profileErrors  map[string]errors.EdgeX
+	services       map[string]*batchCommandService
+}
+
+// IssueBatchCommands issues the commands of the batch, and returns their results in the order of the request.  The
+// failure of a command is reported in its result, an error is only returned when the batch can't be issued at all.  Each
+// command is recorded in the audit log with the caller.
+func IssueBatchCommands(ctx context.Context, req commandDTOs.BatchCommandRequest, caller CommandCaller, dic *di.Container) ([]commandDTOs.BatchCommandResult, errors.EdgeX) {
+	if err := common.Validate(req); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "invalid batch command request", err)
+	}
+	if (len(req.Commands) == 0) == (len(req.Labels) == 0) {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "either the commands or the labels of the devices have to be specified", nil)
+	}
+
+	dscc := bootstrapContainer.DeviceServiceCommandClientFrom(dic.Get)
+	if dscc == nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServerError, "nil DeviceServiceCommandClient returned", nil)
+	}
+
+	config := commandContainer.ConfigurationFrom(dic.Get).BatchCommand
+	targets := &batchCommandTargets{
+		dic:            dic,
+		maxConcurrency: config.MaxConcurrencyPerService,
+		devices:        make(map[string]dtos.Device),
+		deviceErrors:   make(map[string]errors.EdgeX),
+		profiles:       make(map[string]dtos.DeviceProfile),
+		profileErrors:  make(map[string]errors.EdgeX),
+		services:       make(map[string]*batchCommandService),
+	}
+	if targets.maxConcurrency <= 0 {
+		targets.maxConcurrency = 1
+	}
+
+	commands := req.Commands
+	if len(req.Labels) > 0 {
+		// the devices selected by labels are retrieved at once instead of one by one
+		dc := bootstrapContainer.MetadataDeviceClientFrom(dic.Get)
+		if dc == nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceClient returned", nil)
+		}
+		multiDevicesResponse, err := dc.AllDevices(ctx, req.Labels, 0, -1)
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		commands = make([]commandDTOs.BatchCommand, len(multiDevicesResponse.Devices))
+		for i, device := range multiDevicesResponse.Devices {
+			targets.devices[device.Name] = device
+			commands[i] = commandDTOs.BatchCommand{
+				DeviceName:      device.Name,
+				CommandName:     req.CommandName,
+				QueryParameters: req.QueryParameters,
+				Settings:        req.Settings,
+			}
+		}
+	}
+	if config.MaxCommands > 0 && len(commands) > config.MaxCommands {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("the batch has %d commands, which exceeds the maximum of %d", len(commands), config.MaxCommands), nil)
+	}
+
+	results := make([]commandDTOs.BatchCommandResult, len(commands))
+	var wg sync.WaitGroup
+	for i, command := range commands {
+		results[i] = commandDTOs.BatchCommandResult{
+			DeviceName:  command.DeviceName,
+			CommandName: command.CommandName,
+			Method:      commandMethodGet,
+		}
+		if len(command.Settings) > 0 {
+			results[i].Method = commandMethodSet
+		}
+		query := batchCommandQuery(command)
+
+		service, err := targets.serviceOf(ctx, command.DeviceName)
+		if err == nil && results[i].Method == commandMethodSet {
+			err = targets.validateSettings(ctx, command)
+		}
+		if err != nil {
+			// the commands failed before being issued are recorded as well
+			setBatchCommandError(&results[i], err)
+			record, auditErr := startCommandAudit(caller, command.DeviceName, command.CommandName, results[i].Method, query.Encode(), command.Settings, dic)
+			if auditErr != nil {
+				bootstrapContainer.LoggingClientFrom(dic.Get).Error(auditErr.Error())
+			}
+			record.failed(err)
+			continue
+		}
+		wg.Add(1)
+		go func(command commandDTOs.BatchCommand, result *commandDTOs.BatchCommandResult) {
+			defer wg.Done()
+			service.tokens <- struct{}{}
+			defer func() { <-service.tokens }()
+			// the audit starts once the command is issued, so its latency excludes the wait for the device service
+			record, err := startCommandAudit(caller, command.DeviceName, command.CommandName, result.Method, query.Encode(), command.Settings, dic)
+			if err != nil {
+				setBatchCommandError(result, err)
+				return
+			}
+			issueBatchCommand(ctx, dscc, service.baseAddress, command, query, result)
+			record.done(result.StatusCode, result.Message)
+		}(command, &results[i])
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func (t *batchCommandTargets) device(ctx context.Context, name string) (dtos.Device, errors.EdgeX) {
+	if err, ok := t.deviceErrors[name]; ok {
+		return dtos.Device{}, err
+	}
+	device, ok := t.devices[name]
+	if !ok {
+		var err errors.EdgeX
+		device, err = deviceByName(ctx, name, t.dic)
+		if err != nil {
+			t.deviceErrors[name] = err
+			return dtos.Device{}, err
+		}
+		t.devices[name] = device
+	}
+	return device, nil
+}
+
+// validateSettings validates the settings of the set command against the device profile of the device
+func (t *batchCommandTargets) validateSettings(ctx context.Context, command commandDTOs.BatchCommand) errors.EdgeX {
+	device, err := t.device(ctx, command.DeviceName)
+	if err != nil {
+		return err
+	}
+	if err, ok := t.profileErrors[device.ProfileName]; ok {
+		return err
+	}
+	profile, ok := t.profiles[device.ProfileName]
+	if !ok {
+		profile, err = deviceProfileByName(ctx, device.ProfileName, t.dic)
+		if err != nil {
+			t.profileErrors[device.ProfileName] = err
+			return err
+		}
+		t.profiles[device.ProfileName] = profile
+	}
+	return validateSetCommandSettings(profile, command.CommandName, command.Settings)
+}
+
+// serviceOf returns the device service of the device
+func (t *batchCommandTargets) serviceOf(ctx context.Context, deviceName string) (*batchCommandService, errors.EdgeX) {
+	device, err := t.device(ctx, deviceName)
+	if err != nil {
+		return nil, err
+	}
+	serviceName := device.ServiceName
+
+	service, ok := t.services[serviceName]
+	if !ok {
+		service = &batchCommandService{tokens: make(chan struct{}, t.maxConcurrency)}
+		deviceService, err := deviceServiceByName(ctx, serviceName, t.dic)
+		if err != nil {
+			service.err = err
+		} else {
+			service.baseAddress = deviceService.BaseAddress
+		}
+		t.services[serviceName] = service
+	}
+	if service.err != nil {
+		return nil, service.err
+	}
+	return service, nil
+}
+
+func batchCommandQuery(command commandDTOs.BatchCommand) url.Values {
+	query := url.Values{}
+	for key, value := range command.QueryParameters {
+		query.Set(key, value)
+	}
+	return query
+}
+
+// issueBatchCommand issues the command to the device service, and records the response and the timing in the result
+func issueBatchCommand(ctx context.Context, dscc interfaces.DeviceServiceCommandClient, baseAddress string, command commandDTOs.BatchCommand, query url.Values, result *commandDTOs.BatchCommandResult) {
+	started := time.Now()
+	result.Started = started.UnixNano() / int64(time.Millisecond)
+	defer func() {
+		result.Duration = time.Since(started).String()
+	}()
+
+	if result.Method == commandMethodSet {
+		response, err := dscc.SetCommandWithObject(ctx, baseAddress, command.DeviceName, command.CommandName, query.Encode(), command.Settings)
+		if err != nil {
+			setBatchCommandError(result, err)
+			return
+		}
+		result.StatusCode = response.StatusCode
+		result.Message = response.Message
+		return
+	}
+
+	err := ValidateGetCommandParameters(query)
+	if err != nil {
+		setBatchCommandError(result, err)
+		return
+	}
+	eventResponse, err := dscc.GetCommand(ctx, baseAddress, command.DeviceName, command.CommandName, query.Encode())
+	if err != nil {
+		setBatchCommandError(result, err)
+		return
+	}
+	if eventResponse == nil {
+		// the device service returns no event when ds-returnevent is no
+		result.StatusCode = http.StatusOK
+		return
+	}
+	result.StatusCode = eventResponse.StatusCode
+	result.Message = eventResponse.Message
+	result.Event = &eventResponse.Event
+}
+
+func setBatchCommandError(result *commandDTOs.BatchCommandResult, err errors.EdgeX) {
+	result.StatusCode = err.Code()
+	result.Message = err.Error()
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/config"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueBatchCommands_BoundedConcurrencyPerService(t *testing.T) {
+	maxConcurrency := 2
+	serviceNames := []string{testServiceName + "1", testServiceName + "2"}
+	devicesPerService := 6
+
+	dcMock := &mocks.DeviceClient{}
+	dscMock := &mocks.DeviceServiceClient{}
+	dsccMock := &mocks.DeviceServiceCommandClient{}
+	var commands []commandDTOs.BatchCommand
+	for _, serviceName := range serviceNames {
+		baseAddress := "http://" + serviceName
+		dscMock.On("DeviceServiceByName", mock.Anything, serviceName).
+			Return(responses.DeviceServiceResponse{Service: dtos.DeviceService{Name: serviceName, BaseAddress: baseAddress}}, nil)
+		for i := 0; i < devicesPerService; i++ {
+			deviceName := fmt.Sprintf("%s-device%d", serviceName, i)
+			dcMock.On("DeviceByName", mock.Anything, deviceName).
+				Return(responses.DeviceResponse{Device: dtos.Device{Name: deviceName, ServiceName: serviceName}}, nil)
+			// each device is commanded twice, but only looked up once
+			commands = append(commands,
+				commandDTOs.BatchCommand{DeviceName: deviceName, CommandName: testCommandName},
+				commandDTOs.BatchCommand{DeviceName: deviceName, CommandName: testCommandName})
+		}
+	}
+
+	var mutex sync.Mutex
+	running := make(map[string]int)
+	maxRunning := make(map[string]int)
+	dsccMock.On("GetCommand", mock.Anything, mock.Anything, mock.Anything, testCommandName, "").
+		Run(func(args mock.Arguments) {
+			baseAddress := args.String(1)
+			mutex.Lock()
+			running[baseAddress]++
+			if running[baseAddress] > maxRunning[baseAddress] {
+				maxRunning[baseAddress] = running[baseAddress]
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			running[baseAddress]--
+			mutex.Unlock()
+		}).
+		Return(nil, nil)
+
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		commandContainer.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				BatchCommand: config.BatchCommandInfo{MaxCommands: 100, MaxConcurrencyPerService: maxConcurrency},
+			}
+		},
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		bootstrapContainer.MetadataDeviceClientName: func(get di.Get) interface{} {
+			return dcMock
+		},
+		bootstrapContainer.MetadataDeviceServiceClientName: func(get di.Get) interface{} {
+			return dscMock
+		},
+		bootstrapContainer.DeviceServiceCommandClientName: func(get di.Get) interface{} {
+			return dsccMock
+		},
+	})
+
+	results, err := IssueBatchCommands(context.Background(), commandDTOs.BatchCommandRequest{BaseRequest: commonDTO.NewBaseRequest(), Commands: commands}, CommandCaller{}, dic)
+	require.NoError(t, err)
+	require.Len(t, results, len(commands))
+	for i, result := range results {
+		assert.Equal(t, commands[i].DeviceName, result.DeviceName)
+		assert.Equal(t, commandMethodGet, result.Method)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	}
+	for _, serviceName := range serviceNames {
+		assert.Equal(t, maxConcurrency, maxRunning["http://"+serviceName])
+	}
+	dcMock.AssertNumberOfCalls(t, "DeviceByName", len(serviceNames)*devicesPerService)
+	dscMock.AssertNumberOfCalls(t, "DeviceServiceByName", len(serviceNames))
+}
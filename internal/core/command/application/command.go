@@ -8,6 +8,8 @@ package application
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
@@ -33,23 +35,18 @@ func AllCommands(offset int, limit int, dic *di.Container) (deviceCoreCommands [
 		return deviceCoreCommands, totalCount, errors.NewCommonEdgeXWrapper(err)
 	}
 
-	// retrieve device profile information through Metadata DeviceProfileClient
-	dpc := bootstrapContainer.MetadataDeviceProfileClientFrom(dic.Get)
-	if dpc == nil {
-		return deviceCoreCommands, totalCount, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceProfileClient returned", nil)
-	}
-
 	// Prepare the url for command
 	configuration := commandContainer.ConfigurationFrom(dic.Get)
 	serviceUrl := configuration.Service.Url()
 
 	deviceCoreCommands = make([]dtos.DeviceCoreCommand, len(multiDevicesResponse.Devices))
 	for i, device := range multiDevicesResponse.Devices {
-		deviceProfileResponse, err := dpc.DeviceProfileByName(context.Background(), device.ProfileName)
+		// the profiles shared by the devices are only retrieved once while they are cached
+		profile, err := deviceProfileByName(context.Background(), device.ProfileName, dic)
 		if err != nil {
 			return deviceCoreCommands, totalCount, errors.NewCommonEdgeXWrapper(err)
 		}
-		commands, err := buildCoreCommands(device.Name, serviceUrl, deviceProfileResponse.Profile)
+		commands, err := buildCoreCommands(device.Name, serviceUrl, profile)
 		if err != nil {
 			return nil, totalCount, errors.NewCommonEdgeXWrapper(err)
 		}
@@ -68,22 +65,12 @@ func CommandsByDeviceName(name string, dic *di.Container) (deviceCoreCommand dto
 		return deviceCoreCommand, errors.NewCommonEdgeX(errors.KindContractInvalid, "device name is empty", nil)
 	}
 
-	// retrieve device information through Metadata DeviceClient
-	dc := bootstrapContainer.MetadataDeviceClientFrom(dic.Get)
-	if dc == nil {
-		return deviceCoreCommand, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceClient returned", nil)
-	}
-	deviceResponse, err := dc.DeviceByName(context.Background(), name)
+	// retrieve device and device profile information from the cache or Metadata
+	device, err := deviceByName(context.Background(), name, dic)
 	if err != nil {
 		return deviceCoreCommand, errors.NewCommonEdgeXWrapper(err)
 	}
-
-	// retrieve device profile information through Metadata DeviceProfileClient
-	dpc := bootstrapContainer.MetadataDeviceProfileClientFrom(dic.Get)
-	if dpc == nil {
-		return deviceCoreCommand, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceProfileClient returned", nil)
-	}
-	deviceProfileResponse, err := dpc.DeviceProfileByName(context.Background(), deviceResponse.Device.ProfileName)
+	profile, err := deviceProfileByName(context.Background(), device.ProfileName, dic)
 	if err != nil {
 		return deviceCoreCommand, errors.NewCommonEdgeXWrapper(err)
 	}
@@ -92,14 +79,14 @@ func CommandsByDeviceName(name string, dic *di.Container) (deviceCoreCommand dto
 	configuration := commandContainer.ConfigurationFrom(dic.Get)
 	serviceUrl := configuration.Service.Url()
 
-	commands, err := buildCoreCommands(deviceResponse.Device.Name, serviceUrl, deviceProfileResponse.Profile)
+	commands, err := buildCoreCommands(device.Name, serviceUrl, profile)
 	if err != nil {
 		return deviceCoreCommand, errors.NewCommonEdgeXWrapper(err)
 	}
 
 	deviceCoreCommand = dtos.DeviceCoreCommand{
-		DeviceName:   deviceResponse.Device.Name,
-		ProfileName:  deviceResponse.Device.ProfileName,
+		DeviceName:   device.Name,
+		ProfileName:  device.ProfileName,
 		CoreCommands: commands,
 	}
 	return deviceCoreCommand, nil
@@ -182,9 +169,21 @@ func buildCoreCommands(deviceName string, serviceUrl string, profile dtos.Device
 	return commands, nil
 }
 
+// ValidateGetCommandParameters checks the values of the query parameters which tell the device service whether to
+// return and push the event of the get command
+func ValidateGetCommandParameters(query url.Values) errors.EdgeX {
+	for _, key := range []string{common.ReturnEvent, common.PushEvent} {
+		values, ok := query[key]
+		if ok && len(values) > 0 && values[0] != common.ValueYes && values[0] != common.ValueNo {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid query parameter, %s has to be %s or %s", values[0], common.ValueYes, common.ValueNo), nil)
+		}
+	}
+	return nil
+}
+
 // IssueGetCommandByName issues the specified get(read) command referenced by the command name to the device/sensor, also
-// referenced by name.
-func IssueGetCommandByName(deviceName string, commandName string, queryParams string, dic *di.Container) (res *responses.EventResponse, err errors.EdgeX) {
+// referenced by name.  The command is recorded in the audit log with the caller, whether or not it succeeds.
+func IssueGetCommandByName(deviceName string, commandName string, queryParams string, caller CommandCaller, dic *di.Container) (res *responses.EventResponse, err errors.EdgeX) {
 	if deviceName == "" {
 		return res, errors.NewCommonEdgeX(errors.KindContractInvalid, "device name cannot be empty", nil)
 	}
@@ -193,22 +192,28 @@ func IssueGetCommandByName(deviceName string, commandName string, queryParams st
 		return res, errors.NewCommonEdgeX(errors.KindContractInvalid, "command name cannot be empty", nil)
 	}
 
-	// retrieve device information through Metadata DeviceClient
-	dc := bootstrapContainer.MetadataDeviceClientFrom(dic.Get)
-	if dc == nil {
-		return res, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceClient returned", nil)
-	}
-	deviceResponse, err := dc.DeviceByName(context.Background(), deviceName)
+	record, err := startCommandAudit(caller, deviceName, commandName, commandMethodGet, queryParams, nil, dic)
 	if err != nil {
 		return res, errors.NewCommonEdgeXWrapper(err)
 	}
+	defer func() {
+		switch {
+		case err != nil:
+			record.failed(err)
+		case res == nil:
+			// the device service returns no event when ds-returnevent is no
+			record.done(http.StatusOK, "")
+		default:
+			record.done(res.StatusCode, res.Message)
+		}
+	}()
 
-	// retrieve device service information through Metadata DeviceClient
-	dsc := bootstrapContainer.MetadataDeviceServiceClientFrom(dic.Get)
-	if dsc == nil {
-		return res, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceServiceClient returned", nil)
+	// retrieve device and device service information from the cache or Metadata
+	device, err := deviceByName(context.Background(), deviceName, dic)
+	if err != nil {
+		return res, errors.NewCommonEdgeXWrapper(err)
 	}
-	deviceServiceResponse, err := dsc.DeviceServiceByName(context.Background(), deviceResponse.Device.ServiceName)
+	deviceService, err := deviceServiceByName(context.Background(), device.ServiceName, dic)
 	if err != nil {
 		return res, errors.NewCommonEdgeXWrapper(err)
 	}
@@ -218,7 +223,7 @@ func IssueGetCommandByName(deviceName string, commandName string, queryParams st
 	if dscc == nil {
 		return res, errors.NewCommonEdgeX(errors.KindServerError, "nil DeviceServiceCommandClient returned", nil)
 	}
-	res, err = dscc.GetCommand(context.Background(), deviceServiceResponse.Service.BaseAddress, deviceName, commandName, queryParams)
+	res, err = dscc.GetCommand(context.Background(), deviceService.BaseAddress, deviceName, commandName, queryParams)
 	if err != nil {
 		return res, errors.NewCommonEdgeXWrapper(err)
 	}
@@ -227,8 +232,8 @@ func IssueGetCommandByName(deviceName string, commandName string, queryParams st
 }
 
 // IssueSetCommandByName issues the specified set(write) command referenced by the command name to the device/sensor, also
-// referenced by name.
-func IssueSetCommandByName(deviceName string, commandName string, queryParams string, settings map[string]interface{}, dic *di.Container) (response commonDTO.BaseResponse, err errors.EdgeX) {
+// referenced by name.  The command is recorded in the audit log with the caller, whether or not it succeeds.
+func IssueSetCommandByName(deviceName string, commandName string, queryParams string, settings map[string]interface{}, caller CommandCaller, dic *di.Container) (response commonDTO.BaseResponse, err errors.EdgeX) {
 	if deviceName == "" {
 		return response, errors.NewCommonEdgeX(errors.KindContractInvalid, "device name cannot be empty", nil)
 	}
@@ -237,22 +242,35 @@ func IssueSetCommandByName(deviceName string, commandName string, queryParams st
 		return response, errors.NewCommonEdgeX(errors.KindContractInvalid, "command name cannot be empty", nil)
 	}
 
-	// retrieve device information through Metadata DeviceClient
-	dc := bootstrapContainer.MetadataDeviceClientFrom(dic.Get)
-	if dc == nil {
-		return response, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceClient returned", nil)
+	record, err := startCommandAudit(caller, deviceName, commandName, commandMethodSet, queryParams, settings, dic)
+	if err != nil {
+		return response, errors.NewCommonEdgeXWrapper(err)
 	}
-	deviceResponse, err := dc.DeviceByName(context.Background(), deviceName)
+	defer func() {
+		if err != nil {
+			record.failed(err)
+			return
+		}
+		record.done(response.StatusCode, response.Message)
+	}()
+
+	// retrieve device and device service information from the cache or Metadata
+	device, err := deviceByName(context.Background(), deviceName, dic)
 	if err != nil {
 		return response, errors.NewCommonEdgeXWrapper(err)
 	}
 
-	// retrieve device service information through Metadata DeviceClient
-	dsc := bootstrapContainer.MetadataDeviceServiceClientFrom(dic.Get)
-	if dsc == nil {
-		return response, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceServiceClient returned", nil)
+	// validate the settings against the device profile, so that the invalid values never reach the device
+	profile, err := deviceProfileByName(context.Background(), device.ProfileName, dic)
+	if err != nil {
+		return response, errors.NewCommonEdgeXWrapper(err)
 	}
-	deviceServiceResponse, err := dsc.DeviceServiceByName(context.Background(), deviceResponse.Device.ServiceName)
+	err = validateSetCommandSettings(profile, commandName, settings)
+	if err != nil {
+		return response, errors.NewCommonEdgeXWrapper(err)
+	}
+
+	deviceService, err := deviceServiceByName(context.Background(), device.ServiceName, dic)
 	if err != nil {
 		return response, errors.NewCommonEdgeXWrapper(err)
 	}
@@ -262,5 +280,5 @@ func IssueSetCommandByName(deviceName string, commandName string, queryParams st
 	if dscc == nil {
 		return response, errors.NewCommonEdgeX(errors.KindServerError, "nil DeviceServiceCommandClient returned", nil)
 	}
-	return dscc.SetCommandWithObject(context.Background(), deviceServiceResponse.Service.BaseAddress, deviceName, commandName, queryParams, settings)
+	return dscc.SetCommandWithObject(context.Background(), deviceService.BaseAddress, deviceName, commandName, queryParams, settings)
 }
@@ -0,0 +1,190 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+)
+
+// The methods of the command requests, which are the last level of the request topic
+const (
+	commandMethodGet = "get"
+	commandMethodSet = "set"
+)
+
+// SubscribeCommandRequests subscribes to the command requests from the message bus.  Each request is issued to the
+// device in its own goroutine, and the response is published with the correlation id of the request under the
+// PublishTopicPrefix/<device-name>/<command-name>/<method> topic.  The tokens bound the number of the requests issued
+// at the same time to CommandRequests.MaxConcurrency, the next request isn't received until one of them is done.
+func SubscribeCommandRequests(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	configuration := commandContainer.ConfigurationFrom(dic.Get)
+	messageBusInfo := configuration.MessageQueue
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	maxConcurrency := configuration.CommandRequests.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+	tokens := make(chan struct{}, maxConcurrency)
+
+	messageBus := commandContainer.MessagingClientFrom(dic.Get)
+	if messageBus == nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, "nil MessagingClient returned", nil)
+	}
+
+	messages := make(chan types.MessageEnvelope)
+	messageErrors := make(chan error)
+	topics := []types.TopicChannel{
+		{
+			Topic:    messageBusInfo.SubscribeTopic,
+			Messages: messages,
+		},
+	}
+	err := messageBus.Subscribe(topics, messageErrors)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				lc.Infof("Exiting waiting for MessageBus '%s' topic messages", messageBusInfo.SubscribeTopic)
+				return
+			case e := <-messageErrors:
+				lc.Error(e.Error())
+			case requestEnvelope := <-messages:
+				lc.Debugf("Command request received on message queue. Topic: %s, Correlation-id: %s", requestEnvelope.ReceivedTopic, requestEnvelope.CorrelationID)
+				select {
+				case <-ctx.Done():
+					lc.Infof("Exiting waiting for MessageBus '%s' topic messages", messageBusInfo.SubscribeTopic)
+					return
+				case tokens <- struct{}{}:
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-tokens }()
+					responseTopic, responseEnvelope, edgexErr := handleCommandRequest(requestEnvelope, messageBusInfo.PublishTopicPrefix, dic)
+					if edgexErr != nil {
+						lc.Errorf("fail to handle the command request, %v", edgexErr)
+						return
+					}
+					err := messageBus.Publish(responseEnvelope, responseTopic)
+					if err != nil {
+						lc.Errorf("fail to publish the command response to %s, Correlation-id: %s, %v", responseTopic, responseEnvelope.CorrelationID, err)
+					}
+				}()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// handleCommandRequest issues the command request, and returns the response and its topic.  The failures of the
+// command are reported in the response, an error is only returned when the request can't be answered at all.
+func handleCommandRequest(requestEnvelope types.MessageEnvelope, responseTopicPrefix string, dic *di.Container) (string, types.MessageEnvelope, errors.EdgeX) {
+	// the request topic ends with /<device-name>/<command-name>/<method>
+	fields := strings.Split(requestEnvelope.ReceivedTopic, "/")
+	if len(fields) < 4 {
+		return "", types.MessageEnvelope{}, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid command request topic %s", requestEnvelope.ReceivedTopic), nil)
+	}
+	deviceName := fields[len(fields)-3]
+	commandName := fields[len(fields)-2]
+	method := fields[len(fields)-1]
+	responseTopic := strings.Join([]string{responseTopicPrefix, deviceName, commandName, method}, "/")
+
+	var response interface{}
+	var request commandDTOs.CommandRequest
+	edgexErr := unmarshalCommandRequest(requestEnvelope, &request)
+	if edgexErr == nil {
+		caller := CommandCaller{CorrelationId: requestEnvelope.CorrelationID, Channel: CommandChannelMessageBus}
+		response, edgexErr = issueCommandRequest(deviceName, commandName, method, request, caller, dic)
+	}
+	if edgexErr != nil {
+		response = commonDTO.NewBaseResponse(request.RequestId, edgexErr.Error(), edgexErr.Code())
+	}
+
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return "", types.MessageEnvelope{}, errors.NewCommonEdgeX(errors.KindServerError, "failed to encode the command response", err)
+	}
+	responseEnvelope := types.MessageEnvelope{
+		CorrelationID: requestEnvelope.CorrelationID,
+		ContentType:   common.ContentTypeJSON,
+		Payload:       payload,
+	}
+	return responseTopic, responseEnvelope, nil
+}
+
+func unmarshalCommandRequest(envelope types.MessageEnvelope, request *commandDTOs.CommandRequest) errors.EdgeX {
+	if envelope.ContentType != common.ContentTypeJSON && envelope.ContentType != "" {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unsupported content-type '%s' received", envelope.ContentType), nil)
+	}
+	if len(envelope.Payload) == 0 {
+		return nil
+	}
+	err := json.Unmarshal(envelope.Payload, request)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to decode the command request", err)
+	}
+	return nil
+}
+
+func issueCommandRequest(deviceName string, commandName string, method string, request commandDTOs.CommandRequest, caller CommandCaller, dic *di.Container) (interface{}, errors.EdgeX) {
+	query := url.Values{}
+	for key, value := range request.QueryParameters {
+		query.Set(key, value)
+	}
+
+	switch method {
+	case commandMethodGet:
+		err := ValidateGetCommandParameters(query)
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		eventResponse, err := IssueGetCommandByName(deviceName, commandName, query.Encode(), caller, dic)
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		if eventResponse == nil {
+			// the device service returns no event when ds-returnevent is no
+			return commonDTO.NewBaseResponse(request.RequestId, "", http.StatusOK), nil
+		}
+		eventResponse.RequestId = request.RequestId
+		return eventResponse, nil
+	case commandMethodSet:
+		if len(request.Settings) == 0 {
+			return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "settings are required by the set command", nil)
+		}
+		response, err := IssueSetCommandByName(deviceName, commandName, query.Encode(), request.Settings, caller, dic)
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		response.RequestId = request.RequestId
+		return response, nil
+	}
+	return nil, errors.NewCommonEdgeX(errors.KindContractInvalid,
+		fmt.Sprintf("invalid command method %s, which has to be %s or %s", method, commandMethodGet, commandMethodSet), nil)
+}
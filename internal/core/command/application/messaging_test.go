@@ -0,0 +1,259 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/config"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testServiceName     = "testDeviceService"
+	testBaseAddress     = "http://localhost:49990"
+	testRequestTopic    = "edgex/command/request"
+	testResponsePrefix  = "edgex/command/response"
+	testCommandName     = "testCommand"
+	unknownDeviceName   = "unknownDevice"
+	testCorrelationId   = "14a42ea6-c394-41c3-8bcd-a29b9f5e6835"
+	testSubscribedTopic = testRequestTopic + "/#"
+)
+
+// messageBusStub delivers the published messages to the test, and lets the test send messages to the subscriber
+type messageBusStub struct {
+	messages  chan<- types.MessageEnvelope
+	published chan types.MessageEnvelope
+	topics    chan string
+}
+
+func (m *messageBusStub) Connect() error { return nil }
+
+func (m *messageBusStub) Publish(message types.MessageEnvelope, topic string) error {
+	m.topics <- topic
+	m.published <- message
+	return nil
+}
+
+func (m *messageBusStub) Subscribe(topics []types.TopicChannel, _ chan error) error {
+	m.messages = topics[0].Messages
+	return nil
+}
+
+func (m *messageBusStub) Disconnect() error { return nil }
+
+func mockCommandDic(messageBus *messageBusStub) *di.Container {
+	event := dtos.NewEvent("testProfile", testDeviceName, testCommandName)
+	eventResponse := responses.NewEventResponse("", "", http.StatusOK, event)
+	deviceResponse := responses.DeviceResponse{Device: dtos.Device{Name: testDeviceName, ProfileName: testProfileName, ServiceName: testServiceName}}
+	profileResponse := responses.DeviceProfileResponse{Profile: dtos.DeviceProfile{
+		Name: testProfileName,
+		DeviceResources: []dtos.DeviceResource{
+			{Name: "testResource", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeInt16, ReadWrite: common.ReadWrite_RW}},
+		},
+		DeviceCommands: []dtos.DeviceCommand{
+			{Name: testCommandName, ReadWrite: common.ReadWrite_RW, ResourceOperations: []dtos.ResourceOperation{{DeviceResource: "testResource"}}},
+		},
+	}}
+	serviceResponse := responses.DeviceServiceResponse{Service: dtos.DeviceService{Name: testServiceName, BaseAddress: testBaseAddress}}
+
+	dcMock := &mocks.DeviceClient{}
+	dcMock.On("DeviceByName", mock.Anything, testDeviceName).Return(deviceResponse, nil)
+	dcMock.On("DeviceByName", mock.Anything, unknownDeviceName).Return(responses.DeviceResponse{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device not found", nil))
+	dpcMock := &mocks.DeviceProfileClient{}
+	dpcMock.On("DeviceProfileByName", mock.Anything, testProfileName).Return(profileResponse, nil)
+	dscMock := &mocks.DeviceServiceClient{}
+	dscMock.On("DeviceServiceByName", mock.Anything, testServiceName).Return(serviceResponse, nil)
+	dsccMock := &mocks.DeviceServiceCommandClient{}
+	dsccMock.On("GetCommand", mock.Anything, testBaseAddress, testDeviceName, testCommandName, "ds-pushevent=yes").Return(&eventResponse, nil)
+	dsccMock.On("GetCommand", mock.Anything, testBaseAddress, testDeviceName, testCommandName, "ds-returnevent=no").Return(nil, nil)
+	dsccMock.On("SetCommandWithObject", mock.Anything, testBaseAddress, testDeviceName, testCommandName, "", map[string]interface{}{"testResource": "1"}).
+		Return(commonDTO.NewBaseResponse("", "", http.StatusOK), nil)
+
+	return di.NewContainer(di.ServiceConstructorMap{
+		commandContainer.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				MessageQueue: bootstrapConfig.MessageBusInfo{
+					PublishTopicPrefix: testResponsePrefix,
+					SubscribeTopic:     testSubscribedTopic,
+					SubscribeEnabled:   true,
+				},
+			}
+		},
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		commandContainer.MessagingClientName: func(get di.Get) interface{} {
+			return messageBus
+		},
+		bootstrapContainer.MetadataDeviceClientName: func(get di.Get) interface{} {
+			return dcMock
+		},
+		bootstrapContainer.MetadataDeviceProfileClientName: func(get di.Get) interface{} {
+			return dpcMock
+		},
+		bootstrapContainer.MetadataDeviceServiceClientName: func(get di.Get) interface{} {
+			return dscMock
+		},
+		bootstrapContainer.DeviceServiceCommandClientName: func(get di.Get) interface{} {
+			return dsccMock
+		},
+	})
+}
+
+func TestSubscribeCommandRequests(t *testing.T) {
+	messageBus := &messageBusStub{published: make(chan types.MessageEnvelope, 1), topics: make(chan string, 1)}
+	dic := mockCommandDic(messageBus)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	require.NoError(t, SubscribeCommandRequests(ctx, &wg, dic))
+	require.NotNil(t, messageBus.messages)
+
+	requestId := uuid.NewString()
+	tests := []struct {
+		name               string
+		deviceName         string
+		method             string
+		payload            string
+		expectedStatusCode int
+		expectedEvent      bool
+	}{
+		{"Valid - get command", testDeviceName, commandMethodGet, `{"requestId":"` + requestId + `","queryParameters":{"ds-pushevent":"yes"}}`, http.StatusOK, true},
+		{"Valid - get command without event", testDeviceName, commandMethodGet, `{"requestId":"` + requestId + `","queryParameters":{"ds-returnevent":"no"}}`, http.StatusOK, false},
+		{"Valid - set command", testDeviceName, commandMethodSet, `{"requestId":"` + requestId + `","settings":{"testResource":"1"}}`, http.StatusOK, false},
+		{"Invalid - set command with invalid settings", testDeviceName, commandMethodSet, `{"requestId":"` + requestId + `","settings":{"testResource":"1.5"}}`, http.StatusBadRequest, false},
+		{"Invalid - set command without settings", testDeviceName, commandMethodSet, `{"requestId":"` + requestId + `"}`, http.StatusBadRequest, false},
+		{"Invalid - unknown device", unknownDeviceName, commandMethodGet, `{"requestId":"` + requestId + `"}`, http.StatusNotFound, false},
+		{"Invalid - unknown method", testDeviceName, "put", `{"requestId":"` + requestId + `"}`, http.StatusBadRequest, false},
+		{"Invalid - invalid ds-pushevent", testDeviceName, commandMethodGet, `{"requestId":"` + requestId + `","queryParameters":{"ds-pushevent":"123"}}`, http.StatusBadRequest, false},
+		{"Invalid - malformed payload", testDeviceName, commandMethodGet, `{"requestId":`, http.StatusBadRequest, false},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			topic := testRequestTopic + "/" + testCase.deviceName + "/" + testCommandName + "/" + testCase.method
+			messageBus.messages <- types.MessageEnvelope{
+				ReceivedTopic: topic,
+				CorrelationID: testCorrelationId,
+				ContentType:   common.ContentTypeJSON,
+				Payload:       []byte(testCase.payload),
+			}
+
+			var responseTopic string
+			var envelope types.MessageEnvelope
+			select {
+			case responseTopic = <-messageBus.topics:
+				envelope = <-messageBus.published
+			case <-time.After(time.Second):
+				require.Fail(t, "the response was not published")
+			}
+			assert.Equal(t, testResponsePrefix+"/"+testCase.deviceName+"/"+testCommandName+"/"+testCase.method, responseTopic)
+			assert.Equal(t, testCorrelationId, envelope.CorrelationID)
+			assert.Equal(t, common.ContentTypeJSON, envelope.ContentType)
+
+			var response responses.EventResponse
+			require.NoError(t, json.Unmarshal(envelope.Payload, &response))
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			if testCase.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, requestId, response.RequestId)
+				assert.Empty(t, response.Message)
+			} else {
+				assert.NotEmpty(t, response.Message)
+			}
+			assert.Equal(t, testCase.expectedEvent, response.Event.DeviceName == testDeviceName)
+		})
+	}
+}
+
+func TestSubscribeCommandRequests_MaxConcurrency(t *testing.T) {
+	messageBus := &messageBusStub{published: make(chan types.MessageEnvelope, 3), topics: make(chan string, 3)}
+	dic := mockCommandDic(messageBus)
+	commandContainer.ConfigurationFrom(dic.Get).CommandRequests.MaxConcurrency = 2
+	var issued int32
+	release := make(chan struct{})
+	dsccMock := &mocks.DeviceServiceCommandClient{}
+	dsccMock.On("GetCommand", mock.Anything, testBaseAddress, testDeviceName, testCommandName, "ds-returnevent=no").Return(nil, nil).
+		Run(func(mock.Arguments) {
+			atomic.AddInt32(&issued, 1)
+			<-release
+		})
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.DeviceServiceCommandClientName: func(get di.Get) interface{} {
+			return dsccMock
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	require.NoError(t, SubscribeCommandRequests(ctx, &wg, dic))
+
+	for i := 0; i < 3; i++ {
+		messageBus.messages <- types.MessageEnvelope{
+			ReceivedTopic: testRequestTopic + "/" + testDeviceName + "/" + testCommandName + "/" + commandMethodGet,
+			CorrelationID: testCorrelationId,
+			ContentType:   common.ContentTypeJSON,
+			Payload:       []byte(`{"queryParameters":{"ds-returnevent":"no"}}`),
+		}
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&issued) == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued), "the third request should wait for one of the others to be done")
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-messageBus.topics:
+			<-messageBus.published
+		case <-time.After(time.Second):
+			require.Fail(t, "the response was not published")
+		}
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&issued))
+}
+
+func TestHandleCommandRequest_InvalidTopic(t *testing.T) {
+	dic := mockCommandDic(&messageBusStub{})
+	_, _, err := handleCommandRequest(types.MessageEnvelope{ReceivedTopic: "edgex/" + testDeviceName}, testResponsePrefix, dic)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+}
+
+func TestUnmarshalCommandRequest_Numbers(t *testing.T) {
+	var request commandDTOs.CommandRequest
+	err := unmarshalCommandRequest(types.MessageEnvelope{Payload: []byte(`{"settings":{"total":18446744073709551615}}`)}, &request)
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("18446744073709551615"), request.Settings["total"], "the number should keep the precision it is written with")
+}
@@ -0,0 +1,191 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+)
+
+// deviceByName returns the device from the cache, or queries it from core-metadata and caches it.  The device is only
+// cached if no cached metadata has been evicted during the query, since the device may have been queried before its
+// update.
+func deviceByName(ctx context.Context, name string, dic *di.Container) (dtos.Device, errors.EdgeX) {
+	deviceCache := commandContainer.DeviceCacheFrom(dic.Get)
+	var generation uint64
+	if deviceCache != nil {
+		generation = deviceCache.Generation()
+		if device, ok := deviceCache.Get(name); ok {
+			return device.(dtos.Device), nil
+		}
+	}
+
+	dc := bootstrapContainer.MetadataDeviceClientFrom(dic.Get)
+	if dc == nil {
+		return dtos.Device{}, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceClient returned", nil)
+	}
+	res, err := dc.DeviceByName(ctx, name)
+	if err != nil {
+		return dtos.Device{}, errors.NewCommonEdgeXWrapper(err)
+	}
+	if deviceCache != nil {
+		deviceCache.SetIfGeneration(name, res.Device, generation)
+	}
+	return res.Device, nil
+}
+
+// deviceProfileByName returns the device profile from the cache, or queries it from core-metadata and caches it
+func deviceProfileByName(ctx context.Context, name string, dic *di.Container) (dtos.DeviceProfile, errors.EdgeX) {
+	profileCache := commandContainer.DeviceProfileCacheFrom(dic.Get)
+	var generation uint64
+	if profileCache != nil {
+		generation = profileCache.Generation()
+		if profile, ok := profileCache.Get(name); ok {
+			return profile.(dtos.DeviceProfile), nil
+		}
+	}
+
+	dpc := bootstrapContainer.MetadataDeviceProfileClientFrom(dic.Get)
+	if dpc == nil {
+		return dtos.DeviceProfile{}, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceProfileClient returned", nil)
+	}
+	res, err := dpc.DeviceProfileByName(ctx, name)
+	if err != nil {
+		return dtos.DeviceProfile{}, errors.NewCommonEdgeXWrapper(err)
+	}
+	if profileCache != nil {
+		profileCache.SetIfGeneration(name, res.Profile, generation)
+	}
+	return res.Profile, nil
+}
+
+// deviceServiceByName returns the device service from the cache, or queries it from core-metadata and caches it
+func deviceServiceByName(ctx context.Context, name string, dic *di.Container) (dtos.DeviceService, errors.EdgeX) {
+	serviceCache := commandContainer.DeviceServiceCacheFrom(dic.Get)
+	var generation uint64
+	if serviceCache != nil {
+		generation = serviceCache.Generation()
+		if service, ok := serviceCache.Get(name); ok {
+			return service.(dtos.DeviceService), nil
+		}
+	}
+
+	dsc := bootstrapContainer.MetadataDeviceServiceClientFrom(dic.Get)
+	if dsc == nil {
+		return dtos.DeviceService{}, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceServiceClient returned", nil)
+	}
+	res, err := dsc.DeviceServiceByName(ctx, name)
+	if err != nil {
+		return dtos.DeviceService{}, errors.NewCommonEdgeXWrapper(err)
+	}
+	if serviceCache != nil {
+		serviceCache.SetIfGeneration(name, res.Service, generation)
+	}
+	return res.Service, nil
+}
+
+// SubscribeMetadataSystemEvents subscribes to the system events of core-metadata from the message bus, and evicts the
+// changed devices, device profiles and device services from the cache
+func SubscribeMetadataSystemEvents(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	subscribeTopic := commandContainer.ConfigurationFrom(dic.Get).MetadataCache.SubscribeTopic
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+
+	messageBus := commandContainer.MessagingClientFrom(dic.Get)
+	if messageBus == nil {
+		return errors.NewCommonEdgeX(errors.KindServerError, "nil MessagingClient returned", nil)
+	}
+
+	messages := make(chan types.MessageEnvelope)
+	messageErrors := make(chan error)
+	topics := []types.TopicChannel{
+		{
+			Topic:    subscribeTopic,
+			Messages: messages,
+		},
+	}
+	err := messageBus.Subscribe(topics, messageErrors)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				lc.Infof("Exiting waiting for MessageBus '%s' topic messages", subscribeTopic)
+				return
+			case e := <-messageErrors:
+				lc.Error(e.Error())
+			case envelope := <-messages:
+				var event pkgDTOs.SystemEvent
+				err := json.Unmarshal(envelope.Payload, &event)
+				if err != nil {
+					lc.Errorf("fail to decode the system event received on topic %s, %v", envelope.ReceivedTopic, err)
+					continue
+				}
+				edgexErr := evictMetadataCache(event, dic)
+				if edgexErr != nil {
+					lc.Errorf("fail to evict the cached metadata of the %s %s system event, %v", event.Type, event.Action, edgexErr)
+					continue
+				}
+				lc.Debugf("Cached metadata evicted by the %s %s system event, Correlation-id: %s", event.Type, event.Action, envelope.CorrelationID)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// evictMetadataCache removes the metadata changed by the system event from the cache by its name
+func evictMetadataCache(event pkgDTOs.SystemEvent, dic *di.Container) errors.EdgeX {
+	var metadataCache *cache.TTLCache
+	switch event.Type {
+	case pkgDTOs.DeviceSystemEventType:
+		metadataCache = commandContainer.DeviceCacheFrom(dic.Get)
+	case pkgDTOs.DeviceProfileSystemEventType:
+		metadataCache = commandContainer.DeviceProfileCacheFrom(dic.Get)
+	case pkgDTOs.DeviceServiceSystemEventType:
+		metadataCache = commandContainer.DeviceServiceCacheFrom(dic.Get)
+	default:
+		// the other metadata, e.g. the provision watchers, is not cached
+		return nil
+	}
+	if metadataCache == nil {
+		return nil
+	}
+
+	switch event.Action {
+	case pkgDTOs.SystemEventActionAdd:
+		// only the existing metadata is cached
+		return nil
+	case pkgDTOs.SystemEventActionUpdate, pkgDTOs.SystemEventActionDelete:
+		// the devices, device profiles and device services are all identified by name
+		var details struct {
+			Name string `json:"name"`
+		}
+		err := event.DecodeDetails(&details)
+		if err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		metadataCache.Delete(details.Name)
+		return nil
+	}
+	return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unknown system event action %s", event.Action), nil)
+}
@@ -0,0 +1,226 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/config"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testProfileName       = "testProfile"
+	testSystemEventsTopic = "edgex/system-events/core-metadata/#"
+)
+
+func mockMetadataCacheDic(messageBus *messageBusStub) (*di.Container, *mocks.DeviceClient, *mocks.DeviceProfileClient, *mocks.DeviceServiceClient) {
+	dcMock := &mocks.DeviceClient{}
+	dcMock.On("DeviceByName", mock.Anything, testDeviceName).
+		Return(responses.DeviceResponse{Device: dtos.Device{Name: testDeviceName, ProfileName: testProfileName, ServiceName: testServiceName}}, nil)
+	dcMock.On("DeviceByName", mock.Anything, unknownDeviceName).
+		Return(responses.DeviceResponse{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device not found", nil))
+	dpcMock := &mocks.DeviceProfileClient{}
+	dpcMock.On("DeviceProfileByName", mock.Anything, testProfileName).
+		Return(responses.DeviceProfileResponse{Profile: dtos.DeviceProfile{Name: testProfileName}}, nil)
+	dscMock := &mocks.DeviceServiceClient{}
+	dscMock.On("DeviceServiceByName", mock.Anything, testServiceName).
+		Return(responses.DeviceServiceResponse{Service: dtos.DeviceService{Name: testServiceName, BaseAddress: testBaseAddress}}, nil)
+
+	dic := di.NewContainer(di.ServiceConstructorMap{
+		commandContainer.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				MetadataCache: config.MetadataCacheInfo{Enabled: true, TTL: "1m", SubscribeTopic: testSystemEventsTopic},
+			}
+		},
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+		commandContainer.MessagingClientName: func(get di.Get) interface{} {
+			return messageBus
+		},
+		bootstrapContainer.MetadataDeviceClientName: func(get di.Get) interface{} {
+			return dcMock
+		},
+		bootstrapContainer.MetadataDeviceProfileClientName: func(get di.Get) interface{} {
+			return dpcMock
+		},
+		bootstrapContainer.MetadataDeviceServiceClientName: func(get di.Get) interface{} {
+			return dscMock
+		},
+		commandContainer.DeviceCacheName: func(get di.Get) interface{} {
+			return cache.NewTTLCache(time.Minute)
+		},
+		commandContainer.DeviceProfileCacheName: func(get di.Get) interface{} {
+			return cache.NewTTLCache(time.Minute)
+		},
+		commandContainer.DeviceServiceCacheName: func(get di.Get) interface{} {
+			return cache.NewTTLCache(time.Minute)
+		},
+	})
+	return dic, dcMock, dpcMock, dscMock
+}
+
+func buildSystemEvent(t *testing.T, eventType string, action string, details interface{}) pkgDTOs.SystemEvent {
+	event, err := pkgDTOs.NewSystemEvent(eventType, action, common.CoreMetaDataServiceKey, "", details)
+	require.NoError(t, err)
+	return event
+}
+
+func TestMetadataCache(t *testing.T) {
+	dic, dcMock, dpcMock, dscMock := mockMetadataCacheDic(&messageBusStub{})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		device, err := deviceByName(ctx, testDeviceName, dic)
+		require.NoError(t, err)
+		assert.Equal(t, testServiceName, device.ServiceName)
+		profile, err := deviceProfileByName(ctx, device.ProfileName, dic)
+		require.NoError(t, err)
+		assert.Equal(t, testProfileName, profile.Name)
+		service, err := deviceServiceByName(ctx, device.ServiceName, dic)
+		require.NoError(t, err)
+		assert.Equal(t, testBaseAddress, service.BaseAddress)
+	}
+	dcMock.AssertNumberOfCalls(t, "DeviceByName", 1)
+	dpcMock.AssertNumberOfCalls(t, "DeviceProfileByName", 1)
+	dscMock.AssertNumberOfCalls(t, "DeviceServiceByName", 1)
+
+	// the metadata which doesn't exist is not cached
+	for i := 0; i < 2; i++ {
+		_, err := deviceByName(ctx, unknownDeviceName, dic)
+		require.Error(t, err)
+		assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+	}
+	dcMock.AssertNumberOfCalls(t, "DeviceByName", 3)
+}
+
+func TestEvictMetadataCache(t *testing.T) {
+	device := dtos.Device{Name: testDeviceName, ProfileName: testProfileName, ServiceName: testServiceName}
+	profile := dtos.DeviceProfile{Name: testProfileName}
+	service := dtos.DeviceService{Name: testServiceName, BaseAddress: testBaseAddress}
+
+	tests := []struct {
+		name            string
+		event           pkgDTOs.SystemEvent
+		deviceEvicted   bool
+		profileEvicted  bool
+		serviceEvicted  bool
+		errorExpected   bool
+		expectedErrKind errors.ErrKind
+	}{
+		{"device deleted", buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionDelete, device), true, false, false, false, ""},
+		{"other device deleted", buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionDelete, dtos.Device{Name: unknownDeviceName}), false, false, false, false, ""},
+		{"device updated", buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionUpdate, device), true, false, false, false, ""},
+		{"other device updated", buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionUpdate, dtos.Device{Name: unknownDeviceName}), false, false, false, false, ""},
+		{"device added", buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionAdd, device), false, false, false, false, ""},
+		{"device profile updated", buildSystemEvent(t, pkgDTOs.DeviceProfileSystemEventType, pkgDTOs.SystemEventActionUpdate, profile), false, true, false, false, ""},
+		{"device service deleted", buildSystemEvent(t, pkgDTOs.DeviceServiceSystemEventType, pkgDTOs.SystemEventActionDelete, service), false, false, true, false, ""},
+		{"provision watcher deleted", buildSystemEvent(t, pkgDTOs.ProvisionWatcherSystemEventType, pkgDTOs.SystemEventActionDelete, dtos.ProvisionWatcher{Name: testDeviceName}), false, false, false, false, ""},
+		{"unknown action", buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, "rename", device), false, false, false, true, errors.KindContractInvalid},
+		{"malformed details", pkgDTOs.SystemEvent{Type: pkgDTOs.DeviceSystemEventType, Action: pkgDTOs.SystemEventActionDelete, Details: json.RawMessage(`"device"`)}, false, false, false, true, errors.KindContractInvalid},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			dic, _, _, _ := mockMetadataCacheDic(&messageBusStub{})
+			deviceCache := commandContainer.DeviceCacheFrom(dic.Get)
+			profileCache := commandContainer.DeviceProfileCacheFrom(dic.Get)
+			serviceCache := commandContainer.DeviceServiceCacheFrom(dic.Get)
+			deviceCache.Set(testDeviceName, device)
+			profileCache.Set(testProfileName, profile)
+			serviceCache.Set(testServiceName, service)
+
+			err := evictMetadataCache(testCase.event, dic)
+			if testCase.errorExpected {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedErrKind, errors.Kind(err))
+				return
+			}
+			require.NoError(t, err)
+			_, ok := deviceCache.Get(testDeviceName)
+			assert.Equal(t, testCase.deviceEvicted, !ok)
+			_, ok = profileCache.Get(testProfileName)
+			assert.Equal(t, testCase.profileEvicted, !ok)
+			_, ok = serviceCache.Get(testServiceName)
+			assert.Equal(t, testCase.serviceEvicted, !ok)
+		})
+	}
+}
+
+func TestMetadataCache_EvictedDuringQuery(t *testing.T) {
+	dic, _, _, _ := mockMetadataCacheDic(&messageBusStub{})
+	deviceEvent := buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionUpdate, dtos.Device{Name: testDeviceName})
+	dcMock := &mocks.DeviceClient{}
+	dcMock.On("DeviceByName", mock.Anything, testDeviceName).
+		Return(responses.DeviceResponse{Device: dtos.Device{Name: testDeviceName, ServiceName: testServiceName}}, nil).
+		Run(func(mock.Arguments) {
+			// the device is updated after core-metadata returned it, but before it is cached
+			require.NoError(t, evictMetadataCache(deviceEvent, dic))
+		})
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.MetadataDeviceClientName: func(get di.Get) interface{} {
+			return dcMock
+		},
+	})
+
+	_, err := deviceByName(context.Background(), testDeviceName, dic)
+	require.NoError(t, err)
+	_, ok := commandContainer.DeviceCacheFrom(dic.Get).Get(testDeviceName)
+	assert.False(t, ok, "the device queried before the eviction should not be cached")
+	_, err = deviceByName(context.Background(), testDeviceName, dic)
+	require.NoError(t, err)
+	dcMock.AssertNumberOfCalls(t, "DeviceByName", 2)
+}
+
+func TestSubscribeMetadataSystemEvents(t *testing.T) {
+	messageBus := &messageBusStub{}
+	dic, _, _, _ := mockMetadataCacheDic(messageBus)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	require.NoError(t, SubscribeMetadataSystemEvents(ctx, &wg, dic))
+	require.NotNil(t, messageBus.messages)
+
+	deviceCache := commandContainer.DeviceCacheFrom(dic.Get)
+	device := dtos.Device{Name: testDeviceName, ServiceName: testServiceName}
+	deviceCache.Set(testDeviceName, device)
+
+	payload, err := json.Marshal(buildSystemEvent(t, pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionDelete, device))
+	require.NoError(t, err)
+	messageBus.messages <- types.MessageEnvelope{
+		ReceivedTopic: "edgex/system-events/core-metadata/device/delete/" + testServiceName,
+		ContentType:   common.ContentTypeJSON,
+		Payload:       payload,
+	}
+
+	assert.Eventually(t, func() bool {
+		_, ok := deviceCache.Get(testDeviceName)
+		return !ok
+	}, time.Second, 10*time.Millisecond, "the deleted device should be evicted from the cache")
+}
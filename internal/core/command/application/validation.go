@@ -0,0 +1,234 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+const arrayValueTypeSuffix = "array"
+
+// numericValueType is the kind, 'u' for the unsigned integers, 'i' for the signed integers or 'f' for the floats, and
+// the bit size of a numeric value type
+type numericValueType struct {
+	kind    byte
+	bitSize int
+}
+
+// numericValueTypes is keyed by the lower case value types, as the value types of the profiles are case-insensitive
+var numericValueTypes = map[string]numericValueType{
+	strings.ToLower(common.ValueTypeUint8):   {'u', 8},
+	strings.ToLower(common.ValueTypeUint16):  {'u', 16},
+	strings.ToLower(common.ValueTypeUint32):  {'u', 32},
+	strings.ToLower(common.ValueTypeUint64):  {'u', 64},
+	strings.ToLower(common.ValueTypeInt8):    {'i', 8},
+	strings.ToLower(common.ValueTypeInt16):   {'i', 16},
+	strings.ToLower(common.ValueTypeInt32):   {'i', 32},
+	strings.ToLower(common.ValueTypeInt64):   {'i', 64},
+	strings.ToLower(common.ValueTypeFloat32): {'f', 32},
+	strings.ToLower(common.ValueTypeFloat64): {'f', 64},
+}
+
+// validateSetCommandSettings validates the settings of a set command against the device profile before the command is
+// issued to the device service.  The command has to be writable, each setting has to be a writable resource of the
+// command, its value has to parse as the value type of the resource, and a numeric value has to be within the minimum
+// and maximum of the resource.  All the invalid settings are reported in the error.
+func validateSetCommandSettings(profile dtos.DeviceProfile, commandName string, settings map[string]interface{}) errors.EdgeX {
+	resources := make(map[string]dtos.DeviceResource, len(profile.DeviceResources))
+	for _, r := range profile.DeviceResources {
+		resources[r.Name] = r
+	}
+
+	// the command is either a device command or a device resource, and the mappings of the resource operations
+	// translate the values of a device command
+	commandResources := make(map[string]map[string]string)
+	var readWrite string
+	if command, ok := deviceCommandByName(profile.DeviceCommands, commandName); ok {
+		readWrite = command.ReadWrite
+		for _, ro := range command.ResourceOperations {
+			commandResources[ro.DeviceResource] = ro.Mappings
+		}
+	} else if resource, ok := resources[commandName]; ok {
+		readWrite = resource.Properties.ReadWrite
+		commandResources[resource.Name] = nil
+	} else {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("command %s is not defined in device profile %s", commandName, profile.Name), nil)
+	}
+	if !strings.Contains(readWrite, common.ReadWrite_W) {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("command %s of device profile %s is not writable", commandName, profile.Name), nil)
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		mappings, ok := commandResources[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not a resource of command %s", name, commandName))
+			continue
+		}
+		resource, ok := resources[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("resource %s is not defined in device profile %s", name, profile.Name))
+			continue
+		}
+		if !strings.Contains(resource.Properties.ReadWrite, common.ReadWrite_W) {
+			problems = append(problems, fmt.Sprintf("resource %s is not writable", name))
+			continue
+		}
+		if problem := validateSettingValue(resource, unmapSettingValue(settings[name], mappings)); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("invalid settings of command %s: %s", commandName, strings.Join(problems, "; ")), nil)
+	}
+	return nil
+}
+
+func deviceCommandByName(commands []dtos.DeviceCommand, name string) (dtos.DeviceCommand, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return dtos.DeviceCommand{}, false
+}
+
+// unmapSettingValue translates the value back to the raw value of the resource when it is one of the mapped values
+func unmapSettingValue(value interface{}, mappings map[string]string) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	for raw, mapped := range mappings {
+		if mapped == s {
+			return raw
+		}
+	}
+	return value
+}
+
+// validateSettingValue returns the problem of the value of the resource, or an empty string if the value is valid.  The
+// elements of an array value are validated against the value type of the elements.  The Binary and Object values
+// aren't validated.
+func validateSettingValue(resource dtos.DeviceResource, value interface{}) string {
+	valueType := strings.ToLower(resource.Properties.ValueType)
+	if !strings.HasSuffix(valueType, arrayValueTypeSuffix) {
+		return validateScalarSettingValue(resource, valueType, value)
+	}
+
+	elements, ok := value.([]interface{})
+	if !ok {
+		// the array is usually written as a JSON array string, e.g. "[1, 2, 3]"
+		s, isString := value.(string)
+		if !isString || unmarshalArraySettingValue(s, &elements) != nil {
+			return fmt.Sprintf("value %v of resource %s is not a valid %s", value, resource.Name, resource.Properties.ValueType)
+		}
+	}
+	elementType := strings.TrimSuffix(valueType, arrayValueTypeSuffix)
+	for _, element := range elements {
+		if problem := validateScalarSettingValue(resource, elementType, element); problem != "" {
+			return problem
+		}
+	}
+	return ""
+}
+
+func validateScalarSettingValue(resource dtos.DeviceResource, valueType string, value interface{}) string {
+	s, isScalar := settingValueString(value)
+	invalid := fmt.Sprintf("value %v of resource %s is not a valid %s", value, resource.Name, resource.Properties.ValueType)
+
+	switch valueType {
+	case strings.ToLower(common.ValueTypeString):
+		if !isScalar {
+			return invalid
+		}
+		return ""
+	case strings.ToLower(common.ValueTypeBool):
+		if _, err := strconv.ParseBool(s); !isScalar || err != nil {
+			return invalid
+		}
+		return ""
+	}
+
+	numericType, ok := numericValueTypes[valueType]
+	if !ok {
+		return ""
+	}
+	if !isScalar {
+		return invalid
+	}
+	var err error
+	switch numericType.kind {
+	case 'u':
+		_, err = strconv.ParseUint(s, 10, numericType.bitSize)
+	case 'i':
+		_, err = strconv.ParseInt(s, 10, numericType.bitSize)
+	default:
+		_, err = strconv.ParseFloat(s, numericType.bitSize)
+	}
+	if err != nil {
+		return invalid
+	}
+
+	// the numbers are compared exactly, since the integers beyond 2^53 are rounded by float64, and malformed minimum or
+	// maximum of the resource are ignored
+	number, ok := new(big.Rat).SetString(s)
+	if !ok {
+		// the special floats, e.g. NaN and Inf, have no range
+		return ""
+	}
+	properties := resource.Properties
+	if minimum, ok := new(big.Rat).SetString(properties.Minimum); ok && number.Cmp(minimum) < 0 {
+		return fmt.Sprintf("value %s of resource %s is less than the minimum %s", s, resource.Name, properties.Minimum)
+	}
+	if maximum, ok := new(big.Rat).SetString(properties.Maximum); ok && number.Cmp(maximum) > 0 {
+		return fmt.Sprintf("value %s of resource %s is greater than the maximum %s", s, resource.Name, properties.Maximum)
+	}
+	return ""
+}
+
+// unmarshalArraySettingValue decodes the JSON array string of an array value, and its numbers are json.Number like
+// those of the settings
+func unmarshalArraySettingValue(s string, elements *[]interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	return decoder.Decode(elements)
+}
+
+// settingValueString returns the value as the string the device service parses, the JSON numbers and booleans are
+// accepted as well as strings.  The settings are decoded with their numbers as json.Number, whose text is validated as
+// it is written, while float64 is still accepted from the settings decoded otherwise.
+func settingValueString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSetCommandSettings(t *testing.T) {
+	profile := dtos.DeviceProfile{
+		Name: testProfileName,
+		DeviceResources: []dtos.DeviceResource{
+			{Name: "temperature", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeFloat32, ReadWrite: common.ReadWrite_RW, Minimum: "-20", Maximum: "50.5"}},
+			{Name: "speed", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeUint8, ReadWrite: common.ReadWrite_RW}},
+			{Name: "switch", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeBool, ReadWrite: common.ReadWrite_RW}},
+			{Name: "levels", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeInt16Array, ReadWrite: common.ReadWrite_W, Maximum: "100"}},
+			{Name: "counter", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeUint64, ReadWrite: common.ReadWrite_RW, Maximum: "18446744073709551614"}},
+			{Name: "total", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeUint64, ReadWrite: common.ReadWrite_RW}},
+			{Name: "label", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeString, ReadWrite: common.ReadWrite_RW}},
+			{Name: "status", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeString, ReadWrite: common.ReadWrite_R}},
+		},
+		DeviceCommands: []dtos.DeviceCommand{
+			{
+				Name:      "control",
+				ReadWrite: common.ReadWrite_RW,
+				ResourceOperations: []dtos.ResourceOperation{
+					{DeviceResource: "temperature"},
+					{DeviceResource: "speed"},
+					{DeviceResource: "switch", Mappings: map[string]string{"true": "ON", "false": "OFF"}},
+					{DeviceResource: "levels"},
+					{DeviceResource: "counter"},
+					{DeviceResource: "total"},
+					{DeviceResource: "status"},
+				},
+			},
+			{Name: "readings", ReadWrite: common.ReadWrite_R, ResourceOperations: []dtos.ResourceOperation{{DeviceResource: "temperature"}}},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		commandName      string
+		settings         map[string]interface{}
+		errorExpected    bool
+		expectedMessages []string
+	}{
+		{"valid device command", "control", map[string]interface{}{"temperature": "21.5", "speed": "255", "switch": "true", "levels": "[1, -2, 100]"}, false, nil},
+		{"valid JSON values", "control", map[string]interface{}{"temperature": float64(-20), "speed": float64(3), "switch": false, "levels": []interface{}{float64(5)}}, false, nil},
+		{"valid JSON number beyond float64 precision", "control", map[string]interface{}{"total": json.Number("18446744073709551615"), "levels": "[100]"}, false, nil},
+		{"valid mapped value", "control", map[string]interface{}{"switch": "OFF"}, false, nil},
+		{"valid device resource", "label", map[string]interface{}{"label": "room 1"}, false, nil},
+		{"unknown command", "unknown", map[string]interface{}{"temperature": "21.5"}, true, []string{"command unknown is not defined"}},
+		{"read-only command", "readings", map[string]interface{}{"temperature": "21.5"}, true, []string{"command readings of device profile testProfile is not writable"}},
+		{"read-only resource command", "status", map[string]interface{}{"status": "OK"}, true, []string{"command status of device profile testProfile is not writable"}},
+		{"read-only resource", "control", map[string]interface{}{"status": "OK"}, true, []string{"resource status is not writable"}},
+		{"resource not in command", "control", map[string]interface{}{"label": "room 1"}, true, []string{"label is not a resource of command control"}},
+		{"invalid float", "control", map[string]interface{}{"temperature": "warm"}, true, []string{"value warm of resource temperature is not a valid Float32"}},
+		{"uint overflow", "control", map[string]interface{}{"speed": "256"}, true, []string{"value 256 of resource speed is not a valid Uint8"}},
+		{"invalid bool", "control", map[string]interface{}{"switch": "DIM"}, true, []string{"value DIM of resource switch is not a valid Bool"}},
+		{"less than minimum", "control", map[string]interface{}{"temperature": "-20.5"}, true, []string{"value -20.5 of resource temperature is less than the minimum -20"}},
+		{"greater than maximum", "control", map[string]interface{}{"temperature": float64(51)}, true, []string{"value 51 of resource temperature is greater than the maximum 50.5"}},
+		{"uint64 overflow", "control", map[string]interface{}{"total": json.Number("18446744073709551616")}, true, []string{"value 18446744073709551616 of resource total is not a valid Uint64"}},
+		{"greater than uint64 maximum", "control", map[string]interface{}{"counter": json.Number("18446744073709551615")}, true, []string{"value 18446744073709551615 of resource counter is greater than the maximum 18446744073709551614"}},
+		{"array element out of range", "control", map[string]interface{}{"levels": "[1, 101]"}, true, []string{"value 101 of resource levels is greater than the maximum 100"}},
+		{"malformed array", "control", map[string]interface{}{"levels": "1, 2"}, true, []string{"value 1, 2 of resource levels is not a valid Int16Array"}},
+		{"all problems reported", "control", map[string]interface{}{"speed": "-1", "temperature": "100", "label": "room 1"},
+			true, []string{"label is not a resource of command control", "value -1 of resource speed", "value 100 of resource temperature"}},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := validateSetCommandSettings(profile, testCase.commandName, testCase.settings)
+			if !testCase.errorExpected {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+			for _, message := range testCase.expectedMessages {
+				assert.Contains(t, err.Error(), message)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+)
+
+const openTimeout = 5 * time.Second
+
+var (
+	entriesBucketName = []byte("entries")
+	devicesBucketName = []byte("devices")
+	idsBucketName     = []byte("ids")
+	metaBucketName    = []byte("meta")
+	countKey          = []byte("count")
+)
+
+// Log is the durable audit log of the commands issued by core-command.  The entries are keyed by their timestamp, and
+// indexed by device name in a bucket per device, so both the queries by time range and by device only read the
+// entries in range.  The entries are also indexed by id to be updated, and counted as they are added and purged.  The
+// entries are stored in a single file, so they survive the service restart.
+type Log struct {
+	db *bolt.DB
+}
+
+// Open opens or creates the audit log file at path
+func Open(path string) (*Log, errors.EdgeX) {
+	if path == "" {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "audit log file path is required", nil)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to create the directory of audit log file %s", path), err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to open audit log file %s", path), err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{entriesBucketName, devicesBucketName, idsBucketName, metaBucketName} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if tx.Bucket(metaBucketName).Get(countKey) != nil {
+			return nil
+		}
+		// the entries of the file written before they were counted are counted once
+		return putCount(tx, tx.Bucket(entriesBucketName).Stats().KeyN)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to initialize audit log file %s", path), err)
+	}
+	return &Log{db: db}, nil
+}
+
+// Close closes the audit log file
+func (l *Log) Close() {
+	_ = l.db.Close()
+}
+
+// Add appends the entry to the audit log.  The concurrent calls are written to the file together, so auditing many
+// commands at the same time doesn't serialize them on the disk writes.
+func (l *Log) Add(entry dtos.CommandAuditEntry) errors.EdgeX {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode the command audit entry", err)
+	}
+	err = l.db.Batch(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucketName)
+		seq, err := entries.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := entryKey(entry.Timestamp, seq)
+		if err = entries.Put(key, value); err != nil {
+			return err
+		}
+		if err = tx.Bucket(idsBucketName).Put([]byte(entry.Id), key); err != nil {
+			return err
+		}
+		device, err := tx.Bucket(devicesBucketName).CreateBucketIfNotExists([]byte(entry.DeviceName))
+		if err != nil {
+			return err
+		}
+		if err = device.Put(key, []byte{}); err != nil {
+			return err
+		}
+		return putCount(tx, entryCount(tx)+1)
+	})
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the audit log file", err)
+	}
+	return nil
+}
+
+// Update replaces the entry added with the same id, e.g. to record the outcome of the pending command.  The timestamp
+// and the device name of the entry are kept, since the entry is keyed by them.
+func (l *Log) Update(entry dtos.CommandAuditEntry) errors.EdgeX {
+	var notFound bool
+	err := l.db.Batch(func(tx *bolt.Tx) error {
+		key := tx.Bucket(idsBucketName).Get([]byte(entry.Id))
+		if key == nil {
+			// the entry may have been purged in the meantime
+			notFound = true
+			return nil
+		}
+		var stored dtos.CommandAuditEntry
+		entries := tx.Bucket(entriesBucketName)
+		if err := json.Unmarshal(entries.Get(key), &stored); err != nil {
+			return err
+		}
+		entry.Timestamp = stored.Timestamp
+		entry.DeviceName = stored.DeviceName
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		return entries.Put(key, value)
+	})
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the audit log file", err)
+	}
+	if notFound {
+		return errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("command audit entry %s doesn't exist", entry.Id), nil)
+	}
+	return nil
+}
+
+// Entries returns the entries with the timestamp between start and end inclusively, the newest first, and the total
+// count of the entries in the time range.  A negative limit means no limit.
+func (l *Log) Entries(start int64, end int64, offset int, limit int) ([]dtos.CommandAuditEntry, uint32, errors.EdgeX) {
+	return l.query(nil, start, end, offset, limit)
+}
+
+// EntriesByDevice returns the entries of the device with the timestamp between start and end inclusively, the newest
+// first, and the total count of the entries of the device in the time range.  A negative limit means no limit.
+func (l *Log) EntriesByDevice(deviceName string, start int64, end int64, offset int, limit int) ([]dtos.CommandAuditEntry, uint32, errors.EdgeX) {
+	return l.query([]byte(deviceName), start, end, offset, limit)
+}
+
+func (l *Log) query(deviceName []byte, start int64, end int64, offset int, limit int) ([]dtos.CommandAuditEntry, uint32, errors.EdgeX) {
+	entries := make([]dtos.CommandAuditEntry, 0)
+	var count uint32
+	err := l.db.View(func(tx *bolt.Tx) error {
+		entriesBucket := tx.Bucket(entriesBucketName)
+		keysBucket := entriesBucket
+		if deviceName != nil {
+			keysBucket = tx.Bucket(devicesBucketName).Bucket(deviceName)
+			if keysBucket == nil {
+				return nil
+			}
+		}
+
+		c := keysBucket.Cursor()
+		for k, _ := lastKeyBefore(c, end); k != nil && keyTimestamp(k) >= start; k, _ = c.Prev() {
+			count++
+			if int(count) <= offset || (limit >= 0 && len(entries) >= limit) {
+				continue
+			}
+			var entry dtos.CommandAuditEntry
+			if err := json.Unmarshal(entriesBucket.Get(k), &entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, errors.NewCommonEdgeX(errors.KindIOError, "failed to read the audit log file", err)
+	}
+	return entries, count, nil
+}
+
+// Purge removes the entries older than before, and then the oldest entries beyond the newest maxCount entries.  A
+// zero before or maxCount means no limit.  The number of the removed entries is returned.  The device buckets emptied
+// by the purge are removed as well.
+func (l *Log) Purge(before int64, maxCount int) (int, errors.EdgeX) {
+	purged := 0
+	err := l.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucketName)
+		devices := tx.Bucket(devicesBucketName)
+		ids := tx.Bucket(idsBucketName)
+		total := entryCount(tx)
+		excess := 0
+		if maxCount > 0 {
+			excess = total - maxCount
+		}
+
+		// deleting the first entry repeatedly, as the cursor skips an entry when moving on after the deletion
+		c := entries.Cursor()
+		for k, v := c.First(); k != nil && (keyTimestamp(k) < before || purged < excess); k, v = c.First() {
+			var entry struct {
+				Id         string `json:"id"`
+				DeviceName string `json:"deviceName"`
+			}
+			if err := json.Unmarshal(v, &entry); err == nil {
+				if err = ids.Delete([]byte(entry.Id)); err != nil {
+					return err
+				}
+				if err = deleteDeviceKey(devices, []byte(entry.DeviceName), k); err != nil {
+					return err
+				}
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			purged++
+		}
+		return putCount(tx, total-purged)
+	})
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindIOError, "failed to purge the audit log file", err)
+	}
+	return purged, nil
+}
+
+// deleteDeviceKey removes the key of the purged entry from the bucket of the device, and the bucket once it is empty
+func deleteDeviceKey(devices *bolt.Bucket, deviceName []byte, key []byte) error {
+	device := devices.Bucket(deviceName)
+	if device == nil {
+		return nil
+	}
+	if err := device.Delete(key); err != nil {
+		return err
+	}
+	if k, _ := device.Cursor().First(); k == nil {
+		return devices.DeleteBucket(deviceName)
+	}
+	return nil
+}
+
+// entryCount returns the number of the entries, which is counted as they are added and purged rather than by scanning them
+func entryCount(tx *bolt.Tx) int {
+	value := tx.Bucket(metaBucketName).Get(countKey)
+	if value == nil {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(value))
+}
+
+func putCount(tx *bolt.Tx, n int) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(n))
+	return tx.Bucket(metaBucketName).Put(countKey, value)
+}
+
+// lastKeyBefore moves the cursor to the last key with the timestamp no later than end
+func lastKeyBefore(c *bolt.Cursor, end int64) ([]byte, []byte) {
+	if end < 0 {
+		return nil, nil
+	}
+	// no key has the maximum sequence, so the seek lands on the first key after end
+	if k, _ := c.Seek(entryKey(end, math.MaxUint64)); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+// entryKey orders the entries by timestamp, and then by the sequence for the entries of the same timestamp
+func entryKey(timestamp int64, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key, uint64(timestamp))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
+
+func keyTimestamp(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key))
+}
@@ -0,0 +1,188 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+)
+
+const (
+	testDevice1 = "device1"
+	testDevice2 = "device2"
+)
+
+// openTestLog opens a log with the entries of device1 at 10, 20, 30 and 30 again, and of device2 at 15 and 25
+func openTestLog(t *testing.T) (*Log, string) {
+	path := filepath.Join(t.TempDir(), "audit.db")
+	l, err := Open(path)
+	require.NoError(t, err)
+	entries := []dtos.CommandAuditEntry{
+		{Id: "1", Timestamp: 10, DeviceName: testDevice1, CommandName: "get1", StatusCode: 200},
+		{Id: "2", Timestamp: 15, DeviceName: testDevice2, CommandName: "set1", Settings: map[string]interface{}{"r": "1"}, StatusCode: 200},
+		{Id: "3", Timestamp: 20, DeviceName: testDevice1, CommandName: "get1", StatusCode: 404},
+		{Id: "4", Timestamp: 25, DeviceName: testDevice2, CommandName: "set1", StatusCode: 400},
+		{Id: "5", Timestamp: 30, DeviceName: testDevice1, CommandName: "get1", StatusCode: 200},
+		{Id: "6", Timestamp: 30, DeviceName: testDevice1, CommandName: "get2", StatusCode: 200},
+	}
+	for _, entry := range entries {
+		require.NoError(t, l.Add(entry))
+	}
+	return l, path
+}
+
+func entryIds(entries []dtos.CommandAuditEntry) []string {
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.Id
+	}
+	return ids
+}
+
+func TestLogEntries(t *testing.T) {
+	l, path := openTestLog(t)
+
+	tests := []struct {
+		name          string
+		deviceName    string
+		start         int64
+		end           int64
+		offset        int
+		limit         int
+		expectedIds   []string
+		expectedCount uint32
+	}{
+		{"all", "", 0, math.MaxInt64, 0, -1, []string{"6", "5", "4", "3", "2", "1"}, 6},
+		{"all with offset and limit", "", 0, math.MaxInt64, 1, 2, []string{"5", "4"}, 6},
+		{"time range", "", 15, 25, 0, -1, []string{"4", "3", "2"}, 3},
+		{"time range ending between entries", "", 11, 29, 0, -1, []string{"4", "3", "2"}, 3},
+		{"time range of the same timestamp", "", 30, 30, 0, -1, []string{"6", "5"}, 2},
+		{"time range before all", "", 0, 9, 0, -1, []string{}, 0},
+		{"device", testDevice1, 0, math.MaxInt64, 0, -1, []string{"6", "5", "3", "1"}, 4},
+		{"device with time range", testDevice1, 11, 30, 0, 1, []string{"6"}, 3},
+		{"device with offset beyond count", testDevice2, 0, math.MaxInt64, 5, -1, []string{}, 2},
+		{"unknown device", "unknown", 0, math.MaxInt64, 0, -1, []string{}, 0},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			var entries []dtos.CommandAuditEntry
+			var count uint32
+			var err error
+			if testCase.deviceName == "" {
+				entries, count, err = l.Entries(testCase.start, testCase.end, testCase.offset, testCase.limit)
+			} else {
+				entries, count, err = l.EntriesByDevice(testCase.deviceName, testCase.start, testCase.end, testCase.offset, testCase.limit)
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedIds, entryIds(entries))
+			assert.Equal(t, testCase.expectedCount, count)
+		})
+	}
+
+	entries, _, err := l.Entries(15, 15, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1", entries[0].Settings["r"], "the settings should be stored with the entry")
+
+	l.Close()
+	l, err = Open(path)
+	require.NoError(t, err)
+	defer l.Close()
+	_, count, err := l.Entries(0, math.MaxInt64, 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(6), count, "the entries should survive reopening")
+}
+
+func TestLogPurge(t *testing.T) {
+	tests := []struct {
+		name           string
+		before         int64
+		maxCount       int
+		expectedPurged int
+		expectedIds    []string
+		device1Ids     []string
+		device2Kept    bool
+	}{
+		{"no limit", 0, 0, 0, []string{"6", "5", "4", "3", "2", "1"}, []string{"6", "5", "3", "1"}, true},
+		{"by age", 20, 0, 2, []string{"6", "5", "4", "3"}, []string{"6", "5", "3"}, true},
+		{"by count", 0, 3, 3, []string{"6", "5", "4"}, []string{"6", "5"}, true},
+		{"by age and count", 12, 2, 4, []string{"6", "5"}, []string{"6", "5"}, false},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			l, _ := openTestLog(t)
+			defer l.Close()
+
+			purged, err := l.Purge(testCase.before, testCase.maxCount)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedPurged, purged)
+			entries, _, err := l.Entries(0, math.MaxInt64, 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedIds, entryIds(entries))
+			entries, count, err := l.EntriesByDevice(testDevice1, 0, math.MaxInt64, 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.device1Ids, entryIds(entries), "the purged entries should be removed from the device index")
+			assert.Equal(t, uint32(len(testCase.device1Ids)), count)
+
+			_ = l.db.View(func(tx *bolt.Tx) error {
+				assert.Equal(t, len(testCase.expectedIds), entryCount(tx), "the purged entries should be uncounted")
+				assert.Equal(t, testCase.device2Kept, tx.Bucket(devicesBucketName).Bucket([]byte(testDevice2)) != nil, "the emptied device bucket should be removed")
+				return nil
+			})
+
+			// the count is kept for the next purge
+			purged, err = l.Purge(0, 1)
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.expectedIds)-1, purged)
+		})
+	}
+}
+
+func TestLogUpdate(t *testing.T) {
+	l, path := openTestLog(t)
+
+	err := l.Update(dtos.CommandAuditEntry{Id: "3", Timestamp: 99, DeviceName: testDevice2, CommandName: "get1", Status: dtos.CommandAuditStatusDone, StatusCode: 500, Latency: "1ms"})
+	require.NoError(t, err)
+	entries, _, err := l.EntriesByDevice(testDevice1, 20, 20, 0, -1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "3", entries[0].Id)
+	assert.Equal(t, dtos.CommandAuditStatusDone, entries[0].Status)
+	assert.Equal(t, 500, entries[0].StatusCode)
+	assert.Equal(t, "1ms", entries[0].Latency)
+	assert.Equal(t, int64(20), entries[0].Timestamp, "the timestamp of the entry should be kept")
+	assert.Equal(t, testDevice1, entries[0].DeviceName, "the device of the entry should be kept")
+
+	err = l.Update(dtos.CommandAuditEntry{Id: "unknown"})
+	require.Error(t, err)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+
+	_, err = l.Purge(25, 0)
+	require.NoError(t, err)
+	err = l.Update(dtos.CommandAuditEntry{Id: "3"})
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err), "the purged entry should not be updated")
+
+	l.Close()
+	l, err = Open(path)
+	require.NoError(t, err)
+	defer l.Close()
+	purged, err := l.Purge(0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged, "the count should survive reopening")
+}
+
+func TestOpenWithoutPath(t *testing.T) {
+	_, err := Open("")
+	require.Error(t, err)
+}
@@ -26,6 +26,14 @@ type ConfigurationStruct struct {
 	Registry    bootstrapConfig.RegistryInfo
 	Service     bootstrapConfig.ServiceInfo
 	SecretStore bootstrapConfig.SecretStoreInfo
+	// MessageQueue is the message bus of the command channel, which is enabled by SubscribeEnabled.  The command requests
+	// are received on SubscribeTopic and the responses are published under PublishTopicPrefix.  The message bus also
+	// receives the system events of MetadataCache.SubscribeTopic.
+	MessageQueue    bootstrapConfig.MessageBusInfo
+	CommandRequests CommandRequestsInfo
+	BatchCommand    BatchCommandInfo
+	MetadataCache   MetadataCacheInfo
+	CommandAudit    CommandAuditInfo
 }
 
 // WritableInfo contains configuration properties that can be updated and applied without restarting the service.
@@ -34,6 +42,51 @@ type WritableInfo struct {
 	InsecureSecrets bootstrapConfig.InsecureSecrets
 }
 
+// CommandRequestsInfo limits the command requests received from the message bus of MessageQueue
+type CommandRequestsInfo struct {
+	// MaxConcurrency is the maximum number of command requests issued at the same time, the further requests are only
+	// received from the message bus once one of them is done
+	MaxConcurrency int
+}
+
+// BatchCommandInfo limits the commands issued by a batch command request
+type BatchCommandInfo struct {
+	// MaxCommands is the maximum number of commands in a batch, including the commands of the devices selected by labels
+	MaxCommands int
+	// MaxConcurrencyPerService is the maximum number of commands of a batch issued to a device service at the same time
+	MaxConcurrencyPerService int
+}
+
+// MetadataCacheInfo defines the cache of the devices, device profiles and device services retrieved from core-metadata.
+// The cache is meant to be enabled along with SubscribeTopic and the system events of core-metadata, without which the
+// commands are issued to the devices and validated against the device profiles changed for up to TTL.
+type MetadataCacheInfo struct {
+	Enabled bool
+	// TTL is the duration string to cache the metadata, e.g. "1m"
+	TTL string
+	// SubscribeTopic receives the system events of core-metadata from the message bus of MessageQueue, which evict the
+	// changed metadata from the cache before its TTL.  The cache only expires by TTL when SubscribeTopic is empty.
+	SubscribeTopic string
+}
+
+// CommandAuditInfo defines the audit log recording every get and set command issued by core-command
+type CommandAuditInfo struct {
+	// Enabled indicates whether the issued commands are recorded
+	Enabled bool
+	// Path is the path of the file storing the audit log.  Its directory is created at startup and must be writable by
+	// the service, e.g. a volume mounted in the container, otherwise core-command fails to start.
+	Path string
+	// CallerHeaders is the comma separated list of the request headers identifying the caller, e.g. the consumer headers
+	// set by the API gateway.  The first header present in the request is recorded as the caller.
+	CallerHeaders string
+	// RetentionInterval is the duration string between two purges of the audit log, e.g. "1h"
+	RetentionInterval string
+	// MaxAge is the duration string beyond which entries are purged, empty means no age limit
+	MaxAge string
+	// MaxCount is the number of the newest entries to keep, 0 means no count limit
+	MaxCount int
+}
+
 // UpdateFromRaw converts configuration received from the registry to a service-specific configuration struct which is
 // then used to overwrite the service's existing configuration struct.
 func (c *ConfigurationStruct) UpdateFromRaw(rawConfig interface{}) bool {
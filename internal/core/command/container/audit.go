@@ -0,0 +1,26 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/core/command/audit"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// CommandAuditLogName contains the name of the audit.Log implementation in the DIC.
+var CommandAuditLogName = di.TypeInstanceToName((*audit.Log)(nil))
+
+// CommandAuditLogFrom helper function queries the DIC and returns the audit.Log implementation, or nil when the command
+// audit is disabled.
+func CommandAuditLogFrom(get di.Get) *audit.Log {
+	l, ok := get(CommandAuditLogName).(*audit.Log)
+	if !ok {
+		return nil
+	}
+
+	return l
+}
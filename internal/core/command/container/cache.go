@@ -0,0 +1,45 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// DeviceCacheName contains the name of the cache of the devices retrieved from core-metadata in the DIC.
+var DeviceCacheName = "DeviceCache"
+
+// DeviceProfileCacheName contains the name of the cache of the device profiles retrieved from core-metadata in the DIC.
+var DeviceProfileCacheName = "DeviceProfileCache"
+
+// DeviceServiceCacheName contains the name of the cache of the device services retrieved from core-metadata in the DIC.
+var DeviceServiceCacheName = "DeviceServiceCache"
+
+// DeviceCacheFrom helper function queries the DIC and returns the cache of the devices.
+func DeviceCacheFrom(get di.Get) *cache.TTLCache {
+	return ttlCacheFrom(get, DeviceCacheName)
+}
+
+// DeviceProfileCacheFrom helper function queries the DIC and returns the cache of the device profiles.
+func DeviceProfileCacheFrom(get di.Get) *cache.TTLCache {
+	return ttlCacheFrom(get, DeviceProfileCacheName)
+}
+
+// DeviceServiceCacheFrom helper function queries the DIC and returns the cache of the device services.
+func DeviceServiceCacheFrom(get di.Get) *cache.TTLCache {
+	return ttlCacheFrom(get, DeviceServiceCacheName)
+}
+
+func ttlCacheFrom(get di.Get, name string) *cache.TTLCache {
+	c, ok := get(name).(*cache.TTLCache)
+	if !ok {
+		return nil
+	}
+
+	return c
+}
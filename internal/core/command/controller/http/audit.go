@@ -0,0 +1,83 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/application"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type CommandAuditController struct {
+	dic *di.Container
+}
+
+// NewCommandAuditController creates and initializes an CommandAuditController
+func NewCommandAuditController(dic *di.Container) *CommandAuditController {
+	return &CommandAuditController{
+		dic: dic,
+	}
+}
+
+func (ac *CommandAuditController) AllCommandAuditEntries(w http.ResponseWriter, r *http.Request) {
+	ac.commandAuditEntries(w, r, "", false)
+}
+
+func (ac *CommandAuditController) CommandAuditEntriesByDeviceName(w http.ResponseWriter, r *http.Request) {
+	ac.commandAuditEntries(w, r, mux.Vars(r)[common.Name], false)
+}
+
+func (ac *CommandAuditController) CommandAuditEntriesByTimeRange(w http.ResponseWriter, r *http.Request) {
+	ac.commandAuditEntries(w, r, "", true)
+}
+
+func (ac *CommandAuditController) CommandAuditEntriesByDeviceNameAndTimeRange(w http.ResponseWriter, r *http.Request) {
+	ac.commandAuditEntries(w, r, mux.Vars(r)[common.Name], true)
+}
+
+// commandAuditEntries writes the audit entries of the device, or of all the devices when deviceName is empty, and only
+// the entries in the time range of the path when timeRange is true
+func (ac *CommandAuditController) commandAuditEntries(w http.ResponseWriter, r *http.Request, deviceName string, timeRange bool) {
+	lc := container.LoggingClientFrom(ac.dic.Get)
+	ctx := r.Context()
+	config := commandContainer.ConfigurationFrom(ac.dic.Get)
+
+	var start, end int64 = 0, math.MaxInt64
+	var offset, limit int
+	var err errors.EdgeX
+	if timeRange {
+		var rangeStart, rangeEnd int
+		rangeStart, rangeEnd, offset, limit, err = utils.ParseTimeRangeOffsetLimit(r, 0, math.MaxInt32, -1, config.Service.MaxResultCount)
+		start, end = int64(rangeStart), int64(rangeEnd)
+	} else {
+		offset, limit, _, err = utils.ParseGetAllObjectsRequestQueryString(r, 0, math.MaxInt32, -1, config.Service.MaxResultCount)
+	}
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	entries, totalCount, err := application.CommandAuditEntries(deviceName, start, end, offset, limit, ac.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	response := commandDTOs.NewMultiCommandAuditEntriesResponse("", "", http.StatusOK, totalCount, entries)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
@@ -0,0 +1,172 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/command/application"
+	"github.com/edgexfoundry/edgex-go/internal/core/command/audit"
+	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCallerHeader = "X-Consumer-Username"
+	testCaller       = "testUser"
+)
+
+func mockCommandAuditDIC(t *testing.T) *di.Container {
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.db"))
+	require.NoError(t, err)
+	t.Cleanup(auditLog.Close)
+
+	dic := NewMockDIC()
+	dic.Update(di.ServiceConstructorMap{
+		commandContainer.CommandAuditLogName: func(get di.Get) interface{} {
+			return auditLog
+		},
+	})
+	commandContainer.ConfigurationFrom(dic.Get).CommandAudit.CallerHeaders = "X-Consumer-ID, " + testCallerHeader
+	return dic
+}
+
+func TestCommandAuditEntries(t *testing.T) {
+	dic := mockCommandAuditDIC(t)
+	auditLog := commandContainer.CommandAuditLogFrom(dic.Get)
+	for i, deviceName := range []string{testDeviceName, "otherDevice", testDeviceName} {
+		err := auditLog.Add(commandDTOs.CommandAuditEntry{Id: strconv.Itoa(i), Timestamp: int64(i+1) * 100, DeviceName: deviceName, CommandName: testCommandName})
+		require.NoError(t, err)
+	}
+	ac := NewCommandAuditController(dic)
+	assert.NotNil(t, ac)
+
+	tests := []struct {
+		name               string
+		handler            http.HandlerFunc
+		vars               map[string]string
+		query              string
+		errorExpected      bool
+		expectedIds        []string
+		expectedTotalCount uint32
+		expectedStatusCode int
+	}{
+		{"Valid - all entries", ac.AllCommandAuditEntries, nil, "", false, []string{"2", "1", "0"}, 3, http.StatusOK},
+		{"Valid - all entries with offset and limit", ac.AllCommandAuditEntries, nil, "offset=1&limit=1", false, []string{"1"}, 3, http.StatusOK},
+		{"Valid - by device name", ac.CommandAuditEntriesByDeviceName, map[string]string{common.Name: testDeviceName}, "", false, []string{"2", "0"}, 2, http.StatusOK},
+		{"Valid - by time range", ac.CommandAuditEntriesByTimeRange, map[string]string{common.Start: "100", common.End: "200"}, "", false, []string{"1", "0"}, 2, http.StatusOK},
+		{"Valid - by device name and time range", ac.CommandAuditEntriesByDeviceNameAndTimeRange, map[string]string{common.Name: testDeviceName, common.Start: "150", common.End: "300"}, "", false, []string{"2"}, 1, http.StatusOK},
+		{"Invalid - end before start", ac.CommandAuditEntriesByTimeRange, map[string]string{common.Start: "200", common.End: "100"}, "", true, nil, 0, http.StatusBadRequest},
+		{"Invalid - malformed start", ac.CommandAuditEntriesByTimeRange, map[string]string{common.Start: "yesterday", common.End: "100"}, "", true, nil, 0, http.StatusBadRequest},
+		{"Invalid - limit beyond MaxResultCount", ac.AllCommandAuditEntries, nil, "limit=100", true, nil, 0, http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiCommandAuditRoute, http.NoBody)
+			require.NoError(t, err)
+			req.URL.RawQuery = testCase.query
+			req = mux.SetURLVars(req, testCase.vars)
+
+			recorder := httptest.NewRecorder()
+			testCase.handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.errorExpected {
+				var res commonDTO.BaseResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedStatusCode, res.StatusCode, "Response status code not as expected")
+				assert.NotEmpty(t, res.Message, "Response message doesn't contain the error message")
+				return
+			}
+			var res commandDTOs.MultiCommandAuditEntriesResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &res)
+			require.NoError(t, err)
+			assert.Equal(t, common.ApiVersion, res.ApiVersion, "API Version not as expected")
+			assert.Equal(t, testCase.expectedTotalCount, res.TotalCount, "Total count not as expected")
+			ids := make([]string, len(res.Entries))
+			for i, entry := range res.Entries {
+				ids[i] = entry.Id
+			}
+			assert.Equal(t, testCase.expectedIds, ids)
+		})
+	}
+}
+
+func TestCommandAuditEntries_Disabled(t *testing.T) {
+	ac := NewCommandAuditController(NewMockDIC())
+	req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiAllCommandAuditRoute, http.NoBody)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(ac.AllCommandAuditEntries).ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode)
+}
+
+func TestIssueGetCommand_CommandAudit(t *testing.T) {
+	expectedEventResponse := buildEventResponse()
+	dcMock := &mocks.DeviceClient{}
+	dcMock.On("DeviceByName", context.Background(), testDeviceName).Return(buildDeviceResponse(), nil)
+	dscMock := &mocks.DeviceServiceClient{}
+	dscMock.On("DeviceServiceByName", context.Background(), testDeviceServiceName).Return(buildDeviceServiceResponse(), nil)
+	dsccMock := &mocks.DeviceServiceCommandClient{}
+	dsccMock.On("GetCommand", context.Background(), testBaseAddress, testDeviceName, testCommandName, "").Return(&expectedEventResponse, nil)
+
+	dic := mockCommandAuditDIC(t)
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.MetadataDeviceClientName: func(get di.Get) interface{} {
+			return dcMock
+		},
+		bootstrapContainer.MetadataDeviceServiceClientName: func(get di.Get) interface{} {
+			return dscMock
+		},
+		bootstrapContainer.DeviceServiceCommandClientName: func(get di.Get) interface{} {
+			return dsccMock
+		},
+	})
+	cc := NewCommandController(dic)
+
+	correlationId := uuid.NewString()
+	req, err := http.NewRequest(http.MethodGet, common.ApiDeviceNameCommandNameRoute, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set(testCallerHeader, testCaller)
+	req = req.WithContext(context.WithValue(req.Context(), common.CorrelationHeader, correlationId))
+	req = mux.SetURLVars(req, map[string]string{common.Name: testDeviceName, common.Command: testCommandName})
+
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(cc.IssueGetCommandByName).ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+	entries, totalCount, err := commandContainer.CommandAuditLogFrom(dic.Get).EntriesByDevice(testDeviceName, 0, math.MaxInt64, 0, -1)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), totalCount)
+	assert.Equal(t, testCommandName, entries[0].CommandName)
+	assert.Equal(t, testCaller, entries[0].Caller, "the caller should be taken from the first configured header present")
+	assert.Equal(t, correlationId, entries[0].CorrelationId)
+	assert.Equal(t, application.CommandChannelRest, entries[0].Channel)
+	assert.Equal(t, commandDTOs.CommandAuditStatusDone, entries[0].Status)
+	assert.Equal(t, http.StatusOK, entries[0].StatusCode)
+}
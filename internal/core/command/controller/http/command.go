@@ -6,32 +6,36 @@
 package http
 
 import (
-	"fmt"
 	"math"
 	"net/http"
+	"strings"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	responseDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
-	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/command/application"
 	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 
 	"github.com/gorilla/mux"
 )
 
 type CommandController struct {
-	dic *di.Container
+	reader io.DtoReader
+	dic    *di.Container
 }
 
 // NewCommandController creates and initializes an CommandController
 func NewCommandController(dic *di.Container) *CommandController {
 	return &CommandController{
-		dic: dic,
+		reader: io.NewJsonDtoReader(),
+		dic:    dic,
 	}
 }
 
@@ -78,18 +82,6 @@ func (cc *CommandController) CommandsByDeviceName(w http.ResponseWriter, r *http
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
 
-func validateGetCommandParameters(r *http.Request) (err errors.EdgeX) {
-	dsReturnEvent := utils.ParseQueryStringToString(r, common.ReturnEvent, common.ValueYes)
-	dsPushEvent := utils.ParseQueryStringToString(r, common.PushEvent, common.ValueNo)
-	if dsReturnEvent != common.ValueYes && dsReturnEvent != common.ValueNo {
-		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid query parameter, %s has to be %s or %s", dsReturnEvent, common.ValueYes, common.ValueNo), nil)
-	}
-	if dsPushEvent != common.ValueYes && dsPushEvent != common.ValueNo {
-		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid query parameter, %s has to be %s or %s", dsPushEvent, common.ValueYes, common.ValueNo), nil)
-	}
-	return nil
-}
-
 func (cc *CommandController) IssueGetCommandByName(w http.ResponseWriter, r *http.Request) {
 	lc := container.LoggingClientFrom(cc.dic.Get)
 	ctx := r.Context()
@@ -101,13 +93,14 @@ func (cc *CommandController) IssueGetCommandByName(w http.ResponseWriter, r *htt
 
 	// Query params
 	queryParams := r.URL.RawQuery
-	err := validateGetCommandParameters(r)
+	err := application.ValidateGetCommandParameters(r.URL.Query())
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
 	}
 
-	response, err := application.IssueGetCommandByName(deviceName, commandName, queryParams, cc.dic)
+	caller := commandCaller(r, application.CommandChannelRest, cc.dic)
+	response, err := application.IssueGetCommandByName(deviceName, commandName, queryParams, caller, cc.dic)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
@@ -140,7 +133,8 @@ func (cc *CommandController) IssueSetCommandByName(w http.ResponseWriter, r *htt
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
 	}
-	response, err := application.IssueSetCommandByName(deviceName, commandName, queryParams, settings, cc.dic)
+	caller := commandCaller(r, application.CommandChannelRest, cc.dic)
+	response, err := application.IssueSetCommandByName(deviceName, commandName, queryParams, settings, caller, cc.dic)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
@@ -150,3 +144,52 @@ func (cc *CommandController) IssueSetCommandByName(w http.ResponseWriter, r *htt
 	// encode and send out the response
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
+
+func (cc *CommandController) IssueBatchCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer func() { _ = r.Body.Close() }()
+	}
+
+	lc := container.LoggingClientFrom(cc.dic.Get)
+	ctx := r.Context()
+
+	var reqDTO commandDTOs.BatchCommandRequest
+	err := cc.reader.Read(r.Body, &reqDTO)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	caller := commandCaller(r, application.CommandChannelBatch, cc.dic)
+	results, err := application.IssueBatchCommands(ctx, reqDTO, caller, cc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	// each result carries the status of its own command
+	response := commandDTOs.NewBatchCommandResponse(reqDTO.RequestId, "", http.StatusMultiStatus, results)
+	utils.WriteHttpHeader(w, ctx, http.StatusMultiStatus)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
+
+// commandCaller identifies the caller of the request by the first of the configured caller headers present in the
+// request, and the request by its correlation id
+func commandCaller(r *http.Request, channel string, dic *di.Container) application.CommandCaller {
+	caller := application.CommandCaller{
+		CorrelationId: correlation.FromContext(r.Context()),
+		Channel:       channel,
+	}
+	callerHeaders := commandContainer.ConfigurationFrom(dic.Get).CommandAudit.CallerHeaders
+	for _, header := range strings.Split(callerHeaders, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		if identity := r.Header.Get(header); identity != "" {
+			caller.Identity = identity
+			break
+		}
+	}
+	return caller
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/core/command/application"
 	"github.com/edgexfoundry/edgex-go/internal/core/command/config"
 	commandContainer "github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	commandDTOs "github.com/edgexfoundry/edgex-go/internal/core/command/dtos"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
@@ -59,6 +61,10 @@ func NewMockDIC() *di.Container {
 					Port:           mockPort,
 					MaxResultCount: 20,
 				},
+				BatchCommand: config.BatchCommandInfo{
+					MaxCommands:              3,
+					MaxConcurrencyPerService: 2,
+				},
 			}
 		},
 		container.LoggingClientInterfaceName: func(get di.Get) interface{} {
@@ -67,13 +73,14 @@ func NewMockDIC() *di.Container {
 	})
 }
 
+// buildTestSettings returns the settings as they are decoded from the request, whose numbers are json.Number
 func buildTestSettings() map[string]interface{} {
 	var settings = make(map[string]interface{})
 	settings["AHU-TargetTemperature"] = "28.5"
 	settings["AHU-TargetBand"] = "4.0"
 	settings["AHU-TargetHumidity"] = map[string]interface{}{
 		"Accuracy": "0.2-0.3% RH",
-		"Value":    float64(59),
+		"Value":    json.Number("59"),
 	}
 	return settings
 }
@@ -148,6 +155,33 @@ func buildDeviceProfileResponse() responseDTO.DeviceProfileResponse {
 	return deviceResponse
 }
 
+// buildSetCommandProfileResponse returns the profile whose testCommandName command writes the buildTestSettings
+func buildSetCommandProfileResponse() responseDTO.DeviceProfileResponse {
+	profile := dtos.DeviceProfile{
+		Name: testProfileName,
+		DeviceResources: []dtos.DeviceResource{
+			{Name: "AHU-TargetTemperature", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeFloat32, ReadWrite: common.ReadWrite_RW, Minimum: "10", Maximum: "35"}},
+			{Name: "AHU-TargetBand", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeFloat32, ReadWrite: common.ReadWrite_RW}},
+			{Name: "AHU-TargetHumidity", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeObject, ReadWrite: common.ReadWrite_RW}},
+			{Name: testResourceName, Properties: dtos.ResourceProperties{ValueType: common.ValueTypeUint16, ReadWrite: common.ReadWrite_R}},
+		},
+		DeviceCommands: []dtos.DeviceCommand{
+			{
+				Name:      testCommandName,
+				ReadWrite: common.ReadWrite_RW,
+				ResourceOperations: []dtos.ResourceOperation{
+					{DeviceResource: "AHU-TargetTemperature"},
+					{DeviceResource: "AHU-TargetBand"},
+					{DeviceResource: "AHU-TargetHumidity"},
+				},
+			},
+		},
+	}
+	return responseDTO.DeviceProfileResponse{
+		Profile: profile,
+	}
+}
+
 func buildDeviceServiceResponse() responseDTO.DeviceServiceResponse {
 	service := dtos.DeviceService{
 		Name:        testDeviceServiceName,
@@ -427,8 +461,15 @@ func TestIssueSetCommand(t *testing.T) {
 	dscMock := &mocks.DeviceServiceClient{}
 	dscMock.On("DeviceServiceByName", context.Background(), testDeviceServiceName).Return(expectedDeviceServiceResponse, nil)
 
+	dpcMock := &mocks.DeviceProfileClient{}
+	dpcMock.On("DeviceProfileByName", context.Background(), testProfileName).Return(buildSetCommandProfileResponse(), nil)
+
 	testSettings := buildTestSettings()
 	testSettingsJsonStr, _ := json.Marshal(testSettings)
+	outOfRangeSettings, _ := json.Marshal(map[string]interface{}{"AHU-TargetTemperature": "40.5"})
+	invalidValueSettings, _ := json.Marshal(map[string]interface{}{"AHU-TargetTemperature": "warm", "AHU-TargetBand": 4})
+	unknownResourceSettings, _ := json.Marshal(map[string]interface{}{"AHU-TargetTemp": "28.5"})
+	readOnlyResourceSettings, _ := json.Marshal(map[string]interface{}{testResourceName: "1"})
 	dsccMock := &mocks.DeviceServiceCommandClient{}
 	dsccMock.On("SetCommandWithObject", context.Background(), testBaseAddress, testDeviceName, testCommandName, testQueryStrings, testSettings).Return(expectedBaseResponse, nil)
 	dsccMock.On("SetCommandWithObject", context.Background(), testBaseAddress, testDeviceName, testCommandName, "", testSettings).Return(expectedBaseResponse, nil)
@@ -443,6 +484,9 @@ func TestIssueSetCommand(t *testing.T) {
 		bootstrapContainer.MetadataDeviceServiceClientName: func(get di.Get) interface{} { // add v2 API MetadataDeviceProfileClient
 			return dscMock
 		},
+		bootstrapContainer.MetadataDeviceProfileClientName: func(get di.Get) interface{} {
+			return dpcMock
+		},
 		bootstrapContainer.DeviceServiceCommandClientName: func(get di.Get) interface{} { // add v2 API DeviceServiceCommandClient
 			return dsccMock
 		},
@@ -466,6 +510,10 @@ func TestIssueSetCommand(t *testing.T) {
 		{"Invalid - empty device name", "", testCommandName, testQueryStrings, testSettingsJsonStr, true, http.StatusBadRequest},
 		{"Invalid - empty command name", testDeviceName, "", testQueryStrings, testSettingsJsonStr, true, http.StatusBadRequest},
 		{"Invalid - empty settings", testDeviceName, testCommandName, testQueryStrings, []byte{}, true, http.StatusInternalServerError},
+		{"Invalid - value out of range", testDeviceName, testCommandName, testQueryStrings, outOfRangeSettings, true, http.StatusBadRequest},
+		{"Invalid - value mismatching value type", testDeviceName, testCommandName, testQueryStrings, invalidValueSettings, true, http.StatusBadRequest},
+		{"Invalid - resource not in command", testDeviceName, testCommandName, testQueryStrings, unknownResourceSettings, true, http.StatusBadRequest},
+		{"Invalid - read-only resource command", testDeviceName, testResourceName, testQueryStrings, readOnlyResourceSettings, true, http.StatusBadRequest},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -494,3 +542,124 @@ func TestIssueSetCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueBatchCommands(t *testing.T) {
+	var nonExistName = "nonExist"
+	var testLabel = "testLabel"
+
+	expectedEventResponse := buildEventResponse()
+	expectedBaseResponse := commonDTO.NewBaseResponse("", "", http.StatusOK)
+
+	dcMock := &mocks.DeviceClient{}
+	dcMock.On("DeviceByName", context.Background(), testDeviceName).Return(buildDeviceResponse(), nil)
+	dcMock.On("DeviceByName", context.Background(), nonExistName).Return(responseDTO.DeviceResponse{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "fail to query device by name", nil))
+	dcMock.On("AllDevices", context.Background(), []string{testLabel}, 0, -1).Return(buildMultiDevicesResponse(), nil)
+
+	dscMock := &mocks.DeviceServiceClient{}
+	dscMock.On("DeviceServiceByName", context.Background(), testDeviceServiceName).Return(buildDeviceServiceResponse(), nil)
+
+	dpcMock := &mocks.DeviceProfileClient{}
+	dpcMock.On("DeviceProfileByName", context.Background(), testProfileName).Return(buildSetCommandProfileResponse(), nil)
+
+	testSettings := buildTestSettings()
+	dsccMock := &mocks.DeviceServiceCommandClient{}
+	dsccMock.On("GetCommand", context.Background(), testBaseAddress, testDeviceName, testCommandName, "").Return(&expectedEventResponse, nil)
+	dsccMock.On("GetCommand", context.Background(), testBaseAddress, testDeviceName+"1", testCommandName, "").Return(&expectedEventResponse, nil)
+	dsccMock.On("GetCommand", context.Background(), testBaseAddress, testDeviceName+"2", testCommandName, "").Return(&expectedEventResponse, nil)
+	dsccMock.On("SetCommandWithObject", context.Background(), testBaseAddress, testDeviceName, testCommandName, "", testSettings).Return(expectedBaseResponse, nil)
+
+	dic := NewMockDIC()
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.MetadataDeviceClientName: func(get di.Get) interface{} {
+			return dcMock
+		},
+		bootstrapContainer.MetadataDeviceServiceClientName: func(get di.Get) interface{} {
+			return dscMock
+		},
+		bootstrapContainer.MetadataDeviceProfileClientName: func(get di.Get) interface{} {
+			return dpcMock
+		},
+		bootstrapContainer.DeviceServiceCommandClientName: func(get di.Get) interface{} {
+			return dsccMock
+		},
+	})
+	cc := NewCommandController(dic)
+	assert.NotNil(t, cc)
+
+	getCommand := commandDTOs.BatchCommand{DeviceName: testDeviceName, CommandName: testCommandName}
+	setCommand := commandDTOs.BatchCommand{DeviceName: testDeviceName, CommandName: testCommandName, Settings: testSettings}
+	nonExistDeviceCommand := commandDTOs.BatchCommand{DeviceName: nonExistName, CommandName: testCommandName}
+	invalidQueryCommand := commandDTOs.BatchCommand{DeviceName: testDeviceName, CommandName: testCommandName, QueryParameters: map[string]string{common.PushEvent: "maybe"}}
+	invalidSettingsCommand := commandDTOs.BatchCommand{DeviceName: testDeviceName, CommandName: testCommandName, Settings: map[string]interface{}{"AHU-TargetTemperature": "5"}}
+
+	tests := []struct {
+		name                string
+		request             commandDTOs.BatchCommandRequest
+		expectedStatusCode  int
+		expectedResultCodes []int
+	}{
+		{"Valid - get and set commands", commandDTOs.BatchCommandRequest{Commands: []commandDTOs.BatchCommand{getCommand, setCommand}}, http.StatusMultiStatus, []int{http.StatusOK, http.StatusOK}},
+		{"Valid - failed commands", commandDTOs.BatchCommandRequest{Commands: []commandDTOs.BatchCommand{nonExistDeviceCommand, invalidQueryCommand, getCommand}}, http.StatusMultiStatus, []int{http.StatusNotFound, http.StatusBadRequest, http.StatusOK}},
+		{"Valid - invalid settings", commandDTOs.BatchCommandRequest{Commands: []commandDTOs.BatchCommand{invalidSettingsCommand, setCommand}}, http.StatusMultiStatus, []int{http.StatusBadRequest, http.StatusOK}},
+		{"Valid - devices selected by labels", commandDTOs.BatchCommandRequest{Labels: []string{testLabel}, CommandName: testCommandName}, http.StatusMultiStatus, []int{http.StatusOK, http.StatusOK}},
+		{"Invalid - no commands nor labels", commandDTOs.BatchCommandRequest{}, http.StatusBadRequest, nil},
+		{"Invalid - both commands and labels", commandDTOs.BatchCommandRequest{Commands: []commandDTOs.BatchCommand{getCommand}, Labels: []string{testLabel}, CommandName: testCommandName}, http.StatusBadRequest, nil},
+		{"Invalid - labels without command name", commandDTOs.BatchCommandRequest{Labels: []string{testLabel}}, http.StatusBadRequest, nil},
+		{"Invalid - command without device name", commandDTOs.BatchCommandRequest{Commands: []commandDTOs.BatchCommand{{CommandName: testCommandName}}}, http.StatusBadRequest, nil},
+		{"Invalid - too many commands", commandDTOs.BatchCommandRequest{Commands: []commandDTOs.BatchCommand{getCommand, getCommand, getCommand, getCommand}}, http.StatusBadRequest, nil},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.request.BaseRequest = commonDTO.NewBaseRequest()
+			jsonData, err := json.Marshal(testCase.request)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiDeviceCommandBatchRoute, bytes.NewReader(jsonData))
+			require.NoError(t, err)
+
+			// Act
+			recorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(cc.IssueBatchCommands)
+			handler.ServeHTTP(recorder, req)
+
+			// Assert
+			var res commandDTOs.BatchCommandResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &res)
+			require.NoError(t, err)
+			assert.Equal(t, common.ApiVersion, res.ApiVersion, "API Version not as expected")
+			assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode, "Response status code not as expected")
+			if testCase.expectedStatusCode != http.StatusMultiStatus {
+				assert.NotEmpty(t, res.Message, "Response message doesn't contain the error message")
+				return
+			}
+			require.Len(t, res.Results, len(testCase.expectedResultCodes))
+			for i, result := range res.Results {
+				assert.Equal(t, testCase.expectedResultCodes[i], result.StatusCode)
+				if result.StatusCode != http.StatusOK {
+					assert.NotEmpty(t, result.Message)
+					continue
+				}
+				assert.NotZero(t, result.Started)
+				assert.NotEmpty(t, result.Duration)
+				if result.Method == "get" {
+					require.NotNil(t, result.Event)
+					assert.Equal(t, expectedEventResponse.Event.Id, result.Event.Id)
+				} else {
+					assert.Nil(t, result.Event)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueBatchCommands_MalformedRequest(t *testing.T) {
+	cc := NewCommandController(NewMockDIC())
+	req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiDeviceCommandBatchRoute, bytes.NewReader([]byte(`{"commands":`)))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler := http.HandlerFunc(cc.IssueBatchCommands)
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+}
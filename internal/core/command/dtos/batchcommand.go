@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// BatchCommandRequest lists the commands to issue, or selects the devices by Labels and issues CommandName to each of
+// them with the Settings and QueryParameters of the request.  A command is a set command when it has settings, and a
+// get command otherwise.
+type BatchCommandRequest struct {
+	common.BaseRequest `json:",inline"`
+	Commands           []BatchCommand `json:"commands,omitempty" validate:"omitempty,dive"`
+	// Labels must all be labels of the selected devices
+	Labels          []string               `json:"labels,omitempty"`
+	CommandName     string                 `json:"commandName,omitempty" validate:"required_with=Labels"`
+	QueryParameters map[string]string      `json:"queryParameters,omitempty"`
+	Settings        map[string]interface{} `json:"settings,omitempty"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface for the BatchCommandRequest type, which decodes the numbers of the
+// settings, including those of the commands, as json.Number
+func (b *BatchCommandRequest) UnmarshalJSON(data []byte) error {
+	type alias BatchCommandRequest
+	return decodeWithNumbers(data, (*alias)(b))
+}
+
+// BatchCommand is a command of a BatchCommandRequest
+type BatchCommand struct {
+	DeviceName      string                 `json:"deviceName" validate:"required"`
+	CommandName     string                 `json:"commandName" validate:"required"`
+	QueryParameters map[string]string      `json:"queryParameters,omitempty"`
+	Settings        map[string]interface{} `json:"settings,omitempty"`
+}
+
+// BatchCommandResult is the result of a command of the batch, in the order of the request.  StatusCode and Message
+// are those of the response of the device service, or of the failure to issue the command.
+type BatchCommandResult struct {
+	DeviceName  string `json:"deviceName"`
+	CommandName string `json:"commandName"`
+	Method      string `json:"method"`
+	StatusCode  int    `json:"statusCode"`
+	Message     string `json:"message,omitempty"`
+	// Event is the event returned by a get command
+	Event *dtos.Event `json:"event,omitempty"`
+	// Started is the time in milliseconds the command was issued at, and Duration is how long it took, e.g. 12.5ms
+	Started  int64  `json:"started,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+type BatchCommandResponse struct {
+	common.BaseResponse `json:",inline"`
+	Results             []BatchCommandResult `json:"results"`
+}
+
+func NewBatchCommandResponse(requestId string, message string, statusCode int, results []BatchCommandResult) BatchCommandResponse {
+	return BatchCommandResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Results:      results,
+	}
+}
@@ -0,0 +1,55 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// The statuses of the command audit entry.  The entry is pending from the time the command is issued until its
+// response is recorded, so an entry left pending means the outcome of the command is unknown, e.g. the service stopped
+// while the command was in flight.
+const (
+	CommandAuditStatusPending = "pending"
+	CommandAuditStatusDone    = "done"
+)
+
+// CommandAuditEntry records a get or set command issued to a device, whether or not the command succeeded
+type CommandAuditEntry struct {
+	Id string `json:"id"`
+	// Timestamp is the time in nanoseconds the command was issued at
+	Timestamp   int64  `json:"timestamp"`
+	DeviceName  string `json:"deviceName"`
+	CommandName string `json:"commandName"`
+	Method      string `json:"method"`
+	// QueryParameters is the query string passed to the device service
+	QueryParameters string `json:"queryParameters,omitempty"`
+	// Settings is the payload of a set command
+	Settings map[string]interface{} `json:"settings,omitempty"`
+	// Caller is the identity of the caller taken from the request headers, if any
+	Caller        string `json:"caller,omitempty"`
+	CorrelationId string `json:"correlationId,omitempty"`
+	// Channel is how the command was received, i.e. rest, batch or messagebus
+	Channel string `json:"channel"`
+	// Status is either pending or done, and StatusCode, Message and Latency are only set once the command is done
+	Status     string `json:"status"`
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message,omitempty"`
+	// Latency is how long the command took, e.g. 12.5ms
+	Latency string `json:"latency"`
+}
+
+type MultiCommandAuditEntriesResponse struct {
+	common.BaseWithTotalCountResponse `json:",inline"`
+	Entries                           []CommandAuditEntry `json:"entries"`
+}
+
+func NewMultiCommandAuditEntriesResponse(requestId string, message string, statusCode int, totalCount uint32, entries []CommandAuditEntry) MultiCommandAuditEntriesResponse {
+	return MultiCommandAuditEntriesResponse{
+		BaseWithTotalCountResponse: common.NewBaseWithTotalCountResponse(requestId, message, statusCode, totalCount),
+		Entries:                    entries,
+	}
+}
@@ -0,0 +1,40 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// CommandRequest is the payload of a command request received from the message bus.  The device name, the command
+// name and the method, get or set, are the last three levels of the request topic.  The response is an EventResponse
+// for a get command returning an event, or a BaseResponse otherwise, and carries the RequestId of the request.
+type CommandRequest struct {
+	common.BaseRequest `json:",inline"`
+	// QueryParameters are passed to the device service like the query string of the REST API, e.g. ds-pushevent
+	QueryParameters map[string]string `json:"queryParameters,omitempty"`
+	// Settings are the resource values written by a set command
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// UnmarshalJSON implements the Unmarshaler interface for the CommandRequest type, which decodes the numbers of the
+// settings as json.Number
+func (c *CommandRequest) UnmarshalJSON(b []byte) error {
+	type alias CommandRequest
+	return decodeWithNumbers(b, (*alias)(c))
+}
+
+// decodeWithNumbers decodes the JSON data into v, and the numbers decoded into interface values are json.Number rather
+// than float64, so the settings beyond the precision of float64, e.g. a large Uint64, are validated and issued as
+// they are written
+func decodeWithNumbers(b []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -19,8 +19,12 @@ package command
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/edgexfoundry/edgex-go/internal/core/command/application"
 	"github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -65,5 +69,48 @@ func (b *Bootstrap) BootstrapHandler(ctx context.Context, wg *sync.WaitGroup, _
 			return clients.NewDeviceServiceCommandClient()
 		},
 	})
+
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	if configuration.MetadataCache.Enabled {
+		ttl, err := time.ParseDuration(configuration.MetadataCache.TTL)
+		if err != nil {
+			lc.Errorf("Failed to parse MetadataCache.TTL %s, %v", configuration.MetadataCache.TTL, err)
+			return false
+		}
+		dic.Update(di.ServiceConstructorMap{
+			container.DeviceCacheName: func(get di.Get) interface{} {
+				return cache.NewTTLCache(ttl)
+			},
+			container.DeviceProfileCacheName: func(get di.Get) interface{} {
+				return cache.NewTTLCache(ttl)
+			},
+			container.DeviceServiceCacheName: func(get di.Get) interface{} {
+				return cache.NewTTLCache(ttl)
+			},
+		})
+
+		if configuration.MetadataCache.SubscribeTopic != "" {
+			err := application.SubscribeMetadataSystemEvents(ctx, wg, dic)
+			if err != nil {
+				lc.Errorf("Failed to subscribe metadata system events from message bus, %v", err)
+				return false
+			}
+		} else {
+			lc.Warnf("MetadataCache is enabled without SubscribeTopic, the metadata changed in core-metadata is used for up to %s until its TTL expires", configuration.MetadataCache.TTL)
+		}
+	}
+
+	if err := application.StartCommandAudit(ctx, wg, dic); err != nil {
+		lc.Errorf("Failed to start the command audit, %v", err)
+		return false
+	}
+
+	if configuration.MessageQueue.SubscribeEnabled {
+		err := application.SubscribeCommandRequests(ctx, wg, dic)
+		if err != nil {
+			lc.Errorf("Failed to subscribe command requests from message bus, %v", err)
+			return false
+		}
+	}
 	return true
 }
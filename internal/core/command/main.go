@@ -22,6 +22,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal"
 	"github.com/edgexfoundry/edgex-go/internal/core/command/config"
 	"github.com/edgexfoundry/edgex-go/internal/core/command/container"
+	pkgHandlers "github.com/edgexfoundry/edgex-go/internal/pkg/bootstrap/handlers"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/telemetry"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap"
@@ -29,6 +30,7 @@ import (
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/handlers"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
@@ -57,6 +59,11 @@ func Main(ctx context.Context, cancel context.CancelFunc, router *mux.Router) {
 	})
 
 	httpServer := handlers.NewHttpServer(router, true)
+	// the message bus is used by the command channel and by the system events evicting the cached metadata
+	messageBusInfo := func(get di.Get) (bootstrapConfig.MessageBusInfo, bool) {
+		metadataCache := configuration.MetadataCache
+		return configuration.MessageQueue, configuration.MessageQueue.SubscribeEnabled || (metadataCache.Enabled && metadataCache.SubscribeTopic != "")
+	}
 
 	bootstrap.Run(
 		ctx,
@@ -69,6 +76,7 @@ func Main(ctx context.Context, cancel context.CancelFunc, router *mux.Router) {
 		dic,
 		true,
 		[]interfaces.BootstrapHandler{
+			pkgHandlers.NewMessaging(messageBusInfo, container.MessagingClientName).BootstrapHandler,
 			NewBootstrap(router, common.CoreCommandServiceKey).BootstrapHandler,
 			telemetry.BootstrapHandler,
 			httpServer.BootstrapHandler,
@@ -14,6 +14,7 @@ import (
 	"github.com/gorilla/mux"
 
 	commandController "github.com/edgexfoundry/edgex-go/internal/core/command/controller/http"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	commonController "github.com/edgexfoundry/edgex-go/internal/pkg/controller/http"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 )
@@ -32,6 +33,14 @@ func LoadRestRoutes(r *mux.Router, dic *di.Container, serviceName string) {
 	r.HandleFunc(common.ApiDeviceByNameRoute, cmd.CommandsByDeviceName).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceNameCommandNameRoute, cmd.IssueGetCommandByName).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceNameCommandNameRoute, cmd.IssueSetCommandByName).Methods(http.MethodPut)
+	r.HandleFunc(pkgCommon.ApiDeviceCommandBatchRoute, cmd.IssueBatchCommands).Methods(http.MethodPost)
+
+	// Command Audit
+	ac := commandController.NewCommandAuditController(dic)
+	r.HandleFunc(pkgCommon.ApiAllCommandAuditRoute, ac.AllCommandAuditEntries).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiCommandAuditByDeviceNameRoute, ac.CommandAuditEntriesByDeviceName).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiCommandAuditByTimeRangeRoute, ac.CommandAuditEntriesByTimeRange).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiCommandAuditByDeviceNameAndTimeRangeRoute, ac.CommandAuditEntriesByDeviceNameAndTimeRange).Methods(http.MethodGet)
 
 	r.Use(correlation.ManageHeader)
 	r.Use(correlation.LoggingMiddleware(container.LoggingClientFrom(dic.Get)))
@@ -0,0 +1,243 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+)
+
+// AllAggregateFunctions lists the aggregation functions applied when none is specified
+var AllAggregateFunctions = []string{
+	pkgCommon.AggregateMin, pkgCommon.AggregateMax, pkgCommon.AggregateAvg, pkgCommon.AggregateSum,
+	pkgCommon.AggregateCount, pkgCommon.AggregateFirst, pkgCommon.AggregateLast,
+}
+
+// bucket accumulates the values of the readings within a time bucket
+type bucket struct {
+	start       int64
+	count       uint32
+	sum         float64
+	min         float64
+	max         float64
+	first       float64
+	firstOrigin int64
+	last        float64
+	lastOrigin  int64
+}
+
+func (b *bucket) add(origin int64, value float64) {
+	if b.count == 0 {
+		b.min, b.max = value, value
+		b.first, b.firstOrigin = value, origin
+		b.last, b.lastOrigin = value, origin
+	}
+	b.count++
+	b.sum += value
+	if value < b.min {
+		b.min = value
+	}
+	if value > b.max {
+		b.max = value
+	}
+	if origin < b.firstOrigin {
+		b.first, b.firstOrigin = value, origin
+	}
+	if origin >= b.lastOrigin {
+		b.last, b.lastOrigin = value, origin
+	}
+}
+
+func (b *bucket) toDTO(interval int64, functions []string) dataDTOs.ReadingAggregate {
+	aggregate := dataDTOs.ReadingAggregate{Start: b.start, End: b.start + interval}
+	if interval > math.MaxInt64-b.start {
+		// the bucket of a huge interval ends beyond the latest representable time
+		aggregate.End = math.MaxInt64
+	}
+	for _, f := range functions {
+		switch f {
+		case pkgCommon.AggregateMin:
+			aggregate.Min = floatPointer(b.min)
+		case pkgCommon.AggregateMax:
+			aggregate.Max = floatPointer(b.max)
+		case pkgCommon.AggregateAvg:
+			aggregate.Avg = floatPointer(b.sum / float64(b.count))
+		case pkgCommon.AggregateSum:
+			aggregate.Sum = floatPointer(b.sum)
+		case pkgCommon.AggregateCount:
+			count := b.count
+			aggregate.Count = &count
+		case pkgCommon.AggregateFirst:
+			aggregate.First = floatPointer(b.first)
+		case pkgCommon.AggregateLast:
+			aggregate.Last = floatPointer(b.last)
+		}
+	}
+	return aggregate
+}
+
+func floatPointer(f float64) *float64 {
+	return &f
+}
+
+// ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange aggregates the readings of the device resource whose origin
+// is within the specified time range into fixed time buckets of interval nanoseconds.  The whole time range is a single
+// bucket when interval is 0.  Readings are rejected unless the value type defined by the device profile is numeric.
+func ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(deviceName string, resourceName string, start, end int, interval int64, functions []string,
+	ctx context.Context, dic *di.Container) (valueType string, aggregates []dataDTOs.ReadingAggregate, err errors.EdgeX) {
+	if deviceName == "" {
+		return valueType, aggregates, errors.NewCommonEdgeX(errors.KindContractInvalid, "device name is empty", nil)
+	}
+	if resourceName == "" {
+		return valueType, aggregates, errors.NewCommonEdgeX(errors.KindContractInvalid, "resource name is empty", nil)
+	}
+	if len(functions) == 0 {
+		functions = AllAggregateFunctions
+	}
+	for _, f := range functions {
+		if !isAggregateFunction(f) {
+			return valueType, aggregates, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unsupported aggregation function %s", f), nil)
+		}
+	}
+
+	// the time range is bounded so that its length, end - start + 1, is a positive int64
+	if start < 0 || end < start || int64(end) == math.MaxInt64 {
+		return valueType, aggregates, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("the time range from %d to %d is invalid, which must satisfy 0 <= start <= end < %d", start, end, int64(math.MaxInt64)), nil)
+	}
+
+	config := container.ConfigurationFrom(dic.Get)
+	timeRange := int64(end) - int64(start) + 1
+	if interval <= 0 {
+		interval = timeRange
+	}
+	bucketCount := timeRange / interval
+	if timeRange%interval != 0 {
+		bucketCount++
+	}
+	if bucketCount > int64(config.Service.MaxResultCount) {
+		return valueType, aggregates, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("the time range and interval result in %d buckets which exceeds the maximum %d", bucketCount, config.Service.MaxResultCount), nil)
+	}
+
+	// value types of the device resource keyed by profile name, as the device might be moved to another profile
+	valueTypes := make(map[string]string)
+	buckets := make(map[int64]*bucket)
+	dbClient := container.DBClientFrom(dic.Get)
+	err = forEachReadingsPage(dbClient, deviceName, resourceName, start, end, config.Service.MaxResultCount, func(readings []models.Reading) errors.EdgeX {
+		for _, r := range readings {
+			base := r.GetBaseReading()
+			vt, ok := valueTypes[base.ProfileName]
+			if !ok {
+				var err errors.EdgeX
+				vt, err = resourceValueType(ctx, dic, base)
+				if err != nil {
+					return errors.NewCommonEdgeXWrapper(err)
+				}
+				valueTypes[base.ProfileName] = vt
+			}
+			value, err := numericReadingValue(r, vt)
+			if err != nil {
+				return errors.NewCommonEdgeXWrapper(err)
+			}
+			valueType = vt
+
+			index := (base.Origin - int64(start)) / interval
+			b, ok := buckets[index]
+			if !ok {
+				b = &bucket{start: int64(start) + index*interval}
+				buckets[index] = b
+			}
+			b.add(base.Origin, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return valueType, aggregates, errors.NewCommonEdgeXWrapper(err)
+	}
+
+	indexes := make([]int64, 0, len(buckets))
+	for index := range buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	aggregates = make([]dataDTOs.ReadingAggregate, len(indexes))
+	for i, index := range indexes {
+		aggregates[i] = buckets[index].toDTO(interval, functions)
+	}
+	return valueType, aggregates, nil
+}
+
+func isAggregateFunction(f string) bool {
+	for _, supported := range AllAggregateFunctions {
+		if f == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceValueType returns the value type of the device resource defined by the device profile of the reading.  The
+// value type of the reading is used when the device resource no longer exists in the profile.
+func resourceValueType(ctx context.Context, dic *di.Container, reading models.BaseReading) (string, errors.EdgeX) {
+	dpc := bootstrapContainer.MetadataDeviceProfileClientFrom(dic.Get)
+	if dpc == nil {
+		return "", errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceProfileClient returned", nil)
+	}
+	res, err := dpc.DeviceResourceByProfileNameAndResourceName(ctx, reading.ProfileName, reading.ResourceName)
+	if err != nil {
+		if errors.Kind(err) == errors.KindEntityDoesNotExist {
+			return reading.ValueType, nil
+		}
+		return "", errors.NewCommonEdgeX(errors.Kind(err), fmt.Sprintf("failed to query the device resource %s of profile %s", reading.ResourceName, reading.ProfileName), err)
+	}
+	return res.Resource.Properties.ValueType, nil
+}
+
+// numericReadingValue parses the value of a simple reading according to the numeric value type
+func numericReadingValue(r models.Reading, valueType string) (float64, errors.EdgeX) {
+	base := r.GetBaseReading()
+	simpleReading, ok := r.(models.SimpleReading)
+	if !ok {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("reading %s of resource %s is not a numeric reading", base.Id, base.ResourceName), nil)
+	}
+
+	var value float64
+	var err error
+	switch valueType {
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		var v uint64
+		v, err = strconv.ParseUint(simpleReading.Value, 10, 64)
+		value = float64(v)
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
+		var v int64
+		v, err = strconv.ParseInt(simpleReading.Value, 10, 64)
+		value = float64(v)
+	case common.ValueTypeFloat32:
+		value, err = strconv.ParseFloat(simpleReading.Value, 32)
+	case common.ValueTypeFloat64:
+		value, err = strconv.ParseFloat(simpleReading.Value, 64)
+	default:
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("value type %s of resource %s is not numeric", valueType, base.ResourceName), nil)
+	}
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("failed to parse the value %s of reading %s as %s", simpleReading.Value, base.Id, valueType), err)
+	}
+	return value, nil
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	clientMocks "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+)
+
+func simpleReading(resourceName string, origin int64, valueType string, value string) models.SimpleReading {
+	return models.SimpleReading{
+		BaseReading: models.BaseReading{
+			Id:           testUUIDString,
+			Origin:       origin,
+			DeviceName:   testDeviceName,
+			ResourceName: resourceName,
+			ProfileName:  testProfileName,
+			ValueType:    valueType,
+		},
+		Value: value,
+	}
+}
+
+func TestReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(t *testing.T) {
+	numericResource := "Temperature"
+	stringResource := "Status"
+	// readings are returned in descending order of origin
+	numericReadings := []models.Reading{
+		simpleReading(numericResource, 25, common.ValueTypeInt16, "-4"),
+		simpleReading(numericResource, 12, common.ValueTypeInt16, "30"),
+		simpleReading(numericResource, 10, common.ValueTypeInt16, "10"),
+		simpleReading(numericResource, 9, common.ValueTypeInt16, "5"),
+		simpleReading(numericResource, 0, common.ValueTypeInt16, "1"),
+	}
+	stringReadings := []models.Reading{simpleReading(stringResource, 5, common.ValueTypeString, "OK")}
+
+	dic := mocks.NewMockDIC()
+	dbClientMock := &dbMock.DBClient{}
+	// the readings are queried page by page, each of which starts after the last reading of the previous page
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", testDeviceName, numericResource, 0, 29, "", 3).Return(numericReadings[:3], nil)
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", testDeviceName, numericResource, 0, 10, testUUIDString, 3).Return(numericReadings[3:], nil)
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", testDeviceName, numericResource, 0, 0, testUUIDString, 3).Return(nil, nil)
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", testDeviceName, stringResource, 0, 29, "", 3).Return(stringReadings, nil)
+	dpcMock := &clientMocks.DeviceProfileClient{}
+	dpcMock.On("DeviceResourceByProfileNameAndResourceName", mock.Anything, testProfileName, numericResource).Return(
+		responses.DeviceResourceResponse{Resource: dtos.DeviceResource{Properties: dtos.ResourceProperties{ValueType: common.ValueTypeInt16}}}, nil)
+	dpcMock.On("DeviceResourceByProfileNameAndResourceName", mock.Anything, testProfileName, stringResource).Return(
+		responses.DeviceResourceResponse{Resource: dtos.DeviceResource{Properties: dtos.ResourceProperties{ValueType: common.ValueTypeString}}}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+		bootstrapContainer.MetadataDeviceProfileClientName: func(get di.Get) interface{} {
+			return dpcMock
+		},
+	})
+	container.ConfigurationFrom(dic.Get).Service.MaxResultCount = 3
+
+	valueType, aggregates, err := ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(testDeviceName, numericResource, 0, 29, 10, nil, context.Background(), dic)
+	require.NoError(t, err)
+	assert.Equal(t, common.ValueTypeInt16, valueType)
+	require.Len(t, aggregates, 3)
+
+	assert.Equal(t, int64(0), aggregates[0].Start)
+	assert.Equal(t, int64(10), aggregates[0].End)
+	assert.Equal(t, uint32(2), *aggregates[0].Count)
+	assert.Equal(t, float64(1), *aggregates[0].Min)
+	assert.Equal(t, float64(5), *aggregates[0].Max)
+	assert.Equal(t, float64(3), *aggregates[0].Avg)
+	assert.Equal(t, float64(6), *aggregates[0].Sum)
+	assert.Equal(t, float64(1), *aggregates[0].First)
+	assert.Equal(t, float64(5), *aggregates[0].Last)
+
+	assert.Equal(t, int64(10), aggregates[1].Start)
+	assert.Equal(t, float64(10), *aggregates[1].First)
+	assert.Equal(t, float64(30), *aggregates[1].Last)
+	assert.Equal(t, int64(20), aggregates[2].Start)
+	assert.Equal(t, float64(-4), *aggregates[2].Sum)
+
+	_, aggregates, err = ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(testDeviceName, numericResource, 0, 29, 0, []string{pkgCommon.AggregateMax}, context.Background(), dic)
+	require.NoError(t, err)
+	require.Len(t, aggregates, 1)
+	assert.Equal(t, float64(30), *aggregates[0].Max)
+	assert.Nil(t, aggregates[0].Min, "only the requested functions should be returned")
+
+	_, aggregates, err = ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(testDeviceName, numericResource, 0, 29, math.MaxInt64, nil, context.Background(), dic)
+	require.NoError(t, err, "the interval longer than the time range shouldn't overflow")
+	require.Len(t, aggregates, 1)
+	assert.Equal(t, int64(math.MaxInt64), aggregates[0].End)
+	assert.Equal(t, uint32(5), *aggregates[0].Count)
+	assert.Equal(t, int64(math.MaxInt64), (&bucket{start: 10}).toDTO(math.MaxInt64, nil).End, "the bucket end shouldn't overflow")
+
+	for _, r := range [][2]int{{29, 0}, {-1, 29}, {0, math.MaxInt64}, {math.MinInt64, math.MaxInt64 - 1}} {
+		_, _, err = ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(testDeviceName, numericResource, r[0], r[1], 0, nil, context.Background(), dic)
+		require.Error(t, err, "the time range from %d to %d should be rejected", r[0], r[1])
+		assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+	}
+
+	tests := []struct {
+		name         string
+		resourceName string
+		interval     int64
+		functions    []string
+	}{
+		{"Invalid - non-numeric readings", stringResource, 10, nil},
+		{"Invalid - unsupported function", numericResource, 10, []string{"median"}},
+		{"Invalid - too many buckets", numericResource, 1, nil},
+		{"Invalid - empty resource name", "", 10, nil},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, _, err := ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(testDeviceName, testCase.resourceName, 0, 29, testCase.interval, testCase.functions, context.Background(), dic)
+			require.Error(t, err)
+			assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+		})
+	}
+}
@@ -24,7 +24,8 @@ import (
 )
 
 // ValidateEvent validates if e is a valid event with corresponding device profile name and device name and source name
-// ValidateEvent throws error when profileName or deviceName doesn't match to e
+// ValidateEvent throws error when profileName or deviceName doesn't match to e, or when the readings of e don't conform
+// to the device profile under the strict event validation
 func ValidateEvent(e models.Event, profileName string, deviceName string, sourceName string, ctx context.Context, dic *di.Container) errors.EdgeX {
 	if e.ProfileName != profileName {
 		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("event's profileName %s mismatches %s", e.ProfileName, profileName), nil)
@@ -35,7 +36,7 @@ func ValidateEvent(e models.Event, profileName string, deviceName string, source
 	if e.SourceName != sourceName {
 		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("event's sourceName %s mismatches %s", e.SourceName, sourceName), nil)
 	}
-	return nil
+	return validateEventByProfile(e, ctx, dic)
 }
 
 // The AddEvent function accepts the new event model from the controller functions
@@ -43,6 +44,7 @@ func ValidateEvent(e models.Event, profileName string, deviceName string, source
 func AddEvent(e models.Event, ctx context.Context, dic *di.Container) (err errors.EdgeX) {
 	configuration := container.ConfigurationFrom(dic.Get)
 	if !configuration.Writable.PersistData {
+		streamEvent(e, dic)
 		return nil
 	}
 
@@ -65,9 +67,67 @@ func AddEvent(e models.Event, ctx context.Context, dic *di.Container) (err error
 		))
 	}
 
+	streamEvent(e, dic)
 	return nil
 }
 
+// AddEvents validates the events against their device profiles under the strict event validation, and then adds the
+// valid events in a single transaction.  The returned errors correspond to the events, which is nil for an accepted event.
+func AddEvents(events []models.Event, ctx context.Context, dic *di.Container) []errors.EdgeX {
+	configuration := container.ConfigurationFrom(dic.Get)
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	correlationId := correlation.FromContext(ctx)
+
+	errs := make([]errors.EdgeX, len(events))
+	var validEvents []models.Event
+	var validIndexes []int
+	for i, e := range events {
+		if err := validateEventByProfile(e, ctx, dic); err != nil {
+			errs[i] = errors.NewCommonEdgeXWrapper(err)
+			continue
+		}
+		validEvents = append(validEvents, e)
+		validIndexes = append(validIndexes, i)
+	}
+	if len(validEvents) == 0 {
+		return errs
+	}
+
+	if !configuration.Writable.PersistData {
+		for _, e := range validEvents {
+			streamEvent(e, dic)
+		}
+		return errs
+	}
+
+	dbClient := container.DBClientFrom(dic.Get)
+	addedEvents, addErrs := dbClient.AddEvents(validEvents)
+	addedCount := 0
+	for i, index := range validIndexes {
+		if addErrs[i] != nil {
+			errs[index] = errors.NewCommonEdgeXWrapper(addErrs[i])
+			continue
+		}
+		addedCount++
+		streamEvent(addedEvents[i], dic)
+	}
+	lc.Debug(fmt.Sprintf(
+		"%d of %d events created on DB successfully. Correlation-id: %s ",
+		addedCount,
+		len(events),
+		correlationId,
+	))
+
+	return errs
+}
+
+// streamEvent streams the accepted event to the event stream clients when the event stream is enabled
+func streamEvent(e models.Event, dic *di.Container) {
+	if hub := container.EventStreamHubFrom(dic.Get); hub != nil {
+		hub.Publish(e)
+	}
+}
+
 // PublishEvent publishes incoming AddEventRequest in the format of []byte through MessageClient
 func PublishEvent(data []byte, profileName string, deviceName string, sourceName string, ctx context.Context, dic *di.Container) {
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
@@ -79,10 +139,30 @@ func PublishEvent(data []byte, profileName string, deviceName string, sourceName
 	lc.Debug(fmt.Sprintf("Publishing V2 AddEventRequest to message queue. Topic: %s", publishTopic), common.CorrelationHeader, correlationId)
 
 	msgEnvelope := msgTypes.NewMessageEnvelope(data, ctx)
-	err := msgClient.Publish(msgEnvelope, publishTopic)
+	publish := func(envelope msgTypes.MessageEnvelope, topic string) error {
+		err := msgClient.Publish(envelope, topic)
+		if err != nil {
+			lc.Error(fmt.Sprintf("Unable to send message for V2 API event. Correlation-id: %s, Profile Name: %s, "+
+				"Device Name: %s, Source Name: %s, Error: %v", correlationId, profileName, deviceName, sourceName, err))
+		}
+		return err
+	}
+
+	eventOutbox := container.OutboxFrom(dic.Get)
+	if eventOutbox == nil {
+		if publish(msgEnvelope, publishTopic) == nil {
+			lc.Debug(fmt.Sprintf(
+				"V2 API Event Published on message queue. Topic: %s, Correlation-id: %s ", publishTopic, correlationId))
+		}
+		return
+	}
+
+	// the event is queued behind the ones waiting in the outbox to keep the order of events, or when it fails to publish
+	queued, err := eventOutbox.Publish(publishTopic, msgEnvelope, publish)
 	if err != nil {
-		lc.Error(fmt.Sprintf("Unable to send message for V2 API event. Correlation-id: %s, Profile Name: %s, "+
-			"Device Name: %s, Source Name: %s, Error: %v", correlationId, profileName, deviceName, sourceName, err))
+		lc.Error(fmt.Sprintf("Unable to queue V2 API event to the outbox. Correlation-id: %s, Error: %v", correlationId, err))
+	} else if queued {
+		lc.Debug(fmt.Sprintf("V2 API Event queued to the outbox. Topic: %s, Correlation-id: %s ", publishTopic, correlationId))
 	} else {
 		lc.Debug(fmt.Sprintf(
 			"V2 API Event Published on message queue. Topic: %s, Correlation-id: %s ", publishTopic, correlationId))
@@ -14,6 +14,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
 	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces/mocks"
 	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/stream"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
@@ -199,6 +200,9 @@ func TestAddEvent(t *testing.T) {
 	for _, testCase := range tests {
 		t.Run(testCase.Name, func(t *testing.T) {
 			dbClientMock := newMockDB(testCase.Persistence)
+			hub := stream.NewHub(1, 0)
+			subscriber, err := hub.Subscribe(stream.Filter{})
+			require.NoError(t, err)
 
 			dic := mocks.NewMockDIC()
 			dic.Update(di.ServiceConstructorMap{
@@ -212,13 +216,17 @@ func TestAddEvent(t *testing.T) {
 				container.DBClientInterfaceName: func(get di.Get) interface{} {
 					return dbClientMock
 				},
+				container.EventStreamHubName: func(get di.Get) interface{} {
+					return hub
+				},
 			})
-			err := AddEvent(evt, context.Background(), dic)
+			err = AddEvent(evt, context.Background(), dic)
 
 			if testCase.errorExpected {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Len(t, subscriber.Events(), 1, "the accepted event should be streamed")
 			}
 
 			if !testCase.Persistence {
@@ -0,0 +1,51 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/export"
+)
+
+// ExportReadings writes the readings of the device resource within the time range to the writer page by page, in the
+// descending order of origin, so that no more than MaxResultCount readings are held in memory however large the time
+// range is.  The writer is left untouched when the first page fails to be queried.  The count of exported readings is
+// returned.
+func ExportReadings(deviceName string, resourceName string, start int, end int, writer export.Writer, dic *di.Container) (uint32, errors.EdgeX) {
+	if deviceName == "" {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, "device name is empty", nil)
+	}
+	if resourceName == "" {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, "resource name is empty", nil)
+	}
+
+	var exported uint32
+	dbClient := container.DBClientFrom(dic.Get)
+	pageSize := container.ConfigurationFrom(dic.Get).Service.MaxResultCount
+	err := forEachReadingsPage(dbClient, deviceName, resourceName, start, end, pageSize, func(readingModels []models.Reading) errors.EdgeX {
+		readings, err := convertReadingModelsToDTOs(readingModels)
+		if err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		if err = writer.WriteReadings(readings); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		exported += uint32(len(readings))
+		return nil
+	})
+	if err != nil {
+		return exported, errors.NewCommonEdgeXWrapper(err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return exported, errors.NewCommonEdgeXWrapper(err)
+	}
+	return exported, nil
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	dataContainer "github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/outbox"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/telemetry"
+)
+
+// OutboxMetricsName is the name of the outbox metrics reported by the metrics endpoint
+const OutboxMetricsName = "outbox"
+
+// StartOutbox opens the outbox when it's enabled by the configuration, and then forwards the queued events to the
+// message bus in the background until ctx is done.  The outbox is reported as the outbox metrics.
+func StartOutbox(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	info := dataContainer.ConfigurationFrom(dic.Get).Outbox
+	if !info.Enabled {
+		return nil
+	}
+	retryInterval, err := time.ParseDuration(info.RetryInterval)
+	if err != nil || retryInterval <= 0 {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("RetryInterval %s is not a positive duration", info.RetryInterval), err)
+	}
+	maxRetryInterval := retryInterval
+	if info.MaxRetryInterval != "" {
+		maxRetryInterval, err = time.ParseDuration(info.MaxRetryInterval)
+		if err != nil || maxRetryInterval < retryInterval {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("MaxRetryInterval %s is not a duration no less than RetryInterval", info.MaxRetryInterval), err)
+		}
+	}
+
+	lc := container.LoggingClientFrom(dic.Get)
+	serviceMetrics := telemetry.ServiceMetricsFrom(dic.Get)
+	o, edgeXerr := outbox.Open(info.Path, info.MaxSize, lc, func(metrics dataDTOs.OutboxMetrics) {
+		if serviceMetrics != nil {
+			serviceMetrics.Set(OutboxMetricsName, metrics)
+		}
+	})
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	dic.Update(di.ServiceConstructorMap{
+		dataContainer.OutboxName: func(get di.Get) interface{} {
+			return o
+		},
+	})
+
+	publish := func(envelope msgTypes.MessageEnvelope, topic string) error {
+		msgClient := dataContainer.MessagingClientFrom(dic.Get)
+		if msgClient == nil {
+			return fmt.Errorf("message bus client is not available")
+		}
+		return msgClient.Publish(envelope, topic)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		o.Forward(ctx, publish, retryInterval, maxRetryInterval)
+		o.Close()
+		lc.Info("Exiting the outbox")
+	}()
+
+	lc.Infof("Outbox opened at %s with %d queued events", info.Path, o.Depth())
+	return nil
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/config"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/telemetry"
+)
+
+func TestStartOutbox(t *testing.T) {
+	dic := mocks.NewMockDIC()
+	serviceMetrics := telemetry.NewServiceMetrics()
+	dic.Update(di.ServiceConstructorMap{
+		telemetry.ServiceMetricsName: func(get di.Get) interface{} {
+			return serviceMetrics
+		},
+	})
+	configuration := container.ConfigurationFrom(dic.Get)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	require.NoError(t, StartOutbox(ctx, wg, dic))
+	assert.Nil(t, container.OutboxFrom(dic.Get), "the disabled outbox should not be opened")
+
+	tests := []struct {
+		name string
+		info config.OutboxInfo
+	}{
+		{"Invalid - no retry interval", config.OutboxInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "outbox.db")}},
+		{"Invalid - max retry interval less than retry interval", config.OutboxInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "outbox.db"), RetryInterval: "1m", MaxRetryInterval: "1s"}},
+		{"Invalid - no path", config.OutboxInfo{Enabled: true, RetryInterval: "1s"}},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			configuration.Outbox = testCase.info
+			err := StartOutbox(ctx, wg, dic)
+			require.Error(t, err)
+			assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+		})
+	}
+
+	configuration.Outbox = config.OutboxInfo{Enabled: true, Path: filepath.Join(t.TempDir(), "outbox.db"), MaxSize: 10, RetryInterval: "1s"}
+	require.NoError(t, StartOutbox(ctx, wg, dic))
+	require.NotNil(t, container.OutboxFrom(dic.Get))
+	metrics, ok := serviceMetrics.All()[OutboxMetricsName].(dataDTOs.OutboxMetrics)
+	require.True(t, ok, "the outbox metrics should be reported")
+	assert.Zero(t, metrics.Depth)
+	cancel()
+	wg.Wait()
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces"
 )
 
 // ReadingTotalCount return the count of all of readings currently stored in the database and error if any
@@ -216,3 +217,25 @@ func ReadingsByDeviceNameAndResourceNamesAndTimeRange(deviceName string, resourc
 	}
 	return readings, totalCount, nil
 }
+
+// forEachReadingsPage calls handle with each page of the readings of the device resource whose origin is within the
+// time range, in the descending order of origin.  The pages are queried by the cursor of the last reading rather than
+// by offset, so a reading is neither skipped nor repeated when readings are added or removed meanwhile.
+func forEachReadingsPage(dbClient interfaces.DBClient, deviceName string, resourceName string, start int, end int, pageSize int,
+	handle func([]models.Reading) errors.EdgeX) errors.EdgeX {
+	cursorOrigin, cursorId := end, ""
+	for {
+		readings, err := dbClient.ReadingsByDeviceNameAndResourceNameBeforeCursor(deviceName, resourceName, start, cursorOrigin, cursorId, pageSize)
+		if err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		if len(readings) == 0 {
+			return nil
+		}
+		if err = handle(readings); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		last := readings[len(readings)-1].GetBaseReading()
+		cursorOrigin, cursorId = int(last.Origin), last.Id
+	}
+}
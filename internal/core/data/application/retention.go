@@ -0,0 +1,160 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/config"
+	dataContainer "github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/telemetry"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// RetentionMetricsName is the name of the retention metrics reported by the metrics endpoint
+const RetentionMetricsName = "retention"
+
+// retentionPolicy is the parsed config.RetentionInfo
+type retentionPolicy struct {
+	interval time.Duration
+	maxAge   time.Duration
+	maxCount uint32
+	devices  map[string]deviceRetentionPolicy
+}
+
+type deviceRetentionPolicy struct {
+	maxAge   time.Duration
+	maxCount uint32
+}
+
+func parseRetentionPolicy(info config.RetentionInfo) (policy retentionPolicy, edgeXerr errors.EdgeX) {
+	policy.interval, edgeXerr = parseRetentionDuration("Interval", info.Interval)
+	if edgeXerr != nil {
+		return policy, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	if policy.interval == 0 {
+		return policy, errors.NewCommonEdgeX(errors.KindContractInvalid, "retention Interval must be greater than zero", nil)
+	}
+	policy.maxAge, edgeXerr = parseRetentionDuration("MaxAge", info.MaxAge)
+	if edgeXerr != nil {
+		return policy, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	policy.maxCount = info.MaxCount
+
+	policy.devices = make(map[string]deviceRetentionPolicy, len(info.Devices))
+	for deviceName, deviceInfo := range info.Devices {
+		maxAge, edgeXerr := parseRetentionDuration(fmt.Sprintf("MaxAge of device %s", deviceName), deviceInfo.MaxAge)
+		if edgeXerr != nil {
+			return policy, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		policy.devices[deviceName] = deviceRetentionPolicy{maxAge: maxAge, maxCount: deviceInfo.MaxCount}
+	}
+	return policy, nil
+}
+
+// parseRetentionDuration parses the duration string, and an empty string means zero
+func parseRetentionDuration(name string, value string) (time.Duration, errors.EdgeX) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("failed to parse retention %s %s", name, value), err)
+	}
+	if d < 0 {
+		return 0, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("retention %s %s must not be negative", name, value), nil)
+	}
+	return d, nil
+}
+
+// purgeEvents applies the retention policy once and returns the number of purged events.  The devices with their own
+// policy are excluded from the service-wide policy, and a policy without any limit keeps the events forever.
+func purgeEvents(dbClient interfaces.DBClient, policy retentionPolicy) (purged uint32, edgeXerr errors.EdgeX) {
+	deviceNames := make([]string, 0, len(policy.devices))
+	for deviceName := range policy.devices {
+		deviceNames = append(deviceNames, deviceName)
+	}
+	sort.Strings(deviceNames)
+
+	if policy.maxAge > 0 || policy.maxCount > 0 {
+		purged, edgeXerr = dbClient.PruneEvents(int64(policy.maxAge), policy.maxCount, deviceNames)
+		if edgeXerr != nil {
+			return purged, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+	}
+	for _, deviceName := range deviceNames {
+		devicePolicy := policy.devices[deviceName]
+		if devicePolicy.maxAge == 0 && devicePolicy.maxCount == 0 {
+			continue
+		}
+		count, edgeXerr := dbClient.PruneEventsByDeviceName(deviceName, int64(devicePolicy.maxAge), devicePolicy.maxCount)
+		if edgeXerr != nil {
+			return purged, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		purged += count
+	}
+	return purged, nil
+}
+
+// StartRetention validates the retention policy of the configuration and, when the policy is enabled, starts applying
+// the policy every interval in the background until ctx is done.  The runs are reported as the retention metrics.
+func StartRetention(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	info := dataContainer.ConfigurationFrom(dic.Get).Retention
+	if !info.Enabled {
+		return nil
+	}
+	policy, edgeXerr := parseRetentionPolicy(info)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeX(errors.Kind(edgeXerr), "invalid retention policy", edgeXerr)
+	}
+
+	lc := container.LoggingClientFrom(dic.Get)
+	dbClient := dataContainer.DBClientFrom(dic.Get)
+	serviceMetrics := telemetry.ServiceMetricsFrom(dic.Get)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(policy.interval)
+		defer ticker.Stop()
+		metrics := dataDTOs.RetentionMetrics{}
+		for {
+			select {
+			case <-ctx.Done():
+				lc.Info("Exiting the retention policy")
+				return
+			case <-ticker.C:
+				purged, err := purgeEvents(dbClient, policy)
+				metrics.LastRun = time.Now().UnixNano()
+				metrics.PurgedEvents = purged
+				metrics.TotalPurgedEvents += uint64(purged)
+				metrics.LastError = ""
+				if err != nil {
+					metrics.LastError = err.Error()
+					lc.Errorf("fail to apply the retention policy, %v", err)
+				} else {
+					lc.Debugf("%d events purged by the retention policy", purged)
+				}
+				if serviceMetrics != nil {
+					serviceMetrics.Set(RetentionMetricsName, metrics)
+				}
+			}
+		}
+	}()
+
+	lc.Infof("Retention policy applied every %s", policy.interval)
+	return nil
+}
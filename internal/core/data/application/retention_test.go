@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/config"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/telemetry"
+)
+
+func TestParseRetentionPolicy(t *testing.T) {
+	valid := config.RetentionInfo{
+		Interval: "10m",
+		MaxAge:   "24h",
+		MaxCount: 100,
+		Devices:  map[string]config.DeviceRetentionInfo{testDeviceName: {MaxCount: 10}},
+	}
+	policy, err := parseRetentionPolicy(valid)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, policy.interval)
+	assert.Equal(t, 24*time.Hour, policy.maxAge)
+	assert.Equal(t, uint32(100), policy.maxCount)
+	assert.Equal(t, deviceRetentionPolicy{maxCount: 10}, policy.devices[testDeviceName])
+
+	tests := []struct {
+		name string
+		info config.RetentionInfo
+	}{
+		{"Invalid - no interval", config.RetentionInfo{MaxAge: "24h"}},
+		{"Invalid - malformed max age", config.RetentionInfo{Interval: "10m", MaxAge: "one day"}},
+		{"Invalid - negative max age", config.RetentionInfo{Interval: "10m", MaxAge: "-1h"}},
+		{"Invalid - malformed device max age", config.RetentionInfo{Interval: "10m", Devices: map[string]config.DeviceRetentionInfo{testDeviceName: {MaxAge: "1"}}}},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseRetentionPolicy(testCase.info)
+			require.Error(t, err)
+			assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+		})
+	}
+}
+
+func TestPurgeEvents(t *testing.T) {
+	keptDevice := "kept-device"
+	policy := retentionPolicy{
+		maxAge:   time.Hour,
+		maxCount: 100,
+		devices: map[string]deviceRetentionPolicy{
+			testDeviceName: {maxCount: 10},
+			keptDevice:     {},
+		},
+	}
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("PruneEvents", int64(time.Hour), uint32(100), []string{testDeviceName, keptDevice}).Return(uint32(3), nil)
+	dbClientMock.On("PruneEventsByDeviceName", testDeviceName, int64(0), uint32(10)).Return(uint32(2), nil)
+
+	purged, err := purgeEvents(dbClientMock, policy)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(5), purged)
+	dbClientMock.AssertNotCalled(t, "PruneEventsByDeviceName", keptDevice, int64(0), uint32(0))
+
+	dbClientMock = &dbMock.DBClient{}
+	dbClientMock.On("PruneEvents", int64(time.Hour), uint32(100), []string{testDeviceName, keptDevice}).Return(uint32(0), errors.NewCommonEdgeX(errors.KindDatabaseError, "prune failed", nil))
+	_, err = purgeEvents(dbClientMock, policy)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindDatabaseError, errors.Kind(err))
+}
+
+func TestStartRetention(t *testing.T) {
+	dic := mocks.NewMockDIC()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("PruneEvents", int64(0), uint32(10), []string{}).Return(uint32(4), nil)
+	serviceMetrics := telemetry.NewServiceMetrics()
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+		telemetry.ServiceMetricsName: func(get di.Get) interface{} {
+			return serviceMetrics
+		},
+	})
+	configuration := container.ConfigurationFrom(dic.Get)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	configuration.Retention = config.RetentionInfo{Enabled: true, MaxCount: 10}
+	err := StartRetention(ctx, wg, dic)
+	require.Error(t, err, "an enabled retention policy requires an interval")
+
+	configuration.Retention.Interval = "10ms"
+	err = StartRetention(ctx, wg, dic)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		metrics, ok := serviceMetrics.All()[RetentionMetricsName].(dataDTOs.RetentionMetrics)
+		return ok && metrics.TotalPurgedEvents >= 8
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	metrics := serviceMetrics.All()[RetentionMetricsName].(dataDTOs.RetentionMetrics)
+	assert.Equal(t, uint32(4), metrics.PurgedEvents)
+	assert.NotZero(t, metrics.LastRun)
+	assert.Empty(t, metrics.LastError)
+}
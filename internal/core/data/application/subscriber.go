@@ -67,7 +67,13 @@ func SubscribeEvents(ctx context.Context, dic *di.Container) errors.EdgeX {
 					lc.Error(err.Error())
 					break
 				}
-				err = AddEvent(requests.AddEventReqToEventModel(*event), ctx, dic)
+				eventModel := requests.AddEventReqToEventModel(*event)
+				err = validateEventByProfile(eventModel, ctx, dic)
+				if err != nil {
+					lc.Error(err.Error())
+					break
+				}
+				err = AddEvent(eventModel, ctx, dic)
 				if err != nil {
 					lc.Errorf("fail to persist the event, %v", err)
 				}
@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+)
+
+// validateEventByProfile validates the readings of e against the device resources of its device profile when the
+// strict event validation is enabled.  A reading is rejected when its resource isn't defined by the profile, its value
+// type mismatches the resource, or its numeric value is out of the minimum and maximum of the resource.
+func validateEventByProfile(e models.Event, ctx context.Context, dic *di.Container) errors.EdgeX {
+	if !container.ConfigurationFrom(dic.Get).EventValidation.Strict {
+		return nil
+	}
+
+	profile, err := deviceProfileByName(e.ProfileName, ctx, dic)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	resources := make(map[string]dtos.DeviceResource, len(profile.DeviceResources))
+	for _, r := range profile.DeviceResources {
+		resources[r.Name] = r
+	}
+	for _, r := range e.Readings {
+		resource, ok := resources[r.GetBaseReading().ResourceName]
+		if !ok {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid,
+				fmt.Sprintf("reading's resourceName %s is not defined in device profile %s", r.GetBaseReading().ResourceName, profile.Name), nil)
+		}
+		if err = validateReadingByResource(r, resource); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+	}
+	return nil
+}
+
+// deviceProfileByName returns the device profile from the cache, or queries it from core-metadata and caches it
+func deviceProfileByName(name string, ctx context.Context, dic *di.Container) (dtos.DeviceProfile, errors.EdgeX) {
+	profileCache := container.DeviceProfileCacheFrom(dic.Get)
+	if profileCache != nil {
+		if profile, ok := profileCache.Get(name); ok {
+			return profile.(dtos.DeviceProfile), nil
+		}
+	}
+
+	dpc := bootstrapContainer.MetadataDeviceProfileClientFrom(dic.Get)
+	if dpc == nil {
+		return dtos.DeviceProfile{}, errors.NewCommonEdgeX(errors.KindServerError, "nil MetadataDeviceProfileClient returned", nil)
+	}
+	res, err := dpc.DeviceProfileByName(ctx, name)
+	if err != nil {
+		if errors.Kind(err) == errors.KindEntityDoesNotExist {
+			return dtos.DeviceProfile{}, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("event's device profile %s doesn't exist", name), err)
+		}
+		return dtos.DeviceProfile{}, errors.NewCommonEdgeX(errors.Kind(err), fmt.Sprintf("failed to query device profile %s", name), err)
+	}
+	if profileCache != nil {
+		profileCache.Set(name, res.Profile)
+	}
+	return res.Profile, nil
+}
+
+// validateReadingByResource validates the value type of the reading and, for a numeric resource with the minimum or
+// maximum defined, the range of the reading value.  Malformed minimum or maximum of the resource are ignored.
+func validateReadingByResource(r models.Reading, resource dtos.DeviceResource) errors.EdgeX {
+	base := r.GetBaseReading()
+	properties := resource.Properties
+	if !strings.EqualFold(base.ValueType, properties.ValueType) {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("reading's valueType %s mismatches %s defined by resource %s", base.ValueType, properties.ValueType, resource.Name), nil)
+	}
+	if (properties.Minimum == "" && properties.Maximum == "") || !isNumericValueType(properties.ValueType) {
+		return nil
+	}
+
+	value, err := numericReadingValue(r, properties.ValueType)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	if minimum, parseErr := strconv.ParseFloat(properties.Minimum, 64); parseErr == nil && value < minimum {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("reading value %v of resource %s is less than the minimum %s", value, resource.Name, properties.Minimum), nil)
+	}
+	if maximum, parseErr := strconv.ParseFloat(properties.Maximum, 64); parseErr == nil && value > maximum {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("reading value %v of resource %s is greater than the maximum %s", value, resource.Name, properties.Maximum), nil)
+	}
+	return nil
+}
+
+func isNumericValueType(valueType string) bool {
+	switch valueType {
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64,
+		common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64,
+		common.ValueTypeFloat32, common.ValueTypeFloat64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	clientMocks "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+)
+
+func TestValidateEventStrict(t *testing.T) {
+	numericResource := "Temperature"
+	stringResource := "Status"
+	profile := dtos.DeviceProfile{
+		Name: testProfileName,
+		DeviceResources: []dtos.DeviceResource{
+			{Name: numericResource, Properties: dtos.ResourceProperties{ValueType: common.ValueTypeInt16, Minimum: "-10", Maximum: "50"}},
+			{Name: stringResource, Properties: dtos.ResourceProperties{ValueType: common.ValueTypeString}},
+		},
+	}
+	unknownProfileName := "UnknownProfile"
+
+	dic := mocks.NewMockDIC()
+	dpcMock := &clientMocks.DeviceProfileClient{}
+	dpcMock.On("DeviceProfileByName", mock.Anything, testProfileName).Return(responses.DeviceProfileResponse{Profile: profile}, nil)
+	dpcMock.On("DeviceProfileByName", mock.Anything, unknownProfileName).Return(responses.DeviceProfileResponse{},
+		errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "profile doesn't exist", nil))
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.MetadataDeviceProfileClientName: func(get di.Get) interface{} {
+			return dpcMock
+		},
+		container.DeviceProfileCacheName: func(get di.Get) interface{} {
+			return cache.NewTTLCache(time.Minute)
+		},
+	})
+	container.ConfigurationFrom(dic.Get).EventValidation.Strict = true
+
+	newEvent := func(profileName string, readings ...models.Reading) models.Event {
+		return models.Event{
+			Id:          testUUIDString,
+			DeviceName:  testDeviceName,
+			ProfileName: profileName,
+			SourceName:  testSourceName,
+			Origin:      testOriginTime,
+			Readings:    readings,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		event         models.Event
+		errorExpected bool
+	}{
+		{"Valid - readings conform to profile", newEvent(testProfileName, simpleReading(numericResource, 1, common.ValueTypeInt16, "50"), simpleReading(stringResource, 1, common.ValueTypeString, "OK")), false},
+		{"Valid - value type in different case", newEvent(testProfileName, simpleReading(numericResource, 1, "int16", "-10")), false},
+		{"Invalid - undefined resource", newEvent(testProfileName, simpleReading("Humidity", 1, common.ValueTypeInt16, "1")), true},
+		{"Invalid - mismatched value type", newEvent(testProfileName, simpleReading(numericResource, 1, common.ValueTypeFloat32, "1.5")), true},
+		{"Invalid - unparsable value", newEvent(testProfileName, simpleReading(numericResource, 1, common.ValueTypeInt16, "hot")), true},
+		{"Invalid - less than minimum", newEvent(testProfileName, simpleReading(numericResource, 1, common.ValueTypeInt16, "-11")), true},
+		{"Invalid - greater than maximum", newEvent(testProfileName, simpleReading(numericResource, 1, common.ValueTypeInt16, "51")), true},
+		{"Invalid - nonexistent profile", newEvent(unknownProfileName, simpleReading(numericResource, 1, common.ValueTypeInt16, "1")), true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateEvent(testCase.event, testCase.event.ProfileName, testDeviceName, testSourceName, context.Background(), dic)
+			if testCase.errorExpected {
+				require.Error(t, err)
+				assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+	// the existing profile should be queried only once and then served from the cache
+	dpcMock.AssertNumberOfCalls(t, "DeviceProfileByName", 2)
+}
@@ -19,13 +19,19 @@ import (
 )
 
 type ConfigurationStruct struct {
-	Writable     WritableInfo
-	MessageQueue bootstrapConfig.MessageBusInfo
-	Clients      map[string]bootstrapConfig.ClientInfo
-	Databases    map[string]bootstrapConfig.Database
-	Registry     bootstrapConfig.RegistryInfo
-	Service      bootstrapConfig.ServiceInfo
-	SecretStore  bootstrapConfig.SecretStoreInfo
+	Writable        WritableInfo
+	MessageQueue    bootstrapConfig.MessageBusInfo
+	Clients         map[string]bootstrapConfig.ClientInfo
+	Databases       map[string]bootstrapConfig.Database
+	Postgres        PostgresInfo
+	Registry        bootstrapConfig.RegistryInfo
+	Service         bootstrapConfig.ServiceInfo
+	SecretStore     bootstrapConfig.SecretStoreInfo
+	Retention       RetentionInfo
+	EventValidation EventValidationInfo
+	EventStream     EventStreamInfo
+	Outbox          OutboxInfo
+	EventSize       EventSizeInfo
 }
 
 type WritableInfo struct {
@@ -34,6 +40,83 @@ type WritableInfo struct {
 	InsecureSecrets bootstrapConfig.InsecureSecrets
 }
 
+// PostgresInfo defines the settings of the connections to PostgreSQL, which apply when the primary database is postgres
+type PostgresInfo struct {
+	// SSLMode is the sslmode of the connections, i.e. disable, require, verify-ca or verify-full, empty means require
+	SSLMode string
+}
+
+// RetentionInfo defines the policy which purges the persisted events and their readings periodically
+type RetentionInfo struct {
+	// Enabled indicates whether the retention policy is applied
+	Enabled bool
+	// Interval is the duration string between two runs of the retention policy, e.g. "10m"
+	Interval string
+	// MaxAge is the duration string beyond which events are purged, empty means no age limit
+	MaxAge string
+	// MaxCount is the number of the newest events to keep, 0 means no count limit
+	MaxCount uint32
+	// Devices overrides the policy for specific devices keyed by device name.  The override replaces the policy above for
+	// the device, so the events of the device are neither counted nor purged by the policy above.
+	Devices map[string]DeviceRetentionInfo
+}
+
+// DeviceRetentionInfo defines the retention policy of a specific device
+type DeviceRetentionInfo struct {
+	// MaxAge is the duration string beyond which events of the device are purged, empty means no age limit
+	MaxAge string
+	// MaxCount is the number of the newest events of the device to keep, 0 means no count limit
+	MaxCount uint32
+}
+
+// EventValidationInfo defines how the incoming events are validated against their device profile
+type EventValidationInfo struct {
+	// Strict enables rejecting the events whose readings don't conform to the device resources of the device profile
+	Strict bool
+	// ProfileCacheTTL is the duration string to cache the device profiles retrieved from core-metadata, e.g. "1m"
+	ProfileCacheTTL string
+}
+
+// EventStreamInfo defines the live stream of the events accepted by core-data
+type EventStreamInfo struct {
+	// Enabled indicates whether the event stream endpoint accepts clients
+	Enabled bool
+	// BufferSize is the number of events buffered for each client, the oldest event is dropped when the buffer is full
+	BufferSize int
+	// MaxClients is the maximum number of the connected clients, 0 means no limit
+	MaxClients int
+}
+
+// OutboxInfo defines the store-and-forward outbox of the events failed to publish to the message bus
+type OutboxInfo struct {
+	// Enabled indicates whether the events failed to publish are queued and retried instead of being dropped
+	Enabled bool
+	// Path is the path of the file storing the queued events.  Its directory is created at startup and must be writable
+	// by the service, e.g. a volume mounted in the container, otherwise core-data fails to start.
+	Path string
+	// MaxSize is the maximum number of the queued events, the oldest event is dropped when the outbox is full.  0 means no
+	// limit.
+	MaxSize int
+	// RetryInterval is the duration string to wait before the first retry, e.g. "1s", which is doubled after each failed
+	// retry up to MaxRetryInterval
+	RetryInterval string
+	// MaxRetryInterval is the duration string of the longest wait between two retries, e.g. "1m"
+	MaxRetryInterval string
+}
+
+// EventSizeInfo defines the limits on the size of the AddEventRequests received by the REST APIs
+type EventSizeInfo struct {
+	// MaxJSON is the maximum size in kilobytes of the JSON encoded AddEventRequest, or the array of them for the batch
+	// API, 0 means the default 25MB and -1 means no limit
+	MaxJSON int64
+	// MaxCBOR is the maximum size in kilobytes of the CBOR encoded AddEventRequest, or the array of them for the batch
+	// API, 0 means the default 25MB and -1 means no limit
+	MaxCBOR int64
+	// MaxInFlight is the maximum total size in kilobytes of the AddEventRequests being processed concurrently, 0 means no
+	// limit.  A request waits for the others to complete when the limit would be exceeded.
+	MaxInFlight int64
+}
+
 // UpdateFromRaw converts configuration received from the registry to a service-specific configuration struct which is
 // then used to overwrite the service's existing configuration struct.
 func (c *ConfigurationStruct) UpdateFromRaw(rawConfig interface{}) bool {
@@ -93,6 +176,11 @@ func (c *ConfigurationStruct) GetDatabaseInfo() map[string]bootstrapConfig.Datab
 	return c.Databases
 }
 
+// GetPostgresSSLMode returns the sslmode of the connections to PostgreSQL
+func (c *ConfigurationStruct) GetPostgresSSLMode() string {
+	return c.Postgres.SSLMode
+}
+
 // GetInsecureSecrets returns the service's InsecureSecrets.
 func (c *ConfigurationStruct) GetInsecureSecrets() bootstrapConfig.InsecureSecrets {
 	return c.Writable.InsecureSecrets
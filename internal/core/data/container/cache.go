@@ -0,0 +1,25 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// DeviceProfileCacheName contains the name of the cache of the device profiles retrieved from core-metadata in the DIC.
+var DeviceProfileCacheName = "DeviceProfileCache"
+
+// DeviceProfileCacheFrom helper function queries the DIC and returns the cache of the device profiles.
+func DeviceProfileCacheFrom(get di.Get) *cache.TTLCache {
+	c, ok := get(DeviceProfileCacheName).(*cache.TTLCache)
+	if !ok {
+		return nil
+	}
+
+	return c
+}
@@ -0,0 +1,26 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/core/data/outbox"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// OutboxName contains the name of the outbox.Outbox implementation in the DIC.
+var OutboxName = di.TypeInstanceToName((*outbox.Outbox)(nil))
+
+// OutboxFrom helper function queries the DIC and returns the outbox.Outbox implementation, or nil when the outbox is
+// disabled.
+func OutboxFrom(get di.Get) *outbox.Outbox {
+	o, ok := get(OutboxName).(*outbox.Outbox)
+	if !ok {
+		return nil
+	}
+
+	return o
+}
@@ -0,0 +1,26 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/core/data/stream"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// EventStreamHubName contains the name of the stream.Hub implementation in the DIC.
+var EventStreamHubName = di.TypeInstanceToName((*stream.Hub)(nil))
+
+// EventStreamHubFrom helper function queries the DIC and returns the stream.Hub implementation, or nil when the event
+// stream is disabled.
+func EventStreamHubFrom(get di.Get) *stream.Hub {
+	hub, ok := get(EventStreamHubName).(*stream.Hub)
+	if !ok {
+		return nil
+	}
+
+	return hub
+}
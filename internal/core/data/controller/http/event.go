@@ -1,17 +1,16 @@
 package http
 
 import (
-	"bytes"
 	"math"
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/application"
 	dataContainer "github.com/edgexfoundry/edgex-go/internal/core/data/container"
 	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -20,38 +19,90 @@ import (
 	requestDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	responseDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 
 	"github.com/gorilla/mux"
 )
 
+const kilobyte = 1024
+
 type EventController struct {
-	readers map[string]io.DtoReader
-	mux     sync.RWMutex
-	dic     *di.Container
+	dic    *di.Container
+	budget *io.MemoryBudget
 }
 
 // NewEventController creates and initializes an EventController
 func NewEventController(dic *di.Container) *EventController {
-	return &EventController{
-		readers: make(map[string]io.DtoReader),
-		dic:     dic,
+	ec := &EventController{
+		dic: dic,
+	}
+	if maxInFlight := dataContainer.ConfigurationFrom(dic.Get).EventSize.MaxInFlight; maxInFlight > 0 {
+		ec.budget = io.NewMemoryBudget(maxInFlight * kilobyte)
+	}
+	return ec
+}
+
+// maxEventSize converts the maximum event size in kilobytes of the configuration into bytes, where 0 means the default
+// size and a negative value means no limit
+func maxEventSize(kilobytes int64) int64 {
+	switch {
+	case kilobytes == 0:
+		return io.DefaultMaxEventSize
+	case kilobytes < 0:
+		return -1
 	}
+	return kilobytes * kilobyte
 }
 
-func (ec *EventController) getReader(r *http.Request) io.DtoReader {
-	contentType := strings.ToLower(r.Header.Get(common.ContentType))
-	ec.mux.RLock()
-	reader, ok := ec.readers[contentType]
-	ec.mux.RUnlock()
-	if ok {
-		return reader
+// reserveAddEventRequest validates the Content-Length of the request against the maximum event size of the content
+// type, and reserves the memory of the request from the memory budget.  The maximum event size in bytes is returned
+// along with the release function, which must be called both once the request is handled and once the data is
+// published, to release the memory reserved for the request.
+func (ec *EventController) reserveAddEventRequest(r *http.Request) (int64, func(), errors.EdgeX) {
+	eventSize := dataContainer.ConfigurationFrom(ec.dic.Get).EventSize
+	maxSize := maxEventSize(eventSize.MaxJSON)
+	if strings.ToLower(r.Header.Get(common.ContentType)) == common.ContentTypeCBOR {
+		maxSize = maxEventSize(eventSize.MaxCBOR)
+	}
+	if err := io.ValidateEventSize(r.ContentLength, maxSize); err != nil {
+		return 0, nil, err
 	}
 
-	ec.mux.Lock()
-	defer ec.mux.Unlock()
-	reader = io.NewDtoReader(contentType)
-	ec.readers[contentType] = reader
-	return reader
+	release := func() {}
+	if ec.budget != nil {
+		// reserve the maximum event size when the size of the request is unknown, or the whole budget without a limit
+		reserved := r.ContentLength
+		if reserved < 0 {
+			reserved = maxSize
+			if maxSize < 0 {
+				reserved = math.MaxInt64
+			}
+		}
+		var err errors.EdgeX
+		release, err = ec.budget.Acquire(r.Context(), reserved, 2)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return maxSize, release, nil
+}
+
+// readAddEventRequest reads the encoded AddEventRequest, or the array of them, from the request body within the
+// maximum event size of the content type and the memory budget.  The returned release function must be called both
+// once the request is handled and once the data is published, to release the memory reserved for the request.
+func (ec *EventController) readAddEventRequest(r *http.Request) ([]byte, func(), errors.EdgeX) {
+	maxSize, release, err := ec.reserveAddEventRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := io.ReadAddEventRequestInBytes(r.Body, r.ContentLength, maxSize)
+	if err != nil {
+		release()
+		release()
+		return nil, nil, err
+	}
+	return data, release, nil
 }
 
 func (ec *EventController) AddEvent(w http.ResponseWriter, r *http.Request) {
@@ -71,15 +122,17 @@ func (ec *EventController) AddEvent(w http.ResponseWriter, r *http.Request) {
 	sourceName := vars[common.SourceName]
 
 	var addEventReqDTO requestDTO.AddEventRequest
-
-	dataBytes, err := io.ReadAddEventRequestInBytes(r.Body)
+	dataBytes, release, err := ec.readAddEventRequest(r)
 	if err == nil {
+		defer release()
 		// Per https://github.com/edgexfoundry/edgex-go/pull/3202#discussion_r587618347
 		// V2 shall asynchronously publish initially encoded payload (not re-encoding) to message bus
-		go application.PublishEvent(dataBytes, profileName, deviceName, sourceName, ctx, ec.dic)
+		go func() {
+			defer release()
+			application.PublishEvent(dataBytes, profileName, deviceName, sourceName, ctx, ec.dic)
+		}()
 		// unmarshal bytes to AddEventRequest
-		reader := ec.getReader(r)
-		err = reader.Read(bytes.NewReader(dataBytes), &addEventReqDTO)
+		err = io.DecodeAddEventRequest(dataBytes, r.Header.Get(common.ContentType), &addEventReqDTO)
 	}
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
@@ -102,6 +155,73 @@ func (ec *EventController) AddEvent(w http.ResponseWriter, r *http.Request) {
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
 
+// AddEvents adds the events of the JSON or CBOR encoded array of AddEventRequests in a single transaction, publishes
+// each accepted AddEventRequest as it's encoded, and responds with the status of each AddEventRequest
+func (ec *EventController) AddEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer func() { _ = r.Body.Close() }()
+	}
+
+	lc := container.LoggingClientFrom(ec.dic.Get)
+	ctx := r.Context()
+	correlationId := correlation.FromContext(ctx)
+
+	dataBytes, release, err := ec.readAddEventRequest(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	defer release()
+	items, err := io.SplitAddEventRequests(dataBytes, r.Header.Get(common.ContentType))
+	if err != nil {
+		release()
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	addResponses := make([]interface{}, len(items))
+	reqDTOs := make([]requestDTO.AddEventRequest, len(items))
+	var events []models.Event
+	var eventIndexes []int
+	for i, item := range items {
+		err = io.DecodeAddEventRequest(item, r.Header.Get(common.ContentType), &reqDTOs[i])
+		if err != nil {
+			lc.Error(err.Error(), common.CorrelationHeader, correlationId)
+			lc.Debug(err.DebugMessages(), common.CorrelationHeader, correlationId)
+			addResponses[i] = commonDTO.NewBaseResponse("", err.Message(), err.Code())
+			continue
+		}
+		events = append(events, requestDTO.AddEventReqToEventModel(reqDTOs[i]))
+		eventIndexes = append(eventIndexes, i)
+	}
+
+	var acceptedIndexes []int
+	errs := application.AddEvents(events, ctx, ec.dic)
+	for i, index := range eventIndexes {
+		reqId := reqDTOs[index].RequestId
+		if errs[i] != nil {
+			lc.Error(errs[i].Error(), common.CorrelationHeader, correlationId)
+			lc.Debug(errs[i].DebugMessages(), common.CorrelationHeader, correlationId)
+			addResponses[index] = commonDTO.NewBaseResponse(reqId, errs[i].Message(), errs[i].Code())
+			continue
+		}
+		addResponses[index] = commonDTO.NewBaseWithIdResponse(reqId, "", http.StatusCreated, events[i].Id)
+		acceptedIndexes = append(acceptedIndexes, i)
+	}
+
+	// publish the initially encoded AddEventRequests in order, as the single AddEvent API does
+	go func() {
+		defer release()
+		for _, i := range acceptedIndexes {
+			e := events[i]
+			application.PublishEvent(items[eventIndexes[i]], e.ProfileName, e.DeviceName, e.SourceName, ctx, ec.dic)
+		}
+	}()
+
+	utils.WriteHttpHeader(w, ctx, http.StatusMultiStatus)
+	pkg.EncodeAndWriteResponse(addResponses, w, lc)
+}
+
 func (ec *EventController) EventById(w http.ResponseWriter, r *http.Request) {
 	// retrieve all the service injections from bootstrap
 	lc := container.LoggingClientFrom(ec.dic.Get)
@@ -6,17 +6,21 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/config"
 	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
 	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces/mocks"
 	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/io"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
@@ -29,6 +33,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -266,6 +271,169 @@ func TestAddEvent(t *testing.T) {
 	}
 }
 
+func TestAddEventSizeLimit(t *testing.T) {
+	dic := mocks.NewMockDIC()
+	dic.Update(di.ServiceConstructorMap{
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				EventSize: config.EventSizeInfo{MaxJSON: 1, MaxCBOR: 1, MaxInFlight: 2},
+			}
+		},
+	})
+	ec := NewEventController(dic)
+
+	largeRequest := testAddEvent
+	largeRequest.Event.Tags = map[string]interface{}{"padding": strings.Repeat("x", 2*kilobyte)}
+
+	tests := []struct {
+		Name               string
+		RequestContentType string
+		UnknownLength      bool
+	}{
+		{"Invalid - JSON exceeds the limit", common.ContentTypeJSON, false},
+		{"Invalid - CBOR exceeds the limit", common.ContentTypeCBOR, false},
+		{"Invalid - JSON of unknown length exceeds the limit", common.ContentTypeJSON, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.Name, func(t *testing.T) {
+			byteData, err := toByteArray(testCase.RequestContentType, largeRequest)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, common.ApiEventProfileNameDeviceNameSourceNameRoute, bytes.NewReader(byteData))
+			require.NoError(t, err)
+			if testCase.UnknownLength {
+				req.ContentLength = -1
+			}
+			req.Header.Set(common.ContentType, testCase.RequestContentType)
+			req = mux.SetURLVars(req, map[string]string{common.ProfileName: TestDeviceProfileName, common.DeviceName: TestDeviceName, common.SourceName: TestSourceName})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(ec.AddEvent).ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Result().StatusCode, "HTTP status code not as expected")
+			assert.Zero(t, ec.budget.Used(), "the memory should be released")
+		})
+	}
+}
+
+func TestAddEventMemoryBudget(t *testing.T) {
+	dic := mocks.NewMockDIC()
+	dic.Update(di.ServiceConstructorMap{
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				Writable:  config.WritableInfo{PersistData: false},
+				EventSize: config.EventSizeInfo{MaxJSON: 4, MaxCBOR: 4, MaxInFlight: 8},
+			}
+		},
+	})
+	ec := NewEventController(dic)
+
+	largeRequest := testAddEvent
+	largeRequest.Event.Tags = map[string]interface{}{"padding": strings.Repeat("x", 2*kilobyte)}
+	tooLargeRequest := testAddEvent
+	tooLargeRequest.Event.Tags = map[string]interface{}{"padding": strings.Repeat("x", 5*kilobyte)}
+
+	tests := []struct {
+		Name               string
+		Request            requests.AddEventRequest
+		RequestContentType string
+		UnknownLength      bool
+		ExpectedStatusCode int
+	}{
+		{"Valid - large JSON", largeRequest, common.ContentTypeJSON, false, http.StatusCreated},
+		{"Valid - large CBOR", largeRequest, common.ContentTypeCBOR, false, http.StatusCreated},
+		{"Valid - JSON of unknown length", testAddEvent, common.ContentTypeJSON, true, http.StatusCreated},
+		{"Invalid - JSON of unknown length exceeds the limit", tooLargeRequest, common.ContentTypeJSON, true, http.StatusRequestEntityTooLarge},
+		{"Invalid - CBOR of unknown length exceeds the limit", tooLargeRequest, common.ContentTypeCBOR, true, http.StatusRequestEntityTooLarge},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.Name, func(t *testing.T) {
+			byteData, err := toByteArray(testCase.RequestContentType, testCase.Request)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, common.ApiEventProfileNameDeviceNameSourceNameRoute, bytes.NewReader(byteData))
+			require.NoError(t, err)
+			if testCase.UnknownLength {
+				req.ContentLength = -1
+			}
+			req.Header.Set(common.ContentType, testCase.RequestContentType)
+			req = mux.SetURLVars(req, map[string]string{common.ProfileName: TestDeviceProfileName, common.DeviceName: TestDeviceName, common.SourceName: TestSourceName})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(ec.AddEvent).ServeHTTP(recorder, req)
+			assert.Equal(t, testCase.ExpectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			assert.Eventually(t, func() bool { return ec.budget.Used() == 0 }, time.Second, time.Millisecond, "the memory should be released once the event is published")
+		})
+	}
+}
+
+func TestMaxEventSize(t *testing.T) {
+	assert.Equal(t, io.DefaultMaxEventSize, maxEventSize(0), "the default size should apply when no limit is configured")
+	assert.Equal(t, int64(-1), maxEventSize(-1), "-1 should mean no limit")
+	assert.Equal(t, int64(2*kilobyte), maxEventSize(2))
+}
+
+func TestAddEvents(t *testing.T) {
+	duplicateRequest := testAddEvent
+	duplicateRequest.Event.Id = uuid.New().String()
+	invalidRequest := testAddEvent
+	invalidRequest.Event.DeviceName = ""
+
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("AddEvents", mock.MatchedBy(func(events []models.Event) bool {
+		return len(events) == 2 && events[0].Id == testAddEvent.Event.Id && events[1].Id == duplicateRequest.Event.Id
+	})).Return(
+		[]models.Event{persistedEvent, {}},
+		[]errors.EdgeX{nil, errors.NewCommonEdgeX(errors.KindDuplicateName, "Event Id exists", nil)},
+	)
+	dic := mocks.NewMockDIC()
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	ec := NewEventController(dic)
+
+	tests := []struct {
+		Name               string
+		RequestContentType string
+	}{
+		{"Valid - AddEventRequest array JSON", common.ContentTypeJSON},
+		{"Valid - AddEventRequest array CBOR", common.ContentTypeCBOR},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.Name, func(t *testing.T) {
+			byteData, err := toByteArray(testCase.RequestContentType, []requests.AddEventRequest{testAddEvent, invalidRequest, duplicateRequest})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiEventBatchRoute, strings.NewReader(string(byteData)))
+			require.NoError(t, err)
+			req.Header.Set(common.ContentType, testCase.RequestContentType)
+
+			recorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(ec.AddEvents)
+			handler.ServeHTTP(recorder, req)
+
+			var actualResponses []commonDTO.BaseWithIdResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &actualResponses)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusMultiStatus, recorder.Result().StatusCode, "HTTP status code not as expected")
+			require.Len(t, actualResponses, 3)
+			assert.Equal(t, http.StatusCreated, int(actualResponses[0].StatusCode))
+			assert.Equal(t, expectedEventId, actualResponses[0].Id)
+			assert.Equal(t, http.StatusBadRequest, int(actualResponses[1].StatusCode))
+			assert.NotEmpty(t, actualResponses[1].Message)
+			assert.Equal(t, http.StatusConflict, int(actualResponses[2].StatusCode))
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiEventBatchRoute, strings.NewReader("{}"))
+	require.NoError(t, err)
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(ec.AddEvents).ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode, "a non-array body should be rejected")
+}
+
 func TestEventById(t *testing.T) {
 	validEventId := expectedEventId
 	emptyEventId := ""
@@ -9,11 +9,16 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/application"
 	dataContainer "github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/export"
 	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -300,3 +305,129 @@ func (rc *ReadingController) ReadingsByDeviceNameAndResourceNamesAndTimeRange(w
 	utils.WriteHttpHeader(w, ctx, http.StatusOK)
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
+
+// ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange returns min/max/avg/sum/count/first/last of the readings of
+// the device resource over fixed time buckets within the specified time range.  The bucket size is specified by the
+// interval query parameter as a duration, e.g. 15m, and the whole time range is a single bucket when it is omitted.
+func (rc *ReadingController) ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(rc.dic.Get)
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	deviceName := vars[common.Name]
+	resourceName := vars[common.ResourceName]
+
+	// parse time range (start, end), interval and aggregation functions from incoming request
+	start, err := utils.ParsePathParamToInt(r, common.Start)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	end, err := utils.ParsePathParamToInt(r, common.End)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	if end < start {
+		err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("end's value %v is not allowed to be less than start's value %v", end, start), nil)
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	var interval time.Duration
+	if value := utils.ParseQueryStringToString(r, common.Interval, ""); value != "" {
+		var parseErr error
+		interval, parseErr = time.ParseDuration(value)
+		if parseErr != nil || interval <= 0 {
+			err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("failed to parse querystring %s's value %s into a positive duration", common.Interval, value), parseErr)
+			utils.WriteErrorResponse(w, ctx, lc, err, "")
+			return
+		}
+	}
+	functions := utils.ParseQueryStringToStrings(r, pkgCommon.Functions, common.CommaSeparator)
+
+	valueType, aggregates, err := application.ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(deviceName, resourceName, start, end, interval.Nanoseconds(), functions, ctx, rc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	if interval == 0 {
+		interval = time.Duration(end - start + 1)
+	}
+	response := dataDTOs.NewReadingAggregatesResponse("", "", http.StatusOK, deviceName, resourceName, valueType, interval.Nanoseconds(), aggregates)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
+
+// ExportReadingsByDeviceNameAndResourceNameAndTimeRange streams the readings of the device resource within the time
+// range in the format of the query, csv by default, with the chunked transfer encoding
+func (rc *ReadingController) ExportReadingsByDeviceNameAndResourceNameAndTimeRange(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(rc.dic.Get)
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	deviceName := vars[common.Name]
+	resourceName := vars[common.ResourceName]
+
+	// parse time range (start, end) and the export format from incoming request
+	start, err := utils.ParsePathParamToInt(r, common.Start)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	end, err := utils.ParsePathParamToInt(r, common.End)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	if end < start {
+		err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("end's value %v is not allowed to be less than start's value %v", end, start), nil)
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	ew := &exportResponseWriter{w: w}
+	writer, err := export.NewWriter(utils.ParseQueryStringToString(r, pkgCommon.Format, export.FormatCSV), ew)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	w.Header().Set(common.CorrelationHeader, correlation.FromContext(ctx))
+	w.Header().Set(common.ContentType, writer.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s_%s_%d_%d.%s\"", deviceName, resourceName, start, end, writer.FileExtension()))
+
+	count, err := application.ExportReadings(deviceName, resourceName, start, end, writer, rc.dic)
+	if err != nil {
+		if !ew.started {
+			utils.WriteErrorResponse(w, ctx, lc, err, "")
+			return
+		}
+		// the status has been sent along with the exported readings, so the client can only tell by the broken response
+		lc.Errorf("failed to export the readings of device %s resource %s after %d readings exported, %v", deviceName, resourceName, count, err)
+		panic(http.ErrAbortHandler)
+	}
+	if !ew.started {
+		w.WriteHeader(http.StatusOK)
+	}
+	lc.Debugf("%d readings of device %s resource %s exported", count, deviceName, resourceName)
+}
+
+// exportResponseWriter sends the response header on the first write, so that an error occurring before exporting any
+// reading can still be responded as usual
+type exportResponseWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (ew *exportResponseWriter) Write(p []byte) (int, error) {
+	if !ew.started {
+		ew.started = true
+		ew.w.WriteHeader(http.StatusOK)
+	}
+	return ew.w.Write(p)
+}
+
+func (ew *exportResponseWriter) Flush() {
+	if flusher, ok := ew.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
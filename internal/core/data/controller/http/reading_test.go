@@ -9,16 +9,20 @@ import (
 	"testing"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	dataDTOs "github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
 	dbMock "github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces/mocks"
 	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	responseDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 )
 
@@ -682,3 +686,140 @@ func TestReadingsByDeviceNameAndResourceNamesAndTimeRange(t *testing.T) {
 		})
 	}
 }
+
+func TestReadingAggregatesByDeviceNameAndResourceNameAndTimeRange(t *testing.T) {
+	dic := mocks.NewMockDIC()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", TestDeviceName, TestDeviceResourceName, 0, 99, "", 20).Return(nil, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	rc := NewReadingController(dic)
+	assert.NotNil(t, rc)
+
+	tests := []struct {
+		name               string
+		start              string
+		end                string
+		interval           string
+		functions          string
+		errorExpected      bool
+		expectedInterval   int64
+		expectedStatusCode int
+	}{
+		{"Valid - without interval", "0", "99", "", "", false, 100, http.StatusOK},
+		{"Valid - with interval and functions", "0", "99", "20ns", "min,max", false, 20, http.StatusOK},
+		{"Invalid - invalid interval format", "0", "99", "aaa", "", true, 0, http.StatusBadRequest},
+		{"Invalid - negative interval", "0", "99", "-1s", "", true, 0, http.StatusBadRequest},
+		{"Invalid - unsupported function", "0", "99", "20ns", "median", true, 0, http.StatusBadRequest},
+		{"Invalid - invalid start format", "aaa", "99", "", "", true, 0, http.StatusBadRequest},
+		{"Invalid - end before start", "99", "0", "", "", true, 0, http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiReadingAggregateRoute, http.NoBody)
+			require.NoError(t, err)
+			query := req.URL.Query()
+			if testCase.interval != "" {
+				query.Add(common.Interval, testCase.interval)
+			}
+			if testCase.functions != "" {
+				query.Add(pkgCommon.Functions, testCase.functions)
+			}
+			req.URL.RawQuery = query.Encode()
+			req = mux.SetURLVars(req, map[string]string{common.Name: TestDeviceName, common.ResourceName: TestDeviceResourceName, common.Start: testCase.start, common.End: testCase.end})
+
+			// Act
+			recorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(rc.ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange)
+			handler.ServeHTTP(recorder, req)
+
+			// Assert
+			if testCase.errorExpected {
+				var res commonDTO.BaseResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				assert.Equal(t, common.ApiVersion, res.ApiVersion, "API Version not as expected")
+				assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+				assert.Equal(t, testCase.expectedStatusCode, res.StatusCode, "Response status code not as expected")
+				assert.NotEmpty(t, res.Message, "Response message doesn't contain the error message")
+			} else {
+				var res dataDTOs.ReadingAggregatesResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				assert.Equal(t, common.ApiVersion, res.ApiVersion, "API Version not as expected")
+				assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+				assert.Equal(t, testCase.expectedStatusCode, res.StatusCode, "Response status code not as expected")
+				assert.Empty(t, res.Message, "Message should be empty when it is successful")
+				assert.Equal(t, testCase.expectedInterval, res.Interval, "Interval not as expected")
+				assert.Empty(t, res.Aggregates, "Aggregates should be empty when there is no reading")
+			}
+		})
+	}
+}
+
+func TestExportReadingsByDeviceNameAndResourceNameAndTimeRange(t *testing.T) {
+	readings := make([]models.Reading, 3)
+	for i := range readings {
+		readings[i] = models.SimpleReading{
+			BaseReading: models.BaseReading{Id: uuid.NewString(), Origin: int64(30 - i*10), DeviceName: TestDeviceName, ResourceName: TestDeviceResourceName,
+				ProfileName: TestDeviceProfileName, ValueType: common.ValueTypeUint8},
+			Value: "1",
+		}
+	}
+	dic := mocks.NewMockDIC()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", TestDeviceName, TestDeviceResourceName, 0, 99, "", 2).Return(readings[:2], nil)
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", TestDeviceName, TestDeviceResourceName, 0, 20, readings[1].GetBaseReading().Id, 2).Return(readings[2:], nil)
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", TestDeviceName, TestDeviceResourceName, 0, 10, readings[2].GetBaseReading().Id, 2).Return(nil, nil)
+	dbClientMock.On("ReadingsByDeviceNameAndResourceNameBeforeCursor", TestDeviceName, TestDeviceResourceName, 100, 199, "", 2).Return(nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "query failed", nil))
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	container.ConfigurationFrom(dic.Get).Service.MaxResultCount = 2
+	rc := NewReadingController(dic)
+
+	tests := []struct {
+		name                string
+		start               string
+		end                 string
+		format              string
+		expectedStatusCode  int
+		expectedContentType string
+		expectedLines       int
+	}{
+		{"Valid - default CSV format", "0", "99", "", http.StatusOK, "text/csv", 4},
+		{"Valid - NDJSON format", "0", "99", "ndjson", http.StatusOK, "application/x-ndjson", 3},
+		{"Valid - columnar format", "0", "99", "columnar", http.StatusOK, "application/cbor-seq", 0},
+		{"Invalid - unsupported format", "0", "99", "xml", http.StatusBadRequest, common.ContentTypeJSON, 0},
+		{"Invalid - end before start", "99", "0", "", http.StatusBadRequest, common.ContentTypeJSON, 0},
+		{"Invalid - query failed", "100", "199", "", http.StatusInternalServerError, common.ContentTypeJSON, 0},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiReadingExportRoute, http.NoBody)
+			require.NoError(t, err)
+			if testCase.format != "" {
+				query := req.URL.Query()
+				query.Add(pkgCommon.Format, testCase.format)
+				req.URL.RawQuery = query.Encode()
+			}
+			req = mux.SetURLVars(req, map[string]string{common.Name: TestDeviceName, common.ResourceName: TestDeviceResourceName, common.Start: testCase.start, common.End: testCase.end})
+
+			recorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(rc.ExportReadingsByDeviceNameAndResourceNameAndTimeRange)
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			assert.Equal(t, testCase.expectedContentType, recorder.Header().Get(common.ContentType))
+			if testCase.expectedLines > 0 {
+				assert.Equal(t, testCase.expectedLines, strings.Count(recorder.Body.String(), "\n"))
+				assert.True(t, recorder.Flushed, "each chunk of readings should be flushed to the client")
+			}
+		})
+	}
+}
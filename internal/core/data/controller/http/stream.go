@@ -0,0 +1,147 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	dataContainer "github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/stream"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+)
+
+const (
+	// streamWriteWait is the time allowed to write a message to the client
+	streamWriteWait = 10 * time.Second
+	// streamPingPeriod is the period to ping the client to keep the idle connection alive
+	streamPingPeriod = 30 * time.Second
+)
+
+// StreamController streams the events accepted by core-data to the WebSocket clients
+type StreamController struct {
+	dic      *di.Container
+	upgrader websocket.Upgrader
+}
+
+// NewStreamController creates and initializes a StreamController
+func NewStreamController(dic *di.Container) *StreamController {
+	sc := &StreamController{dic: dic}
+	sc.upgrader = websocket.Upgrader{CheckOrigin: sc.checkOrigin}
+	return sc
+}
+
+// checkOrigin accepts the same-origin requests, and the requests from the allowed origin when CORS is enabled
+func (sc *StreamController) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	cors := dataContainer.ConfigurationFrom(sc.dic.Get).Service.CORSConfiguration
+	return cors.EnableCORS && (cors.CORSAllowedOrigin == "*" || cors.CORSAllowedOrigin == origin)
+}
+
+// EventStream upgrades the request to a WebSocket connection and then sends each accepted event matching the profile,
+// device, source and resource names of the query as a JSON text message until either side closes the connection
+func (sc *StreamController) EventStream(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(sc.dic.Get)
+	ctx := r.Context()
+
+	hub := dataContainer.EventStreamHubFrom(sc.dic.Get)
+	if hub == nil {
+		err := errors.NewCommonEdgeX(errors.KindServiceUnavailable, "the event stream is disabled", nil)
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	filter := stream.Filter{
+		ProfileNames:  utils.ParseQueryStringToStrings(r, common.ProfileName, common.CommaSeparator),
+		DeviceNames:   utils.ParseQueryStringToStrings(r, common.DeviceName, common.CommaSeparator),
+		SourceNames:   utils.ParseQueryStringToStrings(r, common.SourceName, common.CommaSeparator),
+		ResourceNames: utils.ParseQueryStringToStrings(r, common.ResourceName, common.CommaSeparator),
+	}
+	subscriber, err := hub.Subscribe(filter)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	defer hub.Unsubscribe(subscriber)
+
+	conn, upgradeErr := sc.upgrader.Upgrade(w, r, nil)
+	if upgradeErr != nil {
+		// the upgrader has replied the error to the client
+		lc.Errorf("fail to upgrade the event stream connection, %v", upgradeErr)
+		return
+	}
+	defer func() {
+		_ = conn.Close()
+		lc.Debugf("Event stream client %s disconnected, %d events dropped", r.RemoteAddr, subscriber.Dropped())
+	}()
+
+	// the client is not expected to send anything, so the messages are read only to detect the closed connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(streamPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case e, ok := <-subscriber.Events():
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(streamWriteWait))
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteJSON(e); err != nil {
+				lc.Debugf("fail to write the event to the event stream client %s, %v", r.RemoteAddr, err)
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// BypassRequestTimeout returns the middleware which serves the long-lived routes, such as the event stream and reading
+// export, directly, so the request timeout middleware of the other APIs, which buffers the whole response and doesn't
+// support hijacking the connection, is skipped.  It must be added to the router before the request timeout middleware.
+func BypassRequestTimeout(routes ...*mux.Route) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := mux.CurrentRoute(r)
+			for _, route := range routes {
+				if current == route {
+					route.GetHandler().ServeHTTP(w, r)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/mocks"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/stream"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+)
+
+func TestEventStream(t *testing.T) {
+	dic := mocks.NewMockDIC()
+	sc := NewStreamController(dic)
+	router := mux.NewRouter()
+	route := router.HandleFunc(pkgCommon.ApiEventStreamRoute, sc.EventStream).Methods(http.MethodGet)
+	router.Use(BypassRequestTimeout(route))
+	router.Use(func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, time.Second, "HTTP request timeout")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + pkgCommon.ApiEventStreamRoute
+
+	_, res, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode, "the disabled event stream should be unavailable")
+
+	hub := stream.NewHub(10, 0)
+	dic.Update(di.ServiceConstructorMap{
+		container.EventStreamHubName: func(get di.Get) interface{} {
+			return hub
+		},
+	})
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?deviceName=device1,device2", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool { return hub.SubscriberCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	hub.Publish(models.Event{Id: "1", DeviceName: "device3"})
+	hub.Publish(models.Event{Id: "2", DeviceName: "device2"})
+	var e dtos.Event
+	require.NoError(t, conn.ReadJSON(&e))
+	assert.Equal(t, "2", e.Id, "only the events of the filtered devices should be streamed")
+
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool { return hub.SubscriberCount() == 0 }, time.Second, 10*time.Millisecond,
+		"the subscriber should be removed when the client disconnects")
+}
@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// ReadingAggregate holds the aggregated values of the readings whose origin is within [Start, End) of a time bucket.
+// Only the values of the requested aggregation functions are set.
+type ReadingAggregate struct {
+	Start int64    `json:"start"`
+	End   int64    `json:"end"`
+	Count *uint32  `json:"count,omitempty"`
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Avg   *float64 `json:"avg,omitempty"`
+	Sum   *float64 `json:"sum,omitempty"`
+	First *float64 `json:"first,omitempty"`
+	Last  *float64 `json:"last,omitempty"`
+}
+
+// ReadingAggregatesResponse defines the Response Content for GET reading aggregates, buckets without any reading are
+// omitted and the others are sorted in ascending order of time
+type ReadingAggregatesResponse struct {
+	common.BaseResponse `json:",inline"`
+	DeviceName          string             `json:"deviceName"`
+	ResourceName        string             `json:"resourceName"`
+	ValueType           string             `json:"valueType,omitempty"`
+	Interval            int64              `json:"interval"`
+	Aggregates          []ReadingAggregate `json:"aggregates"`
+}
+
+func NewReadingAggregatesResponse(requestId string, message string, statusCode int, deviceName string, resourceName string,
+	valueType string, interval int64, aggregates []ReadingAggregate) ReadingAggregatesResponse {
+	return ReadingAggregatesResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		DeviceName:   deviceName,
+		ResourceName: resourceName,
+		ValueType:    valueType,
+		Interval:     interval,
+		Aggregates:   aggregates,
+	}
+}
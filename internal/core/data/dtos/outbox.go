@@ -0,0 +1,25 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+// OutboxMetrics reports the outbox of the events failed to publish to the message bus through the metrics endpoint
+type OutboxMetrics struct {
+	// Depth is the number of events waiting in the outbox
+	Depth int `json:"depth"`
+	// Enqueued is the number of events queued since the service started
+	Enqueued uint64 `json:"enqueued"`
+	// Forwarded is the number of queued events published since the service started
+	Forwarded uint64 `json:"forwarded"`
+	// Retries is the number of failed attempts to publish the queued events since the service started
+	Retries uint64 `json:"retries"`
+	// Dropped is the number of the oldest events dropped since the service started as the outbox was full
+	Dropped uint64 `json:"dropped"`
+	// Quarantined is the number of the events which could not be decoded and were moved aside from the outbox since the
+	// service started
+	Quarantined uint64 `json:"quarantined"`
+	// LastError is the error of the last failed attempt, which is cleared once an event is published
+	LastError string `json:"lastError,omitempty"`
+}
@@ -0,0 +1,18 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+// RetentionMetrics reports the runs of the retention policy through the metrics endpoint
+type RetentionMetrics struct {
+	// LastRun is the timestamp in nanoseconds when the retention policy was applied last time
+	LastRun int64 `json:"lastRun"`
+	// PurgedEvents is the number of events purged by the last run
+	PurgedEvents uint32 `json:"purgedEvents"`
+	// TotalPurgedEvents is the number of events purged since the service started
+	TotalPurgedEvents uint64 `json:"totalPurgedEvents"`
+	// LastError is the error of the last run, if any
+	LastError string `json:"lastError,omitempty"`
+}
@@ -0,0 +1,269 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Formats of the exported readings
+const (
+	FormatCSV      = "csv"
+	FormatNDJSON   = "ndjson"
+	FormatColumnar = "columnar"
+)
+
+// columns lists the reading fields exported by the CSV and columnar formats
+var columns = []string{"id", "origin", "deviceName", "resourceName", "profileName", "valueType", "value", "mediaType", "binaryValue", "objectValue"}
+
+// Writer encodes the exported readings chunk by chunk, so that only a chunk of readings is held in memory
+type Writer interface {
+	// ContentType returns the media type of the encoded readings
+	ContentType() string
+	// FileExtension returns the file name extension of the encoded readings
+	FileExtension() string
+	// WriteReadings encodes a chunk of readings and flushes them to the underlying writer
+	WriteReadings(readings []dtos.BaseReading) errors.EdgeX
+	// Close completes the encoded readings, which must be called once all readings are written
+	Close() errors.EdgeX
+}
+
+// NewWriter returns the Writer of the format which encodes the readings to w.  When w implements http.Flusher, each
+// chunk of readings is flushed to the client once written.
+func NewWriter(format string, w io.Writer) (Writer, errors.EdgeX) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return &csvWriter{flushWriter: flushWriter{w}, writer: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{flushWriter: flushWriter{w}, encoder: json.NewEncoder(w)}, nil
+	case FormatColumnar:
+		return &columnarWriter{flushWriter: flushWriter{w}, encoder: cbor.NewEncoder(w)}, nil
+	default:
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("unsupported export format %s, must be one of %s, %s and %s", format, FormatCSV, FormatNDJSON, FormatColumnar), nil)
+	}
+}
+
+type flushWriter struct {
+	w io.Writer
+}
+
+func (f flushWriter) flush() {
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// csvWriter writes a header row followed by a row per reading, where the binary value is base64 encoded and the object
+// value is JSON encoded
+type csvWriter struct {
+	flushWriter
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) ContentType() string {
+	return "text/csv"
+}
+
+func (c *csvWriter) FileExtension() string {
+	return "csv"
+}
+
+func (c *csvWriter) writeHeader() {
+	if !c.headerWritten {
+		_ = c.writer.Write(columns)
+		c.headerWritten = true
+	}
+}
+
+func (c *csvWriter) WriteReadings(readings []dtos.BaseReading) errors.EdgeX {
+	c.writeHeader()
+	for _, r := range readings {
+		var binaryValue, objectValue string
+		if len(r.BinaryValue) > 0 {
+			binaryValue = base64.StdEncoding.EncodeToString(r.BinaryValue)
+		}
+		if r.ObjectValue != nil {
+			value, err := json.Marshal(r.ObjectValue)
+			if err != nil {
+				return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("failed to encode the object value of reading %s", r.Id), err)
+			}
+			objectValue = string(value)
+		}
+		record := []string{r.Id, strconv.FormatInt(r.Origin, 10), r.DeviceName, r.ResourceName, r.ProfileName, r.ValueType,
+			r.Value, r.MediaType, binaryValue, objectValue}
+		if err := c.writer.Write(record); err != nil {
+			return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the CSV record", err)
+		}
+	}
+	return c.flushChunk()
+}
+
+func (c *csvWriter) flushChunk() errors.EdgeX {
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the CSV records", err)
+	}
+	c.flush()
+	return nil
+}
+
+func (c *csvWriter) Close() errors.EdgeX {
+	c.writeHeader()
+	return c.flushChunk()
+}
+
+// ndjsonWriter writes each reading as a JSON object on its own line
+type ndjsonWriter struct {
+	flushWriter
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (n *ndjsonWriter) FileExtension() string {
+	return "ndjson"
+}
+
+func (n *ndjsonWriter) WriteReadings(readings []dtos.BaseReading) errors.EdgeX {
+	for _, r := range readings {
+		if err := n.encoder.Encode(r); err != nil {
+			return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the JSON reading", err)
+		}
+	}
+	n.flush()
+	return nil
+}
+
+func (n *ndjsonWriter) Close() errors.EdgeX {
+	return nil
+}
+
+// columnarHeader is the first item of the columnar CBOR sequence
+type columnarHeader struct {
+	Format  string   `cbor:"format"`
+	Version int      `cbor:"version"`
+	Columns []string `cbor:"columns"`
+}
+
+// dictionaryColumn encodes a column of few distinct strings as the distinct strings and the index of each value
+type dictionaryColumn struct {
+	Dictionary []string `cbor:"dictionary"`
+	Indexes    []uint32 `cbor:"indexes"`
+	positions  map[string]uint32
+}
+
+func (d *dictionaryColumn) add(value string) {
+	if d.positions == nil {
+		d.positions = make(map[string]uint32)
+	}
+	position, ok := d.positions[value]
+	if !ok {
+		position = uint32(len(d.Dictionary))
+		d.positions[value] = position
+		d.Dictionary = append(d.Dictionary, value)
+	}
+	d.Indexes = append(d.Indexes, position)
+}
+
+// columnarRowGroup holds the columns of a chunk of readings, which follows the header in the columnar CBOR sequence
+type columnarRowGroup struct {
+	Count        int              `cbor:"count"`
+	Id           []string         `cbor:"id"`
+	Origin       []int64          `cbor:"origin"`
+	DeviceName   dictionaryColumn `cbor:"deviceName"`
+	ResourceName dictionaryColumn `cbor:"resourceName"`
+	ProfileName  dictionaryColumn `cbor:"profileName"`
+	ValueType    dictionaryColumn `cbor:"valueType"`
+	Value        []string         `cbor:"value"`
+	MediaType    dictionaryColumn `cbor:"mediaType"`
+	BinaryValue  [][]byte         `cbor:"binaryValue"`
+	ObjectValue  []interface{}    `cbor:"objectValue"`
+}
+
+// columnarWriter writes the readings as a CBOR sequence (RFC 8742) of a header followed by a row group per chunk, in
+// which each column is an array, and the names and types repeated across readings are dictionary encoded
+type columnarWriter struct {
+	flushWriter
+	encoder       *cbor.Encoder
+	headerWritten bool
+}
+
+func (c *columnarWriter) ContentType() string {
+	return "application/cbor-seq"
+}
+
+func (c *columnarWriter) FileExtension() string {
+	return "cbors"
+}
+
+func (c *columnarWriter) writeHeader() errors.EdgeX {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	if err := c.encoder.Encode(columnarHeader{Format: "edgex-readings", Version: 1, Columns: columns}); err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the columnar header", err)
+	}
+	return nil
+}
+
+func (c *columnarWriter) WriteReadings(readings []dtos.BaseReading) errors.EdgeX {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	if len(readings) == 0 {
+		return nil
+	}
+
+	group := columnarRowGroup{
+		Count:       len(readings),
+		Id:          make([]string, len(readings)),
+		Origin:      make([]int64, len(readings)),
+		Value:       make([]string, len(readings)),
+		BinaryValue: make([][]byte, len(readings)),
+		ObjectValue: make([]interface{}, len(readings)),
+	}
+	for i, r := range readings {
+		group.Id[i] = r.Id
+		group.Origin[i] = r.Origin
+		group.DeviceName.add(r.DeviceName)
+		group.ResourceName.add(r.ResourceName)
+		group.ProfileName.add(r.ProfileName)
+		group.ValueType.add(r.ValueType)
+		group.Value[i] = r.Value
+		group.MediaType.add(r.MediaType)
+		group.BinaryValue[i] = r.BinaryValue
+		group.ObjectValue[i] = r.ObjectValue
+	}
+	if err := c.encoder.Encode(group); err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the columnar row group", err)
+	}
+	c.flush()
+	return nil
+}
+
+func (c *columnarWriter) Close() errors.EdgeX {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.flush()
+	return nil
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReadings() []dtos.BaseReading {
+	simple := dtos.BaseReading{Id: "1", Origin: 2, DeviceName: "device", ResourceName: "resource", ProfileName: "profile",
+		ValueType: common.ValueTypeInt16, SimpleReading: dtos.SimpleReading{Value: "12"}}
+	binary := dtos.BaseReading{Id: "2", Origin: 1, DeviceName: "device", ResourceName: "resource", ProfileName: "profile",
+		ValueType: common.ValueTypeBinary, BinaryReading: dtos.BinaryReading{BinaryValue: []byte{1, 2}, MediaType: "image/jpeg"}}
+	return []dtos.BaseReading{simple, binary}
+}
+
+func TestNewWriter(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatNDJSON, FormatColumnar, "CSV"} {
+		_, err := NewWriter(format, &bytes.Buffer{})
+		assert.NoError(t, err, format)
+	}
+	_, err := NewWriter("parquet", &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestCSVWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter(FormatCSV, buf)
+	require.NoError(t, err)
+	readings := testReadings()
+	require.NoError(t, writer.WriteReadings(readings[:1]))
+	require.NoError(t, writer.WriteReadings(readings[1:]))
+	require.NoError(t, writer.Close())
+
+	records, readErr := csv.NewReader(buf).ReadAll()
+	require.NoError(t, readErr)
+	require.Len(t, records, 3, "a header row should be followed by a row per reading")
+	assert.Equal(t, columns, records[0])
+	assert.Equal(t, []string{"1", "2", "device", "resource", "profile", common.ValueTypeInt16, "12", "", "", ""}, records[1])
+	assert.Equal(t, "AQI=", records[2][8], "the binary value should be base64 encoded")
+
+	buf.Reset()
+	writer, err = NewWriter(FormatCSV, buf)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	records, readErr = csv.NewReader(buf).ReadAll()
+	require.NoError(t, readErr)
+	assert.Len(t, records, 1, "the header row should be written without readings")
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter(FormatNDJSON, buf)
+	require.NoError(t, err)
+	readings := testReadings()
+	require.NoError(t, writer.WriteReadings(readings))
+	require.NoError(t, writer.Close())
+
+	decoder := json.NewDecoder(buf)
+	for _, expected := range readings {
+		var r dtos.BaseReading
+		require.NoError(t, decoder.Decode(&r))
+		assert.Equal(t, expected, r)
+	}
+	assert.False(t, decoder.More())
+}
+
+func TestColumnarWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter(FormatColumnar, buf)
+	require.NoError(t, err)
+	readings := testReadings()
+	require.NoError(t, writer.WriteReadings(readings))
+	require.NoError(t, writer.WriteReadings(readings[:1]))
+	require.NoError(t, writer.Close())
+
+	decoder := cbor.NewDecoder(buf)
+	var header columnarHeader
+	require.NoError(t, decoder.Decode(&header))
+	assert.Equal(t, columns, header.Columns)
+
+	var group columnarRowGroup
+	require.NoError(t, decoder.Decode(&group))
+	assert.Equal(t, 2, group.Count)
+	assert.Equal(t, []int64{2, 1}, group.Origin)
+	assert.Equal(t, []string{"device"}, group.DeviceName.Dictionary, "the repeated device name should be stored once")
+	assert.Equal(t, []uint32{0, 0}, group.DeviceName.Indexes)
+	assert.Equal(t, []string{common.ValueTypeInt16, common.ValueTypeBinary}, group.ValueType.Dictionary)
+	assert.Equal(t, []byte{1, 2}, group.BinaryValue[1])
+
+	group = columnarRowGroup{}
+	require.NoError(t, decoder.Decode(&group))
+	assert.Equal(t, 1, group.Count)
+	assert.Equal(t, io.EOF, decoder.Decode(&group))
+}
@@ -14,6 +14,7 @@ type DBClient interface {
 	CloseSession()
 
 	AddEvent(e model.Event) (model.Event, errors.EdgeX)
+	AddEvents(events []model.Event) ([]model.Event, []errors.EdgeX)
 	EventById(id string) (model.Event, errors.EdgeX)
 	DeleteEventById(id string) errors.EdgeX
 	EventTotalCount() (uint32, errors.EdgeX)
@@ -24,6 +25,8 @@ type DBClient interface {
 	DeleteEventsByDeviceName(deviceName string) errors.EdgeX
 	EventsByTimeRange(start int, end int, offset int, limit int) ([]model.Event, errors.EdgeX)
 	DeleteEventsByAge(age int64) errors.EdgeX
+	PruneEvents(age int64, maxCount uint32, excludedDeviceNames []string) (uint32, errors.EdgeX)
+	PruneEventsByDeviceName(deviceName string, age int64, maxCount uint32) (uint32, errors.EdgeX)
 	ReadingTotalCount() (uint32, errors.EdgeX)
 	AllReadings(offset int, limit int) ([]model.Reading, errors.EdgeX)
 	ReadingsByTimeRange(start int, end int, offset int, limit int) ([]model.Reading, errors.EdgeX)
@@ -31,6 +34,7 @@ type DBClient interface {
 	ReadingsByDeviceName(offset int, limit int, name string) ([]model.Reading, errors.EdgeX)
 	ReadingsByDeviceNameAndResourceName(deviceName string, resourceName string, offset int, limit int) ([]model.Reading, errors.EdgeX)
 	ReadingsByDeviceNameAndResourceNameAndTimeRange(deviceName string, resourceName string, start int, end int, offset int, limit int) ([]model.Reading, errors.EdgeX)
+	ReadingsByDeviceNameAndResourceNameBeforeCursor(deviceName string, resourceName string, start int, cursorOrigin int, cursorId string, limit int) ([]model.Reading, errors.EdgeX)
 	ReadingCountByDeviceName(deviceName string) (uint32, errors.EdgeX)
 	ReadingCountByResourceName(resourceName string) (uint32, errors.EdgeX)
 	ReadingCountByResourceNameAndTimeRange(resourceName string, start int, end int) (uint32, errors.EdgeX)
@@ -38,6 +38,31 @@ func (_m *DBClient) AddEvent(e models.Event) (models.Event, errors.EdgeX) {
 	return r0, r1
 }
 
+// AddEvents provides a mock function with given fields: events
+func (_m *DBClient) AddEvents(events []models.Event) ([]models.Event, []errors.EdgeX) {
+	ret := _m.Called(events)
+
+	var r0 []models.Event
+	if rf, ok := ret.Get(0).(func([]models.Event) []models.Event); ok {
+		r0 = rf(events)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	var r1 []errors.EdgeX
+	if rf, ok := ret.Get(1).(func([]models.Event) []errors.EdgeX); ok {
+		r1 = rf(events)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
 // AllEvents provides a mock function with given fields: offset, limit
 func (_m *DBClient) AllEvents(offset int, limit int) ([]models.Event, errors.EdgeX) {
 	ret := _m.Called(offset, limit)
@@ -283,6 +308,52 @@ func (_m *DBClient) EventsByTimeRange(start int, end int, offset int, limit int)
 	return r0, r1
 }
 
+// PruneEvents provides a mock function with given fields: age, maxCount, excludedDeviceNames
+func (_m *DBClient) PruneEvents(age int64, maxCount uint32, excludedDeviceNames []string) (uint32, errors.EdgeX) {
+	ret := _m.Called(age, maxCount, excludedDeviceNames)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(int64, uint32, []string) uint32); ok {
+		r0 = rf(age, maxCount, excludedDeviceNames)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(int64, uint32, []string) errors.EdgeX); ok {
+		r1 = rf(age, maxCount, excludedDeviceNames)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
+// PruneEventsByDeviceName provides a mock function with given fields: deviceName, age, maxCount
+func (_m *DBClient) PruneEventsByDeviceName(deviceName string, age int64, maxCount uint32) (uint32, errors.EdgeX) {
+	ret := _m.Called(deviceName, age, maxCount)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(string, int64, uint32) uint32); ok {
+		r0 = rf(deviceName, age, maxCount)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(string, int64, uint32) errors.EdgeX); ok {
+		r1 = rf(deviceName, age, maxCount)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
 // ReadingCountByDeviceName provides a mock function with given fields: deviceName
 func (_m *DBClient) ReadingCountByDeviceName(deviceName string) (uint32, errors.EdgeX) {
 	ret := _m.Called(deviceName)
@@ -542,6 +613,31 @@ func (_m *DBClient) ReadingsByDeviceNameAndResourceNameAndTimeRange(deviceName s
 	return r0, r1
 }
 
+// ReadingsByDeviceNameAndResourceNameBeforeCursor provides a mock function with given fields: deviceName, resourceName, start, cursorOrigin, cursorId, limit
+func (_m *DBClient) ReadingsByDeviceNameAndResourceNameBeforeCursor(deviceName string, resourceName string, start int, cursorOrigin int, cursorId string, limit int) ([]models.Reading, errors.EdgeX) {
+	ret := _m.Called(deviceName, resourceName, start, cursorOrigin, cursorId, limit)
+
+	var r0 []models.Reading
+	if rf, ok := ret.Get(0).(func(string, string, int, int, string, int) []models.Reading); ok {
+		r0 = rf(deviceName, resourceName, start, cursorOrigin, cursorId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Reading)
+		}
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(string, string, int, int, string, int) errors.EdgeX); ok {
+		r1 = rf(deviceName, resourceName, start, cursorOrigin, cursorId, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
 // ReadingsByDeviceNameAndResourceNamesAndTimeRange provides a mock function with given fields: deviceName, resourceNames, start, end, offset, limit
 func (_m *DBClient) ReadingsByDeviceNameAndResourceNamesAndTimeRange(deviceName string, resourceNames []string, start int, end int, offset int, limit int) ([]models.Reading, uint32, errors.EdgeX) {
 	ret := _m.Called(deviceName, resourceNames, start, end, offset, limit)
@@ -18,13 +18,19 @@ package data
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/application"
 	dataContainer "github.com/edgexfoundry/edgex-go/internal/core/data/container"
+	"github.com/edgexfoundry/edgex-go/internal/core/data/stream"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/cache"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/telemetry"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	clients "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/http"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 
 	"github.com/gorilla/mux"
 )
@@ -50,6 +56,55 @@ func (b *Bootstrap) BootstrapHandler(ctx context.Context, wg *sync.WaitGroup, st
 	configuration := dataContainer.ConfigurationFrom(dic.Get)
 	lc := container.LoggingClientFrom(dic.Get)
 
+	// initialize clients required by the service
+	dic.Update(di.ServiceConstructorMap{
+		container.MetadataDeviceProfileClientName: func(get di.Get) interface{} { // add v2 API MetadataDeviceProfileClient
+			return clients.NewDeviceProfileClient(configuration.Clients[common.CoreMetaDataServiceKey].Url() + common.ApiDeviceProfileRoute)
+		},
+		telemetry.ServiceMetricsName: func(get di.Get) interface{} {
+			return telemetry.NewServiceMetrics()
+		},
+	})
+
+	if configuration.EventValidation.Strict {
+		ttl, err := time.ParseDuration(configuration.EventValidation.ProfileCacheTTL)
+		if err != nil {
+			lc.Errorf("Failed to parse EventValidation.ProfileCacheTTL %s, %v", configuration.EventValidation.ProfileCacheTTL, err)
+			return false
+		}
+		dic.Update(di.ServiceConstructorMap{
+			dataContainer.DeviceProfileCacheName: func(get di.Get) interface{} {
+				return cache.NewTTLCache(ttl)
+			},
+		})
+	}
+
+	if configuration.EventStream.Enabled {
+		hub := stream.NewHub(configuration.EventStream.BufferSize, configuration.EventStream.MaxClients)
+		dic.Update(di.ServiceConstructorMap{
+			dataContainer.EventStreamHubName: func(get di.Get) interface{} {
+				return hub
+			},
+		})
+		// disconnect the event stream clients when the service stops
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			hub.Close()
+		}()
+	}
+
+	if err := application.StartOutbox(ctx, wg, dic); err != nil {
+		lc.Errorf("Failed to start the outbox, %v", err)
+		return false
+	}
+
+	if err := application.StartRetention(ctx, wg, dic); err != nil {
+		lc.Errorf("Failed to start the retention policy, %v", err)
+		return false
+	}
+
 	if configuration.MessageQueue.SubscribeEnabled {
 		err := application.SubscribeEvents(ctx, dic)
 		if err != nil {
@@ -16,95 +16,20 @@ package messaging
 
 import (
 	"context"
-	"fmt"
-	"strings"
 	"sync"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/data/container"
-	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
-	bootstrapMessaging "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/messaging"
+	pkgHandlers "github.com/edgexfoundry/edgex-go/internal/pkg/bootstrap/handlers"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
-	"github.com/edgexfoundry/go-mod-messaging/v2/messaging"
-	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
 )
 
 // BootstrapHandler fulfills the BootstrapHandler contract.  if enabled, tt creates and initializes the Messaging client
 // and adds it to the DIC
 func BootstrapHandler(ctx context.Context, wg *sync.WaitGroup, startupTimer startup.Timer, dic *di.Container) bool {
-	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
-	messageBusInfo := container.ConfigurationFrom(dic.Get).MessageQueue
-
-	messageBusInfo.AuthMode = strings.ToLower(strings.TrimSpace(messageBusInfo.AuthMode))
-	if len(messageBusInfo.AuthMode) > 0 && messageBusInfo.AuthMode != bootstrapMessaging.AuthModeNone {
-		if err := bootstrapMessaging.SetOptionsAuthData(&messageBusInfo, lc, dic); err != nil {
-			lc.Error(err.Error())
-			return false
-		}
-	}
-
-	msgClient, err := messaging.NewMessageClient(
-		types.MessageBusConfig{
-			PublishHost: types.HostInfo{
-				Host:     messageBusInfo.Host,
-				Port:     messageBusInfo.Port,
-				Protocol: messageBusInfo.Protocol,
-			},
-			SubscribeHost: types.HostInfo{
-				Host:     messageBusInfo.Host,
-				Port:     messageBusInfo.Port,
-				Protocol: messageBusInfo.Protocol,
-			},
-			Type:     messageBusInfo.Type,
-			Optional: messageBusInfo.Optional,
-		})
-
-	if err != nil {
-		lc.Errorf("Failed to create MessageClient: %v", err)
-		return false
+	messageBusInfo := func(get di.Get) (bootstrapConfig.MessageBusInfo, bool) {
+		return container.ConfigurationFrom(get).MessageQueue, true
 	}
-
-	for startupTimer.HasNotElapsed() {
-		select {
-		case <-ctx.Done():
-			return false
-		default:
-			err = msgClient.Connect()
-			if err != nil {
-				lc.Warnf("Unable to connect MessageBus: %w", err)
-				startupTimer.SleepForInterval()
-				continue
-			}
-
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				<-ctx.Done()
-				if msgClient != nil {
-					_ = msgClient.Disconnect()
-				}
-				lc.Infof("Disconnected from MessageBus")
-			}()
-
-			dic.Update(di.ServiceConstructorMap{
-				container.MessagingClientName: func(get di.Get) interface{} {
-					return msgClient
-				},
-			})
-
-			lc.Info(fmt.Sprintf(
-				"Connected to %s Message Bus @ %s://%s:%d publishing on '%s' prefix topic with AuthMode='%s'",
-				messageBusInfo.Type,
-				messageBusInfo.Protocol,
-				messageBusInfo.Host,
-				messageBusInfo.Port,
-				messageBusInfo.PublishTopicPrefix,
-				messageBusInfo.AuthMode))
-
-			return true
-		}
-	}
-
-	lc.Error("Connecting to MessageBus time out")
-	return false
+	return pkgHandlers.NewMessaging(messageBusInfo, container.MessagingClientName).BootstrapHandler(ctx, wg, startupTimer, dic)
 }
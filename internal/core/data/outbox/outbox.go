@@ -0,0 +1,354 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+)
+
+const openTimeout = 5 * time.Second
+
+var (
+	bucketName           = []byte("outbox")
+	quarantineBucketName = []byte("quarantine")
+)
+
+// PublishFunc publishes the message envelope to the topic of the message bus
+type PublishFunc func(envelope msgTypes.MessageEnvelope, topic string) error
+
+// entry is a message waiting in the outbox
+type entry struct {
+	Topic    string                   `json:"topic"`
+	Envelope msgTypes.MessageEnvelope `json:"envelope"`
+	Attempts int                      `json:"attempts"`
+}
+
+// Outbox is the durable first-in-first-out queue of the messages failed to publish to the message bus, which are
+// forwarded to the message bus with backoff in the order they are queued.  The messages are stored in a single file,
+// so they survive the service restart.  The messages which can't be decoded are moved to the quarantine bucket of the
+// same file, so they neither block the outbox nor get lost.
+type Outbox struct {
+	db       *bolt.DB
+	maxSize  int
+	lc       logger.LoggingClient
+	notify   chan struct{}
+	mutex    sync.Mutex
+	metrics  dtos.OutboxMetrics
+	onChange func(dtos.OutboxMetrics)
+}
+
+// Open opens or creates the outbox file at path.  The oldest message is dropped when a message is queued to the outbox
+// holding maxSize messages, 0 means no limit.  onChange, if not nil, is called with the metrics whenever they change.
+func Open(path string, maxSize int, lc logger.LoggingClient, onChange func(dtos.OutboxMetrics)) (*Outbox, errors.EdgeX) {
+	if path == "" {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "outbox file path is required", nil)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to create the directory of outbox file %s", path), err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to open outbox file %s", path), err)
+	}
+
+	o := &Outbox{db: db, maxSize: maxSize, lc: lc, notify: make(chan struct{}, 1), onChange: onChange}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(quarantineBucketName); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucketIfNotExists(bucketName)
+		if err != nil {
+			return err
+		}
+		o.metrics.Depth = b.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to initialize outbox file %s", path), err)
+	}
+	o.changed()
+	return o, nil
+}
+
+// Close closes the outbox file
+func (o *Outbox) Close() {
+	_ = o.db.Close()
+}
+
+// Depth returns the number of messages waiting in the outbox
+func (o *Outbox) Depth() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.metrics.Depth
+}
+
+// Metrics returns the current metrics of the outbox
+func (o *Outbox) Metrics() dtos.OutboxMetrics {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.metrics
+}
+
+// changed reports the metrics, which must be called with the mutex held
+func (o *Outbox) changed() {
+	if o.onChange != nil {
+		o.onChange(o.metrics)
+	}
+}
+
+// Enqueue queues the message envelope to be published to the topic, dropping the oldest messages when the outbox is full
+func (o *Outbox) Enqueue(topic string, envelope msgTypes.MessageEnvelope) errors.EdgeX {
+	value, err := json.Marshal(entry{Topic: topic, Envelope: envelope})
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode the outbox entry", err)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.enqueue(value)
+}
+
+// Publish publishes the message envelope to the topic right away when the outbox is empty, and otherwise queues it
+// behind the waiting messages to keep their order.  The message failed to publish is queued as well.  The outbox is
+// locked from checking its depth until the message is published or queued, so a message published concurrently can't
+// overtake the one being queued.  It returns whether the message is queued, along with the error of queueing it.
+func (o *Outbox) Publish(topic string, envelope msgTypes.MessageEnvelope, publish PublishFunc) (bool, errors.EdgeX) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.metrics.Depth == 0 && publish(envelope, topic) == nil {
+		return false, nil
+	}
+
+	value, err := json.Marshal(entry{Topic: topic, Envelope: envelope})
+	if err != nil {
+		return true, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode the outbox entry", err)
+	}
+	return true, o.enqueue(value)
+}
+
+// enqueue stores the encoded entry, which must be called with the mutex held
+func (o *Outbox) enqueue(value []byte) errors.EdgeX {
+	dropped := 0
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+		c := b.Cursor()
+		for o.maxSize > 0 && o.metrics.Depth-dropped >= o.maxSize {
+			if k, _ := c.First(); k == nil {
+				break
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			dropped++
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(sequenceKey(seq), value)
+	})
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the outbox file", err)
+	}
+
+	if dropped > 0 {
+		o.lc.Warnf("Outbox is full, %d oldest messages dropped", dropped)
+	}
+	o.metrics.Depth += 1 - dropped
+	o.metrics.Dropped += uint64(dropped)
+	o.metrics.Enqueued++
+	o.changed()
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Forward publishes the queued messages one by one in order until ctx is done.  When publishing or reading the outbox
+// fails, the message is retried after retryInterval, which is doubled after each failure up to maxRetryInterval and
+// reset once a message is published, so the messages are forwarded shortly after the message bus reconnects.
+func (o *Outbox) Forward(ctx context.Context, publish PublishFunc, retryInterval time.Duration, maxRetryInterval time.Duration) {
+	backoff := retryInterval
+	for {
+		key, e, err := o.oldest()
+		if err != nil {
+			o.lc.Errorf("failed to read the outbox, retry in %s, %v", backoff, err)
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff, maxRetryInterval)
+			continue
+		}
+		if key == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-o.notify:
+				continue
+			}
+		}
+
+		if err = publish(e.Envelope, e.Topic); err != nil {
+			o.retried(key, e, err)
+			o.lc.Debugf("failed to forward the outbox message to topic %s after %d attempts, retry in %s, %v", e.Topic, e.Attempts+1, backoff, err)
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff, maxRetryInterval)
+			continue
+		}
+
+		o.forwarded(key)
+		backoff = retryInterval
+	}
+}
+
+// sleep waits for d, and returns false when ctx is done meanwhile
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func nextBackoff(backoff time.Duration, maxRetryInterval time.Duration) time.Duration {
+	if backoff *= 2; backoff > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return backoff
+}
+
+// oldest returns the key and the entry of the oldest message, or nil key when the outbox is empty.  The messages which
+// can't be decoded are quarantined on the way.
+func (o *Outbox) oldest() (key []byte, e entry, err error) {
+	for {
+		var value []byte
+		key = nil
+		err = o.db.View(func(tx *bolt.Tx) error {
+			k, v := tx.Bucket(bucketName).Cursor().First()
+			if k != nil {
+				key = append([]byte{}, k...)
+				value = append([]byte{}, v...)
+			}
+			return nil
+		})
+		if err != nil || key == nil {
+			return nil, e, err
+		}
+		decodeErr := json.Unmarshal(value, &e)
+		if decodeErr == nil {
+			return key, e, nil
+		}
+		if err = o.quarantine(key, value, decodeErr); err != nil {
+			return nil, e, err
+		}
+	}
+}
+
+// quarantine moves the message which can't be decoded to the quarantine bucket
+func (o *Outbox) quarantine(key []byte, value []byte, cause error) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	removed := false
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+		if b.Get(key) == nil {
+			return nil
+		}
+		removed = true
+		if err := tx.Bucket(quarantineBucketName).Put(key, value); err != nil {
+			return err
+		}
+		return b.Delete(key)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to quarantine the message which can't be decoded, %w", err)
+	}
+	if removed {
+		o.lc.Errorf("the outbox message %x can't be decoded and is quarantined, %v", key, cause)
+		o.metrics.Depth--
+		o.metrics.Quarantined++
+		o.changed()
+	}
+	return nil
+}
+
+// retried records the failed attempt of the message, unless the message has been dropped
+func (o *Outbox) retried(key []byte, e entry, cause error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	e.Attempts++
+	if value, err := json.Marshal(e); err == nil {
+		_ = o.db.Update(func(tx *bolt.Tx) error {
+			b := tx.Bucket(bucketName)
+			if b.Get(key) == nil {
+				return nil
+			}
+			return b.Put(key, value)
+		})
+	}
+	o.metrics.Retries++
+	o.metrics.LastError = cause.Error()
+	o.changed()
+}
+
+// forwarded removes the published message
+func (o *Outbox) forwarded(key []byte) {
+	o.remove(key)
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.metrics.Forwarded++
+	o.metrics.LastError = ""
+	o.changed()
+}
+
+// remove deletes the message, unless the message has been dropped
+func (o *Outbox) remove(key []byte) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	removed := false
+	err := o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+		if b.Get(key) == nil {
+			return nil
+		}
+		removed = true
+		return b.Delete(key)
+	})
+	if err != nil {
+		o.lc.Errorf("failed to remove the message from the outbox, %v", err)
+		return
+	}
+	if removed {
+		o.metrics.Depth--
+		o.changed()
+	}
+}
+
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
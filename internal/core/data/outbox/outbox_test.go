@@ -0,0 +1,184 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/data/dtos"
+)
+
+func envelope(payload string) msgTypes.MessageEnvelope {
+	return msgTypes.MessageEnvelope{CorrelationID: payload, Payload: []byte(payload), ContentType: "application/json"}
+}
+
+func TestOutboxEnqueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	o, err := Open(path, 2, logger.NewMockClient(), nil)
+	require.NoError(t, err)
+
+	for _, payload := range []string{"1", "2", "3"} {
+		require.NoError(t, o.Enqueue("topic", envelope(payload)))
+	}
+	metrics := o.Metrics()
+	assert.Equal(t, 2, metrics.Depth, "the outbox should be capped")
+	assert.Equal(t, uint64(3), metrics.Enqueued)
+	assert.Equal(t, uint64(1), metrics.Dropped)
+	o.Close()
+
+	o, err = Open(path, 2, logger.NewMockClient(), nil)
+	require.NoError(t, err)
+	defer o.Close()
+	assert.Equal(t, 2, o.Depth(), "the queued messages should survive reopening")
+	key, e, readErr := o.oldest()
+	require.NoError(t, readErr)
+	require.NotNil(t, key)
+	assert.Equal(t, "2", string(e.Envelope.Payload), "the oldest message should be dropped")
+	assert.Equal(t, "topic", e.Topic)
+}
+
+func TestOutboxForward(t *testing.T) {
+	var reported sync.Map
+	o, err := Open(filepath.Join(t.TempDir(), "outbox.db"), 0, logger.NewMockClient(), func(metrics dtos.OutboxMetrics) {
+		reported.Store("metrics", metrics)
+	})
+	require.NoError(t, err)
+	defer o.Close()
+
+	var mutex sync.Mutex
+	var published []string
+	failures := 2
+	publish := func(envelope msgTypes.MessageEnvelope, topic string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if failures > 0 {
+			failures--
+			return fmt.Errorf("message bus unavailable")
+		}
+		published = append(published, string(envelope.Payload))
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Forward(ctx, publish, time.Millisecond, 4*time.Millisecond)
+	}()
+	for _, payload := range []string{"1", "2", "3"} {
+		require.NoError(t, o.Enqueue("topic", envelope(payload)))
+	}
+
+	require.Eventually(t, func() bool { return o.Depth() == 0 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, []string{"1", "2", "3"}, published, "the messages should be forwarded in order")
+	metrics := o.Metrics()
+	assert.Equal(t, uint64(3), metrics.Forwarded)
+	assert.Equal(t, uint64(2), metrics.Retries)
+	assert.Empty(t, metrics.LastError, "the last error should be cleared once a message is forwarded")
+	value, ok := reported.Load("metrics")
+	require.True(t, ok)
+	assert.Equal(t, metrics, value, "the latest metrics should be reported")
+}
+
+func TestOutboxPublish(t *testing.T) {
+	o, err := Open(filepath.Join(t.TempDir(), "outbox.db"), 0, logger.NewMockClient(), nil)
+	require.NoError(t, err)
+	defer o.Close()
+
+	var mutex sync.Mutex
+	var attempts []string
+	publish := func(envelope msgTypes.MessageEnvelope, topic string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		attempts = append(attempts, string(envelope.Payload))
+		if string(envelope.Payload) == "fail" {
+			return fmt.Errorf("message bus unavailable")
+		}
+		return nil
+	}
+
+	queued, edgeXerr := o.Publish("topic", envelope("1"), publish)
+	require.NoError(t, edgeXerr)
+	assert.False(t, queued, "the message should be published right away when the outbox is empty")
+	queued, edgeXerr = o.Publish("topic", envelope("fail"), publish)
+	require.NoError(t, edgeXerr)
+	assert.True(t, queued, "the message failed to publish should be queued")
+
+	// the messages published concurrently are queued behind the waiting one, rather than published before it
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(payload string) {
+			defer wg.Done()
+			queued, err := o.Publish("topic", envelope(payload), publish)
+			assert.NoError(t, err)
+			assert.True(t, queued)
+		}(fmt.Sprint(i))
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"1", "fail"}, attempts, "no message should be published while the outbox isn't empty")
+	assert.Equal(t, 11, o.Depth())
+}
+
+func TestOutboxForwardQuarantine(t *testing.T) {
+	o, err := Open(filepath.Join(t.TempDir(), "outbox.db"), 0, logger.NewMockClient(), nil)
+	require.NoError(t, err)
+	defer o.Close()
+
+	// queue a message which can't be decoded ahead of a valid one
+	require.NoError(t, o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketName)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(sequenceKey(seq), []byte("corrupted"))
+	}))
+	o.metrics.Depth++
+	require.NoError(t, o.Enqueue("topic", envelope("1")))
+
+	published := make(chan string, 1)
+	publish := func(envelope msgTypes.MessageEnvelope, topic string) error {
+		published <- string(envelope.Payload)
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Forward(ctx, publish, time.Hour, time.Hour)
+	}()
+
+	select {
+	case payload := <-published:
+		assert.Equal(t, "1", payload, "the message after the corrupted one should be forwarded without waiting")
+	case <-time.After(time.Second):
+		assert.Fail(t, "the corrupted message should not block the outbox")
+	}
+	cancel()
+	<-done
+
+	metrics := o.Metrics()
+	assert.Equal(t, uint64(1), metrics.Quarantined)
+	require.NoError(t, o.db.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, []byte("corrupted"), tx.Bucket(quarantineBucketName).Get(sequenceKey(1)), "the corrupted message should be kept aside")
+		return nil
+	}))
+}
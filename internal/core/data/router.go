@@ -15,6 +15,7 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 
 	dataController "github.com/edgexfoundry/edgex-go/internal/core/data/controller/http"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	commonController "github.com/edgexfoundry/edgex-go/internal/pkg/controller/http"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 )
@@ -30,6 +31,7 @@ func LoadRestRoutes(r *mux.Router, dic *di.Container, serviceName string) {
 	// Events
 	ec := dataController.NewEventController(dic)
 	r.HandleFunc(common.ApiEventProfileNameDeviceNameSourceNameRoute, ec.AddEvent).Methods(http.MethodPost)
+	r.HandleFunc(pkgCommon.ApiEventBatchRoute, ec.AddEvents).Methods(http.MethodPost)
 	r.HandleFunc(common.ApiEventIdRoute, ec.EventById).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiEventIdRoute, ec.DeleteEventById).Methods(http.MethodDelete)
 	r.HandleFunc(common.ApiEventCountRoute, ec.EventTotalCount).Methods(http.MethodGet)
@@ -52,7 +54,15 @@ func LoadRestRoutes(r *mux.Router, dic *di.Container, serviceName string) {
 	r.HandleFunc(common.ApiReadingByDeviceNameAndResourceNameRoute, rc.ReadingsByDeviceNameAndResourceName).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiReadingByDeviceNameAndResourceNameAndTimeRangeRoute, rc.ReadingsByDeviceNameAndResourceNameAndTimeRange).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiReadingByDeviceNameAndTimeRangeRoute, rc.ReadingsByDeviceNameAndResourceNamesAndTimeRange).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiReadingAggregateRoute, rc.ReadingAggregatesByDeviceNameAndResourceNameAndTimeRange).Methods(http.MethodGet)
+	exportRoute := r.HandleFunc(pkgCommon.ApiReadingExportRoute, rc.ExportReadingsByDeviceNameAndResourceNameAndTimeRange).Methods(http.MethodGet)
+
+	// Event stream
+	sc := dataController.NewStreamController(dic)
+	streamRoute := r.HandleFunc(pkgCommon.ApiEventStreamRoute, sc.EventStream).Methods(http.MethodGet)
 
 	r.Use(correlation.ManageHeader)
 	r.Use(correlation.LoggingMiddleware(container.LoggingClientFrom(dic.Get)))
+	// the request timeout middleware is added by the http server bootstrap handler after this one
+	r.Use(dataController.BypassRequestTimeout(streamRoute, exportRoute))
 }
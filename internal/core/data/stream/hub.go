@@ -0,0 +1,164 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stream
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+// Filter selects the events streamed to a subscriber.  An empty list matches any name.  When ResourceNames is not empty,
+// only the matching readings are streamed and the events without any matching reading are skipped.
+type Filter struct {
+	ProfileNames  []string
+	DeviceNames   []string
+	SourceNames   []string
+	ResourceNames []string
+}
+
+func matches(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns the event to stream, and false when the event doesn't match the filter
+func (f Filter) apply(e models.Event) (models.Event, bool) {
+	if !matches(f.ProfileNames, e.ProfileName) || !matches(f.DeviceNames, e.DeviceName) || !matches(f.SourceNames, e.SourceName) {
+		return e, false
+	}
+	if len(f.ResourceNames) == 0 {
+		return e, true
+	}
+	readings := make([]models.Reading, 0, len(e.Readings))
+	for _, r := range e.Readings {
+		if matches(f.ResourceNames, r.GetBaseReading().ResourceName) {
+			readings = append(readings, r)
+		}
+	}
+	e.Readings = readings
+	return e, len(readings) > 0
+}
+
+// Subscriber receives the streamed events through a bounded buffer.  The oldest buffered event is dropped when the
+// buffer is full, so a slow subscriber never blocks the event ingestion.
+type Subscriber struct {
+	filter  Filter
+	events  chan dtos.Event
+	dropped uint64
+}
+
+// Events returns the channel of the streamed events, which is closed when the subscriber is unsubscribed
+func (s *Subscriber) Events() <-chan dtos.Event {
+	return s.events
+}
+
+// Dropped returns the number of events dropped because the buffer was full
+func (s *Subscriber) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Subscriber) send(e dtos.Event) {
+	for {
+		select {
+		case s.events <- e:
+			return
+		default:
+		}
+		// the buffer is full, so drop the oldest event and retry
+		select {
+		case <-s.events:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+	}
+}
+
+// Hub streams the events published by core-data to the subscribers.  It is safe for concurrent use.
+type Hub struct {
+	mutex          sync.RWMutex
+	subscribers    map[*Subscriber]struct{}
+	bufferSize     int
+	maxSubscribers int
+	closed         bool
+}
+
+// NewHub creates a Hub whose subscribers buffer up to bufferSize events, and the number of subscribers is unlimited when
+// maxSubscribers is 0
+func NewHub(bufferSize int, maxSubscribers int) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Hub{
+		subscribers:    make(map[*Subscriber]struct{}),
+		bufferSize:     bufferSize,
+		maxSubscribers: maxSubscribers,
+	}
+}
+
+// Subscribe adds a subscriber receiving the events which match filter
+func (h *Hub) Subscribe(filter Filter) (*Subscriber, errors.EdgeX) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.closed {
+		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "the event stream is closed", nil)
+	}
+	if h.maxSubscribers > 0 && len(h.subscribers) >= h.maxSubscribers {
+		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "the number of event stream subscribers reaches the limit", nil)
+	}
+	s := &Subscriber{filter: filter, events: make(chan dtos.Event, h.bufferSize)}
+	h.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+// Unsubscribe removes the subscriber and closes its channel
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		close(s.events)
+	}
+}
+
+// Close unsubscribes all subscribers and rejects the new ones
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.closed = true
+	for s := range h.subscribers {
+		delete(h.subscribers, s)
+		close(s.events)
+	}
+}
+
+// SubscriberCount returns the number of the current subscribers
+func (h *Hub) SubscriberCount() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.subscribers)
+}
+
+// Publish streams the event to the matching subscribers without blocking
+func (h *Hub) Publish(e models.Event) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	for s := range h.subscribers {
+		if filtered, ok := s.filter.apply(e); ok {
+			s.send(dtos.FromEventModelToDTO(filtered))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package stream
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(deviceName string, resourceNames ...string) models.Event {
+	e := models.Event{Id: deviceName, DeviceName: deviceName, ProfileName: "profile", SourceName: "source"}
+	for _, name := range resourceNames {
+		e.Readings = append(e.Readings, models.SimpleReading{BaseReading: models.BaseReading{DeviceName: deviceName, ResourceName: name}})
+	}
+	return e
+}
+
+func TestHubFilter(t *testing.T) {
+	hub := NewHub(10, 0)
+	all, err := hub.Subscribe(Filter{})
+	require.NoError(t, err)
+	byDevice, err := hub.Subscribe(Filter{DeviceNames: []string{"device1"}})
+	require.NoError(t, err)
+	byResource, err := hub.Subscribe(Filter{ProfileNames: []string{"profile"}, ResourceNames: []string{"temperature"}})
+	require.NoError(t, err)
+
+	hub.Publish(testEvent("device1", "temperature", "humidity"))
+	hub.Publish(testEvent("device2", "humidity"))
+
+	assert.Len(t, all.Events(), 2)
+	require.Len(t, byDevice.Events(), 1)
+	assert.Equal(t, "device1", (<-byDevice.Events()).DeviceName)
+	require.Len(t, byResource.Events(), 1)
+	e := <-byResource.Events()
+	require.Len(t, e.Readings, 1, "only the matching readings should be streamed")
+	assert.Equal(t, "temperature", e.Readings[0].ResourceName)
+
+	hub.Unsubscribe(all)
+	received := 0
+	for range all.Events() {
+		received++
+	}
+	assert.Equal(t, 2, received, "the buffered events should be received until the channel is closed")
+	assert.Equal(t, 2, hub.SubscriberCount())
+}
+
+func TestHubBoundedBuffer(t *testing.T) {
+	hub := NewHub(2, 1)
+	s, err := hub.Subscribe(Filter{})
+	require.NoError(t, err)
+	_, err = hub.Subscribe(Filter{})
+	require.Error(t, err)
+	assert.Equal(t, errors.KindServiceUnavailable, errors.Kind(err))
+
+	for _, deviceName := range []string{"device1", "device2", "device3"} {
+		hub.Publish(testEvent(deviceName))
+	}
+	assert.Equal(t, uint64(1), s.Dropped())
+	assert.Equal(t, "device2", (<-s.Events()).DeviceName, "the oldest event should be dropped")
+	assert.Equal(t, "device3", (<-s.Events()).DeviceName)
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub(1, 0)
+	s, err := hub.Subscribe(Filter{})
+	require.NoError(t, err)
+
+	hub.Close()
+	_, ok := <-s.Events()
+	assert.False(t, ok, "the channels of the subscribers should be closed")
+	hub.Unsubscribe(s)
+	_, err = hub.Subscribe(Filter{})
+	require.Error(t, err)
+	assert.Equal(t, errors.KindServiceUnavailable, errors.Kind(err))
+}
@@ -0,0 +1,315 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+)
+
+// ExportMetadata returns the archive of all the device services, device profiles, devices and provision watchers,
+// each sorted by name
+func ExportMetadata(dic *di.Container) (archive metadataDTOs.MetadataArchive, err errors.EdgeX) {
+	dbClient := container.DBClientFrom(dic.Get)
+
+	deviceServices, err := dbClient.AllDeviceServices(0, -1, nil)
+	if err != nil {
+		return archive, errors.NewCommonEdgeXWrapper(err)
+	}
+	deviceProfiles, err := dbClient.AllDeviceProfiles(0, -1, nil)
+	if err != nil {
+		return archive, errors.NewCommonEdgeXWrapper(err)
+	}
+	devices, err := dbClient.AllDevices(0, -1, nil)
+	if err != nil {
+		return archive, errors.NewCommonEdgeXWrapper(err)
+	}
+	provisionWatchers, err := dbClient.AllProvisionWatchers(0, -1, nil)
+	if err != nil {
+		return archive, errors.NewCommonEdgeXWrapper(err)
+	}
+
+	archive = metadataDTOs.MetadataArchive{
+		Versionable:       commonDTO.NewVersionable(),
+		Version:           metadataDTOs.MetadataArchiveVersion,
+		Created:           pkgCommon.MakeTimestamp(),
+		DeviceServices:    make([]dtos.DeviceService, len(deviceServices)),
+		DeviceProfiles:    make([]dtos.DeviceProfile, len(deviceProfiles)),
+		Devices:           make([]dtos.Device, len(devices)),
+		ProvisionWatchers: make([]dtos.ProvisionWatcher, len(provisionWatchers)),
+	}
+	for i, ds := range deviceServices {
+		archive.DeviceServices[i] = dtos.FromDeviceServiceModelToDTO(ds)
+	}
+	for i, dp := range deviceProfiles {
+		archive.DeviceProfiles[i] = dtos.FromDeviceProfileModelToDTO(dp)
+	}
+	for i, d := range devices {
+		archive.Devices[i] = dtos.FromDeviceModelToDTO(d)
+	}
+	for i, pw := range provisionWatchers {
+		archive.ProvisionWatchers[i] = dtos.FromProvisionWatcherModelToDTO(pw)
+	}
+	sort.Slice(archive.DeviceServices, func(i, j int) bool { return archive.DeviceServices[i].Name < archive.DeviceServices[j].Name })
+	sort.Slice(archive.DeviceProfiles, func(i, j int) bool { return archive.DeviceProfiles[i].Name < archive.DeviceProfiles[j].Name })
+	sort.Slice(archive.Devices, func(i, j int) bool { return archive.Devices[i].Name < archive.Devices[j].Name })
+	sort.Slice(archive.ProvisionWatchers, func(i, j int) bool { return archive.ProvisionWatchers[i].Name < archive.ProvisionWatchers[j].Name })
+	return archive, nil
+}
+
+// importEntry is the planned import of an entity, where apply is nil if the entity is skipped or fails
+type importEntry struct {
+	result metadataDTOs.ImportResult
+	apply  func() errors.EdgeX
+}
+
+// ImportMetadata imports the entities of the archive in dependency order, i.e. device services, device profiles,
+// devices and then provision watchers.  All the entities are checked before importing any of them, so that a dry-run
+// reports the same actions as the import, and the ImportModeFail aborts the import before anything is changed.  The
+// report is returned along with the error of the aborted import.
+func ImportMetadata(archive metadataDTOs.MetadataArchive, mode string, dryRun bool, ctx context.Context, dic *di.Container) (report metadataDTOs.ImportReport, err errors.EdgeX) {
+	report = metadataDTOs.ImportReport{Mode: mode, DryRun: dryRun, Results: []metadataDTOs.ImportResult{}}
+	if archive.Version < 1 || archive.Version > metadataDTOs.MetadataArchiveVersion {
+		return report, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unsupported archive version %d, must be between 1 and %d", archive.Version, metadataDTOs.MetadataArchiveVersion), nil)
+	}
+	switch mode {
+	case metadataDTOs.ImportModeOverwrite, metadataDTOs.ImportModeSkip, metadataDTOs.ImportModeFail:
+	default:
+		return report, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unsupported import mode %s, must be one of %s, %s and %s", mode, metadataDTOs.ImportModeOverwrite, metadataDTOs.ImportModeSkip, metadataDTOs.ImportModeFail), nil)
+	}
+
+	entries, err := planImport(archive, mode, ctx, dic)
+	if err != nil {
+		return report, errors.NewCommonEdgeXWrapper(err)
+	}
+	for _, e := range entries {
+		report.Add(e.result)
+	}
+	if dryRun {
+		return report, nil
+	}
+	if mode == metadataDTOs.ImportModeFail && report.Failed > 0 {
+		return report, errors.NewCommonEdgeX(errors.KindStatusConflict, fmt.Sprintf("%d entities fail to be imported, nothing is imported", report.Failed), nil)
+	}
+
+	report = metadataDTOs.ImportReport{Mode: mode, Results: []metadataDTOs.ImportResult{}}
+	for _, e := range entries {
+		if e.apply != nil {
+			if err := e.apply(); err != nil {
+				e.result.Action = metadataDTOs.ImportActionFail
+				e.result.Message = err.Error()
+			}
+		}
+		report.Add(e.result)
+	}
+	bootstrapContainer.LoggingClientFrom(dic.Get).Infof("Metadata archive imported in %s mode: %d created, %d updated, %d skipped, %d failed. Correlation-id: %s",
+		mode, report.Created, report.Updated, report.Skipped, report.Failed, correlation.FromContext(ctx))
+	return report, nil
+}
+
+func planImport(archive metadataDTOs.MetadataArchive, mode string, ctx context.Context, dic *di.Container) (entries []importEntry, err errors.EdgeX) {
+	dbClient := container.DBClientFrom(dic.Get)
+	// the names of the entities to be imported, which can be referenced by the later entities
+	imported := map[string]map[string]bool{
+		metadataDTOs.ArchiveDeviceServiceType: {},
+		metadataDTOs.ArchiveDeviceProfileType: {},
+	}
+	// plan returns the entry of the entity, which is created, or updated or skipped according to the mode if exists
+	plan := func(entityType string, name string, validateErr error, exists func(string) (bool, errors.EdgeX), create func() errors.EdgeX, update func() errors.EdgeX) (importEntry, errors.EdgeX) {
+		entry := importEntry{result: metadataDTOs.ImportResult{Type: entityType, Name: name, Action: metadataDTOs.ImportActionFail}}
+		if validateErr != nil {
+			entry.result.Message = fmt.Sprintf("invalid %s: %v", entityType, validateErr)
+			return entry, nil
+		}
+		found, err := exists(name)
+		if err != nil {
+			return entry, errors.NewCommonEdgeXWrapper(err)
+		}
+		switch {
+		case !found:
+			entry.result.Action = metadataDTOs.ImportActionCreate
+			entry.apply = create
+		case mode == metadataDTOs.ImportModeOverwrite:
+			entry.result.Action = metadataDTOs.ImportActionUpdate
+			entry.apply = update
+		case mode == metadataDTOs.ImportModeSkip:
+			entry.result.Action = metadataDTOs.ImportActionSkip
+		default:
+			entry.result.Message = fmt.Sprintf("%s %s already exists", entityType, name)
+		}
+		return entry, nil
+	}
+	// available checks whether the referenced entity exists or is to be imported
+	available := func(entityType string, name string, exists func(string) (bool, errors.EdgeX)) (bool, errors.EdgeX) {
+		if imported[entityType][name] {
+			return true, nil
+		}
+		return exists(name)
+	}
+	// checkReferences fails the entry if the device service or device profile it references isn't available
+	checkReferences := func(entry *importEntry, serviceName string, profileName string) errors.EdgeX {
+		if entry.apply == nil {
+			return nil
+		}
+		for _, ref := range []struct{ entityType, name string }{
+			{metadataDTOs.ArchiveDeviceServiceType, serviceName},
+			{metadataDTOs.ArchiveDeviceProfileType, profileName},
+		} {
+			exists := dbClient.DeviceServiceNameExists
+			if ref.entityType == metadataDTOs.ArchiveDeviceProfileType {
+				exists = dbClient.DeviceProfileNameExists
+			}
+			ok, err := available(ref.entityType, ref.name, exists)
+			if err != nil {
+				return errors.NewCommonEdgeXWrapper(err)
+			}
+			if !ok {
+				*entry = importEntry{result: metadataDTOs.ImportResult{Type: entry.result.Type, Name: entry.result.Name, Action: metadataDTOs.ImportActionFail,
+					Message: fmt.Sprintf("%s %s does not exist", ref.entityType, ref.name)}}
+				return nil
+			}
+		}
+		return nil
+	}
+	// duplicated fails the entry if the name appears more than once in the archive
+	names := make(map[string]bool)
+	duplicated := func(entityType string, name string) (importEntry, bool) {
+		key := entityType + "/" + name
+		if names[key] {
+			return importEntry{result: metadataDTOs.ImportResult{Type: entityType, Name: name, Action: metadataDTOs.ImportActionFail,
+				Message: fmt.Sprintf("%s %s is duplicated in the archive", entityType, name)}}, true
+		}
+		names[key] = true
+		return importEntry{}, false
+	}
+
+	for _, ds := range archive.DeviceServices {
+		if entry, ok := duplicated(metadataDTOs.ArchiveDeviceServiceType, ds.Name); ok {
+			entries = append(entries, entry)
+			continue
+		}
+		model := dtos.ToDeviceServiceModel(ds)
+		model.Id, model.Created, model.Modified = "", 0, 0
+		entry, err := plan(metadataDTOs.ArchiveDeviceServiceType, ds.Name, common.Validate(ds), dbClient.DeviceServiceNameExists,
+			func() errors.EdgeX {
+				_, err := AddDeviceService(model, ctx, dic)
+				return err
+			},
+			func() errors.EdgeX {
+				update := dtos.FromDeviceServiceModelToUpdateDTO(model)
+				update.Id = nil
+				return PatchDeviceService(update, nil, ctx, dic)
+			})
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		imported[metadataDTOs.ArchiveDeviceServiceType][ds.Name] = entry.apply != nil
+		entries = append(entries, entry)
+	}
+
+	for _, dp := range archive.DeviceProfiles {
+		if entry, ok := duplicated(metadataDTOs.ArchiveDeviceProfileType, dp.Name); ok {
+			entries = append(entries, entry)
+			continue
+		}
+		model := dtos.ToDeviceProfileModel(dp)
+		model.Id, model.Created, model.Modified = "", 0, 0
+		entry, err := plan(metadataDTOs.ArchiveDeviceProfileType, dp.Name, dp.Validate(), dbClient.DeviceProfileNameExists,
+			func() errors.EdgeX {
+				_, err := AddDeviceProfile(model, ctx, dic)
+				return err
+			},
+			func() errors.EdgeX {
+				// overwriting is requested explicitly, so the update breaking the devices is forced
+				return UpdateDeviceProfile(model, true, ctx, dic)
+			})
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		imported[metadataDTOs.ArchiveDeviceProfileType][dp.Name] = entry.apply != nil
+		entries = append(entries, entry)
+	}
+
+	for _, d := range archive.Devices {
+		if entry, ok := duplicated(metadataDTOs.ArchiveDeviceType, d.Name); ok {
+			entries = append(entries, entry)
+			continue
+		}
+		model := dtos.ToDeviceModel(d)
+		model.Id, model.Created, model.Modified = "", 0, 0
+		entry, err := plan(metadataDTOs.ArchiveDeviceType, d.Name, common.Validate(d), dbClient.DeviceNameExists,
+			func() errors.EdgeX {
+				_, err := AddDevice(model, ctx, dic)
+				return err
+			},
+			func() errors.EdgeX {
+				update := dtos.FromDeviceModelToUpdateDTO(model)
+				update.Id = nil
+				return PatchDevice(update, nil, ctx, dic)
+			})
+		if err == nil {
+			err = checkReferences(&entry, d.ServiceName, d.ProfileName)
+		}
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, pw := range archive.ProvisionWatchers {
+		if entry, ok := duplicated(metadataDTOs.ArchiveProvisionWatcherType, pw.Name); ok {
+			entries = append(entries, entry)
+			continue
+		}
+		model := dtos.ToProvisionWatcherModel(pw)
+		model.Id, model.Created, model.Modified = "", 0, 0
+		entry, err := plan(metadataDTOs.ArchiveProvisionWatcherType, pw.Name, common.Validate(pw), provisionWatcherNameExists(dic),
+			func() errors.EdgeX {
+				_, err := AddProvisionWatcher(model, ctx, dic)
+				return err
+			},
+			func() errors.EdgeX {
+				update := dtos.FromProvisionWatcherModelToUpdateDTO(model)
+				update.Id = nil
+				return PatchProvisionWatcher(ctx, update, nil, dic)
+			})
+		if err == nil {
+			err = checkReferences(&entry, pw.ServiceName, pw.ProfileName)
+		}
+		if err != nil {
+			return nil, errors.NewCommonEdgeXWrapper(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// provisionWatcherNameExists checks the provision watcher existence by name, as the DBClient doesn't provide it
+func provisionWatcherNameExists(dic *di.Container) func(string) (bool, errors.EdgeX) {
+	dbClient := container.DBClientFrom(dic.Get)
+	return func(name string) (bool, errors.EdgeX) {
+		_, err := dbClient.ProvisionWatcherByName(name)
+		if errors.Kind(err) == errors.KindEntityDoesNotExist {
+			return false, nil
+		} else if err != nil {
+			return false, errors.NewCommonEdgeXWrapper(err)
+		}
+		return true, nil
+	}
+}
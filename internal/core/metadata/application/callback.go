@@ -0,0 +1,164 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/callback"
+	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+// StartCallbackQueue opens the queue of the callbacks to the device services and starts delivering the queued
+// callbacks until ctx is done
+func StartCallbackQueue(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	info := metadataContainer.ConfigurationFrom(dic.Get).Callback
+	durations := make(map[string]time.Duration)
+	for name, value := range map[string]string{"RetryInterval": info.RetryInterval, "MaxRetryInterval": info.MaxRetryInterval, "Timeout": info.Timeout} {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%s %s is not a positive duration", name, value), err)
+		}
+		durations[name] = d
+	}
+	if durations["MaxRetryInterval"] < durations["RetryInterval"] {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("MaxRetryInterval %s is less than RetryInterval %s", info.MaxRetryInterval, info.RetryInterval), nil)
+	}
+
+	lc := container.LoggingClientFrom(dic.Get)
+	send := func(ctx context.Context, c *metadataDTOs.Callback) error {
+		ctx, cancel := context.WithTimeout(ctx, durations["Timeout"])
+		defer cancel()
+		if err := deliverCallback(ctx, dic, c); err != nil {
+			return err
+		}
+		return nil
+	}
+	policy := callback.RetryPolicy{
+		Interval:    durations["RetryInterval"],
+		MaxInterval: durations["MaxRetryInterval"],
+		MaxAttempts: info.MaxAttempts,
+	}
+	queue, err := callback.Open(info.Path, send, policy, lc)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	dic.Update(di.ServiceConstructorMap{
+		metadataContainer.CallbackQueueName: func(get di.Get) interface{} {
+			return queue
+		},
+	})
+	queue.Start(ctx, wg)
+
+	lc.Infof("Device service callback queue opened at %s", info.Path)
+	return nil
+}
+
+// CallbackStatusByServiceName returns the pending and failed callbacks of the device service
+func CallbackStatusByServiceName(name string, dic *di.Container) (status metadataDTOs.CallbackStatus, err errors.EdgeX) {
+	if name == "" {
+		return status, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	queue, err := callbackQueue(dic)
+	if err != nil {
+		return status, errors.NewCommonEdgeXWrapper(err)
+	}
+	status, err = queue.Status(name)
+	if err != nil {
+		return status, errors.NewCommonEdgeXWrapper(err)
+	}
+	return status, nil
+}
+
+// AllCallbackStatus returns the pending and failed callbacks of the device services having any
+func AllCallbackStatus(dic *di.Container) ([]metadataDTOs.CallbackStatus, errors.EdgeX) {
+	queue, err := callbackQueue(dic)
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	statuses, err := queue.AllStatus()
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	return statuses, nil
+}
+
+// ResyncDeviceService brings the device service up to date with the metadata.  The failed callbacks for deleting devices
+// and provision watchers are queued again, the other failed callbacks are discarded, and the callbacks for updating the
+// device service, the device profiles in use, the devices and the provision watchers of the device service with their
+// current state are queued after the pending callbacks, which are retried immediately.
+func ResyncDeviceService(name string, ctx context.Context, dic *di.Container) errors.EdgeX {
+	if name == "" {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	queue, err := callbackQueue(dic)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	lc := container.LoggingClientFrom(dic.Get)
+
+	ds, err := dbClient.DeviceServiceByName(name)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	devices, err := dbClient.DevicesByServiceName(0, -1, name)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	provisionWatchers, err := dbClient.ProvisionWatchersByServiceName(0, -1, name)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+
+	retried, err := queue.Retry(name, func(c metadataDTOs.Callback) bool {
+		return c.Action == metadataDTOs.CallbackDeleteDevice || c.Action == metadataDTOs.CallbackDeleteProvisionWatcher
+	})
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+
+	updateDeviceServiceCallback(ctx, dic, ds)
+	profiles := make(map[string]bool)
+	for _, d := range devices {
+		if profiles[d.ProfileName] {
+			continue
+		}
+		profiles[d.ProfileName] = true
+		profile, err := dbClient.DeviceProfileByName(d.ProfileName)
+		if err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		enqueueCallback(ctx, dic, name, metadataDTOs.CallbackUpdateDeviceProfile, profile.Name, dtos.FromDeviceProfileModelToDTO(profile))
+	}
+	for _, d := range devices {
+		updateDeviceCallback(ctx, dic, name, d)
+	}
+	for _, pw := range provisionWatchers {
+		updateProvisionWatcherCallback(ctx, dic, name, pw)
+	}
+
+	lc.Infof("Device service %s resync queued, %d failed callbacks retried, %d device profiles, %d devices and %d provision watchers",
+		name, retried, len(profiles), len(devices), len(provisionWatchers))
+	return nil
+}
+
+func callbackQueue(dic *di.Container) (*callback.Queue, errors.EdgeX) {
+	queue := metadataContainer.CallbackQueueFrom(dic.Get)
+	if queue == nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "device service callback queue is not available", nil)
+	}
+	return queue, nil
+}
@@ -8,10 +8,13 @@ package application
 import (
 	"context"
 	"fmt"
+	"path"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -50,7 +53,8 @@ func AddDevice(d models.Device, ctx context.Context, dic *di.Container) (id stri
 		addedDevice.Id,
 		correlation.FromContext(ctx),
 	))
-	go addDeviceCallback(ctx, dic, dtos.FromDeviceModelToDTO(d))
+	addDeviceCallback(ctx, dic, dtos.FromDeviceModelToDTO(d))
+	publishSystemEvent(pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionAdd, addedDevice.ServiceName, dtos.FromDeviceModelToDTO(addedDevice), ctx, dic)
 	return addedDevice.Id, nil
 }
 
@@ -68,7 +72,8 @@ func DeleteDeviceByName(name string, ctx context.Context, dic *di.Container) err
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
-	go deleteDeviceCallback(ctx, dic, device)
+	deleteDeviceCallback(ctx, dic, device)
+	publishSystemEvent(pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionDelete, device.ServiceName, dtos.FromDeviceModelToDTO(device), ctx, dic)
 	return nil
 }
 
@@ -106,7 +111,7 @@ func DeviceNameExists(name string, dic *di.Container) (exists bool, err errors.E
 }
 
 // PatchDevice executes the PATCH operation with the device DTO to replace the old data
-func PatchDevice(dto dtos.UpdateDevice, ctx context.Context, dic *di.Container) errors.EdgeX {
+func PatchDevice(dto dtos.UpdateDevice, expectedRevision *uint64, ctx context.Context, dic *di.Container) errors.EdgeX {
 	dbClient := container.DBClientFrom(dic.Get)
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 
@@ -127,7 +132,11 @@ func PatchDevice(dto dtos.UpdateDevice, ctx context.Context, dic *di.Container)
 		}
 	}
 
-	device, err := deviceByDTO(dbClient, dto)
+	device, revision, err := deviceByDTO(dbClient, dto)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	err = checkRevision("device", device.Name, revision, expectedRevision)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
@@ -140,7 +149,7 @@ func PatchDevice(dto dtos.UpdateDevice, ctx context.Context, dic *di.Container)
 
 	requests.ReplaceDeviceModelFieldsWithDTO(&device, dto)
 
-	err = dbClient.UpdateDevice(device)
+	err = dbClient.UpdateDeviceWithRevision(device, revision)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
@@ -151,29 +160,38 @@ func PatchDevice(dto dtos.UpdateDevice, ctx context.Context, dic *di.Container)
 	))
 
 	if oldServiceName != "" {
-		go updateDeviceCallback(ctx, dic, oldServiceName, device)
+		updateDeviceCallback(ctx, dic, oldServiceName, device)
 	}
-	go updateDeviceCallback(ctx, dic, device.ServiceName, device)
+	updateDeviceCallback(ctx, dic, device.ServiceName, device)
+	publishSystemEvent(pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionUpdate, device.ServiceName, dtos.FromDeviceModelToDTO(device), ctx, dic)
 	return nil
 }
 
-func deviceByDTO(dbClient interfaces.DBClient, dto dtos.UpdateDevice) (device models.Device, edgeXerr errors.EdgeX) {
+// deviceByDTO returns the device to patch and its revision
+func deviceByDTO(dbClient interfaces.DBClient, dto dtos.UpdateDevice) (device models.Device, revision uint64, edgeXerr errors.EdgeX) {
 	// The ID or Name is required by DTO and the DTO also accepts empty string ID if the Name is provided
+	name := ""
 	if dto.Id != nil && *dto.Id != "" {
 		device, edgeXerr = dbClient.DeviceById(*dto.Id)
 		if edgeXerr != nil {
-			return device, errors.NewCommonEdgeXWrapper(edgeXerr)
+			return device, revision, errors.NewCommonEdgeXWrapper(edgeXerr)
 		}
+		name = device.Name
 	} else {
-		device, edgeXerr = dbClient.DeviceByName(*dto.Name)
-		if edgeXerr != nil {
-			return device, errors.NewCommonEdgeXWrapper(edgeXerr)
-		}
+		name = *dto.Name
+	}
+	if dto.Name != nil && *dto.Name != name {
+		return device, revision, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("device name '%s' not match the exsting '%s' ", *dto.Name, name), nil)
+	}
+	id := device.Id
+	device, revision, edgeXerr = dbClient.DeviceAndRevisionByName(name)
+	if edgeXerr != nil {
+		return device, revision, errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
-	if dto.Name != nil && *dto.Name != device.Name {
-		return device, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("device name '%s' not match the exsting '%s' ", *dto.Name, device.Name), nil)
+	if id != "" && id != device.Id {
+		return device, revision, errors.NewCommonEdgeX(errors.KindStatusConflict, fmt.Sprintf("device '%s' was replaced by another device with the same name", name), nil)
 	}
-	return device, nil
+	return device, revision, nil
 }
 
 // AllDevices query the devices with offset, limit, and labels
@@ -193,18 +211,65 @@ func AllDevices(offset int, limit int, labels []string, dic *di.Container) (devi
 	return devices, totalCount, nil
 }
 
-// DeviceByName query the device by name
-func DeviceByName(name string, dic *di.Container) (device dtos.Device, err errors.EdgeX) {
+// SearchDevices query the devices which match the filter with offset and limit, and returns the total count of the
+// matching devices
+func SearchDevices(offset int, limit int, filter pkgModels.DeviceSearchFilter, dic *di.Container) (devices []dtos.Device, totalCount uint32, err errors.EdgeX) {
+	err = validateDeviceSearchFilter(filter)
+	if err != nil {
+		return devices, totalCount, errors.NewCommonEdgeXWrapper(err)
+	}
+	dbClient := container.DBClientFrom(dic.Get)
+	deviceModels, totalCount, err := dbClient.SearchDevices(offset, limit, filter)
+	if err != nil {
+		return devices, totalCount, errors.NewCommonEdgeXWrapper(err)
+	}
+	devices = make([]dtos.Device, len(deviceModels))
+	for i, d := range deviceModels {
+		devices[i] = dtos.FromDeviceModelToDTO(d)
+	}
+	return devices, totalCount, nil
+}
+
+func validateDeviceSearchFilter(filter pkgModels.DeviceSearchFilter) errors.EdgeX {
+	if _, err := path.Match(filter.NamePattern, ""); err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid device name pattern '%s'", filter.NamePattern), err)
+	}
+	switch filter.AdminState {
+	case "", models.Locked, models.Unlocked:
+	default:
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid admin state '%s'", filter.AdminState), nil)
+	}
+	switch filter.OperatingState {
+	case "", models.Up, models.Down, models.Unknown:
+	default:
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid operating state '%s'", filter.OperatingState), nil)
+	}
+	switch filter.SortBy {
+	case "", pkgModels.DeviceSortByName, pkgModels.DeviceSortByCreated, pkgModels.DeviceSortByModified:
+	default:
+		return errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("devices can only be sorted by %s, %s or %s", pkgModels.DeviceSortByName, pkgModels.DeviceSortByCreated, pkgModels.DeviceSortByModified), nil)
+	}
+	for key := range filter.ProtocolProperties {
+		if key == "" {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, "protocol property name is empty", nil)
+		}
+	}
+	return nil
+}
+
+// DeviceByName query the device and its revision by name
+func DeviceByName(name string, dic *di.Container) (device dtos.Device, revision uint64, err errors.EdgeX) {
 	if name == "" {
-		return device, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+		return device, revision, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
 	}
 	dbClient := container.DBClientFrom(dic.Get)
-	d, err := dbClient.DeviceByName(name)
+	d, revision, err := dbClient.DeviceAndRevisionByName(name)
 	if err != nil {
-		return device, errors.NewCommonEdgeXWrapper(err)
+		return device, revision, errors.NewCommonEdgeXWrapper(err)
 	}
 	device = dtos.FromDeviceModelToDTO(d)
-	return device, nil
+	return device, revision, nil
 }
 
 // DevicesByProfileName query the devices with offset, limit, and profile name
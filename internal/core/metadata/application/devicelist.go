@@ -0,0 +1,191 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+)
+
+// templateVariable matches the {{variable}} placeholder of the protocol template
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// AddDevices adds the devices of the list in bulk.  All the devices are validated before adding any of them, and the
+// row errors are returned along with the error if any device is invalid.  The added devices of each device service are
+// queued as a single callback, which still makes one AddDevice callback request per device.
+func AddDevices(list metadataDTOs.DeviceList, ctx context.Context, dic *di.Container) (ids []string, rowErrors []metadataDTOs.DeviceRowError, err errors.EdgeX) {
+	if len(list.Devices) == 0 {
+		return nil, nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "no device to add", nil)
+	}
+	devices, rowErrors, err := validateDeviceList(list, dic)
+	if err != nil {
+		return nil, nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	if len(rowErrors) > 0 {
+		return nil, rowErrors, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("%d of %d devices are invalid, no device is added", len(rowErrors), len(devices)), nil)
+	}
+
+	dbClient := container.DBClientFrom(dic.Get)
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	added := make(map[string][]dtos.Device)
+	var serviceNames []string
+	for i, d := range devices {
+		addedDevice, err := dbClient.AddDevice(dtos.ToDeviceModel(d))
+		if err != nil {
+			rowErrors = append(rowErrors, metadataDTOs.DeviceRowError{Row: list.Devices[i].Row, Name: d.Name, Message: err.Error()})
+			continue
+		}
+		ids = append(ids, addedDevice.Id)
+		if _, ok := added[addedDevice.ServiceName]; !ok {
+			serviceNames = append(serviceNames, addedDevice.ServiceName)
+		}
+		addedDTO := dtos.FromDeviceModelToDTO(addedDevice)
+		added[addedDevice.ServiceName] = append(added[addedDevice.ServiceName], addedDTO)
+		publishSystemEvent(pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionAdd, addedDevice.ServiceName, addedDTO, ctx, dic)
+	}
+	for _, serviceName := range serviceNames {
+		addDevicesCallback(ctx, dic, serviceName, added[serviceName])
+	}
+
+	lc.Debugf("%d of %d devices created on DB successfully. Correlation-ID: %s", len(ids), len(devices), correlation.FromContext(ctx))
+	return ids, rowErrors, nil
+}
+
+// validateDeviceList generates the devices of the list, and returns the error of each invalid device
+func validateDeviceList(list metadataDTOs.DeviceList, dic *di.Container) (devices []dtos.Device, rowErrors []metadataDTOs.DeviceRowError, err errors.EdgeX) {
+	dbClient := container.DBClientFrom(dic.Get)
+	// the existence of the referenced device services and device profiles
+	services := make(map[string]bool)
+	profiles := make(map[string]bool)
+	exists := func(cache map[string]bool, name string, check func(string) (bool, errors.EdgeX)) (bool, errors.EdgeX) {
+		if found, ok := cache[name]; ok {
+			return found, nil
+		}
+		found, err := check(name)
+		if err != nil {
+			return false, errors.NewCommonEdgeXWrapper(err)
+		}
+		cache[name] = found
+		return found, nil
+	}
+
+	names := make(map[string]int)
+	devices = make([]dtos.Device, len(list.Devices))
+	for i, entry := range list.Devices {
+		rowError := func(message string) {
+			rowErrors = append(rowErrors, metadataDTOs.DeviceRowError{Row: entry.Row, Name: entry.Name, Message: message})
+		}
+		device, genErr := generateDevice(entry, list.ProtocolTemplates)
+		if genErr != nil {
+			rowError(genErr.Error())
+			continue
+		}
+		devices[i] = device
+		if validateErr := common.Validate(device); validateErr != nil {
+			rowError(validateErr.Error())
+			continue
+		}
+		if row, ok := names[device.Name]; ok {
+			rowError(fmt.Sprintf("device name %s is duplicated with row %d", device.Name, row))
+			continue
+		}
+		names[device.Name] = entry.Row
+
+		found, err := dbClient.DeviceNameExists(device.Name)
+		if err != nil {
+			return nil, nil, errors.NewCommonEdgeXWrapper(err)
+		} else if found {
+			rowError(fmt.Sprintf("device %s already exists", device.Name))
+			continue
+		}
+		found, err = exists(services, device.ServiceName, dbClient.DeviceServiceNameExists)
+		if err != nil {
+			return nil, nil, errors.NewCommonEdgeXWrapper(err)
+		} else if !found {
+			rowError(fmt.Sprintf("device service '%s' does not exists", device.ServiceName))
+			continue
+		}
+		found, err = exists(profiles, device.ProfileName, dbClient.DeviceProfileNameExists)
+		if err != nil {
+			return nil, nil, errors.NewCommonEdgeXWrapper(err)
+		} else if !found {
+			rowError(fmt.Sprintf("device profile '%s' does not exists", device.ProfileName))
+		}
+	}
+	return devices, rowErrors, nil
+}
+
+// generateDevice generates the protocols of the device from the protocol template, and defaults the states of the
+// device to UNLOCKED and UP
+func generateDevice(entry metadataDTOs.DeviceListEntry, templates map[string]map[string]dtos.ProtocolProperties) (dtos.Device, error) {
+	device := entry.Device
+	if device.AdminState == "" {
+		device.AdminState = models.Unlocked
+	}
+	if device.OperatingState == "" {
+		device.OperatingState = models.Up
+	}
+
+	protocols := make(map[string]dtos.ProtocolProperties)
+	if entry.ProtocolTemplate != "" {
+		template, ok := templates[entry.ProtocolTemplate]
+		if !ok {
+			return device, fmt.Errorf("protocol template %s is not defined", entry.ProtocolTemplate)
+		}
+		for protocol, properties := range template {
+			protocols[protocol] = make(dtos.ProtocolProperties, len(properties))
+			for k, v := range properties {
+				protocols[protocol][k] = v
+			}
+		}
+	}
+	for protocol, properties := range device.Protocols {
+		if _, ok := protocols[protocol]; !ok {
+			protocols[protocol] = make(dtos.ProtocolProperties, len(properties))
+		}
+		for k, v := range properties {
+			protocols[protocol][k] = v
+		}
+	}
+
+	// the device name can be referred as {{name}} unless it's overridden by the variables
+	variables := map[string]string{"name": device.Name}
+	for k, v := range entry.Variables {
+		variables[k] = v
+	}
+	for protocol, properties := range protocols {
+		for k, v := range properties {
+			var undefined string
+			properties[k] = templateVariable.ReplaceAllStringFunc(v, func(placeholder string) string {
+				name := templateVariable.FindStringSubmatch(placeholder)[1]
+				value, ok := variables[name]
+				if !ok && undefined == "" {
+					undefined = name
+				}
+				return value
+			})
+			if undefined != "" {
+				return device, fmt.Errorf("variable %s of protocol %s property %s is not defined", undefined, protocol, k)
+			}
+		}
+	}
+	device.Protocols = protocols
+	return device, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -26,7 +27,7 @@ func AddDeviceProfile(d models.DeviceProfile, ctx context.Context, dic *di.Conta
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 
 	correlationId := correlation.FromContext(ctx)
-	addedDeviceProfile, err := dbClient.AddDeviceProfile(d)
+	addedDeviceProfile, err := dbClient.AddDeviceProfile(d, correlationId)
 	if err != nil {
 		return "", errors.NewCommonEdgeXWrapper(err)
 	}
@@ -36,17 +37,28 @@ func AddDeviceProfile(d models.DeviceProfile, ctx context.Context, dic *di.Conta
 		addedDeviceProfile.Id,
 		correlationId,
 	))
-
+	publishSystemEvent(pkgDTOs.DeviceProfileSystemEventType, pkgDTOs.SystemEventActionAdd, "", dtos.FromDeviceProfileModelToDTO(addedDeviceProfile), ctx, dic)
 	return addedDeviceProfile.Id, nil
 }
 
 // The UpdateDeviceProfile function accepts the device profile model from the controller functions
-// and invokes updateDeviceProfile function in the infrastructure layer
-func UpdateDeviceProfile(d models.DeviceProfile, ctx context.Context, dic *di.Container) (err errors.EdgeX) {
+// and invokes updateDeviceProfile function in the infrastructure layer.  The update breaking the devices using
+// the device profile is rejected unless it's forced, and the unforced update is rejected with a conflict if the device
+// profile is updated by another client after its impact is checked.
+func UpdateDeviceProfile(d models.DeviceProfile, force bool, ctx context.Context, dic *di.Container) (err errors.EdgeX) {
 	dbClient := container.DBClientFrom(dic.Get)
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 
-	err = dbClient.UpdateDeviceProfile(d)
+	if force {
+		err = dbClient.UpdateDeviceProfile(d, correlation.FromContext(ctx))
+	} else {
+		var revision uint32
+		revision, err = checkDeviceProfileUpdateImpact(d, dic)
+		if err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		err = dbClient.UpdateDeviceProfileWithRevision(d, revision, correlation.FromContext(ctx))
+	}
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
@@ -56,6 +68,7 @@ func UpdateDeviceProfile(d models.DeviceProfile, ctx context.Context, dic *di.Co
 		correlation.FromContext(ctx),
 	))
 	go updateDeviceProfileCallback(ctx, dic, dtos.FromDeviceProfileModelToDTO(d))
+	publishSystemEvent(pkgDTOs.DeviceProfileSystemEventType, pkgDTOs.SystemEventActionUpdate, "", dtos.FromDeviceProfileModelToDTO(d), ctx, dic)
 	return nil
 }
 
@@ -96,10 +109,16 @@ func DeleteDeviceProfileByName(name string, ctx context.Context, dic *di.Contain
 		return errors.NewCommonEdgeX(errors.KindStatusConflict, "fail to delete the device profile when associated provisionWatcher exists", nil)
 	}
 
+	// The deleted device profile is published with the system event
+	deviceProfile, err := dbClient.DeviceProfileByName(name)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
 	err = dbClient.DeleteDeviceProfileByName(name)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
+	publishSystemEvent(pkgDTOs.DeviceProfileSystemEventType, pkgDTOs.SystemEventActionDelete, "", dtos.FromDeviceProfileModelToDTO(deviceProfile), ctx, dic)
 	return nil
 }
 
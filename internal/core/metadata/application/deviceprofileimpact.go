@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+// DeviceProfileUpdateImpact compares the device profile with the stored one and returns the impact of the update on
+// the devices using the device profile
+func DeviceProfileUpdateImpact(d models.DeviceProfile, dic *di.Container) (impact metadataDTOs.DeviceProfileImpact, err errors.EdgeX) {
+	impact, _, err = deviceProfileUpdateImpact(d, dic)
+	if err != nil {
+		return impact, errors.NewCommonEdgeXWrapper(err)
+	}
+	return impact, nil
+}
+
+// deviceProfileUpdateImpact returns the impact of the update along with the latest revision of the stored device
+// profile which the impact is computed against
+func deviceProfileUpdateImpact(d models.DeviceProfile, dic *di.Container) (impact metadataDTOs.DeviceProfileImpact, revision uint32, err errors.EdgeX) {
+	if d.Name == "" {
+		return impact, 0, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	dbClient := container.DBClientFrom(dic.Get)
+	stored, revision, err := dbClient.DeviceProfileAndRevisionByName(d.Name)
+	if err != nil {
+		return impact, 0, errors.NewCommonEdgeXWrapper(err)
+	}
+	devices, err := dbClient.DevicesByProfileName(0, -1, d.Name)
+	if err != nil {
+		return impact, 0, errors.NewCommonEdgeXWrapper(err)
+	}
+	return deviceProfileImpact(stored, d, devices), revision, nil
+}
+
+func deviceProfileImpact(stored models.DeviceProfile, updated models.DeviceProfile, devices []models.Device) metadataDTOs.DeviceProfileImpact {
+	impact := metadataDTOs.DeviceProfileImpact{ProfileName: updated.Name}
+
+	resources := make(map[string]models.DeviceResource, len(updated.DeviceResources))
+	for _, r := range updated.DeviceResources {
+		resources[r.Name] = r
+	}
+	for _, r := range stored.DeviceResources {
+		u, ok := resources[r.Name]
+		if !ok {
+			impact.RemovedResources = append(impact.RemovedResources, r.Name)
+		} else if !strings.EqualFold(u.Properties.ValueType, r.Properties.ValueType) {
+			impact.ChangedValueTypes = append(impact.ChangedValueTypes, metadataDTOs.ValueTypeChange{
+				ResourceName: r.Name,
+				From:         r.Properties.ValueType,
+				To:           u.Properties.ValueType,
+			})
+		}
+	}
+	commands := make(map[string]bool, len(updated.DeviceCommands))
+	for _, c := range updated.DeviceCommands {
+		commands[c.Name] = true
+	}
+	for _, c := range stored.DeviceCommands {
+		if !commands[c.Name] {
+			impact.RemovedCommands = append(impact.RemovedCommands, c.Name)
+		}
+	}
+
+	// the AutoEvent source is either a device resource or a device command
+	removed := make(map[string]bool, len(impact.RemovedResources)+len(impact.RemovedCommands))
+	for _, name := range append(impact.RemovedResources, impact.RemovedCommands...) {
+		removed[name] = true
+	}
+	for _, device := range devices {
+		impact.AffectedDevices = append(impact.AffectedDevices, device.Name)
+		for _, autoEvent := range device.AutoEvents {
+			if removed[autoEvent.SourceName] {
+				impact.BrokenAutoEvents = append(impact.BrokenAutoEvents, metadataDTOs.BrokenAutoEvent{
+					DeviceName: device.Name,
+					SourceName: autoEvent.SourceName,
+				})
+			}
+		}
+	}
+
+	impact.Breaking = len(devices) > 0 &&
+		(len(impact.RemovedResources) > 0 || len(impact.RemovedCommands) > 0 || len(impact.ChangedValueTypes) > 0)
+	return impact
+}
+
+// checkDeviceProfileUpdateImpact rejects the breaking update of the device profile, and returns the latest revision of
+// the device profile checked, which the update must be applied to
+func checkDeviceProfileUpdateImpact(d models.DeviceProfile, dic *di.Container) (uint32, errors.EdgeX) {
+	impact, revision, err := deviceProfileUpdateImpact(d, dic)
+	if err != nil {
+		return 0, errors.NewCommonEdgeXWrapper(err)
+	}
+	if !impact.Breaking {
+		return revision, nil
+	}
+	var reasons []string
+	if len(impact.RemovedResources) > 0 {
+		reasons = append(reasons, fmt.Sprintf("removes device resources %v", impact.RemovedResources))
+	}
+	if len(impact.RemovedCommands) > 0 {
+		reasons = append(reasons, fmt.Sprintf("removes device commands %v", impact.RemovedCommands))
+	}
+	for _, c := range impact.ChangedValueTypes {
+		reasons = append(reasons, fmt.Sprintf("changes the value type of device resource %s from %s to %s", c.ResourceName, c.From, c.To))
+	}
+	return 0, errors.NewCommonEdgeX(errors.KindStatusConflict, fmt.Sprintf(
+		"the update of device profile %s %s, which affects %d device(s) and breaks %d AutoEvent(s); use force=true to update anyway",
+		d.Name, strings.Join(reasons, ", "), len(impact.AffectedDevices), len(impact.BrokenAutoEvents)), nil)
+}
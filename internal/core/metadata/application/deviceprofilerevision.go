@@ -0,0 +1,205 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+)
+
+// the fields maintained by core-metadata, which aren't part of the device profile definition
+var deviceProfileBookkeepingFields = map[string]bool{"id": true, "created": true, "modified": true}
+
+// DeviceProfileRevisions query the revisions of the device profile from the latest with offset and limit
+func DeviceProfileRevisions(offset int, limit int, name string, dic *di.Container) (revisions []metadataDTOs.DeviceProfileRevision, totalCount uint32, err errors.EdgeX) {
+	if name == "" {
+		return revisions, totalCount, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	dbClient := container.DBClientFrom(dic.Get)
+	exists, err := dbClient.DeviceProfileNameExists(name)
+	if err != nil {
+		return revisions, totalCount, errors.NewCommonEdgeXWrapper(err)
+	} else if !exists {
+		return revisions, totalCount, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("device profile '%s' does not exist", name), nil)
+	}
+	models, err := dbClient.DeviceProfileRevisions(offset, limit, name)
+	if err == nil {
+		totalCount, err = dbClient.DeviceProfileRevisionCount(name)
+	}
+	if err != nil {
+		return revisions, totalCount, errors.NewCommonEdgeXWrapper(err)
+	}
+	revisions = make([]metadataDTOs.DeviceProfileRevision, len(models))
+	for i, r := range models {
+		revisions[i] = metadataDTOs.FromDeviceProfileRevisionModelToDTO(r)
+	}
+	return revisions, totalCount, nil
+}
+
+// DeviceProfileRevision query the revision of the device profile by revision number
+func DeviceProfileRevision(name string, revision uint32, dic *di.Container) (deviceProfileRevision metadataDTOs.DeviceProfileRevision, err errors.EdgeX) {
+	if name == "" {
+		return deviceProfileRevision, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	dbClient := container.DBClientFrom(dic.Get)
+	r, err := dbClient.DeviceProfileRevision(name, revision)
+	if err != nil {
+		return deviceProfileRevision, errors.NewCommonEdgeXWrapper(err)
+	}
+	return metadataDTOs.FromDeviceProfileRevisionModelToDTO(r), nil
+}
+
+// DiffDeviceProfileRevisions returns the changes of the device profile from one revision to another
+func DiffDeviceProfileRevisions(name string, from uint32, to uint32, dic *di.Container) (diff metadataDTOs.DeviceProfileDiff, err errors.EdgeX) {
+	fromRevision, err := DeviceProfileRevision(name, from, dic)
+	if err != nil {
+		return diff, errors.NewCommonEdgeXWrapper(err)
+	}
+	toRevision, err := DeviceProfileRevision(name, to, dic)
+	if err != nil {
+		return diff, errors.NewCommonEdgeXWrapper(err)
+	}
+	changes, err := diffDeviceProfiles(fromRevision.Profile, toRevision.Profile)
+	if err != nil {
+		return diff, errors.NewCommonEdgeXWrapper(err)
+	}
+	return metadataDTOs.DeviceProfileDiff{
+		ProfileName:  name,
+		FromRevision: from,
+		ToRevision:   to,
+		Changes:      changes,
+	}, nil
+}
+
+// RollbackDeviceProfile updates the device profile with the definition of the revision.  The rollback is recorded as
+// a new revision, and the device services are notified as the device profile is updated.  The rollback breaking the
+// devices using the device profile is rejected unless it's forced.
+func RollbackDeviceProfile(name string, revision uint32, force bool, ctx context.Context, dic *di.Container) errors.EdgeX {
+	if name == "" {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	dbClient := container.DBClientFrom(dic.Get)
+	r, err := dbClient.DeviceProfileRevision(name, revision)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	err = UpdateDeviceProfile(r.Profile, force, ctx, dic)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+
+	bootstrapContainer.LoggingClientFrom(dic.Get).Debugf("DeviceProfile %s rolled back to revision %d. Correlation-id: %s", name, revision, correlation.FromContext(ctx))
+	return nil
+}
+
+// diffDeviceProfiles compares the JSON representations of the device profiles field by field
+func diffDeviceProfiles(from dtos.DeviceProfile, to dtos.DeviceProfile) ([]metadataDTOs.DeviceProfileChange, errors.EdgeX) {
+	var values [2]interface{}
+	for i, dp := range []dtos.DeviceProfile{from, to} {
+		data, err := json.Marshal(dp)
+		if err == nil {
+			err = json.Unmarshal(data, &values[i])
+		}
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindServerError, "fail to encode the device profile for comparison", err)
+		}
+	}
+	for _, v := range values {
+		for field := range deviceProfileBookkeepingFields {
+			delete(v.(map[string]interface{}), field)
+		}
+	}
+
+	changes := []metadataDTOs.DeviceProfileChange{}
+	diffValues("", values[0], values[1], &changes)
+	return changes, nil
+}
+
+func diffValues(path string, from interface{}, to interface{}, changes *[]metadataDTOs.DeviceProfileChange) {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := make([]string, 0, len(fromMap)+len(toMap))
+		for k := range fromMap {
+			keys = append(keys, k)
+		}
+		for k := range toMap {
+			if _, ok := fromMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+			diffValues(fieldPath, fromMap[k], toMap[k], changes)
+		}
+		return
+	}
+
+	fromNames, fromElements, fromNamed := namedElements(from)
+	toNames, toElements, toNamed := namedElements(to)
+	if fromNamed && toNamed {
+		names := fromNames
+		for _, name := range toNames {
+			if _, ok := fromElements[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			diffValues(fmt.Sprintf("%s[%s]", path, name), fromElements[name], toElements[name], changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	change := metadataDTOs.DeviceProfileChange{Path: path, Kind: metadataDTOs.DeviceProfileChangeUpdated, From: from, To: to}
+	if from == nil {
+		change.Kind = metadataDTOs.DeviceProfileChangeAdded
+	} else if to == nil {
+		change.Kind = metadataDTOs.DeviceProfileChangeRemoved
+	}
+	*changes = append(*changes, change)
+}
+
+// namedElements indexes the elements by name if the value is an array of the objects having unique names, e.g. the
+// deviceResources and deviceCommands, so that the elements are compared by name rather than position
+func namedElements(value interface{}) (names []string, named map[string]interface{}, ok bool) {
+	elements, ok := value.([]interface{})
+	if !ok && value != nil {
+		return nil, nil, false
+	}
+	named = make(map[string]interface{}, len(elements))
+	for _, e := range elements {
+		object, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, nil, false
+		}
+		name, ok := object["name"].(string)
+		if _, exists := named[name]; !ok || exists {
+			return nil, nil, false
+		}
+		names = append(names, name)
+		named[name] = object
+	}
+	return names, named, true
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -38,37 +39,41 @@ func AddDeviceService(d models.DeviceService, ctx context.Context, dic *di.Conta
 		addedDeviceService.Id,
 		correlationId,
 	)
-
+	publishSystemEvent(pkgDTOs.DeviceServiceSystemEventType, pkgDTOs.SystemEventActionAdd, addedDeviceService.Name, dtos.FromDeviceServiceModelToDTO(addedDeviceService), ctx, dic)
 	return addedDeviceService.Id, nil
 }
 
-// DeviceServiceByName query the device service by name
-func DeviceServiceByName(name string, ctx context.Context, dic *di.Container) (deviceService dtos.DeviceService, err errors.EdgeX) {
+// DeviceServiceByName query the device service and its revision by name
+func DeviceServiceByName(name string, ctx context.Context, dic *di.Container) (deviceService dtos.DeviceService, revision uint64, err errors.EdgeX) {
 	if name == "" {
-		return deviceService, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+		return deviceService, revision, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
 	}
 	dbClient := container.DBClientFrom(dic.Get)
-	ds, err := dbClient.DeviceServiceByName(name)
+	ds, revision, err := dbClient.DeviceServiceAndRevisionByName(name)
 	if err != nil {
-		return deviceService, errors.NewCommonEdgeXWrapper(err)
+		return deviceService, revision, errors.NewCommonEdgeXWrapper(err)
 	}
 	deviceService = dtos.FromDeviceServiceModelToDTO(ds)
-	return deviceService, nil
+	return deviceService, revision, nil
 }
 
 // PatchDeviceService executes the PATCH operation with the device service DTO to replace the old data
-func PatchDeviceService(dto dtos.UpdateDeviceService, ctx context.Context, dic *di.Container) errors.EdgeX {
+func PatchDeviceService(dto dtos.UpdateDeviceService, expectedRevision *uint64, ctx context.Context, dic *di.Container) errors.EdgeX {
 	dbClient := container.DBClientFrom(dic.Get)
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 
-	deviceService, err := deviceServiceByDTO(dbClient, dto)
+	deviceService, revision, err := deviceServiceByDTO(dbClient, dto)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	err = checkRevision("device service", deviceService.Name, revision, expectedRevision)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
 
 	requests.ReplaceDeviceServiceModelFieldsWithDTO(&deviceService, dto)
 
-	err = dbClient.UpdateDeviceService(deviceService)
+	err = dbClient.UpdateDeviceServiceWithRevision(deviceService, revision)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
@@ -77,27 +82,36 @@ func PatchDeviceService(dto dtos.UpdateDeviceService, ctx context.Context, dic *
 		"DeviceService patched on DB successfully. Correlation-ID: %s ",
 		correlation.FromContext(ctx),
 	)
-	go updateDeviceServiceCallback(ctx, dic, deviceService)
+	updateDeviceServiceCallback(ctx, dic, deviceService)
+	publishSystemEvent(pkgDTOs.DeviceServiceSystemEventType, pkgDTOs.SystemEventActionUpdate, deviceService.Name, dtos.FromDeviceServiceModelToDTO(deviceService), ctx, dic)
 	return nil
 }
 
-func deviceServiceByDTO(dbClient interfaces.DBClient, dto dtos.UpdateDeviceService) (deviceService models.DeviceService, err errors.EdgeX) {
+// deviceServiceByDTO returns the device service to patch and its revision
+func deviceServiceByDTO(dbClient interfaces.DBClient, dto dtos.UpdateDeviceService) (deviceService models.DeviceService, revision uint64, err errors.EdgeX) {
 	// The ID or Name is required by DTO and the DTO also accepts empty string ID if the Name is provided
+	name := ""
 	if dto.Id != nil && *dto.Id != "" {
 		deviceService, err = dbClient.DeviceServiceById(*dto.Id)
 		if err != nil {
-			return deviceService, errors.NewCommonEdgeXWrapper(err)
+			return deviceService, revision, errors.NewCommonEdgeXWrapper(err)
 		}
+		name = deviceService.Name
 	} else {
-		deviceService, err = dbClient.DeviceServiceByName(*dto.Name)
-		if err != nil {
-			return deviceService, errors.NewCommonEdgeXWrapper(err)
-		}
+		name = *dto.Name
+	}
+	if dto.Name != nil && *dto.Name != name {
+		return deviceService, revision, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("device service name '%s' not match the exsting '%s' ", *dto.Name, name), nil)
 	}
-	if dto.Name != nil && *dto.Name != deviceService.Name {
-		return deviceService, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("device service name '%s' not match the exsting '%s' ", *dto.Name, deviceService.Name), nil)
+	id := deviceService.Id
+	deviceService, revision, err = dbClient.DeviceServiceAndRevisionByName(name)
+	if err != nil {
+		return deviceService, revision, errors.NewCommonEdgeXWrapper(err)
+	}
+	if id != "" && id != deviceService.Id {
+		return deviceService, revision, errors.NewCommonEdgeX(errors.KindStatusConflict, fmt.Sprintf("device service '%s' was replaced by another device service with the same name", name), nil)
 	}
-	return deviceService, nil
+	return deviceService, revision, nil
 }
 
 // DeleteDeviceServiceByName delete the device service by name
@@ -123,10 +137,22 @@ func DeleteDeviceServiceByName(name string, ctx context.Context, dic *di.Contain
 		return errors.NewCommonEdgeX(errors.KindStatusConflict, "fail to delete the device service when associated provisionWatcher exists", nil)
 	}
 
+	// The deleted device service is published with the system event
+	deviceService, err := dbClient.DeviceServiceByName(name)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
 	err = dbClient.DeleteDeviceServiceByName(name)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
+	// the callbacks can't be delivered to the removed device service
+	if queue := container.CallbackQueueFrom(dic.Get); queue != nil {
+		if err = queue.Discard(name); err != nil {
+			bootstrapContainer.LoggingClientFrom(dic.Get).Errorf("fail to discard the callbacks of device service %s, err: %v", name, err)
+		}
+	}
+	publishSystemEvent(pkgDTOs.DeviceServiceSystemEventType, pkgDTOs.SystemEventActionDelete, name, dtos.FromDeviceServiceModelToDTO(deviceService), ctx, dic)
 	return nil
 }
 
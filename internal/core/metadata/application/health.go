@@ -0,0 +1,264 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	clients "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/http"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/health"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+)
+
+// healthUpdateAttempts is how many times the operating state of a device is updated before giving up on the conflicts
+// with the other updates
+const healthUpdateAttempts = 3
+
+// StartHealthMonitor starts checking the liveness of the device services periodically until ctx is done, unless the
+// health check is disabled
+func StartHealthMonitor(ctx context.Context, wg *sync.WaitGroup, dic *di.Container) errors.EdgeX {
+	info := metadataContainer.ConfigurationFrom(dic.Get).HealthCheck
+	lc := container.LoggingClientFrom(dic.Get)
+	if !info.Enabled {
+		lc.Info("Device service health check is disabled")
+		return nil
+	}
+	durations := make(map[string]time.Duration)
+	for name, value := range map[string]string{"Interval": info.Interval, "Timeout": info.Timeout} {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%s %s is not a positive duration", name, value), err)
+		}
+		durations[name] = d
+	}
+	if info.FailureThreshold < 1 {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("FailureThreshold %d is less than 1", info.FailureThreshold), nil)
+	}
+
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	list := func() ([]models.DeviceService, error) {
+		return dbClient.AllDeviceServices(0, -1, nil)
+	}
+	check := func(ctx context.Context, ds models.DeviceService) error {
+		ctx, cancel := context.WithTimeout(ctx, durations["Timeout"])
+		defer cancel()
+		if _, err := clients.NewCommonClient(ds.BaseAddress).Ping(ctx); err != nil {
+			return err
+		}
+		updateDeviceServiceLastConnected(ds.Name, dic)
+		return nil
+	}
+	changed := func(ctx context.Context, health metadataDTOs.DeviceServiceHealth, previous string) {
+		deviceServiceOperatingStateChanged(ctx, dic, health, previous)
+	}
+	policy := health.Policy{
+		Interval:         durations["Interval"],
+		FailureThreshold: info.FailureThreshold,
+	}
+	monitor := health.NewMonitor(list, check, changed, policy, lc)
+	seedHealthMonitor(monitor, dic)
+	dic.Update(di.ServiceConstructorMap{
+		metadataContainer.HealthMonitorName: func(get di.Get) interface{} {
+			return monitor
+		},
+	})
+	monitor.Start(ctx, wg)
+
+	lc.Infof("Device service health check started, every %s", info.Interval)
+	return nil
+}
+
+// seedHealthMonitor seeds the device services whose devices were marked DOWN by the health check before the service
+// restart as DOWN, so that their devices are marked UP again once the device services recover
+func seedHealthMonitor(monitor *health.Monitor, dic *di.Container) {
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	lc := container.LoggingClientFrom(dic.Get)
+	services, err := dbClient.AllDeviceServices(0, -1, nil)
+	if err != nil {
+		lc.Errorf("failed to list the device services to seed the health check, %v", err)
+		return
+	}
+	for _, ds := range services {
+		names, err := dbClient.DevicesDownByHealthCheck(ds.Name)
+		if err != nil {
+			lc.Errorf("failed to query the devices marked DOWN by the health check of the device service %s, %v", ds.Name, err)
+			continue
+		}
+		if len(names) > 0 {
+			monitor.Seed(ds.Name, models.Down)
+		}
+	}
+}
+
+// updateDeviceServiceLastConnected updates the LastConnected of the device service to now, without changing the other
+// fields or the revision of the device service
+func updateDeviceServiceLastConnected(name string, dic *di.Container) {
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	lc := container.LoggingClientFrom(dic.Get)
+	err := dbClient.UpdateDeviceServiceLastConnected(name, time.Now().UnixNano())
+	if errors.Kind(err) == errors.KindStatusConflict {
+		// the device service is updated at the same time, and the last connected time is updated by the next check
+		lc.Debugf("skip updating the last connected time of the device service %s, %v", name, err)
+	} else if err != nil {
+		lc.Errorf("failed to update the last connected time of the device service %s, %v", name, err)
+	}
+}
+
+// deviceServiceOperatingStateChanged marks the UP devices of the device service DOWN when the device service is DOWN,
+// or marks the devices it marked DOWN UP again when the device service recovers, and then sends the notification of the
+// change.  The devices marked DOWN are persisted, so that they are marked UP again even after the service restart,
+// while the devices marked DOWN by the user are left as they are.
+func deviceServiceOperatingStateChanged(ctx context.Context, dic *di.Container, health metadataDTOs.DeviceServiceHealth, previous string) {
+	lc := container.LoggingClientFrom(dic.Get)
+
+	var updated int
+	if health.OperatingState == models.Down {
+		updated = markDevicesDown(ctx, dic, health.ServiceName)
+	} else {
+		updated = markDevicesUp(ctx, dic, health.ServiceName)
+	}
+	lc.Infof("%d devices of the device service %s are marked %s", updated, health.ServiceName, health.OperatingState)
+
+	severity := models.Normal
+	if health.OperatingState == models.Down {
+		severity = models.Critical
+	}
+	action := fmt.Sprintf("operating state changed from %s to %s", previous, health.OperatingState)
+	if health.LastError != "" {
+		action = fmt.Sprintf("%s, %s", action, health.LastError)
+	}
+	postNotification(ctx, dic, health.ServiceName, action, severity)
+}
+
+// markDevicesDown marks the UP devices of the device service DOWN, and records them before they are marked so that
+// none of them is left DOWN once the device service recovers
+func markDevicesDown(ctx context.Context, dic *di.Container, serviceName string) int {
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	lc := container.LoggingClientFrom(dic.Get)
+
+	devices, err := dbClient.DevicesByServiceName(0, -1, serviceName)
+	if err != nil {
+		lc.Errorf("failed to query the devices of the device service %s, %v", serviceName, err)
+		return 0
+	}
+	var names []string
+	for _, d := range devices {
+		if d.OperatingState != models.Down {
+			names = append(names, d.Name)
+		}
+	}
+	if err := dbClient.AddDevicesDownByHealthCheck(serviceName, names); err != nil {
+		lc.Errorf("failed to record the devices of the device service %s to be marked DOWN, %v", serviceName, err)
+		return 0
+	}
+	updated, _ := updateDevicesOperatingState(ctx, dic, serviceName, names, models.Down)
+	return updated
+}
+
+// markDevicesUp marks the devices which were marked DOWN by the health check UP again, and then forgets them
+func markDevicesUp(ctx context.Context, dic *di.Container, serviceName string) int {
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	lc := container.LoggingClientFrom(dic.Get)
+
+	names, err := dbClient.DevicesDownByHealthCheck(serviceName)
+	if err != nil {
+		lc.Errorf("failed to query the devices marked DOWN by the health check of the device service %s, %v", serviceName, err)
+		return 0
+	}
+	updated, failed := updateDevicesOperatingState(ctx, dic, serviceName, names, models.Up)
+	if failed > 0 {
+		// keep the record to retry on the next recovery
+		return updated
+	}
+	if err := dbClient.DeleteDevicesDownByHealthCheck(serviceName); err != nil {
+		lc.Errorf("failed to forget the devices marked DOWN by the health check of the device service %s, %v", serviceName, err)
+	}
+	return updated
+}
+
+// updateDevicesOperatingState marks the named devices of the device service with the operating state, and returns the
+// number of the updated devices and of the devices failed to be updated.  Each device is read again along with its
+// revision and updated at that revision, so that only its operating state is changed and the update made by a user in
+// the meantime is never reverted.  The update is retried on the conflict with another update.  The devices removed,
+// moved to another device service, or already marked by a user in the meantime are skipped.
+func updateDevicesOperatingState(ctx context.Context, dic *di.Container, serviceName string, names []string, operatingState models.OperatingState) (updated int, failed int) {
+	dbClient := metadataContainer.DBClientFrom(dic.Get)
+	lc := container.LoggingClientFrom(dic.Get)
+	for _, name := range names {
+		for attempt := 1; ; attempt++ {
+			d, revision, err := dbClient.DeviceAndRevisionByName(name)
+			if errors.Kind(err) == errors.KindEntityDoesNotExist {
+				break
+			} else if err != nil {
+				lc.Errorf("failed to query the device %s to mark it %s, %v", name, operatingState, err)
+				failed++
+				break
+			}
+			// the UP devices are marked DOWN, and only the DOWN devices are marked UP again
+			if d.ServiceName != serviceName || (d.OperatingState == models.Down) == (operatingState == models.Down) {
+				break
+			}
+			d.OperatingState = operatingState
+			err = dbClient.UpdateDeviceWithRevision(d, revision)
+			if err == nil {
+				updated++
+				publishSystemEvent(pkgDTOs.DeviceSystemEventType, pkgDTOs.SystemEventActionUpdate, d.ServiceName, dtos.FromDeviceModelToDTO(d), ctx, dic)
+				break
+			}
+			if errors.Kind(err) == errors.KindStatusConflict && attempt < healthUpdateAttempts {
+				continue
+			}
+			lc.Errorf("failed to mark the device %s %s, %v", name, operatingState, err)
+			failed++
+			break
+		}
+	}
+	return updated, failed
+}
+
+// DeviceServiceHealthByName returns the liveness of the device service
+func DeviceServiceHealthByName(name string, dic *di.Container) (metadataDTOs.DeviceServiceHealth, errors.EdgeX) {
+	if name == "" {
+		return metadataDTOs.DeviceServiceHealth{}, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+	}
+	monitor, err := healthMonitor(dic)
+	if err != nil {
+		return metadataDTOs.DeviceServiceHealth{}, errors.NewCommonEdgeXWrapper(err)
+	}
+	h, ok := monitor.Health(name)
+	if !ok {
+		return h, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("device service %s is not checked yet", name), nil)
+	}
+	return h, nil
+}
+
+// AllDeviceServiceHealth returns the liveness of all the checked device services
+func AllDeviceServiceHealth(dic *di.Container) ([]metadataDTOs.DeviceServiceHealth, errors.EdgeX) {
+	monitor, err := healthMonitor(dic)
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	return monitor.AllHealth(), nil
+}
+
+func healthMonitor(dic *di.Container) (*health.Monitor, errors.EdgeX) {
+	monitor := metadataContainer.HealthMonitorFrom(dic.Get)
+	if monitor == nil {
+		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, "device service health check is not enabled", nil)
+	}
+	return monitor, nil
+}
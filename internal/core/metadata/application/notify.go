@@ -7,10 +7,13 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
 	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -19,6 +22,7 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/interfaces"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
@@ -31,78 +35,37 @@ const (
 )
 
 func newDeviceServiceCallbackClient(ctx context.Context, dic *di.Container, deviceServiceName string) (interfaces.DeviceServiceCallbackClient, errors.EdgeX) {
-	ds, err := DeviceServiceByName(deviceServiceName, ctx, dic)
+	ds, err := metadataContainer.DBClientFrom(dic.Get).DeviceServiceByName(deviceServiceName)
 	if err != nil {
 		return nil, errors.NewCommonEdgeXWrapper(err)
 	}
 	return clients.NewDeviceServiceCallbackClient(ds.BaseAddress), nil
 }
 
-// addDeviceCallback invoke device service's callback function for adding new device
+// addDeviceCallback queues the callback to the device service for adding new device
 func addDeviceCallback(ctx context.Context, dic *di.Container, device dtos.Device) {
-	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, device.ServiceName)
-	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", device.ServiceName, err)
-		return
-	}
-
-	request := requests.NewAddDeviceRequest(device)
-	response, err := deviceServiceCallbackClient.AddDeviceCallback(ctx, request)
-	if err != nil {
-		lc.Errorf("fail to invoke device service callback for adding device %s, err: %v", device.Name, err)
-		return
-	}
-	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for adding device %s, err: %s", device.Name, response.Message)
-	}
+	enqueueCallback(ctx, dic, device.ServiceName, metadataDTOs.CallbackAddDevice, device.Name, device)
+}
 
-	go sendNotification(ctx, dic, device.Name, deviceCreateAction)
+// addDevicesCallback queues a single callback to the device service for adding the batch of new devices.  The batch only
+// saves the queue entries, the devices are still added to the device service by one AddDevice callback request each,
+// in order, as the device services have no callback for adding multiple devices.
+func addDevicesCallback(ctx context.Context, dic *di.Container, serviceName string, devices []dtos.Device) {
+	enqueueCallback(ctx, dic, serviceName, metadataDTOs.CallbackAddDevices, fmt.Sprintf("%d devices", len(devices)), devices)
 }
 
-// updateDeviceCallback invoke device service's callback function for updating device
+// updateDeviceCallback queues the callback to the device service for updating device
 func updateDeviceCallback(ctx context.Context, dic *di.Container, serviceName string, device models.Device) {
-	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, serviceName)
-	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", serviceName, err)
-		return
-	}
-
-	request := requests.NewUpdateDeviceRequest(dtos.FromDeviceModelToUpdateDTO(device))
-	response, err := deviceServiceCallbackClient.UpdateDeviceCallback(ctx, request)
-	if err != nil {
-		lc.Errorf("fail to invoke device service callback for updating device %s, err: %v", device.Name, err)
-		return
-	}
-	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for updating device %s, err: %s", device.Name, response.Message)
-	}
-
-	go sendNotification(ctx, dic, device.Name, deviceUpdateAction)
+	enqueueCallback(ctx, dic, serviceName, metadataDTOs.CallbackUpdateDevice, device.Name, dtos.FromDeviceModelToUpdateDTO(device))
 }
 
-// deleteDeviceCallback invoke device service's callback function for deleting device
+// deleteDeviceCallback queues the callback to the device service for deleting device
 func deleteDeviceCallback(ctx context.Context, dic *di.Container, device models.Device) {
-	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, device.ServiceName)
-	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", device.ServiceName, err)
-		return
-	}
-	response, err := deviceServiceCallbackClient.DeleteDeviceCallback(ctx, device.Name)
-	if err != nil {
-		lc.Errorf("fail to invoke device service callback for deleting device %s, err: %v", device.Name, err)
-		return
-	}
-	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for deleting device %s, err: %s", device.Name, response.Message)
-	}
-
-	go sendNotification(ctx, dic, device.Name, deviceRemoveAction)
+	enqueueCallback(ctx, dic, device.ServiceName, metadataDTOs.CallbackDeleteDevice, device.Name, nil)
 }
 
-// updateDeviceProfileCallback invoke device service's callback function for updating device profile
+// updateDeviceProfileCallback queues the callback to each device service of the devices using the device profile for
+// updating device profile
 func updateDeviceProfileCallback(ctx context.Context, dic *di.Container, deviceProfile dtos.DeviceProfile) {
 	lc := container.LoggingClientFrom(dic.Get)
 	devices, _, err := DevicesByProfileName(0, -1, deviceProfile.Name, dic)
@@ -110,113 +73,184 @@ func updateDeviceProfileCallback(ctx context.Context, dic *di.Container, deviceP
 		lc.Errorf("fail to query associated devices by deviceProfile name %s, err: %v", deviceProfile.Name, err)
 		return
 	}
-	// Invoke callback for each device service
+	// Queue the callback for each device service
 	dsMap := make(map[string]bool)
 	for _, d := range devices {
 		if _, ok := dsMap[d.ServiceName]; ok {
-			// skip the invoked device service
+			// skip the queued device service
 			continue
 		}
 		dsMap[d.ServiceName] = true
-
-		deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, d.ServiceName)
-		if err != nil {
-			lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", d.ServiceName, err)
-			continue
-		}
-
-		request := requests.NewDeviceProfileRequest(deviceProfile)
-		response, err := deviceServiceCallbackClient.UpdateDeviceProfileCallback(ctx, request)
-		if err != nil {
-			lc.Errorf("fail to invoke device service callback for updating device profile %s, err: %v", deviceProfile.Name, err)
-			continue
-		}
-		if response.StatusCode != http.StatusOK {
-			lc.Errorf("fail to invoke device service callback for updating device profile %s, err: %s", deviceProfile.Name, response.Message)
-		}
+		enqueueCallback(ctx, dic, d.ServiceName, metadataDTOs.CallbackUpdateDeviceProfile, deviceProfile.Name, deviceProfile)
 	}
 }
 
-// addProvisionWatcherCallback invoke device service's callback function for adding new provision watcher
+// addProvisionWatcherCallback queues the callback to the device service for adding new provision watcher
 func addProvisionWatcherCallback(ctx context.Context, dic *di.Container, pw dtos.ProvisionWatcher) {
-	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, pw.ServiceName)
-	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", pw.ServiceName, err)
-		return
-	}
-
-	request := requests.NewAddProvisionWatcherRequest(pw)
-	response, err := deviceServiceCallbackClient.AddProvisionWatcherCallback(ctx, request)
-	if err != nil {
-		lc.Errorf("fail to invoke device service callback for adding  provision watcher %s, err: %v", pw.Name, err)
-		return
-	}
-	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for adding  provision watcher %s, err: %s", pw.Name, response.Message)
-	}
+	enqueueCallback(ctx, dic, pw.ServiceName, metadataDTOs.CallbackAddProvisionWatcher, pw.Name, pw)
 }
 
-// updateProvisionWatcherCallback invoke device service's callback function for updating provision watcher
+// updateProvisionWatcherCallback queues the callback to the device service for updating provision watcher
 func updateProvisionWatcherCallback(ctx context.Context, dic *di.Container, serviceName string, pw models.ProvisionWatcher) {
+	enqueueCallback(ctx, dic, serviceName, metadataDTOs.CallbackUpdateProvisionWatcher, pw.Name, dtos.FromProvisionWatcherModelToUpdateDTO(pw))
+}
+
+// deleteProvisionWatcherCallback queues the callback to the device service for deleting provision watcher
+func deleteProvisionWatcherCallback(ctx context.Context, dic *di.Container, pw models.ProvisionWatcher) {
+	enqueueCallback(ctx, dic, pw.ServiceName, metadataDTOs.CallbackDeleteProvisionWatcher, pw.Name, nil)
+}
+
+// updateDeviceServiceCallback queues the callback to the device service for updating device service
+func updateDeviceServiceCallback(ctx context.Context, dic *di.Container, ds models.DeviceService) {
+	enqueueCallback(ctx, dic, ds.Name, metadataDTOs.CallbackUpdateDeviceService, ds.Name, dtos.FromDeviceServiceModelToUpdateDTO(ds))
+}
+
+// enqueueCallback queues the callback with the JSON encoded payload to the device service.  The callback is delivered
+// once in the background instead when the callback queue is not available.
+func enqueueCallback(ctx context.Context, dic *di.Container, serviceName string, action string, name string, payload interface{}) {
 	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, serviceName)
-	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", serviceName, err)
-		return
+	callback := metadataDTOs.Callback{
+		ServiceName:   serviceName,
+		Action:        action,
+		Name:          name,
+		CorrelationId: correlation.FromContext(ctx),
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			lc.Errorf("fail to encode the %s callback of %s, err: %v", action, name, err)
+			return
+		}
+		callback.Payload = data
 	}
 
-	request := requests.NewUpdateProvisionWatcherRequest(dtos.FromProvisionWatcherModelToUpdateDTO(pw))
-	response, err := deviceServiceCallbackClient.UpdateProvisionWatcherCallback(ctx, request)
-	if err != nil {
-		lc.Errorf("fail to invoke device service callback for updating provision watcher %s, err: %v", pw.Name, err)
+	queue := metadataContainer.CallbackQueueFrom(dic.Get)
+	if queue == nil {
+		go func() {
+			if err := deliverCallback(ctx, dic, &callback); err != nil {
+				lc.Errorf("fail to invoke device service callback %s for %s, err: %v", action, name, err)
+			}
+		}()
 		return
 	}
-	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for updating provision watcher %s, err: %s", pw.Name, response.Message)
+	if err := queue.Enqueue(callback); err != nil {
+		lc.Errorf("fail to queue the %s callback of %s to device service %s, err: %v", action, name, serviceName, err)
 	}
 }
 
-// deleteProvisionWatcherCallback invoke device service's callback function for deleting provision watcher
-func deleteProvisionWatcherCallback(ctx context.Context, dic *di.Container, pw models.ProvisionWatcher) {
+// deliverCallback invokes the device service's callback function.  The callback to the device service which doesn't
+// exist any more is discarded.
+func deliverCallback(ctx context.Context, dic *di.Container, callback *metadataDTOs.Callback) errors.EdgeX {
 	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, pw.ServiceName)
+	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, callback.ServiceName)
 	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", pw.ServiceName, err)
-		return
+		if errors.Kind(err) == errors.KindEntityDoesNotExist {
+			lc.Warnf("device service %s doesn't exist, discard the %s callback of %s", callback.ServiceName, callback.Action, callback.Name)
+			return nil
+		}
+		return errors.NewCommonEdgeX(errors.Kind(err), fmt.Sprintf("fail to new a device service callback client by serviceName %s", callback.ServiceName), err)
+	}
+
+	var response commonDTO.BaseResponse
+	var notifiedAction string
+	switch callback.Action {
+	case metadataDTOs.CallbackAddDevice:
+		var device dtos.Device
+		if err = decodeCallbackPayload(callback, &device); err == nil {
+			response, err = deviceServiceCallbackClient.AddDeviceCallback(ctx, requests.NewAddDeviceRequest(device))
+		}
+		notifiedAction = deviceCreateAction
+	case metadataDTOs.CallbackAddDevices:
+		if err = deliverAddDevicesCallback(ctx, dic, deviceServiceCallbackClient, callback); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+		return nil
+	case metadataDTOs.CallbackUpdateDevice:
+		var device dtos.UpdateDevice
+		if err = decodeCallbackPayload(callback, &device); err == nil {
+			response, err = deviceServiceCallbackClient.UpdateDeviceCallback(ctx, requests.NewUpdateDeviceRequest(device))
+		}
+		notifiedAction = deviceUpdateAction
+	case metadataDTOs.CallbackDeleteDevice:
+		response, err = deviceServiceCallbackClient.DeleteDeviceCallback(ctx, callback.Name)
+		notifiedAction = deviceRemoveAction
+	case metadataDTOs.CallbackUpdateDeviceProfile:
+		var deviceProfile dtos.DeviceProfile
+		if err = decodeCallbackPayload(callback, &deviceProfile); err == nil {
+			response, err = deviceServiceCallbackClient.UpdateDeviceProfileCallback(ctx, requests.NewDeviceProfileRequest(deviceProfile))
+		}
+	case metadataDTOs.CallbackAddProvisionWatcher:
+		var pw dtos.ProvisionWatcher
+		if err = decodeCallbackPayload(callback, &pw); err == nil {
+			response, err = deviceServiceCallbackClient.AddProvisionWatcherCallback(ctx, requests.NewAddProvisionWatcherRequest(pw))
+		}
+	case metadataDTOs.CallbackUpdateProvisionWatcher:
+		var pw dtos.UpdateProvisionWatcher
+		if err = decodeCallbackPayload(callback, &pw); err == nil {
+			response, err = deviceServiceCallbackClient.UpdateProvisionWatcherCallback(ctx, requests.NewUpdateProvisionWatcherRequest(pw))
+		}
+	case metadataDTOs.CallbackDeleteProvisionWatcher:
+		response, err = deviceServiceCallbackClient.DeleteProvisionWatcherCallback(ctx, callback.Name)
+	case metadataDTOs.CallbackUpdateDeviceService:
+		var ds dtos.UpdateDeviceService
+		if err = decodeCallbackPayload(callback, &ds); err == nil {
+			response, err = deviceServiceCallbackClient.UpdateDeviceServiceCallback(ctx, requests.NewUpdateDeviceServiceRequest(ds))
+		}
+	default:
+		err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unknown callback action %s", callback.Action), nil)
 	}
-	response, err := deviceServiceCallbackClient.DeleteProvisionWatcherCallback(ctx, pw.Name)
 	if err != nil {
-		lc.Errorf("fail to invoke device service callback for deleting provision watcher %s, err: %v", pw.Name, err)
-		return
+		return errors.NewCommonEdgeXWrapper(err)
 	}
 	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for deleting provision watcher %s, err: %s", pw.Name, response.Message)
+		return errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("device service %s responded %d, %s", callback.ServiceName, response.StatusCode, response.Message), nil)
 	}
-}
 
-// updateDeviceServiceCallback invoke device service's callback function for updating device service
-func updateDeviceServiceCallback(ctx context.Context, dic *di.Container, ds models.DeviceService) {
-	lc := container.LoggingClientFrom(dic.Get)
-	deviceServiceCallbackClient, err := newDeviceServiceCallbackClient(ctx, dic, ds.Name)
-	if err != nil {
-		lc.Errorf("fail to new a device service callback client by serviceName %s, err: %v", ds.Name, err)
-		return
+	if notifiedAction != "" {
+		go sendNotification(ctx, dic, callback.Name, notifiedAction)
 	}
+	return nil
+}
 
-	request := requests.NewUpdateDeviceServiceRequest(dtos.FromDeviceServiceModelToUpdateDTO(ds))
-	response, err := deviceServiceCallbackClient.UpdateDeviceServiceCallback(ctx, request)
-	if err != nil {
-		lc.Errorf("fail to invoke device service callback for updating device service %s, err: %v", ds.Name, err)
-		return
+// deliverAddDevicesCallback adds the devices of the batch to the device service one by one, i.e. one AddDeviceCallback
+// request per device.  When a device fails to be added, the payload is replaced with the devices not added yet, so that
+// the retry doesn't add the added ones again.
+func deliverAddDevicesCallback(ctx context.Context, dic *di.Container, client interfaces.DeviceServiceCallbackClient, callback *metadataDTOs.Callback) errors.EdgeX {
+	var devices []dtos.Device
+	if err := decodeCallbackPayload(callback, &devices); err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
 	}
-	if response.StatusCode != http.StatusOK {
-		lc.Errorf("fail to invoke device service callback for updating device service %s, err: %s", ds.Name, response.Message)
+	for i, device := range devices {
+		response, err := client.AddDeviceCallback(ctx, requests.NewAddDeviceRequest(device))
+		if err == nil && response.StatusCode != http.StatusOK {
+			err = errors.NewCommonEdgeX(errors.KindServerError, fmt.Sprintf("device service %s responded %d, %s", callback.ServiceName, response.StatusCode, response.Message), nil)
+		}
+		if err != nil {
+			if data, encodeErr := json.Marshal(devices[i:]); encodeErr == nil {
+				callback.Payload = data
+				callback.Name = fmt.Sprintf("%d devices", len(devices)-i)
+			}
+			return errors.NewCommonEdgeX(errors.Kind(err), fmt.Sprintf("fail to add device %s to device service %s", device.Name, callback.ServiceName), err)
+		}
+		go sendNotification(ctx, dic, device.Name, deviceCreateAction)
+	}
+	return nil
+}
+
+func decodeCallbackPayload(callback *metadataDTOs.Callback, v interface{}) errors.EdgeX {
+	if err := json.Unmarshal(callback.Payload, v); err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("fail to decode the %s callback payload of %s", callback.Action, callback.Name), err)
 	}
+	return nil
 }
 
 // sendNotification sends a notification after adding or updating the metadata
 func sendNotification(ctx context.Context, dic *di.Container, name string, action string) {
+	postNotification(ctx, dic, name, action, models.Normal)
+}
+
+// postNotification sends a notification of the severity about the action on the metadata
+func postNotification(ctx context.Context, dic *di.Container, name string, action string, severity string) {
 	config := metadataContainer.ConfigurationFrom(dic.Get)
 	if !config.Notifications.PostDeviceChanges {
 		return
@@ -230,7 +264,7 @@ func sendNotification(ctx context.Context, dic *di.Container, name string, actio
 		Description: config.Notifications.Description,
 		Labels:      []string{config.Notifications.Label},
 		Sender:      config.Notifications.Sender,
-		Severity:    models.Normal,
+		Severity:    severity,
 	}
 
 	req := requests.NewAddNotificationRequest(dto)
@@ -12,6 +12,7 @@ import (
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
@@ -28,6 +29,10 @@ func AddProvisionWatcher(pw models.ProvisionWatcher, ctx context.Context, dic *d
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 	correlationId := correlation.FromContext(ctx)
 
+	err = validateProvisionWatcherIdentifiers(pw.Identifiers)
+	if err != nil {
+		return "", errors.NewCommonEdgeXWrapper(err)
+	}
 	exists, err := dbClient.DeviceServiceNameExists(pw.ServiceName)
 	if err != nil {
 		return "", errors.NewCommonEdgeXWrapper(err)
@@ -50,20 +55,21 @@ func AddProvisionWatcher(pw models.ProvisionWatcher, ctx context.Context, dic *d
 		addProvisionWatcher.Id,
 		correlationId,
 	)
-	go addProvisionWatcherCallback(ctx, dic, dtos.FromProvisionWatcherModelToDTO(pw))
+	addProvisionWatcherCallback(ctx, dic, dtos.FromProvisionWatcherModelToDTO(pw))
+	publishSystemEvent(pkgDTOs.ProvisionWatcherSystemEventType, pkgDTOs.SystemEventActionAdd, addProvisionWatcher.ServiceName, dtos.FromProvisionWatcherModelToDTO(addProvisionWatcher), ctx, dic)
 	return addProvisionWatcher.Id, nil
 }
 
-// ProvisionWatcherByName query the provision watcher by name
-func ProvisionWatcherByName(name string, dic *di.Container) (provisionWatcher dtos.ProvisionWatcher, err errors.EdgeX) {
+// ProvisionWatcherByName query the provision watcher and its revision by name
+func ProvisionWatcherByName(name string, dic *di.Container) (provisionWatcher dtos.ProvisionWatcher, revision uint64, err errors.EdgeX) {
 	if name == "" {
-		return provisionWatcher, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
+		return provisionWatcher, revision, errors.NewCommonEdgeX(errors.KindContractInvalid, "name is empty", nil)
 	}
 
 	dbClient := container.DBClientFrom(dic.Get)
-	pw, err := dbClient.ProvisionWatcherByName(name)
+	pw, revision, err := dbClient.ProvisionWatcherAndRevisionByName(name)
 	if err != nil {
-		return provisionWatcher, errors.NewCommonEdgeXWrapper(err)
+		return provisionWatcher, revision, errors.NewCommonEdgeXWrapper(err)
 	}
 	provisionWatcher = dtos.FromProvisionWatcherModelToDTO(pw)
 
@@ -143,15 +149,21 @@ func DeleteProvisionWatcherByName(ctx context.Context, name string, dic *di.Cont
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
-	go deleteProvisionWatcherCallback(ctx, dic, pw)
+	deleteProvisionWatcherCallback(ctx, dic, pw)
+	publishSystemEvent(pkgDTOs.ProvisionWatcherSystemEventType, pkgDTOs.SystemEventActionDelete, pw.ServiceName, dtos.FromProvisionWatcherModelToDTO(pw), ctx, dic)
 	return nil
 }
 
 // PatchProvisionWatcher executes the PATCH operation with the provisionWatcher DTO to replace the old data
-func PatchProvisionWatcher(ctx context.Context, dto dtos.UpdateProvisionWatcher, dic *di.Container) errors.EdgeX {
+func PatchProvisionWatcher(ctx context.Context, dto dtos.UpdateProvisionWatcher, expectedRevision *uint64, dic *di.Container) errors.EdgeX {
 	dbClient := container.DBClientFrom(dic.Get)
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 
+	if dto.Identifiers != nil {
+		if err := validateProvisionWatcherIdentifiers(dto.Identifiers); err != nil {
+			return errors.NewCommonEdgeXWrapper(err)
+		}
+	}
 	if dto.ServiceName != nil {
 		exists, edgeXerr := dbClient.DeviceServiceNameExists(*dto.ServiceName)
 		if edgeXerr != nil {
@@ -169,7 +181,11 @@ func PatchProvisionWatcher(ctx context.Context, dto dtos.UpdateProvisionWatcher,
 		}
 	}
 
-	pw, err := provisionWatcherByDTO(dbClient, dto)
+	pw, revision, err := provisionWatcherByDTO(dbClient, dto)
+	if err != nil {
+		return errors.NewCommonEdgeXWrapper(err)
+	}
+	err = checkRevision("provision watcher", pw.Name, revision, expectedRevision)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
@@ -182,7 +198,7 @@ func PatchProvisionWatcher(ctx context.Context, dto dtos.UpdateProvisionWatcher,
 
 	requests.ReplaceProvisionWatcherModelFieldsWithDTO(&pw, dto)
 
-	err = dbClient.UpdateProvisionWatcher(pw)
+	err = dbClient.UpdateProvisionWatcherWithRevision(pw, revision)
 	if err != nil {
 		return errors.NewCommonEdgeXWrapper(err)
 	}
@@ -190,27 +206,36 @@ func PatchProvisionWatcher(ctx context.Context, dto dtos.UpdateProvisionWatcher,
 	lc.Debugf("ProvisionWatcher patched on DB successfully. Correlation-ID: %s ", correlation.FromContext(ctx))
 
 	if oldServiceName != "" {
-		go updateProvisionWatcherCallback(ctx, dic, oldServiceName, pw)
+		updateProvisionWatcherCallback(ctx, dic, oldServiceName, pw)
 	}
-	go updateProvisionWatcherCallback(ctx, dic, pw.ServiceName, pw)
+	updateProvisionWatcherCallback(ctx, dic, pw.ServiceName, pw)
+	publishSystemEvent(pkgDTOs.ProvisionWatcherSystemEventType, pkgDTOs.SystemEventActionUpdate, pw.ServiceName, dtos.FromProvisionWatcherModelToDTO(pw), ctx, dic)
 	return nil
 }
 
-func provisionWatcherByDTO(dbClient interfaces.DBClient, dto dtos.UpdateProvisionWatcher) (pw models.ProvisionWatcher, edgexErr errors.EdgeX) {
+// provisionWatcherByDTO returns the provision watcher to patch and its revision
+func provisionWatcherByDTO(dbClient interfaces.DBClient, dto dtos.UpdateProvisionWatcher) (pw models.ProvisionWatcher, revision uint64, edgexErr errors.EdgeX) {
 	// The ID or Name is required by DTO and the DTO also accepts empty string ID if the Name is provided
+	name := ""
 	if dto.Id != nil && *dto.Id != "" {
 		pw, edgexErr = dbClient.ProvisionWatcherById(*dto.Id)
 		if edgexErr != nil {
-			return pw, errors.NewCommonEdgeXWrapper(edgexErr)
+			return pw, revision, errors.NewCommonEdgeXWrapper(edgexErr)
 		}
+		name = pw.Name
 	} else {
-		pw, edgexErr = dbClient.ProvisionWatcherByName(*dto.Name)
-		if edgexErr != nil {
-			return pw, errors.NewCommonEdgeXWrapper(edgexErr)
-		}
+		name = *dto.Name
+	}
+	if dto.Name != nil && *dto.Name != name {
+		return pw, revision, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("provision watcher name '%s' not match the existing '%s' ", *dto.Name, name), nil)
+	}
+	id := pw.Id
+	pw, revision, edgexErr = dbClient.ProvisionWatcherAndRevisionByName(name)
+	if edgexErr != nil {
+		return pw, revision, errors.NewCommonEdgeXWrapper(edgexErr)
 	}
-	if dto.Name != nil && *dto.Name != pw.Name {
-		return pw, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("provision watcher name '%s' not match the existing '%s' ", *dto.Name, pw.Name), nil)
+	if id != "" && id != pw.Id {
+		return pw, revision, errors.NewCommonEdgeX(errors.KindStatusConflict, fmt.Sprintf("provision watcher '%s' was replaced by another provision watcher with the same name", name), nil)
 	}
-	return pw, nil
+	return pw, revision, nil
 }
@@ -0,0 +1,153 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+// MatchProvisionWatchers matches the candidate device against the provision watchers, and tells which provision
+// watchers would provision or block the device by the rules of matchProvisionWatcher.  The rules are core-metadata's
+// own, the device services match the discovered devices in their SDK and may decide differently.
+func MatchProvisionWatchers(req metadataDTOs.ProvisionWatcherMatchRequest, dic *di.Container) ([]metadataDTOs.ProvisionWatcherMatch, errors.EdgeX) {
+	if err := common.Validate(req); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "invalid provision watcher match request", err)
+	}
+
+	dbClient := container.DBClientFrom(dic.Get)
+	var pws []models.ProvisionWatcher
+	var err errors.EdgeX
+	if req.ServiceName != "" {
+		exists, existsErr := dbClient.DeviceServiceNameExists(req.ServiceName)
+		if existsErr != nil {
+			return nil, errors.NewCommonEdgeXWrapper(existsErr)
+		} else if !exists {
+			return nil, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("device service '%s' does not exists", req.ServiceName), nil)
+		}
+		pws, err = dbClient.ProvisionWatchersByServiceName(0, -1, req.ServiceName)
+	} else {
+		pws, err = dbClient.AllProvisionWatchers(0, -1, nil)
+	}
+	if err != nil {
+		return nil, errors.NewCommonEdgeXWrapper(err)
+	}
+	sort.Slice(pws, func(i, j int) bool {
+		return pws[i].Name < pws[j].Name
+	})
+
+	matches := make([]metadataDTOs.ProvisionWatcherMatch, len(pws))
+	for i, pw := range pws {
+		matches[i] = matchProvisionWatcher(pw, req.Protocols)
+	}
+	return matches, nil
+}
+
+// matchProvisionWatcher matches the protocols against the provision watcher.  All the identifiers must match the
+// properties of a single protocol, while a blocking identifier blocks the device if it equals the property of any
+// protocol.
+func matchProvisionWatcher(pw models.ProvisionWatcher, protocols map[string]dtos.ProtocolProperties) metadataDTOs.ProvisionWatcherMatch {
+	match := metadataDTOs.ProvisionWatcherMatch{
+		Name:        pw.Name,
+		ServiceName: pw.ServiceName,
+		ProfileName: pw.ProfileName,
+		AdminState:  string(pw.AdminState),
+	}
+	protocolNames := make([]string, 0, len(protocols))
+	for protocol := range protocols {
+		protocolNames = append(protocolNames, protocol)
+	}
+	sort.Strings(protocolNames)
+	identifiers := sortedIdentifiers(pw.Identifiers)
+	blockingIdentifiers := make([]string, 0, len(pw.BlockingIdentifiers))
+	for name := range pw.BlockingIdentifiers {
+		blockingIdentifiers = append(blockingIdentifiers, name)
+	}
+	sort.Strings(blockingIdentifiers)
+
+	var mismatches []string
+	for _, protocol := range protocolNames {
+		var reasons []string
+		for _, name := range identifiers {
+			pattern := pw.Identifiers[name]
+			value, ok := protocols[protocol][name]
+			if !ok {
+				reasons = append(reasons, fmt.Sprintf("protocol %s has no property %s", protocol, name))
+				continue
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				reasons = append(reasons, fmt.Sprintf("identifier %s has invalid regular expression '%s', %v", name, pattern, err))
+				continue
+			}
+			if !re.MatchString(value) {
+				reasons = append(reasons, fmt.Sprintf("protocol %s property %s value '%s' doesn't match '%s'", protocol, name, value, pattern))
+			}
+		}
+		if len(reasons) == 0 {
+			match.Matched = true
+			match.MatchedProtocol = protocol
+			break
+		}
+		mismatches = append(mismatches, reasons...)
+	}
+	if match.Matched {
+		match.Reasons = append(match.Reasons, fmt.Sprintf("protocol %s matches all the identifiers", match.MatchedProtocol))
+	} else {
+		match.Reasons = append(match.Reasons, mismatches...)
+	}
+
+	for _, protocol := range protocolNames {
+		for _, name := range blockingIdentifiers {
+			value, ok := protocols[protocol][name]
+			if !ok {
+				continue
+			}
+			for _, blocked := range pw.BlockingIdentifiers[name] {
+				if value == blocked {
+					match.Blocked = true
+					match.Reasons = append(match.Reasons, fmt.Sprintf("protocol %s property %s value '%s' is blocked", protocol, name, value))
+				}
+			}
+		}
+	}
+
+	if pw.AdminState != models.Unlocked {
+		match.Reasons = append(match.Reasons, fmt.Sprintf("provision watcher is %s", pw.AdminState))
+	}
+	match.Provisioned = match.Matched && !match.Blocked && pw.AdminState == models.Unlocked
+	return match
+}
+
+// validateProvisionWatcherIdentifiers checks that the identifiers are valid regular expressions
+func validateProvisionWatcherIdentifiers(identifiers map[string]string) errors.EdgeX {
+	for _, name := range sortedIdentifiers(identifiers) {
+		if _, err := regexp.Compile(identifiers[name]); err != nil {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid,
+				fmt.Sprintf("identifier %s has invalid regular expression '%s'", name, identifiers[name]), err)
+		}
+	}
+	return nil
+}
+
+func sortedIdentifiers(identifiers map[string]string) []string {
+	names := make([]string, 0, len(identifiers))
+	for name := range identifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,25 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// checkRevision rejects the update of the entity if the client expects another revision, e.g. the entity has been
+// modified since the client read it.  Any revision is accepted if expectedRevision is nil.  The error wraps
+// utils.ErrRevisionMismatch, so that it's responded with 412 Precondition Failed rather than 409 of its kind.
+func checkRevision(entityType string, name string, revision uint64, expectedRevision *uint64) errors.EdgeX {
+	if expectedRevision != nil && *expectedRevision != revision {
+		return errors.NewCommonEdgeX(errors.KindStatusConflict,
+			fmt.Sprintf("%s '%s' is at revision %d rather than the expected revision %d", entityType, name, revision, *expectedRevision), utils.ErrRevisionMismatch)
+	}
+	return nil
+}
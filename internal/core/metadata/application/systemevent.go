@@ -0,0 +1,61 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+)
+
+// publishSystemEvent publishes the system event of the metadata change with the DTO of the changed metadata to the
+// PublishTopicPrefix/<type>/<action>/<owner> topic, when the system events are enabled.  The owner is the device service
+// the changed metadata belongs to, which is omitted from the topic when empty.
+func publishSystemEvent(eventType string, action string, owner string, details interface{}, ctx context.Context, dic *di.Container) {
+	msgClient := metadataContainer.MessagingClientFrom(dic.Get)
+	if msgClient == nil {
+		return
+	}
+	lc := container.LoggingClientFrom(dic.Get)
+	correlationId := correlation.FromContext(ctx)
+
+	event, err := pkgDTOs.NewSystemEvent(eventType, action, common.CoreMetaDataServiceKey, owner, details)
+	if err != nil {
+		lc.Errorf("fail to create the %s %s system event, err: %v, Correlation-id: %s", eventType, action, err, correlationId)
+		return
+	}
+	payload, encodeErr := json.Marshal(event)
+	if encodeErr != nil {
+		lc.Errorf("fail to encode the %s %s system event, err: %v, Correlation-id: %s", eventType, action, encodeErr, correlationId)
+		return
+	}
+
+	envelope := msgTypes.NewMessageEnvelope(payload, ctx)
+	envelope.ContentType = common.ContentTypeJSON
+	topic := systemEventTopic(metadataContainer.ConfigurationFrom(dic.Get).MessageQueue.PublishTopicPrefix, eventType, action, owner)
+	if err := msgClient.Publish(envelope, topic); err != nil {
+		lc.Errorf("fail to publish the %s %s system event to topic %s, err: %v, Correlation-id: %s", eventType, action, topic, err, correlationId)
+		return
+	}
+	lc.Debugf("%s %s system event published to topic %s, Correlation-id: %s", eventType, action, topic, correlationId)
+}
+
+func systemEventTopic(prefix string, eventType string, action string, owner string) string {
+	segments := []string{strings.TrimSuffix(prefix, "/"), eventType, action}
+	if owner != "" {
+		segments = append(segments, owner)
+	}
+	return strings.Join(segments, "/")
+}
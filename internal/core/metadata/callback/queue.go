@@ -0,0 +1,456 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package callback
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+const openTimeout = 5 * time.Second
+
+var (
+	pendingBucketName = []byte("pending")
+	failedBucketName  = []byte("failed")
+)
+
+// SendFunc delivers the callback to the device service.  The callback may be updated by the failed delivery, e.g. to
+// keep only the undelivered part of a batch, which is stored for the retry.
+type SendFunc func(ctx context.Context, callback *dtos.Callback) error
+
+// RetryPolicy defines how the callbacks failed to deliver are retried
+type RetryPolicy struct {
+	// Interval is the wait before the first retry, which is doubled after each failure up to MaxInterval
+	Interval    time.Duration
+	MaxInterval time.Duration
+	// MaxAttempts is the number of attempts before a callback is moved to the failed callbacks, 0 means no limit
+	MaxAttempts int
+}
+
+// Queue is the durable queue of the device service callbacks.  The callbacks of each device service are delivered one
+// by one in the order they are queued by a worker of the device service, so a device service being unavailable
+// doesn't delay the callbacks of the others.  The callbacks are stored in a single file, so they survive the service
+// restart.
+type Queue struct {
+	db      *bolt.DB
+	lc      logger.LoggingClient
+	send    SendFunc
+	policy  RetryPolicy
+	mutex   sync.Mutex
+	ctx     context.Context
+	closed  bool
+	workers map[string]chan struct{}
+	running sync.WaitGroup
+}
+
+// Open opens or creates the callback queue file at path.  The queued callbacks are not delivered until Start is called.
+func Open(path string, send SendFunc, policy RetryPolicy, lc logger.LoggingClient) (*Queue, errors.EdgeX) {
+	if path == "" {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "callback queue file path is required", nil)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to create the directory of callback queue file %s", path), err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to open callback queue file %s", path), err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{pendingBucketName, failedBucketName} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, fmt.Sprintf("unable to initialize callback queue file %s", path), err)
+	}
+
+	return &Queue{db: db, lc: lc, send: send, policy: policy, workers: make(map[string]chan struct{})}, nil
+}
+
+// Start starts delivering the queued callbacks until ctx is done, and then closes the queue file
+func (q *Queue) Start(ctx context.Context, wg *sync.WaitGroup) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.ctx = ctx
+	_ = q.db.View(func(tx *bolt.Tx) error {
+		pending := tx.Bucket(pendingBucketName)
+		return pending.ForEach(func(serviceName, v []byte) error {
+			if v == nil && !empty(pending.Bucket(serviceName)) {
+				q.startWorker(string(serviceName))
+			}
+			return nil
+		})
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		q.mutex.Lock()
+		q.closed = true
+		q.mutex.Unlock()
+		q.running.Wait()
+		_ = q.db.Close()
+		q.lc.Info("Exiting the device service callback queue")
+	}()
+}
+
+// startWorker starts the worker of the device service unless it is running, which must be called with the mutex held
+func (q *Queue) startWorker(serviceName string) {
+	if q.ctx == nil || q.closed {
+		return
+	}
+	if _, ok := q.workers[serviceName]; ok {
+		return
+	}
+	wake := make(chan struct{}, 1)
+	q.workers[serviceName] = wake
+	q.running.Add(1)
+	go q.work(serviceName, wake)
+}
+
+// wakeWorker makes the worker of the device service retry immediately, which must be called with the mutex held
+func (q *Queue) wakeWorker(serviceName string) {
+	if wake, ok := q.workers[serviceName]; ok {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+		return
+	}
+	q.startWorker(serviceName)
+}
+
+// Enqueue queues the callback to be delivered to the device service of callback.ServiceName
+func (q *Queue) Enqueue(callback dtos.Callback) errors.EdgeX {
+	if callback.Id == "" {
+		callback.Id = uuid.New().String()
+	}
+	if callback.Created == 0 {
+		callback.Created = time.Now().UnixNano()
+	}
+	value, err := json.Marshal(callback)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode the callback", err)
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.closed {
+		return errors.NewCommonEdgeX(errors.KindServiceUnavailable, "callback queue is closed", nil)
+	}
+	err = q.db.Update(func(tx *bolt.Tx) error {
+		return put(tx, pendingBucketName, callback.ServiceName, value)
+	})
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to write the callback queue file", err)
+	}
+	q.startWorker(callback.ServiceName)
+	return nil
+}
+
+// work delivers the callbacks of the device service until no callback is pending or the queue is closed
+func (q *Queue) work(serviceName string, wake chan struct{}) {
+	defer q.running.Done()
+	backoff := q.policy.Interval
+	for {
+		key, callback, err := q.oldest(serviceName)
+		if err != nil {
+			q.lc.Errorf("failed to read the callback queue of device service %s, %v", serviceName, err)
+		}
+		if key == nil {
+			if q.stopWorker(serviceName) {
+				return
+			}
+			continue
+		}
+
+		ctx := context.WithValue(q.ctx, common.CorrelationHeader, callback.CorrelationId)
+		err = q.send(ctx, &callback)
+		if err == nil {
+			q.update(serviceName, key, nil, nil)
+			backoff = q.policy.Interval
+			continue
+		}
+
+		callback.Attempts++
+		callback.LastAttempt = time.Now().UnixNano()
+		callback.LastError = err.Error()
+		if q.policy.MaxAttempts > 0 && callback.Attempts >= q.policy.MaxAttempts {
+			q.lc.Errorf("failed to deliver %s callback of %s to device service %s after %d attempts, %v", callback.Action, callback.Name, serviceName, callback.Attempts, err)
+			q.update(serviceName, key, &callback, failedBucketName)
+			backoff = q.policy.Interval
+			continue
+		}
+		q.update(serviceName, key, &callback, pendingBucketName)
+		q.lc.Debugf("failed to deliver %s callback of %s to device service %s after %d attempts, retry in %s, %v", callback.Action, callback.Name, serviceName, callback.Attempts, backoff, err)
+
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-wake:
+			backoff = q.policy.Interval
+			continue
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > q.policy.MaxInterval {
+			backoff = q.policy.MaxInterval
+		}
+	}
+}
+
+// stopWorker removes the worker of the device service when no callback is pending or the queue is closed
+func (q *Queue) stopWorker(serviceName string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	pending := false
+	_ = q.db.View(func(tx *bolt.Tx) error {
+		pending = !empty(tx.Bucket(pendingBucketName).Bucket([]byte(serviceName)))
+		return nil
+	})
+	if pending && !q.closed && q.ctx.Err() == nil {
+		return false
+	}
+	delete(q.workers, serviceName)
+	return true
+}
+
+// oldest returns the key and the oldest pending callback of the device service, or nil key when none is pending
+func (q *Queue) oldest(serviceName string) (key []byte, callback dtos.Callback, err error) {
+	err = q.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(pendingBucketName).Bucket([]byte(serviceName))
+		if b == nil {
+			return nil
+		}
+		k, v := b.Cursor().First()
+		if k == nil {
+			return nil
+		}
+		key = append([]byte{}, k...)
+		return json.Unmarshal(v, &callback)
+	})
+	if err != nil {
+		// drop the corrupted callback, so it doesn't block the queue
+		if key != nil {
+			q.update(serviceName, key, nil, nil)
+		}
+		return nil, callback, err
+	}
+	return key, callback, nil
+}
+
+// update removes the pending callback of key, and stores callback to the bucket if callback is not nil.  The callback
+// keeps its key, so it keeps its position when it is stored to the pending bucket, and it is queued again in its
+// original position when it is retried from the failed bucket.
+func (q *Queue) update(serviceName string, key []byte, callback *dtos.Callback, bucketName []byte) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(pendingBucketName).Bucket([]byte(serviceName))
+		if b == nil || b.Get(key) == nil {
+			// the callback has been discarded
+			return nil
+		}
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+		if callback == nil {
+			return nil
+		}
+		value, err := json.Marshal(callback)
+		if err != nil {
+			return err
+		}
+		if string(bucketName) == string(pendingBucketName) {
+			return b.Put(key, value)
+		}
+		return putAt(tx, bucketName, serviceName, key, value)
+	})
+	if err != nil {
+		q.lc.Errorf("failed to update the callback queue of device service %s, %v", serviceName, err)
+	}
+}
+
+// Status returns the pending and failed callbacks of the device service
+func (q *Queue) Status(serviceName string) (status dtos.CallbackStatus, edgeXerr errors.EdgeX) {
+	status = dtos.CallbackStatus{ServiceName: serviceName, Pending: []dtos.Callback{}, Failed: []dtos.Callback{}}
+	err := q.db.View(func(tx *bolt.Tx) (err error) {
+		if status.Pending, err = callbacks(tx, pendingBucketName, serviceName); err != nil {
+			return err
+		}
+		status.Failed, err = callbacks(tx, failedBucketName, serviceName)
+		return err
+	})
+	if err != nil {
+		return status, errors.NewCommonEdgeX(errors.KindIOError, "failed to read the callback queue file", err)
+	}
+	return status, nil
+}
+
+// AllStatus returns the pending and failed callbacks of the device services having any
+func (q *Queue) AllStatus() ([]dtos.CallbackStatus, errors.EdgeX) {
+	var serviceNames []string
+	err := q.db.View(func(tx *bolt.Tx) error {
+		seen := make(map[string]bool)
+		for _, bucketName := range [][]byte{pendingBucketName, failedBucketName} {
+			b := tx.Bucket(bucketName)
+			err := b.ForEach(func(name, v []byte) error {
+				if v == nil && !seen[string(name)] && !empty(b.Bucket(name)) {
+					seen[string(name)] = true
+					serviceNames = append(serviceNames, string(name))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindIOError, "failed to read the callback queue file", err)
+	}
+
+	statuses := make([]dtos.CallbackStatus, len(serviceNames))
+	for i, serviceName := range serviceNames {
+		status, edgeXerr := q.Status(serviceName)
+		if edgeXerr != nil {
+			return nil, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		statuses[i] = status
+	}
+	return statuses, nil
+}
+
+// Retry queues the failed callbacks of the device service which retain returns true for again, discards the other
+// failed callbacks, and makes the pending callbacks to be retried immediately.  The failed callbacks are queued again
+// in their original order, ahead of the callbacks queued after them.  It returns the number of the failed callbacks
+// queued again.
+func (q *Queue) Retry(serviceName string, retain func(callback dtos.Callback) bool) (int, errors.EdgeX) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	retried := 0
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		failed := tx.Bucket(failedBucketName)
+		b := failed.Bucket([]byte(serviceName))
+		if b == nil {
+			return nil
+		}
+		err := b.ForEach(func(k, v []byte) error {
+			var callback dtos.Callback
+			if err := json.Unmarshal(v, &callback); err != nil || !retain(callback) {
+				return nil
+			}
+			callback.Attempts = 0
+			value, err := json.Marshal(callback)
+			if err != nil {
+				return err
+			}
+			retried++
+			return putAt(tx, pendingBucketName, serviceName, k, value)
+		})
+		if err != nil {
+			return err
+		}
+		return failed.DeleteBucket([]byte(serviceName))
+	})
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindIOError, "failed to update the callback queue file", err)
+	}
+	q.wakeWorker(serviceName)
+	return retried, nil
+}
+
+// Discard removes the pending and failed callbacks of the device service, e.g. when the device service is removed
+func (q *Queue) Discard(serviceName string) errors.EdgeX {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		for _, bucketName := range [][]byte{pendingBucketName, failedBucketName} {
+			b := tx.Bucket(bucketName)
+			if b.Bucket([]byte(serviceName)) == nil {
+				continue
+			}
+			if err := b.DeleteBucket([]byte(serviceName)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindIOError, "failed to update the callback queue file", err)
+	}
+	return nil
+}
+
+// put stores the value to the bucket of the device service under the bucket of bucketName with a new sequence key,
+// which keeps the values in the order they are stored
+func put(tx *bolt.Tx, bucketName []byte, serviceName string, value []byte) error {
+	b, err := tx.Bucket(bucketName).CreateBucketIfNotExists([]byte(serviceName))
+	if err != nil {
+		return err
+	}
+	seq, err := tx.Bucket(pendingBucketName).NextSequence()
+	if err != nil {
+		return err
+	}
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return b.Put(key, value)
+}
+
+// putAt stores the value to the bucket of the device service under the bucket of bucketName with the sequence key
+// given by put, which keeps the value in the position it was first stored
+func putAt(tx *bolt.Tx, bucketName []byte, serviceName string, key []byte, value []byte) error {
+	b, err := tx.Bucket(bucketName).CreateBucketIfNotExists([]byte(serviceName))
+	if err != nil {
+		return err
+	}
+	return b.Put(key, value)
+}
+
+func empty(b *bolt.Bucket) bool {
+	if b == nil {
+		return true
+	}
+	k, _ := b.Cursor().First()
+	return k == nil
+}
+
+func callbacks(tx *bolt.Tx, bucketName []byte, serviceName string) ([]dtos.Callback, error) {
+	result := []dtos.Callback{}
+	b := tx.Bucket(bucketName).Bucket([]byte(serviceName))
+	if b == nil {
+		return result, nil
+	}
+	err := b.ForEach(func(_, v []byte) error {
+		var callback dtos.Callback
+		if err := json.Unmarshal(v, &callback); err != nil {
+			return err
+		}
+		result = append(result, callback)
+		return nil
+	})
+	return result, err
+}
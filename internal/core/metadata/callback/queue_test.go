@@ -0,0 +1,196 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package callback
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+const (
+	testServiceName  = "test-service"
+	otherServiceName = "other-service"
+)
+
+var testPolicy = RetryPolicy{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond, MaxAttempts: 3}
+
+// recorder records the delivered callbacks, and fails the callbacks to the unavailable device services
+type recorder struct {
+	mutex       sync.Mutex
+	delivered   []string
+	unavailable map[string]bool
+}
+
+func (r *recorder) send(_ context.Context, callback *dtos.Callback) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.unavailable[callback.ServiceName] {
+		return fmt.Errorf("device service %s unavailable", callback.ServiceName)
+	}
+	r.delivered = append(r.delivered, callback.ServiceName+"/"+callback.Name)
+	return nil
+}
+
+func (r *recorder) setUnavailable(serviceName string, unavailable bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.unavailable[serviceName] = unavailable
+}
+
+func (r *recorder) result() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string{}, r.delivered...)
+}
+
+func testCallback(serviceName string, action string, name string) dtos.Callback {
+	return dtos.Callback{ServiceName: serviceName, Action: action, Name: name}
+}
+
+func TestQueueDelivery(t *testing.T) {
+	r := &recorder{unavailable: map[string]bool{otherServiceName: true}}
+	q, err := Open(filepath.Join(t.TempDir(), "callback.db"), r.send, testPolicy, logger.NewMockClient())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	q.Start(ctx, wg)
+
+	require.NoError(t, q.Enqueue(testCallback(otherServiceName, dtos.CallbackAddDevice, "device-0")))
+	for _, name := range []string{"device-1", "device-2", "device-3"} {
+		require.NoError(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackAddDevice, name)))
+	}
+
+	require.Eventually(t, func() bool { return len(r.result()) == 3 }, time.Second, time.Millisecond,
+		"the unavailable device service shouldn't block the callbacks of the others")
+	assert.Equal(t, []string{testServiceName + "/device-1", testServiceName + "/device-2", testServiceName + "/device-3"}, r.result(),
+		"the callbacks should be delivered in order")
+
+	require.Eventually(t, func() bool {
+		status, err := q.Status(otherServiceName)
+		return err == nil && len(status.Failed) == 1
+	}, time.Second, time.Millisecond, "the callback should fail after the maximum attempts")
+	status, err := q.Status(otherServiceName)
+	require.NoError(t, err)
+	assert.Empty(t, status.Pending)
+	assert.Equal(t, testPolicy.MaxAttempts, status.Failed[0].Attempts)
+	assert.NotEmpty(t, status.Failed[0].LastError)
+	assert.NotEmpty(t, status.Failed[0].Id)
+
+	statuses, err := q.AllStatus()
+	require.NoError(t, err)
+	require.Len(t, statuses, 1, "only the device services having callbacks should be reported")
+	assert.Equal(t, otherServiceName, statuses[0].ServiceName)
+}
+
+func TestQueueRetry(t *testing.T) {
+	r := &recorder{unavailable: map[string]bool{testServiceName: true}}
+	q, err := Open(filepath.Join(t.TempDir(), "callback.db"), r.send, testPolicy, logger.NewMockClient())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	q.Start(ctx, wg)
+
+	require.NoError(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackUpdateDevice, "device-1")))
+	require.NoError(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackDeleteDevice, "device-2")))
+	require.Eventually(t, func() bool {
+		status, err := q.Status(testServiceName)
+		return err == nil && len(status.Failed) == 2
+	}, time.Second, time.Millisecond)
+
+	r.setUnavailable(testServiceName, false)
+	retried, err := q.Retry(testServiceName, func(callback dtos.Callback) bool {
+		return callback.Action == dtos.CallbackDeleteDevice
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried)
+	require.Eventually(t, func() bool { return len(r.result()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{testServiceName + "/device-2"}, r.result(), "only the retained failed callback should be retried")
+
+	status, err := q.Status(testServiceName)
+	require.NoError(t, err)
+	assert.Empty(t, status.Failed, "the other failed callbacks should be discarded")
+}
+
+func TestQueueRetryOrder(t *testing.T) {
+	r := &recorder{unavailable: map[string]bool{}}
+	q, err := Open(filepath.Join(t.TempDir(), "callback.db"), r.send, testPolicy, logger.NewMockClient())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// fail the callbacks queued before the device service is unavailable, and then queue a newer one
+	for _, name := range []string{"device-1", "device-2"} {
+		require.NoError(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackUpdateDevice, name)))
+	}
+	for i := 0; i < 2; i++ {
+		key, callback, err := q.oldest(testServiceName)
+		require.NoError(t, err)
+		require.NotNil(t, key)
+		q.update(testServiceName, key, &callback, failedBucketName)
+	}
+	require.NoError(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackUpdateDevice, "device-3")))
+
+	retried, err := q.Retry(testServiceName, func(dtos.Callback) bool { return true })
+	require.NoError(t, err)
+	assert.Equal(t, 2, retried)
+	status, err := q.Status(testServiceName)
+	require.NoError(t, err)
+	require.Len(t, status.Pending, 3)
+	assert.Equal(t, "device-1", status.Pending[0].Name, "the retried callbacks should be queued ahead of the newer ones")
+
+	q.Start(ctx, wg)
+	require.Eventually(t, func() bool { return len(r.result()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{testServiceName + "/device-1", testServiceName + "/device-2", testServiceName + "/device-3"}, r.result(),
+		"the retried callbacks should be delivered in their original order")
+}
+
+func TestQueuePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "callback.db")
+	r := &recorder{unavailable: map[string]bool{}}
+	q, err := Open(path, r.send, testPolicy, logger.NewMockClient())
+	require.NoError(t, err)
+	require.NoError(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackAddDevice, "device-1")))
+	require.NoError(t, q.Enqueue(testCallback(otherServiceName, dtos.CallbackAddDevice, "device-2")))
+	require.NoError(t, q.Discard(otherServiceName))
+	status, err := q.Status(testServiceName)
+	require.NoError(t, err)
+	assert.Len(t, status.Pending, 1, "the callbacks shouldn't be delivered before the queue starts")
+	require.NoError(t, q.db.Close())
+
+	q, err = Open(path, r.send, testPolicy, logger.NewMockClient())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	q.Start(ctx, wg)
+	require.Eventually(t, func() bool { return len(r.result()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{testServiceName + "/device-1"}, r.result(), "the queued callbacks should survive reopening")
+
+	cancel()
+	wg.Wait()
+	assert.Error(t, q.Enqueue(testCallback(testServiceName, dtos.CallbackAddDevice, "device-3")), "the closed queue should reject callbacks")
+}
@@ -27,6 +27,10 @@ type ConfigurationStruct struct {
 	Registry      bootstrapConfig.RegistryInfo
 	Service       bootstrapConfig.ServiceInfo
 	SecretStore   bootstrapConfig.SecretStoreInfo
+	Callback      CallbackInfo
+	MessageQueue  bootstrapConfig.MessageBusInfo
+	SystemEvents  SystemEventsInfo
+	HealthCheck   HealthCheckInfo
 }
 
 type WritableInfo struct {
@@ -44,6 +48,39 @@ type NotificationInfo struct {
 	Slug              string
 }
 
+// CallbackInfo defines the queue of the callbacks to the device services
+type CallbackInfo struct {
+	// Path is the file storing the queued callbacks.  Its directory is created at startup and must be writable by the
+	// service, e.g. a volume mounted in the container, otherwise core-metadata fails to start.
+	Path string
+	// RetryInterval is the wait before retrying a callback failed to deliver, which is doubled after each failure up to
+	// MaxRetryInterval
+	RetryInterval    string
+	MaxRetryInterval string
+	// MaxAttempts is the number of attempts before a callback is moved to the failed callbacks, 0 means no limit
+	MaxAttempts int
+	// Timeout is the timeout of each attempt
+	Timeout string
+}
+
+// SystemEventsInfo defines the system events published when the metadata is added, updated or deleted
+type SystemEventsInfo struct {
+	// Enabled turns on publishing the system events to the message bus of MessageQueue, under the
+	// MessageQueue.PublishTopicPrefix/<type>/<action>/<owner> topics
+	Enabled bool
+}
+
+// HealthCheckInfo defines the periodic health checks of the device services
+type HealthCheckInfo struct {
+	// Enabled turns on pinging the BaseAddress of each device service every Interval.  The device service and its
+	// devices are marked DOWN after FailureThreshold consecutive failed checks, and marked UP once a check succeeds.
+	Enabled          bool
+	Interval         string
+	FailureThreshold int
+	// Timeout is the timeout of each check
+	Timeout string
+}
+
 // UpdateFromRaw converts configuration received from the registry to a service-specific configuration struct which is
 // then used to overwrite the service's existing configuration struct.
 func (c *ConfigurationStruct) UpdateFromRaw(rawConfig interface{}) bool {
@@ -0,0 +1,23 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/callback"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+var CallbackQueueName = di.TypeInstanceToName((*callback.Queue)(nil))
+
+func CallbackQueueFrom(get di.Get) *callback.Queue {
+	q, ok := get(CallbackQueueName).(*callback.Queue)
+	if !ok {
+		return nil
+	}
+
+	return q
+}
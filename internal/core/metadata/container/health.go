@@ -0,0 +1,23 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/health"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+var HealthMonitorName = di.TypeInstanceToName((*health.Monitor)(nil))
+
+func HealthMonitorFrom(get di.Get) *health.Monitor {
+	m, ok := get(HealthMonitorName).(*health.Monitor)
+	if !ok {
+		return nil
+	}
+
+	return m
+}
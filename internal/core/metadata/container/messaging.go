@@ -0,0 +1,24 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-messaging/v2/messaging"
+)
+
+// MessagingClientName contains the name of the messaging client instance in the DIC.
+var MessagingClientName = di.TypeInstanceToName((*messaging.MessageClient)(nil))
+
+// MessagingClientFrom helper function queries the DIC and returns the messaging client.
+func MessagingClientFrom(get di.Get) messaging.MessageClient {
+	client, ok := get(MessagingClientName).(messaging.MessageClient)
+	if !ok {
+		return nil
+	}
+
+	return client
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	goio "io"
+	"net/http"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/io"
+	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ArchiveController struct {
+	dic *di.Container
+}
+
+// NewArchiveController creates and initializes an ArchiveController
+func NewArchiveController(dic *di.Container) *ArchiveController {
+	return &ArchiveController{
+		dic: dic,
+	}
+}
+
+func (ac *ArchiveController) ExportMetadata(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(ac.dic.Get)
+	ctx := r.Context()
+
+	format := strings.ToLower(utils.ParseQueryStringToString(r, pkgCommon.Format, metadataDTOs.ArchiveFormatJSON))
+	if format != metadataDTOs.ArchiveFormatJSON && format != metadataDTOs.ArchiveFormatYAML {
+		err := errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("unsupported archive format %s, must be %s or %s", format, metadataDTOs.ArchiveFormatJSON, metadataDTOs.ArchiveFormatYAML), nil)
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	archive, err := application.ExportMetadata(ac.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	data, contentType, err := encodeArchive(archive, format)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	w.Header().Set(common.CorrelationHeader, correlation.FromContext(ctx))
+	w.Header().Set(common.ContentType, contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"metadata_%d.%s\"", archive.Created, format))
+	w.WriteHeader(http.StatusOK)
+	if _, writeErr := w.Write(data); writeErr != nil {
+		lc.Errorf("failed to write the metadata archive, %v", writeErr)
+	}
+}
+
+func (ac *ArchiveController) ImportMetadata(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer func() { _ = r.Body.Close() }()
+	}
+
+	lc := container.LoggingClientFrom(ac.dic.Get)
+	ctx := r.Context()
+
+	mode := utils.ParseQueryStringToString(r, pkgCommon.Mode, metadataDTOs.ImportModeFail)
+	dryRun, err := utils.ParseQueryStringToBool(r, pkgCommon.DryRun, false)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	var archive metadataDTOs.MetadataArchive
+	err = decodeArchive(r, &archive)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	report, err := application.ImportMetadata(archive, mode, dryRun, ctx, ac.dic)
+	if err != nil && errors.Kind(err) != errors.KindStatusConflict {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	// the aborted import is responded with the report telling which entities fail
+	response := metadataDTOs.NewImportReportResponse("", "", http.StatusOK, report)
+	if err != nil {
+		lc.Error(err.Error(), common.CorrelationHeader, correlation.FromContext(ctx))
+		response = metadataDTOs.NewImportReportResponse("", err.Message(), err.Code(), report)
+	}
+	utils.WriteHttpHeader(w, ctx, response.StatusCode)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
+
+// encodeArchive encodes the archive in the format.  The YAML archive is converted from the JSON one, so that the
+// fields are named the same in both formats.
+func encodeArchive(archive metadataDTOs.MetadataArchive, format string) ([]byte, string, errors.EdgeX) {
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return nil, "", errors.NewCommonEdgeX(errors.KindServerError, "failed to encode the metadata archive", err)
+	}
+	if format == metadataDTOs.ArchiveFormatJSON {
+		return data, common.ContentTypeJSON, nil
+	}
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	if err == nil {
+		data, err = yaml.Marshal(value)
+	}
+	if err != nil {
+		return nil, "", errors.NewCommonEdgeX(errors.KindServerError, "failed to encode the metadata archive as yaml", err)
+	}
+	return data, common.ContentTypeYAML, nil
+}
+
+// decodeArchive decodes the request body as JSON, or as YAML if the Content-Type is YAML
+func decodeArchive(r *http.Request, archive *metadataDTOs.MetadataArchive) errors.EdgeX {
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get(common.ContentType), ";")[0]))
+	if contentType != common.ContentTypeYAML && contentType != "application/yaml" && contentType != "text/yaml" {
+		return io.NewJsonDtoReader().Read(r.Body, archive)
+	}
+
+	return decodeYamlAsJson(r.Body, archive)
+}
+
+// decodeYamlAsJson decodes the YAML into v through JSON, so that the fields are named by the json tags of v
+func decodeYamlAsJson(reader goio.Reader, v interface{}) errors.EdgeX {
+	var value interface{}
+	edgexErr := io.NewYamlDtoReader().Read(reader, &value)
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to decode the yaml", err)
+	}
+	return nil
+}
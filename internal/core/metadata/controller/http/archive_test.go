@@ -0,0 +1,242 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestArchive() metadataDTOs.MetadataArchive {
+	device := buildTestDeviceRequest().Device
+	orphan := device
+	orphan.Name = "orphan"
+	orphan.ServiceName = "unknownService"
+	return metadataDTOs.MetadataArchive{
+		Version:        metadataDTOs.MetadataArchiveVersion,
+		DeviceServices: []dtos.DeviceService{{Name: TestDeviceServiceName, BaseAddress: testBaseAddress, AdminState: models.Unlocked}},
+		DeviceProfiles: []dtos.DeviceProfile{buildTestDeviceProfileRequest().Profile},
+		Devices:        []dtos.Device{device, orphan},
+	}
+}
+
+func TestExportMetadata(t *testing.T) {
+	archive := buildTestArchive()
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("AllDeviceServices", 0, -1, []string(nil)).Return([]models.DeviceService{dtos.ToDeviceServiceModel(archive.DeviceServices[0])}, nil)
+	dbClientMock.On("AllDeviceProfiles", 0, -1, []string(nil)).Return([]models.DeviceProfile{dtos.ToDeviceProfileModel(archive.DeviceProfiles[0])}, nil)
+	dbClientMock.On("AllDevices", 0, -1, []string(nil)).Return([]models.Device{dtos.ToDeviceModel(archive.Devices[1]), dtos.ToDeviceModel(archive.Devices[0])}, nil)
+	dbClientMock.On("AllProvisionWatchers", 0, -1, []string(nil)).Return([]models.ProvisionWatcher{}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewArchiveController(dic)
+
+	export := func(format string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiMetadataExportRoute, http.NoBody)
+		require.NoError(t, err)
+		if format != "" {
+			query := req.URL.Query()
+			query.Add(pkgCommon.Format, format)
+			req.URL.RawQuery = query.Encode()
+		}
+		recorder := httptest.NewRecorder()
+		http.HandlerFunc(controller.ExportMetadata).ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	recorder := export("")
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.Equal(t, common.ContentTypeJSON, recorder.Header().Get(common.ContentType))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), ".json")
+	var exported metadataDTOs.MetadataArchive
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &exported))
+	assert.Equal(t, common.ApiVersion, exported.ApiVersion)
+	assert.Equal(t, metadataDTOs.MetadataArchiveVersion, exported.Version)
+	require.Len(t, exported.Devices, 2)
+	assert.Equal(t, TestDeviceName, exported.Devices[0].Name, "the devices should be sorted by name")
+	assert.Empty(t, exported.ProvisionWatchers)
+
+	recorder = export(metadataDTOs.ArchiveFormatYAML)
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.Equal(t, common.ContentTypeYAML, recorder.Header().Get(common.ContentType))
+	assert.Contains(t, recorder.Body.String(), "serviceName: "+TestDeviceServiceName, "the yaml fields should be named as the json ones")
+	req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiMetadataImportRoute, recorder.Body)
+	require.NoError(t, err)
+	req.Header.Set(common.ContentType, common.ContentTypeYAML)
+	var decoded metadataDTOs.MetadataArchive
+	require.NoError(t, decodeArchive(req, &decoded))
+	decoded.Created = exported.Created
+	assert.Equal(t, exported, decoded, "the yaml archive should be decoded as the json one")
+
+	recorder = export("xml")
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+}
+
+func TestImportMetadata(t *testing.T) {
+	archive := buildTestArchive()
+	profile := dtos.ToDeviceProfileModel(archive.DeviceProfiles[0])
+	profile.Id = ""
+	device := dtos.ToDeviceModel(archive.Devices[0])
+	device.Id = ""
+
+	// the device service exists, and the device profile and devices are new
+	mockDBClient := func() *dbMock.DBClient {
+		dbClientMock := &dbMock.DBClient{}
+		dbClientMock.On("DeviceServiceNameExists", TestDeviceServiceName).Return(true, nil)
+		dbClientMock.On("DeviceServiceNameExists", "unknownService").Return(false, nil)
+		dbClientMock.On("DeviceProfileNameExists", profile.Name).Return(false, nil).Once()
+		dbClientMock.On("DeviceProfileNameExists", profile.Name).Return(true, nil)
+		dbClientMock.On("DeviceNameExists", mock.Anything).Return(false, nil)
+		dbClientMock.On("AddDeviceProfile", profile, mock.Anything).Return(profile, nil)
+		dbClientMock.On("AddDevice", device).Return(device, nil)
+		dbClientMock.On("DeviceServiceByName", TestDeviceServiceName).Return(models.DeviceService{Name: TestDeviceServiceName, BaseAddress: testBaseAddress}, nil)
+		return dbClientMock
+	}
+
+	tests := []struct {
+		name               string
+		mode               string
+		dryRun             string
+		version            int
+		expectedStatusCode int
+		expectedReport     metadataDTOs.ImportReport
+		expectedImport     bool
+	}{
+		{"Valid - dry-run", metadataDTOs.ImportModeSkip, "true", 1, http.StatusOK,
+			metadataDTOs.ImportReport{Mode: metadataDTOs.ImportModeSkip, DryRun: true, Created: 2, Skipped: 1, Failed: 1}, false},
+		{"Valid - skip", metadataDTOs.ImportModeSkip, "", 1, http.StatusOK,
+			metadataDTOs.ImportReport{Mode: metadataDTOs.ImportModeSkip, Created: 2, Skipped: 1, Failed: 1}, true},
+		{"Invalid - fail on the existing device service", "", "", 1, http.StatusConflict,
+			metadataDTOs.ImportReport{Mode: metadataDTOs.ImportModeFail, Created: 2, Failed: 2}, false},
+		{"Invalid - unsupported mode", "replace", "", 1, http.StatusBadRequest, metadataDTOs.ImportReport{}, false},
+		{"Invalid - unsupported version", metadataDTOs.ImportModeSkip, "", 2, http.StatusBadRequest, metadataDTOs.ImportReport{}, false},
+		{"Invalid - dryRun is not a boolean", metadataDTOs.ImportModeSkip, "yes", 1, http.StatusBadRequest, metadataDTOs.ImportReport{}, false},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			dic := mockDic()
+			dbClientMock := mockDBClient()
+			dic.Update(di.ServiceConstructorMap{
+				container.DBClientInterfaceName: func(get di.Get) interface{} {
+					return dbClientMock
+				},
+			})
+			controller := NewArchiveController(dic)
+
+			archive.Version = testCase.version
+			jsonData, err := json.Marshal(archive)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiMetadataImportRoute, strings.NewReader(string(jsonData)))
+			require.NoError(t, err)
+			query := req.URL.Query()
+			if testCase.mode != "" {
+				query.Add(pkgCommon.Mode, testCase.mode)
+			}
+			if testCase.dryRun != "" {
+				query.Add(pkgCommon.DryRun, testCase.dryRun)
+			}
+			req.URL.RawQuery = query.Encode()
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.ImportMetadata).ServeHTTP(recorder, req)
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode == http.StatusBadRequest {
+				return
+			}
+
+			var response metadataDTOs.ImportReportResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, testCase.expectedStatusCode, response.StatusCode)
+			report := response.Report
+			assert.Equal(t, testCase.expectedReport.Mode, report.Mode)
+			assert.Equal(t, testCase.expectedReport.DryRun, report.DryRun)
+			assert.Equal(t, testCase.expectedReport.Created, report.Created)
+			assert.Equal(t, testCase.expectedReport.Skipped, report.Skipped)
+			assert.Equal(t, testCase.expectedReport.Failed, report.Failed)
+			require.Len(t, report.Results, 4)
+			assert.Equal(t, metadataDTOs.ArchiveDeviceServiceType, report.Results[0].Type, "the entities should be imported in dependency order")
+			assert.Equal(t, metadataDTOs.ArchiveDeviceProfileType, report.Results[1].Type)
+			orphan := report.Results[3]
+			assert.Equal(t, metadataDTOs.ImportActionFail, orphan.Action)
+			assert.Contains(t, orphan.Message, "unknownService")
+
+			if testCase.expectedImport {
+				dbClientMock.AssertCalled(t, "AddDeviceProfile", profile, mock.Anything)
+				dbClientMock.AssertCalled(t, "AddDevice", device)
+			} else {
+				dbClientMock.AssertNotCalled(t, "AddDeviceProfile", mock.Anything, mock.Anything)
+				dbClientMock.AssertNotCalled(t, "AddDevice", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestImportMetadata_Overwrite(t *testing.T) {
+	archive := buildTestArchive()
+	archive.DeviceProfiles = nil
+	archive.Devices = nil
+	ds := dtos.ToDeviceServiceModel(archive.DeviceServices[0])
+	notFound := errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "provision watcher doesn't exist", nil)
+	archive.ProvisionWatchers = []dtos.ProvisionWatcher{{
+		Name:        "watcher",
+		Identifiers: map[string]string{"address": "localhost"},
+		ServiceName: TestDeviceServiceName,
+		ProfileName: TestDeviceProfileName,
+		AdminState:  models.Unlocked,
+	}}
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceServiceNameExists", TestDeviceServiceName).Return(true, nil)
+	dbClientMock.On("DeviceServiceByName", TestDeviceServiceName).Return(ds, nil)
+	dbClientMock.On("DeviceServiceAndRevisionByName", TestDeviceServiceName).Return(ds, TestRevision, nil)
+	dbClientMock.On("UpdateDeviceServiceWithRevision", ds, TestRevision).Return(nil)
+	dbClientMock.On("DeviceProfileNameExists", TestDeviceProfileName).Return(false, nil)
+	dbClientMock.On("ProvisionWatcherByName", "watcher").Return(models.ProvisionWatcher{}, notFound)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewArchiveController(dic)
+
+	jsonData, err := json.Marshal(archive)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiMetadataImportRoute+"?"+pkgCommon.Mode+"="+metadataDTOs.ImportModeOverwrite, strings.NewReader(string(jsonData)))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(controller.ImportMetadata).ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	var response metadataDTOs.ImportReportResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Report.Updated)
+	assert.Equal(t, 1, response.Report.Failed, "the provision watcher referencing the unknown device profile should fail")
+	assert.Equal(t, metadataDTOs.ImportActionUpdate, response.Report.Results[0].Action)
+	dbClientMock.AssertCalled(t, "UpdateDeviceServiceWithRevision", ds, TestRevision)
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/callback"
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCallbackQueue opens a callback queue which isn't started, so the queued callbacks stay pending
+func newTestCallbackQueue(t *testing.T, dic *di.Container) *callback.Queue {
+	send := func(ctx context.Context, c *metadataDTOs.Callback) error { return nil }
+	queue, err := callback.Open(filepath.Join(t.TempDir(), "callback.db"), send, callback.RetryPolicy{}, bootstrapContainer.LoggingClientFrom(dic.Get))
+	require.NoError(t, err)
+	dic.Update(di.ServiceConstructorMap{
+		container.CallbackQueueName: func(get di.Get) interface{} {
+			return queue
+		},
+	})
+	return queue
+}
+
+func TestCallbackStatus(t *testing.T) {
+	dic := mockDic()
+	controller := NewCallbackController(dic)
+
+	req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiAllCallbackRoute, http.NoBody)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(controller.AllCallbackStatus).ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode, "the status should be unavailable without the callback queue")
+
+	queue := newTestCallbackQueue(t, dic)
+	require.NoError(t, queue.Enqueue(metadataDTOs.Callback{ServiceName: TestDeviceServiceName, Action: metadataDTOs.CallbackDeleteDevice, Name: TestDeviceName}))
+
+	recorder = httptest.NewRecorder()
+	http.HandlerFunc(controller.AllCallbackStatus).ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	var multiResponse metadataDTOs.MultiCallbackStatusResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &multiResponse))
+	require.Len(t, multiResponse.Statuses, 1)
+	assert.Equal(t, TestDeviceServiceName, multiResponse.Statuses[0].ServiceName)
+
+	tests := []struct {
+		name               string
+		serviceName        string
+		expectedPending    int
+		expectedStatusCode int
+	}{
+		{"Valid - pending callbacks", TestDeviceServiceName, 1, http.StatusOK},
+		{"Valid - no callbacks", testDeviceServiceName, 0, http.StatusOK},
+		{"Invalid - empty name", "", 0, http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiCallbackByServiceNameRoute, http.NoBody)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{common.Name: testCase.serviceName})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.CallbackStatusByServiceName).ServeHTTP(recorder, req)
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode != http.StatusOK {
+				return
+			}
+			var response metadataDTOs.CallbackStatusResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, testCase.serviceName, response.Status.ServiceName)
+			assert.Len(t, response.Status.Pending, testCase.expectedPending)
+			assert.Empty(t, response.Status.Failed)
+		})
+	}
+}
+
+func TestResyncDeviceService(t *testing.T) {
+	device := dtos.ToDeviceModel(buildTestDeviceRequest().Device)
+	profile := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	notFound := "notFoundService"
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceServiceByName", TestDeviceServiceName).Return(models.DeviceService{Name: TestDeviceServiceName, BaseAddress: testBaseAddress}, nil)
+	dbClientMock.On("DevicesByServiceName", 0, -1, TestDeviceServiceName).Return([]models.Device{device, device}, nil)
+	dbClientMock.On("ProvisionWatchersByServiceName", 0, -1, TestDeviceServiceName).Return([]models.ProvisionWatcher{}, nil)
+	dbClientMock.On("DeviceProfileByName", device.ProfileName).Return(profile, nil)
+	dbClientMock.On("DeviceServiceByName", notFound).Return(models.DeviceService{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device service doesn't exist", nil))
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	queue := newTestCallbackQueue(t, dic)
+	controller := NewCallbackController(dic)
+
+	tests := []struct {
+		name               string
+		serviceName        string
+		expectedStatusCode int
+	}{
+		{"Valid - resync", TestDeviceServiceName, http.StatusAccepted},
+		{"Invalid - device service not found", notFound, http.StatusNotFound},
+		{"Invalid - empty name", "", http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiDeviceServiceResyncRoute, http.NoBody)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{common.Name: testCase.serviceName})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.ResyncDeviceService).ServeHTTP(recorder, req)
+			assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+		})
+	}
+
+	status, err := queue.Status(TestDeviceServiceName)
+	require.NoError(t, err)
+	var actions []string
+	for _, c := range status.Pending {
+		actions = append(actions, c.Action)
+	}
+	assert.Equal(t, []string{metadataDTOs.CallbackUpdateDeviceService, metadataDTOs.CallbackUpdateDeviceProfile,
+		metadataDTOs.CallbackUpdateDevice, metadataDTOs.CallbackUpdateDevice}, actions,
+		"the device service, its device profiles and devices should be queued in order")
+}
@@ -16,4 +16,5 @@ const (
 	TestDeviceCommandName  = "TestDeviceCommand"
 	TestDeviceName         = "TestDevice"
 	TestDeviceServiceName  = "TestDeviceServiceName"
+	TestRevision           = uint64(2)
 )
@@ -6,14 +6,18 @@
 package http
 
 import (
+	"fmt"
 	"math"
 	"net/http"
+	"strings"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
 	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
@@ -23,6 +27,8 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	requestDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	responseDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 
 	"github.com/gorilla/mux"
 )
@@ -173,18 +179,28 @@ func (dc *DeviceController) PatchDevice(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// the If-Match header is only allowed when a single entity is patched
+	expectedRevision, err := utils.ParseIfMatchRevision(r)
+	if err == nil && expectedRevision != nil && len(reqDTOs) != 1 {
+		err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%s header is not allowed when patching %d entities", pkgCommon.IfMatch, len(reqDTOs)), nil)
+	}
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
 	var updateResponses []interface{}
 	for _, dto := range reqDTOs {
 		var response interface{}
 		reqId := dto.RequestId
-		err := application.PatchDevice(dto.Device, ctx, dc.dic)
+		err := application.PatchDevice(dto.Device, expectedRevision, ctx, dc.dic)
 		if err != nil {
 			lc.Error(err.Error(), common.CorrelationHeader, correlationId)
 			lc.Debug(err.DebugMessages(), common.CorrelationHeader, correlationId)
 			response = commonDTO.NewBaseResponse(
 				reqId,
 				err.Message(),
-				err.Code())
+				utils.IfMatchStatusCode(err))
 		} else {
 			response = commonDTO.NewBaseResponse(
 				reqId,
@@ -220,6 +236,67 @@ func (dc *DeviceController) AllDevices(w http.ResponseWriter, r *http.Request) {
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
 
+func (dc *DeviceController) SearchDevices(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(dc.dic.Get)
+	ctx := r.Context()
+	config := metadataContainer.ConfigurationFrom(dc.dic.Get)
+
+	// parse URL query string for offset, limit, labels and the other criteria of the search
+	offset, limit, labels, err := utils.ParseGetAllObjectsRequestQueryString(r, 0, math.MaxInt32, -1, config.Service.MaxResultCount)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	filter, err := parseDeviceSearchFilter(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+	filter.Labels = labels
+	devices, totalCount, err := application.SearchDevices(offset, limit, filter, dc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	response := responseDTO.NewMultiDevicesResponse("", "", http.StatusOK, totalCount, devices)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
+
+// parseDeviceSearchFilter parses the search criteria other than the labels, each protocol property is specified as a
+// separate key:value query parameter
+func parseDeviceSearchFilter(r *http.Request) (pkgModels.DeviceSearchFilter, errors.EdgeX) {
+	filter := pkgModels.DeviceSearchFilter{
+		NamePattern:    utils.ParseQueryStringToString(r, common.Name, ""),
+		ServiceName:    utils.ParseQueryStringToString(r, common.Service, ""),
+		ProfileName:    utils.ParseQueryStringToString(r, common.Profile, ""),
+		AdminState:     models.AdminState(utils.ParseQueryStringToString(r, pkgCommon.AdminState, "")),
+		OperatingState: models.OperatingState(utils.ParseQueryStringToString(r, pkgCommon.OperatingState, "")),
+		SortBy:         utils.ParseQueryStringToString(r, pkgCommon.SortBy, ""),
+	}
+	switch order := utils.ParseQueryStringToString(r, pkgCommon.Order, pkgCommon.OrderAscending); order {
+	case pkgCommon.OrderAscending:
+	case pkgCommon.OrderDescending:
+		filter.Descending = true
+	default:
+		return filter, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("querystring %s's value %s is neither %s nor %s", pkgCommon.Order, order, pkgCommon.OrderAscending, pkgCommon.OrderDescending), nil)
+	}
+	for _, property := range r.URL.Query()[pkgCommon.Property] {
+		parts := strings.SplitN(property, ":", 2)
+		if len(parts) != 2 {
+			return filter, errors.NewCommonEdgeX(errors.KindContractInvalid,
+				fmt.Sprintf("querystring %s's value %s is not in the key:value format", pkgCommon.Property, property), nil)
+		}
+		if filter.ProtocolProperties == nil {
+			filter.ProtocolProperties = make(map[string]string)
+		}
+		filter.ProtocolProperties[parts[0]] = parts[1]
+	}
+	return filter, nil
+}
+
 func (dc *DeviceController) DeviceByName(w http.ResponseWriter, r *http.Request) {
 	lc := container.LoggingClientFrom(dc.dic.Get)
 	ctx := r.Context()
@@ -228,13 +305,14 @@ func (dc *DeviceController) DeviceByName(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	name := vars[common.Name]
 
-	device, err := application.DeviceByName(name, dc.dic)
+	device, revision, err := application.DeviceByName(name, dc.dic)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
 	}
 
 	response := responseDTO.NewDeviceResponse("", "", http.StatusOK, device)
+	utils.WriteETagHeader(w, revision)
 	utils.WriteHttpHeader(w, ctx, http.StatusOK)
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
@@ -10,11 +10,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
@@ -439,14 +442,14 @@ func TestPatchDevice(t *testing.T) {
 	dbClientMock.On("DeviceServiceNameExists", *valid.Device.ServiceName).Return(true, nil)
 	dbClientMock.On("DeviceProfileNameExists", *valid.Device.ProfileName).Return(true, nil)
 	dbClientMock.On("DeviceById", *valid.Device.Id).Return(dsModels, nil)
-	dbClientMock.On("UpdateDevice", mock.Anything).Return(nil)
+	dbClientMock.On("DeviceAndRevisionByName", *valid.Device.Name).Return(dsModels, TestRevision, nil)
+	dbClientMock.On("UpdateDeviceWithRevision", mock.Anything, TestRevision).Return(nil)
 	dbClientMock.On("DeviceServiceByName", *valid.Device.ServiceName).Return(models.DeviceService{BaseAddress: testBaseAddress}, nil)
 
 	validWithNoReqID := testReq
 	validWithNoReqID.RequestId = ""
 	validWithNoId := testReq
 	validWithNoId.Device.Id = nil
-	dbClientMock.On("DeviceByName", *validWithNoId.Device.Name).Return(dsModels, nil)
 	validWithNoName := testReq
 	validWithNoName.Device.Name = nil
 
@@ -478,7 +481,7 @@ func TestPatchDevice(t *testing.T) {
 	notFoundName := "notFoundName"
 	invalidNotFoundName.Device.Name = &notFoundName
 	notFoundNameError := errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("%s doesn't exist in the database", notFoundName), nil)
-	dbClientMock.On("DeviceByName", *invalidNotFoundName.Device.Name).Return(dsModels, notFoundNameError)
+	dbClientMock.On("DeviceAndRevisionByName", *invalidNotFoundName.Device.Name).Return(dsModels, uint64(0), notFoundNameError)
 
 	notFountServiceName := "notFoundService"
 	notFoundService := testReq
@@ -640,6 +643,167 @@ func TestAllDevices(t *testing.T) {
 	}
 }
 
+func TestSearchDevices(t *testing.T) {
+	device := dtos.ToDeviceModel(buildTestDeviceRequest().Device)
+	expectedFilter := pkgModels.DeviceSearchFilter{
+		NamePattern:        "test-*",
+		ServiceName:        TestDeviceServiceName,
+		ProfileName:        TestDeviceProfileName,
+		AdminState:         models.Unlocked,
+		OperatingState:     models.Up,
+		Labels:             testDeviceLabels,
+		ProtocolProperties: map[string]string{"Address": "localhost", "Port": "502"},
+		SortBy:             pkgModels.DeviceSortByCreated,
+		Descending:         true,
+	}
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("SearchDevices", 0, 10, expectedFilter).Return([]models.Device{device, device}, uint32(5), nil)
+	dbClientMock.On("SearchDevices", 1, 2, pkgModels.DeviceSearchFilter{}).Return([]models.Device{device}, uint32(2), nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceController(dic)
+	assert.NotNil(t, controller)
+
+	allCriteria := url.Values{
+		common.Offset:            {"0"},
+		common.Limit:             {"10"},
+		common.Name:              {"test-*"},
+		common.Service:           {TestDeviceServiceName},
+		common.Profile:           {TestDeviceProfileName},
+		pkgCommon.AdminState:     {models.Unlocked},
+		pkgCommon.OperatingState: {models.Up},
+		common.Labels:            {strings.Join(testDeviceLabels, ",")},
+		pkgCommon.Property:       {"Address:localhost", "Port:502"},
+		pkgCommon.SortBy:         {pkgModels.DeviceSortByCreated},
+		pkgCommon.Order:          {pkgCommon.OrderDescending},
+	}
+	with := func(key string, value string) url.Values {
+		query := url.Values{common.Offset: {"1"}, common.Limit: {"2"}}
+		query.Set(key, value)
+		return query
+	}
+
+	tests := []struct {
+		name               string
+		query              url.Values
+		expectedCount      int
+		expectedTotalCount uint32
+		expectedStatusCode int
+	}{
+		{"Valid - search devices with all the criteria", allCriteria, 2, 5, http.StatusOK},
+		{"Valid - search devices without criteria", url.Values{common.Offset: {"1"}, common.Limit: {"2"}}, 1, 2, http.StatusOK},
+		{"Valid - ascending order", with(pkgCommon.Order, pkgCommon.OrderAscending), 1, 2, http.StatusOK},
+		{"Invalid - unknown order", with(pkgCommon.Order, "random"), 0, 0, http.StatusBadRequest},
+		{"Invalid - property without value", with(pkgCommon.Property, "Address"), 0, 0, http.StatusBadRequest},
+		{"Invalid - property without name", with(pkgCommon.Property, ":localhost"), 0, 0, http.StatusBadRequest},
+		{"Invalid - unknown admin state", with(pkgCommon.AdminState, "OPEN"), 0, 0, http.StatusBadRequest},
+		{"Invalid - unknown operating state", with(pkgCommon.OperatingState, "ENABLED"), 0, 0, http.StatusBadRequest},
+		{"Invalid - unknown sort field", with(pkgCommon.SortBy, "serviceName"), 0, 0, http.StatusBadRequest},
+		{"Invalid - malformed name pattern", with(common.Name, "test-["), 0, 0, http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiDeviceSearchRoute, http.NoBody)
+			require.NoError(t, err)
+			req.URL.RawQuery = testCase.query.Encode()
+
+			// Act
+			recorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(controller.SearchDevices)
+			handler.ServeHTTP(recorder, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode != http.StatusOK {
+				var res commonDTO.BaseResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedStatusCode, int(res.StatusCode), "Response status code not as expected")
+				assert.NotEmpty(t, res.Message, "Response message doesn't contain the error message")
+			} else {
+				var res responseDTO.MultiDevicesResponse
+				err = json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				assert.Equal(t, common.ApiVersion, res.ApiVersion, "API Version not as expected")
+				assert.Equal(t, testCase.expectedCount, len(res.Devices), "Device count not as expected")
+				assert.Equal(t, testCase.expectedTotalCount, res.TotalCount, "Total count not as expected")
+			}
+		})
+	}
+}
+func TestPatchDevice_IfMatch(t *testing.T) {
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	testReq := buildTestUpdateDeviceRequest()
+	testReq.Device.Id = nil
+	testReq.Device.ServiceName = nil
+	testReq.Device.ProfileName = nil
+	device := models.Device{Name: *testReq.Device.Name, ServiceName: TestDeviceServiceName}
+	dbClientMock.On("DeviceAndRevisionByName", device.Name).Return(device, TestRevision, nil)
+	dbClientMock.On("DeviceServiceByName", device.ServiceName).Return(models.DeviceService{BaseAddress: testBaseAddress}, nil)
+	concurrentUpdate := false
+	dbClientMock.On("UpdateDeviceWithRevision", mock.Anything, TestRevision).Return(func(models.Device, uint64) errors.EdgeX {
+		if concurrentUpdate {
+			return errors.NewCommonEdgeX(errors.KindStatusConflict, "device was modified by another update at the same time", nil)
+		}
+		return nil
+	})
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceController(dic)
+
+	tests := []struct {
+		name                 string
+		ifMatch              string
+		request              []requests.UpdateDeviceRequest
+		concurrentUpdate     bool
+		expectedStatusCode   int
+		expectedResponseCode int
+	}{
+		{"Valid - matched revision", `"2"`, []requests.UpdateDeviceRequest{testReq}, false, http.StatusMultiStatus, http.StatusOK},
+		{"Valid - any revision", "*", []requests.UpdateDeviceRequest{testReq}, false, http.StatusMultiStatus, http.StatusOK},
+		{"Invalid - mismatched revision", `"1"`, []requests.UpdateDeviceRequest{testReq}, false, http.StatusMultiStatus, http.StatusPreconditionFailed},
+		{"Invalid - concurrent update", "", []requests.UpdateDeviceRequest{testReq}, true, http.StatusMultiStatus, http.StatusConflict},
+		{"Invalid - multiple devices", `"2"`, []requests.UpdateDeviceRequest{testReq, testReq}, false, http.StatusBadRequest, http.StatusBadRequest},
+		{"Invalid - malformed revision", "abc", []requests.UpdateDeviceRequest{testReq}, false, http.StatusBadRequest, http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			concurrentUpdate = testCase.concurrentUpdate
+			jsonData, err := json.Marshal(testCase.request)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPatch, common.ApiDeviceRoute, strings.NewReader(string(jsonData)))
+			require.NoError(t, err)
+			if testCase.ifMatch != "" {
+				req.Header.Set(pkgCommon.IfMatch, testCase.ifMatch)
+			}
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.PatchDevice).ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode == http.StatusMultiStatus {
+				var res []commonDTO.BaseResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				assert.Equal(t, testCase.expectedResponseCode, res[0].StatusCode, "BaseResponse status code not as expected")
+			} else {
+				var res commonDTO.BaseResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				assert.Equal(t, testCase.expectedResponseCode, res.StatusCode, "BaseResponse status code not as expected")
+				assert.NotEmpty(t, res.Message, "Response message doesn't contain the error message")
+			}
+		})
+	}
+}
+
 func TestDeviceByName(t *testing.T) {
 	device := dtos.ToDeviceModel(buildTestDeviceRequest().Device)
 	emptyName := ""
@@ -647,8 +811,8 @@ func TestDeviceByName(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("DeviceByName", device.Name).Return(device, nil)
-	dbClientMock.On("DeviceByName", notFoundName).Return(models.Device{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device doesn't exist in the database", nil))
+	dbClientMock.On("DeviceAndRevisionByName", device.Name).Return(device, TestRevision, nil)
+	dbClientMock.On("DeviceAndRevisionByName", notFoundName).Return(models.Device{}, uint64(0), errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device doesn't exist in the database", nil))
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -697,6 +861,7 @@ func TestDeviceByName(t *testing.T) {
 				assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
 				assert.Equal(t, testCase.expectedStatusCode, int(res.StatusCode), "Response status code not as expected")
 				assert.Equal(t, testCase.deviceName, res.Device.Name, "Name not as expected")
+				assert.Equal(t, `"2"`, recorder.Header().Get(pkgCommon.ETag), "ETag not as expected")
 				assert.Empty(t, res.Message, "Message should be empty when it is successful")
 			}
 		})
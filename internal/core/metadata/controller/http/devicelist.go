@@ -0,0 +1,217 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	goio "io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+const (
+	// templatesFileName is the optional form file of the protocol templates referred by the CSV device list
+	templatesFileName = "templates"
+
+	// the columns of the CSV device list, where any other column is a variable of the protocol template
+	csvColumnName             = "name"
+	csvColumnDescription      = "description"
+	csvColumnServiceName      = "serviceName"
+	csvColumnProfileName      = "profileName"
+	csvColumnAdminState       = "adminState"
+	csvColumnOperatingState   = "operatingState"
+	csvColumnLabels           = "labels"
+	csvColumnProtocolTemplate = "protocolTemplate"
+	// csvColumnProtocolPrefix prefixes the column of a protocol property as protocols.<protocol>.<property>
+	csvColumnProtocolPrefix = "protocols."
+	// csvLabelSeparator separates the labels in the labels column
+	csvLabelSeparator = ";"
+)
+
+// AddDevicesByFile adds the devices listed in the uploaded CSV or YAML file in bulk
+func (dc *DeviceController) AddDevicesByFile(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer func() { _ = r.Body.Close() }()
+	}
+
+	lc := container.LoggingClientFrom(dc.dic.Get)
+	ctx := r.Context()
+
+	list, err := readDeviceList(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	ids, rowErrors, err := application.AddDevices(list, ctx, dc.dic)
+	if err != nil && len(rowErrors) == 0 {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	// the row errors are responded along with the error, or along with the ids of the devices which are added
+	var response metadataDTOs.AddDevicesResponse
+	switch {
+	case err != nil:
+		lc.Error(err.Error())
+		response = metadataDTOs.NewAddDevicesResponse("", err.Message(), err.Code(), nil, rowErrors)
+	case len(rowErrors) > 0:
+		response = metadataDTOs.NewAddDevicesResponse("", fmt.Sprintf("%d devices failed to be added", len(rowErrors)), http.StatusMultiStatus, ids, rowErrors)
+	default:
+		response = metadataDTOs.NewAddDevicesResponse("", "", http.StatusCreated, ids, nil)
+	}
+	utils.WriteHttpHeader(w, ctx, response.StatusCode)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
+
+// readDeviceList reads the device list from the multipart form, where the format is specified by the format query
+// parameter or by the extension of the file name
+func readDeviceList(r *http.Request) (metadataDTOs.DeviceList, errors.EdgeX) {
+	var list metadataDTOs.DeviceList
+	file, header, fileErr := r.FormFile(yamlFileName)
+	if fileErr == http.ErrMissingFile {
+		return list, errors.NewCommonEdgeX(errors.KindContractInvalid, "missing device list file", nil)
+	} else if fileErr != nil {
+		return list, errors.NewCommonEdgeX(errors.KindServerError, fileErr.Error(), nil)
+	}
+	defer func() { _ = file.Close() }()
+
+	format := utils.ParseQueryStringToString(r, pkgCommon.Format, "")
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(header.Filename), ".")
+	}
+	switch strings.ToLower(format) {
+	case metadataDTOs.DeviceListFormatCSV:
+		entries, err := readDeviceListCSV(file)
+		if err != nil {
+			return list, errors.NewCommonEdgeXWrapper(err)
+		}
+		list.Devices = entries
+	case metadataDTOs.DeviceListFormatYAML, "yml":
+		err := decodeYamlAsJson(file, &list)
+		if err != nil {
+			return list, errors.NewCommonEdgeXWrapper(err)
+		}
+		for i := range list.Devices {
+			list.Devices[i].Row = i + 1
+		}
+	default:
+		return list, errors.NewCommonEdgeX(errors.KindContractInvalid,
+			fmt.Sprintf("unsupported device list format '%s', must be %s or %s", format, metadataDTOs.DeviceListFormatCSV, metadataDTOs.DeviceListFormatYAML), nil)
+	}
+
+	// the templates file defines the protocol templates in addition to the ones of the YAML device list
+	templatesFile, _, fileErr := r.FormFile(templatesFileName)
+	if fileErr == http.ErrMissingFile {
+		return list, nil
+	} else if fileErr != nil {
+		return list, errors.NewCommonEdgeX(errors.KindServerError, fileErr.Error(), nil)
+	}
+	defer func() { _ = templatesFile.Close() }()
+	var templates map[string]map[string]dtos.ProtocolProperties
+	err := decodeYamlAsJson(templatesFile, &templates)
+	if err != nil {
+		return list, errors.NewCommonEdgeXWrapper(err)
+	}
+	if list.ProtocolTemplates == nil {
+		list.ProtocolTemplates = make(map[string]map[string]dtos.ProtocolProperties, len(templates))
+	}
+	for name, template := range templates {
+		list.ProtocolTemplates[name] = template
+	}
+	return list, nil
+}
+
+// readDeviceListCSV reads the devices of the CSV file with a header row, and the row of each device is the line of the
+// file
+func readDeviceListCSV(reader goio.Reader) ([]metadataDTOs.DeviceListEntry, errors.EdgeX) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	columns, err := csvReader.Read()
+	if err == goio.EOF {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "missing the header row of the csv device list", nil)
+	} else if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to read the csv device list", err)
+	}
+	for i, column := range columns {
+		columns[i] = strings.TrimSpace(column)
+		if strings.HasPrefix(columns[i], csvColumnProtocolPrefix) && len(strings.SplitN(columns[i], ".", 3)) != 3 {
+			return nil, errors.NewCommonEdgeX(errors.KindContractInvalid,
+				fmt.Sprintf("invalid column '%s', the protocol property must be %s<protocol>.<property>", columns[i], csvColumnProtocolPrefix), nil)
+		}
+	}
+
+	var entries []metadataDTOs.DeviceListEntry
+	for {
+		record, err := csvReader.Read()
+		if err == goio.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to read the csv device list", err)
+		}
+		// the blank lines are skipped by the csv reader, so the row is located by the position of the record
+		line, _ := csvReader.FieldPos(0)
+		entry := metadataDTOs.DeviceListEntry{Row: line}
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			switch column := columns[j]; column {
+			case csvColumnName:
+				entry.Name = value
+			case csvColumnDescription:
+				entry.Description = value
+			case csvColumnServiceName:
+				entry.ServiceName = value
+			case csvColumnProfileName:
+				entry.ProfileName = value
+			case csvColumnAdminState:
+				entry.AdminState = value
+			case csvColumnOperatingState:
+				entry.OperatingState = value
+			case csvColumnLabels:
+				for _, label := range strings.Split(value, csvLabelSeparator) {
+					if label = strings.TrimSpace(label); label != "" {
+						entry.Labels = append(entry.Labels, label)
+					}
+				}
+			case csvColumnProtocolTemplate:
+				entry.ProtocolTemplate = value
+			default:
+				if strings.HasPrefix(column, csvColumnProtocolPrefix) {
+					if value == "" {
+						continue
+					}
+					parts := strings.SplitN(column, ".", 3)
+					if entry.Protocols == nil {
+						entry.Protocols = make(map[string]dtos.ProtocolProperties)
+					}
+					if entry.Protocols[parts[1]] == nil {
+						entry.Protocols[parts[1]] = make(dtos.ProtocolProperties)
+					}
+					entry.Protocols[parts[1]][parts[2]] = value
+					continue
+				}
+				if entry.Variables == nil {
+					entry.Variables = make(map[string]string)
+				}
+				entry.Variables[column] = value
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testProtocolTemplates = `
+modbus:
+  modbus-tcp:
+    Address: "{{host}}"
+    Port: "502"
+    UnitID: "{{unit}}"
+rtu:
+  modbus-rtu:
+    Address: "{{serialPort}}"
+`
+
+func createDeviceListRequest(fileName string, fileContents string, templates string) (*http.Request, error) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	files := map[string]string{"file": fileContents}
+	if templates != "" {
+		files[templatesFileName] = templates
+	}
+	for field, contents := range files {
+		name := fileName
+		if field == templatesFileName {
+			name = "templates.yaml"
+		}
+		part, err := writer.CreateFormFile(field, name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = part.Write([]byte(contents)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiDeviceUploadFileRoute, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req, nil
+}
+
+func TestAddDevicesByFile(t *testing.T) {
+	validCSV := "name,serviceName,profileName,labels,protocolTemplate,host,unit,protocols.modbus-tcp.Timeout\n" +
+		"Meter-1,TestDeviceServiceName,TestDeviceProfileName,floor1;meter,modbus,10.0.0.1,1,5\n" +
+		"\n" +
+		"Meter-2,TestDeviceServiceName,TestDeviceProfileName,,modbus,10.0.0.2,2,\n"
+	validYAML := `
+protocolTemplates:
+  modbus:
+    modbus-tcp:
+      Address: "{{host}}"
+      Port: "502"
+      UnitID: "{{unit}}"
+devices:
+  - name: Meter-1
+    serviceName: TestDeviceServiceName
+    profileName: TestDeviceProfileName
+    protocolTemplate: modbus
+    variables:
+      host: 10.0.0.1
+      unit: "1"
+    protocols:
+      modbus-tcp:
+        Timeout: "5"
+  - name: Meter-2
+    serviceName: TestDeviceServiceName
+    profileName: TestDeviceProfileName
+    adminState: LOCKED
+    protocolTemplate: modbus
+    variables:
+      host: 10.0.0.2
+      unit: "2"
+`
+	invalidCSV := "name,serviceName,profileName,protocolTemplate,host,unit\n" +
+		"Meter-1,TestDeviceServiceName,TestDeviceProfileName,modbus,10.0.0.1,1\n" +
+		"Meter-1,TestDeviceServiceName,TestDeviceProfileName,modbus,10.0.0.2,2\n" +
+		"Meter-3,TestDeviceServiceName,TestDeviceProfileName,unknown,10.0.0.3,3\n" +
+		"Meter-5,notFoundService,TestDeviceProfileName,modbus,10.0.0.5,5\n" +
+		"existed,TestDeviceServiceName,TestDeviceProfileName,modbus,10.0.0.6,6\n" +
+		"Meter-7,TestDeviceServiceName,TestDeviceProfileName,modbus,10.0.0.7,7\n" +
+		"Meter-8,TestDeviceServiceName,TestDeviceProfileName,rtu,10.0.0.8,8\n"
+	failedCSV := "name,serviceName,profileName,protocolTemplate,host,unit\n" +
+		"Meter-1,TestDeviceServiceName,TestDeviceProfileName,modbus,10.0.0.1,1\n" +
+		"failed,TestDeviceServiceName,TestDeviceProfileName,modbus,10.0.0.2,2\n"
+
+	tests := []struct {
+		name               string
+		fileName           string
+		contents           string
+		templates          string
+		expectedStatusCode int
+		expectedIds        int
+		expectedErrorRows  []int
+	}{
+		{"Valid - csv with templates", "devices.csv", validCSV, testProtocolTemplates, http.StatusCreated, 2, nil},
+		{"Valid - yaml", "devices.yaml", validYAML, "", http.StatusCreated, 2, nil},
+		{"Invalid - invalid rows", "devices.csv", invalidCSV, testProtocolTemplates, http.StatusBadRequest, 0, []int{3, 4, 5, 6, 8}},
+		{"Invalid - unsupported format", "devices.txt", validCSV, testProtocolTemplates, http.StatusBadRequest, 0, nil},
+		{"Invalid - invalid protocol column", "devices.csv", "name,protocols.modbus\nMeter-1,1\n", "", http.StatusBadRequest, 0, nil},
+		{"Invalid - empty csv", "devices.csv", "", "", http.StatusBadRequest, 0, nil},
+		{"Partial - device failed to be added", "devices.csv", failedCSV, testProtocolTemplates, http.StatusMultiStatus, 1, []int{3}},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			dic := mockDic()
+			queue := newTestCallbackQueue(t, dic)
+			var added []models.Device
+			dbClientMock := &dbMock.DBClient{}
+			dbClientMock.On("DeviceNameExists", "existed").Return(true, nil)
+			dbClientMock.On("DeviceNameExists", mock.Anything).Return(false, nil)
+			dbClientMock.On("DeviceServiceNameExists", "notFoundService").Return(false, nil)
+			dbClientMock.On("DeviceServiceNameExists", TestDeviceServiceName).Return(true, nil)
+			dbClientMock.On("DeviceProfileNameExists", TestDeviceProfileName).Return(true, nil)
+			dbClientMock.On("AddDevice", mock.MatchedBy(func(d models.Device) bool { return d.Name == "failed" })).
+				Return(models.Device{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "failed to add", nil))
+			dbClientMock.On("AddDevice", mock.Anything).Return(func(d models.Device) models.Device {
+				d.Id = d.Name
+				added = append(added, d)
+				return d
+			}, nil)
+			dic.Update(di.ServiceConstructorMap{
+				container.DBClientInterfaceName: func(get di.Get) interface{} {
+					return dbClientMock
+				},
+			})
+			controller := NewDeviceController(dic)
+
+			req, err := createDeviceListRequest(testCase.fileName, testCase.contents, testCase.templates)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.AddDevicesByFile).ServeHTTP(recorder, req)
+
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			var res metadataDTOs.AddDevicesResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+			assert.Equal(t, testCase.expectedStatusCode, res.StatusCode, "Response status code not as expected")
+			assert.Len(t, res.Ids, testCase.expectedIds)
+			rows := make([]int, 0, len(res.Errors))
+			for _, rowError := range res.Errors {
+				rows = append(rows, rowError.Row)
+			}
+			if testCase.expectedErrorRows != nil {
+				assert.Equal(t, testCase.expectedErrorRows, rows)
+			} else {
+				assert.Empty(t, rows)
+			}
+			if testCase.expectedStatusCode == http.StatusBadRequest {
+				dbClientMock.AssertNotCalled(t, "AddDevice", mock.Anything)
+				return
+			}
+
+			// the added devices are sent to the device service by a single callback
+			status, err := queue.Status(TestDeviceServiceName)
+			require.NoError(t, err)
+			require.Len(t, status.Pending, 1)
+			assert.Equal(t, metadataDTOs.CallbackAddDevices, status.Pending[0].Action)
+			if testCase.expectedStatusCode != http.StatusCreated {
+				return
+			}
+			require.Len(t, added, 2)
+			assert.Equal(t, "10.0.0.1", added[0].Protocols["modbus-tcp"]["Address"])
+			assert.Equal(t, "1", added[0].Protocols["modbus-tcp"]["UnitID"])
+			assert.Equal(t, "502", added[0].Protocols["modbus-tcp"]["Port"])
+			assert.Equal(t, "5", added[0].Protocols["modbus-tcp"]["Timeout"])
+			assert.Equal(t, "10.0.0.2", added[1].Protocols["modbus-tcp"]["Address"])
+			assert.NotContains(t, added[1].Protocols["modbus-tcp"], "Timeout")
+			assert.EqualValues(t, models.Up, added[1].OperatingState)
+		})
+	}
+}
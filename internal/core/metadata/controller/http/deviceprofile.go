@@ -13,6 +13,7 @@ import (
 	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 
@@ -99,8 +100,14 @@ func (dc *DeviceProfileController) UpdateDeviceProfile(w http.ResponseWriter, r
 	ctx := r.Context()
 	correlationId := correlation.FromContext(ctx)
 
+	force, err := utils.ParseQueryStringToBool(r, pkgCommon.Force, false)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
 	var reqDTOs []requestDTO.DeviceProfileRequest
-	err := dc.jsonDtoReader.Read(r.Body, &reqDTOs)
+	err = dc.jsonDtoReader.Read(r.Body, &reqDTOs)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
@@ -111,7 +118,7 @@ func (dc *DeviceProfileController) UpdateDeviceProfile(w http.ResponseWriter, r
 	for i, d := range deviceProfiles {
 		var response interface{}
 		reqId := reqDTOs[i].RequestId
-		err := application.UpdateDeviceProfile(d, ctx, dc.dic)
+		err := application.UpdateDeviceProfile(d, force, ctx, dc.dic)
 		if err != nil {
 			lc.Error(err.Error(), common.CorrelationHeader, correlationId)
 			lc.Debug(err.DebugMessages(), common.CorrelationHeader, correlationId)
@@ -177,6 +184,12 @@ func (dc *DeviceProfileController) UpdateDeviceProfileByYaml(w http.ResponseWrit
 	lc := container.LoggingClientFrom(dc.dic.Get)
 	ctx := r.Context()
 
+	force, err := utils.ParseQueryStringToBool(r, pkgCommon.Force, false)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
 	file, _, fileErr := r.FormFile(yamlFileName)
 	if fileErr == http.ErrMissingFile {
 		utils.WriteErrorResponse(w, ctx, lc, errors.NewCommonEdgeX(errors.KindContractInvalid, "missing yaml file", nil), "")
@@ -187,14 +200,14 @@ func (dc *DeviceProfileController) UpdateDeviceProfileByYaml(w http.ResponseWrit
 	}
 
 	var deviceProfileDTO dtos.DeviceProfile
-	err := dc.yamlDtoReader.Read(file, &deviceProfileDTO)
+	err = dc.yamlDtoReader.Read(file, &deviceProfileDTO)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
 	}
 
 	deviceProfile := dtos.ToDeviceProfileModel(deviceProfileDTO)
-	err = application.UpdateDeviceProfile(deviceProfile, ctx, dc.dic)
+	err = application.UpdateDeviceProfile(deviceProfile, force, ctx, dc.dic)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
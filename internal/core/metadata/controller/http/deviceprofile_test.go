@@ -35,6 +35,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -132,7 +133,7 @@ func TestAddDeviceProfile_Created(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("AddDeviceProfile", deviceProfileModel).Return(deviceProfileModel, nil)
+	dbClientMock.On("AddDeviceProfile", deviceProfileModel, mock.Anything).Return(deviceProfileModel, nil)
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -271,8 +272,8 @@ func TestAddDeviceProfile_Duplicated(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("AddDeviceProfile", duplicateNameModel).Return(duplicateNameModel, duplicateNameDBError)
-	dbClientMock.On("AddDeviceProfile", duplicateIdModel).Return(duplicateIdModel, duplicateIdDBError)
+	dbClientMock.On("AddDeviceProfile", duplicateNameModel, mock.Anything).Return(duplicateNameModel, duplicateNameDBError)
+	dbClientMock.On("AddDeviceProfile", duplicateIdModel, mock.Anything).Return(duplicateIdModel, duplicateIdDBError)
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -365,8 +366,11 @@ func TestUpdateDeviceProfile(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("UpdateDeviceProfile", deviceProfileModel).Return(nil)
-	dbClientMock.On("UpdateDeviceProfile", notFoundDeviceProfileModel).Return(notFoundDBError)
+	dbClientMock.On("UpdateDeviceProfileWithRevision", deviceProfileModel, uint32(0), mock.Anything).Return(nil)
+	dbClientMock.On("DeviceProfileByName", deviceProfileModel.Name).Return(deviceProfileModel, nil)
+	dbClientMock.On("DeviceProfileAndRevisionByName", deviceProfileModel.Name).Return(deviceProfileModel, uint32(0), nil)
+	dbClientMock.On("DeviceProfileByName", notFoundDeviceProfileModel.Name).Return(models.DeviceProfile{}, notFoundDBError)
+	dbClientMock.On("DeviceProfileAndRevisionByName", notFoundDeviceProfileModel.Name).Return(models.DeviceProfile{}, uint32(0), notFoundDBError)
 	dbClientMock.On("DeviceCountByProfileName", deviceProfileModel.Name).Return(uint32(1), nil)
 	dbClientMock.On("DevicesByProfileName", 0, -1, deviceProfileModel.Name).Return([]models.Device{{ServiceName: testDeviceServiceName}}, nil)
 	dbClientMock.On("DeviceServiceByName", testDeviceServiceName).Return(models.DeviceService{}, nil)
@@ -441,7 +445,7 @@ func TestAddDeviceProfileByYaml_Created(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("AddDeviceProfile", deviceProfileModel).Return(deviceProfileModel, nil)
+	dbClientMock.On("AddDeviceProfile", deviceProfileModel, mock.Anything).Return(deviceProfileModel, nil)
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -552,7 +556,7 @@ func TestAddDeviceProfileByYaml_Duplicated(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("AddDeviceProfile", deviceProfileModel).Return(deviceProfileModel, dbError)
+	dbClientMock.On("AddDeviceProfile", deviceProfileModel, mock.Anything).Return(deviceProfileModel, dbError)
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -656,8 +660,11 @@ func TestUpdateDeviceProfileByYaml(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("UpdateDeviceProfile", validDeviceProfileModel).Return(nil)
-	dbClientMock.On("UpdateDeviceProfile", notFoundDeviceProfileModel).Return(notFoundDBError)
+	dbClientMock.On("UpdateDeviceProfileWithRevision", validDeviceProfileModel, uint32(0), mock.Anything).Return(nil)
+	dbClientMock.On("DeviceProfileByName", validDeviceProfileModel.Name).Return(validDeviceProfileModel, nil)
+	dbClientMock.On("DeviceProfileAndRevisionByName", validDeviceProfileModel.Name).Return(validDeviceProfileModel, uint32(0), nil)
+	dbClientMock.On("DeviceProfileByName", notFoundDeviceProfileModel.Name).Return(models.DeviceProfile{}, notFoundDBError)
+	dbClientMock.On("DeviceProfileAndRevisionByName", notFoundDeviceProfileModel.Name).Return(models.DeviceProfile{}, uint32(0), notFoundDBError)
 	dbClientMock.On("DeviceCountByProfileName", validDeviceProfileModel.Name).Return(uint32(1), nil)
 	dbClientMock.On("DevicesByProfileName", 0, -1, validDeviceProfileModel.Name).Return([]models.Device{{ServiceName: testDeviceServiceName}}, nil)
 	dbClientMock.On("DeviceServiceByName", testDeviceServiceName).Return(models.DeviceService{}, nil)
@@ -782,9 +789,11 @@ func TestDeleteDeviceProfileByName(t *testing.T) {
 	dbClientMock := &dbMock.DBClient{}
 	dbClientMock.On("DevicesByProfileName", 0, 1, deviceProfile.Name).Return([]models.Device{}, nil)
 	dbClientMock.On("ProvisionWatchersByProfileName", 0, 1, deviceProfile.Name).Return([]models.ProvisionWatcher{}, nil)
+	dbClientMock.On("DeviceProfileByName", deviceProfile.Name).Return(deviceProfile, nil)
 	dbClientMock.On("DeleteDeviceProfileByName", deviceProfile.Name).Return(nil)
 	dbClientMock.On("DevicesByProfileName", 0, 1, notFoundName).Return([]models.Device{}, nil)
 	dbClientMock.On("ProvisionWatchersByProfileName", 0, 1, notFoundName).Return([]models.ProvisionWatcher{}, nil)
+	dbClientMock.On("DeviceProfileByName", notFoundName).Return(models.DeviceProfile{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device profile doesn't exist in the database", nil))
 	dbClientMock.On("DeleteDeviceProfileByName", notFoundName).Return(errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device profile doesn't exist in the database", nil))
 	dbClientMock.On("DevicesByProfileName", 0, 1, deviceExists).Return([]models.Device{models.Device{}}, nil)
 	dbClientMock.On("DevicesByProfileName", 0, 1, provisionWatcherExists).Return([]models.Device{}, nil)
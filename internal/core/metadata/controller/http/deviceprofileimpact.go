@@ -0,0 +1,46 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	requestDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+)
+
+// DeviceProfileUpdateImpact returns the impact of updating the device profile without updating it
+func (dc *DeviceProfileController) DeviceProfileUpdateImpact(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer func() { _ = r.Body.Close() }()
+	}
+
+	lc := container.LoggingClientFrom(dc.dic.Get)
+	ctx := r.Context()
+
+	var reqDTO requestDTO.DeviceProfileRequest
+	err := dc.jsonDtoReader.Read(r.Body, &reqDTO)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	impact, err := application.DeviceProfileUpdateImpact(dtos.ToDeviceProfileModel(reqDTO.Profile), dc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	response := metadataDTOs.NewDeviceProfileImpactResponse(reqDTO.RequestId, "", http.StatusOK, impact)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
@@ -0,0 +1,179 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// buildBreakingDeviceProfileRequest returns the test device profile request which removes the device command and
+// changes the value type of the device resource
+func buildBreakingDeviceProfileRequest() requests.DeviceProfileRequest {
+	request := buildTestDeviceProfileRequest()
+	request.Profile.DeviceResources = append([]dtos.DeviceResource{}, request.Profile.DeviceResources...)
+	request.Profile.DeviceResources[0].Properties.ValueType = common.ValueTypeFloat32
+	request.Profile.DeviceCommands = nil
+	return request
+}
+
+func mockDeviceProfileImpactDBClient(stored models.DeviceProfile) *dbMock.DBClient {
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceProfileAndRevisionByName", stored.Name).Return(stored, uint32(2), nil)
+	dbClientMock.On("DevicesByProfileName", 0, -1, stored.Name).Return([]models.Device{
+		{Name: TestDeviceName, ServiceName: testDeviceServiceName, AutoEvents: []models.AutoEvent{
+			{Interval: "1s", SourceName: TestDeviceCommandName},
+			{Interval: "1s", SourceName: TestDeviceResourceName},
+		}},
+		{Name: "idle", ServiceName: testDeviceServiceName},
+	}, nil)
+	return dbClientMock
+}
+
+func TestDeviceProfileUpdateImpact(t *testing.T) {
+	stored := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	dic := mockDic()
+	dbClientMock := mockDeviceProfileImpactDBClient(stored)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	jsonData, err := json.Marshal(buildBreakingDeviceProfileRequest())
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiDeviceProfileImpactRoute, strings.NewReader(string(jsonData)))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(controller.DeviceProfileUpdateImpact).ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	var response metadataDTOs.DeviceProfileImpactResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	impact := response.Impact
+	assert.True(t, impact.Breaking)
+	assert.Empty(t, impact.RemovedResources)
+	assert.Equal(t, []string{TestDeviceCommandName}, impact.RemovedCommands)
+	require.Len(t, impact.ChangedValueTypes, 1)
+	assert.Equal(t, TestDeviceResourceName, impact.ChangedValueTypes[0].ResourceName)
+	assert.Equal(t, common.ValueTypeFloat32, impact.ChangedValueTypes[0].To)
+	assert.Equal(t, []string{TestDeviceName, "idle"}, impact.AffectedDevices)
+	assert.Equal(t, []metadataDTOs.BrokenAutoEvent{{DeviceName: TestDeviceName, SourceName: TestDeviceCommandName}}, impact.BrokenAutoEvents)
+	dbClientMock.AssertNotCalled(t, "UpdateDeviceProfile", mock.Anything, mock.Anything)
+}
+
+func TestUpdateDeviceProfile_Breaking(t *testing.T) {
+	stored := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	breaking := buildBreakingDeviceProfileRequest()
+	breakingModel := requests.DeviceProfileReqToDeviceProfileModel(breaking)
+
+	dic := mockDic()
+	dbClientMock := mockDeviceProfileImpactDBClient(stored)
+	dbClientMock.On("UpdateDeviceProfile", breakingModel, mock.Anything).Return(nil)
+	dbClientMock.On("DeviceCountByProfileName", stored.Name).Return(uint32(2), nil)
+	dbClientMock.On("DeviceServiceByName", testDeviceServiceName).Return(models.DeviceService{}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	tests := []struct {
+		name               string
+		force              string
+		expectedStatusCode int
+	}{
+		{"Invalid - breaking update without force", "", http.StatusConflict},
+		{"Invalid - force is not a boolean", "yes", http.StatusBadRequest},
+		{"Valid - forced breaking update", "true", http.StatusOK},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			jsonData, err := json.Marshal([]requests.DeviceProfileRequest{breaking})
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPut, common.ApiDeviceProfileRoute, strings.NewReader(string(jsonData)))
+			require.NoError(t, err)
+			if testCase.force != "" {
+				query := req.URL.Query()
+				query.Add(pkgCommon.Force, testCase.force)
+				req.URL.RawQuery = query.Encode()
+			}
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.UpdateDeviceProfile).ServeHTTP(recorder, req)
+
+			if testCase.expectedStatusCode == http.StatusBadRequest {
+				assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+				return
+			}
+			require.Equal(t, http.StatusMultiStatus, recorder.Result().StatusCode)
+			var responses []commonDTO.BaseResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &responses))
+			require.Len(t, responses, 1)
+			assert.Equal(t, testCase.expectedStatusCode, responses[0].StatusCode)
+			if testCase.expectedStatusCode == http.StatusConflict {
+				assert.Contains(t, responses[0].Message, "force=true")
+				dbClientMock.AssertNotCalled(t, "UpdateDeviceProfile", mock.Anything, mock.Anything)
+				dbClientMock.AssertNotCalled(t, "UpdateDeviceProfileWithRevision", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+	dbClientMock.AssertCalled(t, "UpdateDeviceProfile", breakingModel, mock.Anything)
+}
+
+func TestUpdateDeviceProfile_RevisionConflict(t *testing.T) {
+	stored := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	request := buildTestDeviceProfileRequest()
+	request.Profile.Description = "updated"
+	updated := requests.DeviceProfileReqToDeviceProfileModel(request)
+
+	dic := mockDic()
+	dbClientMock := mockDeviceProfileImpactDBClient(stored)
+	dbClientMock.On("UpdateDeviceProfileWithRevision", updated, uint32(2), mock.Anything).Return(
+		errors.NewCommonEdgeX(errors.KindStatusConflict, "device profile is at revision 3 rather than the expected revision 2", nil))
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	jsonData, err := json.Marshal([]requests.DeviceProfileRequest{request})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPut, common.ApiDeviceProfileRoute, strings.NewReader(string(jsonData)))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(controller.UpdateDeviceProfile).ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusMultiStatus, recorder.Result().StatusCode)
+	var responses []commonDTO.BaseResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &responses))
+	require.Len(t, responses, 1)
+	assert.Equal(t, http.StatusConflict, responses[0].StatusCode,
+		"the update should be rejected when the device profile is updated after its impact is checked")
+	dbClientMock.AssertNotCalled(t, "UpdateDeviceProfile", mock.Anything, mock.Anything)
+}
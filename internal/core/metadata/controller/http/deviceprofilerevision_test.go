@@ -0,0 +1,233 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestDeviceProfileRevisions returns two revisions of the test device profile, the second one changes the units
+// of the device resource, removes the device command and adds a label
+func buildTestDeviceProfileRevisions() []pkgModels.DeviceProfileRevision {
+	first := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	second := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	second.DeviceResources[0].Properties.Units = "C"
+	second.DeviceCommands = nil
+	second.Labels = append(second.Labels, "NEW")
+	return []pkgModels.DeviceProfileRevision{
+		{Revision: 1, ProfileName: first.Name, CorrelationId: ExampleUUID, Created: 1, Profile: first},
+		{Revision: 2, ProfileName: second.Name, Created: 2, Profile: second},
+	}
+}
+
+func TestDeviceProfileRevisions(t *testing.T) {
+	revisions := buildTestDeviceProfileRevisions()
+	name := TestDeviceProfileName
+	notFound := "notFound"
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceProfileNameExists", name).Return(true, nil)
+	dbClientMock.On("DeviceProfileNameExists", notFound).Return(false, nil)
+	dbClientMock.On("DeviceProfileRevisions", 0, 20, name).Return([]pkgModels.DeviceProfileRevision{revisions[1], revisions[0]}, nil)
+	dbClientMock.On("DeviceProfileRevisionCount", name).Return(uint32(2), nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	tests := []struct {
+		name               string
+		profileName        string
+		expectedStatusCode int
+	}{
+		{"Valid - revisions", name, http.StatusOK},
+		{"Invalid - device profile not found", notFound, http.StatusNotFound},
+		{"Invalid - empty name", "", http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiAllDeviceProfileRevisionsRoute, http.NoBody)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{common.Name: testCase.profileName})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.DeviceProfileRevisions).ServeHTTP(recorder, req)
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode != http.StatusOK {
+				return
+			}
+			var response metadataDTOs.MultiDeviceProfileRevisionsResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, uint32(2), response.TotalCount)
+			require.Len(t, response.Revisions, 2)
+			assert.Equal(t, uint32(2), response.Revisions[0].Revision)
+			assert.Equal(t, ExampleUUID, response.Revisions[1].CorrelationId)
+		})
+	}
+}
+
+func TestDeviceProfileRevision(t *testing.T) {
+	revisions := buildTestDeviceProfileRevisions()
+	name := TestDeviceProfileName
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceProfileRevision", name, uint32(1)).Return(revisions[0], nil)
+	dbClientMock.On("DeviceProfileRevision", name, uint32(3)).Return(pkgModels.DeviceProfileRevision{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "revision doesn't exist", nil))
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	tests := []struct {
+		name               string
+		revision           string
+		expectedStatusCode int
+	}{
+		{"Valid - revision", "1", http.StatusOK},
+		{"Invalid - revision not found", "3", http.StatusNotFound},
+		{"Invalid - zero revision", "0", http.StatusBadRequest},
+		{"Invalid - non-numeric revision", "latest", http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiDeviceProfileRevisionByNumberRoute, http.NoBody)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{common.Name: name, pkgCommon.Revision: testCase.revision})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.DeviceProfileRevision).ServeHTTP(recorder, req)
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode != http.StatusOK {
+				return
+			}
+			var response metadataDTOs.DeviceProfileRevisionResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, uint32(1), response.Revision.Revision)
+			assert.Equal(t, name, response.Revision.Profile.Name)
+		})
+	}
+}
+
+func TestDiffDeviceProfileRevisions(t *testing.T) {
+	revisions := buildTestDeviceProfileRevisions()
+	name := TestDeviceProfileName
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceProfileRevision", name, uint32(1)).Return(revisions[0], nil)
+	dbClientMock.On("DeviceProfileRevision", name, uint32(2)).Return(revisions[1], nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	diff := func(from string, to string) (*httptest.ResponseRecorder, metadataDTOs.DeviceProfileDiffResponse) {
+		req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiDeviceProfileRevisionDiffRoute, http.NoBody)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{common.Name: name, pkgCommon.From: from, pkgCommon.To: to})
+		recorder := httptest.NewRecorder()
+		http.HandlerFunc(controller.DiffDeviceProfileRevisions).ServeHTTP(recorder, req)
+		var response metadataDTOs.DeviceProfileDiffResponse
+		if recorder.Result().StatusCode == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		}
+		return recorder, response
+	}
+
+	recorder, response := diff("1", "2")
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.Equal(t, uint32(1), response.Diff.FromRevision)
+	assert.Equal(t, uint32(2), response.Diff.ToRevision)
+	changes := make(map[string]metadataDTOs.DeviceProfileChange)
+	for _, c := range response.Diff.Changes {
+		changes[c.Path] = c
+	}
+	require.Len(t, changes, 3, "only the changed fields should be reported")
+	command := "deviceCommands[" + TestDeviceCommandName + "]"
+	assert.Equal(t, metadataDTOs.DeviceProfileChangeRemoved, changes[command].Kind, "the device command should be located by name")
+	units := "deviceResources[" + TestDeviceResourceName + "].properties.units"
+	assert.Equal(t, metadataDTOs.DeviceProfileChangeUpdated, changes[units].Kind)
+	assert.Equal(t, "", changes[units].From)
+	assert.Equal(t, "C", changes[units].To)
+	assert.Equal(t, metadataDTOs.DeviceProfileChangeUpdated, changes["labels"].Kind)
+
+	recorder, response = diff("2", "2")
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.Empty(t, response.Diff.Changes)
+
+	recorder, _ = diff("1", "x")
+	assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+}
+
+func TestRollbackDeviceProfile(t *testing.T) {
+	revisions := buildTestDeviceProfileRevisions()
+	name := TestDeviceProfileName
+
+	dic := mockDic()
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceProfileRevision", name, uint32(1)).Return(revisions[0], nil)
+	dbClientMock.On("DeviceProfileRevision", name, uint32(3)).Return(pkgModels.DeviceProfileRevision{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "revision doesn't exist", nil))
+	dbClientMock.On("DeviceProfileAndRevisionByName", name).Return(revisions[1].Profile, uint32(2), nil)
+	dbClientMock.On("UpdateDeviceProfileWithRevision", revisions[0].Profile, uint32(2), mock.Anything).Return(nil)
+	dbClientMock.On("DeviceCountByProfileName", name).Return(uint32(0), nil)
+	dbClientMock.On("DevicesByProfileName", 0, -1, name).Return([]models.Device{}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewDeviceProfileController(dic)
+
+	tests := []struct {
+		name               string
+		revision           string
+		expectedStatusCode int
+	}{
+		{"Valid - rollback", "1", http.StatusOK},
+		{"Invalid - revision not found", "3", http.StatusNotFound},
+		{"Invalid - zero revision", "0", http.StatusBadRequest},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiDeviceProfileRevisionRollbackRoute, http.NoBody)
+			require.NoError(t, err)
+			req = mux.SetURLVars(req, map[string]string{common.Name: name, pkgCommon.Revision: testCase.revision})
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.RollbackDeviceProfile).ServeHTTP(recorder, req)
+			assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+		})
+	}
+	dbClientMock.AssertCalled(t, "UpdateDeviceProfileWithRevision", revisions[0].Profile, uint32(2), mock.Anything)
+}
@@ -6,6 +6,7 @@
 package http
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 
@@ -13,6 +14,7 @@ import (
 	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 
@@ -22,6 +24,7 @@ import (
 	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	requestDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	responseDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 
 	"github.com/gorilla/mux"
 )
@@ -92,13 +95,14 @@ func (dc *DeviceServiceController) DeviceServiceByName(w http.ResponseWriter, r
 	vars := mux.Vars(r)
 	name := vars[common.Name]
 
-	deviceService, err := application.DeviceServiceByName(name, ctx, dc.dic)
+	deviceService, revision, err := application.DeviceServiceByName(name, ctx, dc.dic)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
 	}
 
 	response := responseDTO.NewDeviceServiceResponse("", "", http.StatusOK, deviceService)
+	utils.WriteETagHeader(w, revision)
 	utils.WriteHttpHeader(w, ctx, http.StatusOK)
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
@@ -120,18 +124,28 @@ func (dc *DeviceServiceController) PatchDeviceService(w http.ResponseWriter, r *
 		return
 	}
 
+	// the If-Match header is only allowed when a single entity is patched
+	expectedRevision, err := utils.ParseIfMatchRevision(r)
+	if err == nil && expectedRevision != nil && len(reqDTOs) != 1 {
+		err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%s header is not allowed when patching %d entities", pkgCommon.IfMatch, len(reqDTOs)), nil)
+	}
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
 	var updateResponses []interface{}
 	for _, dto := range reqDTOs {
 		var response interface{}
 		reqId := dto.RequestId
-		err := application.PatchDeviceService(dto.Service, ctx, dc.dic)
+		err := application.PatchDeviceService(dto.Service, expectedRevision, ctx, dc.dic)
 		if err != nil {
 			lc.Error(err.Error(), common.CorrelationHeader, correlationId)
 			lc.Debug(err.DebugMessages(), common.CorrelationHeader, correlationId)
 			response = commonDTO.NewBaseResponse(
 				reqId,
 				err.Message(),
-				err.Code())
+				utils.IfMatchStatusCode(err))
 		} else {
 			response = commonDTO.NewBaseResponse(
 				reqId,
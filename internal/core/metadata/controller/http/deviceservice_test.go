@@ -15,6 +15,7 @@ import (
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
 	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
@@ -200,8 +201,8 @@ func TestDeviceServiceByName(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &dbMock.DBClient{}
-	dbClientMock.On("DeviceServiceByName", deviceService.Name).Return(deviceService, nil)
-	dbClientMock.On("DeviceServiceByName", notFoundName).Return(models.DeviceService{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device service doesn't exist in the database", nil))
+	dbClientMock.On("DeviceServiceAndRevisionByName", deviceService.Name).Return(deviceService, TestRevision, nil)
+	dbClientMock.On("DeviceServiceAndRevisionByName", notFoundName).Return(models.DeviceService{}, uint64(0), errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device service doesn't exist in the database", nil))
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -250,6 +251,7 @@ func TestDeviceServiceByName(t *testing.T) {
 				assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
 				assert.Equal(t, testCase.expectedStatusCode, int(res.StatusCode), "Response status code not as expected")
 				assert.Equal(t, testCase.deviceServiceName, res.Service.Name, "Name not as expected")
+				assert.Equal(t, `"2"`, recorder.Header().Get(pkgCommon.ETag), "ETag not as expected")
 				assert.Empty(t, res.Message, "Message should be empty when it is successful")
 			}
 		})
@@ -271,7 +273,8 @@ func TestPatchDeviceService(t *testing.T) {
 
 	valid := testReq
 	dbClientMock.On("DeviceServiceById", *valid.Service.Id).Return(dsModels, nil)
-	dbClientMock.On("UpdateDeviceService", mock.Anything).Return(nil)
+	dbClientMock.On("DeviceServiceAndRevisionByName", *valid.Service.Name).Return(dsModels, TestRevision, nil)
+	dbClientMock.On("UpdateDeviceServiceWithRevision", mock.Anything, TestRevision).Return(nil)
 	validWithNoReqID := testReq
 	validWithNoReqID.RequestId = ""
 	validWithNoId := testReq
@@ -308,7 +311,7 @@ func TestPatchDeviceService(t *testing.T) {
 	notFoundName := "notFoundName"
 	invalidNotFoundName.Service.Name = &notFoundName
 	notFoundNameError := errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("%s doesn't exist in the database", notFoundName), nil)
-	dbClientMock.On("DeviceServiceByName", *invalidNotFoundName.Service.Name).Return(dsModels, notFoundNameError)
+	dbClientMock.On("DeviceServiceAndRevisionByName", *invalidNotFoundName.Service.Name).Return(dsModels, uint64(0), notFoundNameError)
 
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
@@ -481,9 +484,11 @@ func TestDeleteDeviceServiceByName(t *testing.T) {
 	dbClientMock := &dbMock.DBClient{}
 	dbClientMock.On("DevicesByServiceName", 0, 1, deviceService.Name).Return([]models.Device{}, nil)
 	dbClientMock.On("ProvisionWatchersByServiceName", 0, 1, deviceService.Name).Return([]models.ProvisionWatcher{}, nil)
+	dbClientMock.On("DeviceServiceByName", deviceService.Name).Return(deviceService, nil)
 	dbClientMock.On("DeleteDeviceServiceByName", deviceService.Name).Return(nil)
 	dbClientMock.On("DevicesByServiceName", 0, 1, notFoundName).Return([]models.Device{}, nil)
 	dbClientMock.On("ProvisionWatchersByServiceName", 0, 1, notFoundName).Return([]models.ProvisionWatcher{}, nil)
+	dbClientMock.On("DeviceServiceByName", notFoundName).Return(models.DeviceService{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device service doesn't exist in the database", nil))
 	dbClientMock.On("DeleteDeviceServiceByName", notFoundName).Return(errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "device service doesn't exist in the database", nil))
 	dbClientMock.On("DevicesByServiceName", 0, 1, deviceExists).Return([]models.Device{models.Device{}}, nil)
 	dbClientMock.On("DevicesByServiceName", 0, 1, provisionWatcherExists).Return([]models.Device{}, nil)
@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+
+	"github.com/gorilla/mux"
+)
+
+type HealthController struct {
+	dic *di.Container
+}
+
+// NewHealthController creates and initializes a HealthController
+func NewHealthController(dic *di.Container) *HealthController {
+	return &HealthController{
+		dic: dic,
+	}
+}
+
+func (hc *HealthController) AllDeviceServiceHealth(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(hc.dic.Get)
+	ctx := r.Context()
+
+	healths, err := application.AllDeviceServiceHealth(hc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	response := metadataDTOs.NewMultiDeviceServiceHealthResponse("", "", http.StatusOK, healths)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
+
+func (hc *HealthController) DeviceServiceHealthByName(w http.ResponseWriter, r *http.Request) {
+	lc := container.LoggingClientFrom(hc.dic.Get)
+	ctx := r.Context()
+
+	// URL parameters
+	vars := mux.Vars(r)
+	name := vars[common.Name]
+
+	health, err := application.DeviceServiceHealthByName(name, hc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	response := metadataDTOs.NewDeviceServiceHealthResponse("", "", http.StatusOK, health)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
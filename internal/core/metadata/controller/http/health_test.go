@@ -0,0 +1,272 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/config"
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHealthCheckDB is the device service and its devices updated by the health check, along with the revisions of the
+// devices and the devices marked DOWN by the health check
+type fakeHealthCheckDB struct {
+	mutex     sync.Mutex
+	devices   map[string]models.Device
+	revisions map[string]uint64
+	downed    map[string]bool
+	// patches are applied to the devices by a user right before the health check updates them
+	patches map[string]func(*models.Device)
+}
+
+func newFakeHealthCheckDB(ds models.DeviceService, devices []models.Device, downed ...string) (*fakeHealthCheckDB, *dbMock.DBClient) {
+	f := &fakeHealthCheckDB{
+		devices:   make(map[string]models.Device),
+		revisions: make(map[string]uint64),
+		downed:    make(map[string]bool),
+		patches:   make(map[string]func(*models.Device)),
+	}
+	for _, d := range devices {
+		f.devices[d.Name] = d
+	}
+	for _, name := range downed {
+		f.downed[name] = true
+	}
+
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("AllDeviceServices", 0, -1, []string(nil)).Return([]models.DeviceService{ds}, nil)
+	dbClientMock.On("UpdateDeviceServiceLastConnected", ds.Name, mock.Anything).Return(nil)
+	dbClientMock.On("DevicesByServiceName", 0, -1, ds.Name).Return(func(int, int, string) []models.Device {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		result := make([]models.Device, 0, len(f.devices))
+		for _, d := range f.devices {
+			result = append(result, d)
+		}
+		return result
+	}, nil)
+	dbClientMock.On("DeviceAndRevisionByName", mock.Anything).Return(func(name string) models.Device {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return f.devices[name]
+	}, func(name string) uint64 {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return f.revisions[name]
+	}, nil)
+	dbClientMock.On("UpdateDeviceWithRevision", mock.Anything, mock.Anything).Return(func(d models.Device, revision uint64) errors.EdgeX {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		if patch, ok := f.patches[d.Name]; ok {
+			delete(f.patches, d.Name)
+			patched := f.devices[d.Name]
+			patch(&patched)
+			f.devices[d.Name] = patched
+			f.revisions[d.Name]++
+		}
+		if f.revisions[d.Name] != revision {
+			return errors.NewCommonEdgeX(errors.KindStatusConflict, "revision mismatch", nil)
+		}
+		f.devices[d.Name] = d
+		f.revisions[d.Name]++
+		return nil
+	})
+	dbClientMock.On("AddDevicesDownByHealthCheck", ds.Name, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		for _, name := range args.Get(1).([]string) {
+			f.downed[name] = true
+		}
+	})
+	dbClientMock.On("DevicesDownByHealthCheck", ds.Name).Return(func(string) []string {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		names := make([]string, 0, len(f.downed))
+		for name := range f.downed {
+			names = append(names, name)
+		}
+		return names
+	}, nil)
+	dbClientMock.On("DeleteDevicesDownByHealthCheck", ds.Name).Return(nil).Run(func(args mock.Arguments) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.downed = make(map[string]bool)
+	})
+	return f, dbClientMock
+}
+
+// patch applies the patch to the device by a user, right before the health check updates the device next time
+func (f *fakeHealthCheckDB) patch(name string, patch func(*models.Device)) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.patches[name] = patch
+}
+
+func (f *fakeHealthCheckDB) device(name string) models.Device {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.devices[name]
+}
+
+func (f *fakeHealthCheckDB) stateOf(name string) models.OperatingState {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.devices[name].OperatingState
+}
+
+func (f *fakeHealthCheckDB) downedCount() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.downed)
+}
+
+func startTestHealthMonitor(t *testing.T, dic *di.Container, dbClientMock *dbMock.DBClient) {
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				HealthCheck: config.HealthCheckInfo{Enabled: true, Interval: "10ms", FailureThreshold: 2, Timeout: "1s"},
+			}
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+	require.NoError(t, application.StartHealthMonitor(ctx, &wg, dic))
+}
+
+func TestDeviceServiceHealth(t *testing.T) {
+	// the device service responds to the ping until it's stopped
+	var stopped int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&stopped) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"apiVersion":"v2","timestamp":"now"}`))
+	}))
+	defer server.Close()
+
+	dic := mockDic()
+	controller := NewHealthController(dic)
+	req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiAllDeviceServiceHealthRoute, http.NoBody)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(controller.AllDeviceServiceHealth).ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Result().StatusCode, "the health should be unavailable without the health check")
+
+	ds := models.DeviceService{Name: TestDeviceServiceName, BaseAddress: server.URL, AdminState: models.Unlocked}
+	devices := []models.Device{
+		{Name: TestDeviceName, ServiceName: TestDeviceServiceName, OperatingState: models.Up},
+		{Name: "already-down", ServiceName: TestDeviceServiceName, OperatingState: models.Down},
+	}
+	db, dbClientMock := newFakeHealthCheckDB(ds, devices)
+	startTestHealthMonitor(t, dic, dbClientMock)
+
+	healthOf := func(name string) (int, metadataDTOs.DeviceServiceHealth) {
+		req, err := http.NewRequest(http.MethodGet, pkgCommon.ApiDeviceServiceHealthByNameRoute, http.NoBody)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{common.Name: name})
+		recorder := httptest.NewRecorder()
+		http.HandlerFunc(controller.DeviceServiceHealthByName).ServeHTTP(recorder, req)
+		var response metadataDTOs.DeviceServiceHealthResponse
+		if recorder.Result().StatusCode == http.StatusOK {
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		}
+		return recorder.Result().StatusCode, response.Health
+	}
+
+	assert.Eventually(t, func() bool {
+		code, health := healthOf(TestDeviceServiceName)
+		return code == http.StatusOK && health.OperatingState == models.Up && health.LastConnected > 0
+	}, time.Second, 5*time.Millisecond)
+	dbClientMock.AssertCalled(t, "UpdateDeviceServiceLastConnected", TestDeviceServiceName, mock.Anything)
+	dbClientMock.AssertNotCalled(t, "UpdateDeviceWithRevision", mock.Anything, mock.Anything)
+	dbClientMock.AssertNotCalled(t, "UpdateDeviceService", mock.Anything)
+
+	code, _ := healthOf("unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = healthOf("")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the device is updated by a user while the health check marks it DOWN
+	db.patch(TestDeviceName, func(d *models.Device) { d.Labels = []string{"patched"} })
+	atomic.StoreInt32(&stopped, 1)
+	assert.Eventually(t, func() bool {
+		_, health := healthOf(TestDeviceServiceName)
+		return health.OperatingState == models.Down && db.stateOf(TestDeviceName) == models.Down
+	}, time.Second, 5*time.Millisecond, "the device service and its devices should be marked DOWN")
+	assert.Equal(t, 1, db.downedCount(), "only the devices marked DOWN by the health check should be recorded")
+	assert.Equal(t, []string{"patched"}, db.device(TestDeviceName).Labels, "the update made in the meantime should be kept")
+	_, health := healthOf(TestDeviceServiceName)
+	assert.GreaterOrEqual(t, health.Failures, 2)
+	assert.NotEmpty(t, health.LastError)
+
+	atomic.StoreInt32(&stopped, 0)
+	assert.Eventually(t, func() bool {
+		_, health := healthOf(TestDeviceServiceName)
+		return health.OperatingState == models.Up && db.stateOf(TestDeviceName) == models.Up && db.downedCount() == 0
+	}, time.Second, 5*time.Millisecond, "the device service and its devices should be marked UP on recovery")
+	assert.Equal(t, models.OperatingState(models.Down), db.stateOf("already-down"), "the device not marked DOWN by the health check should stay DOWN")
+
+	recorder = httptest.NewRecorder()
+	http.HandlerFunc(controller.AllDeviceServiceHealth).ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	var multiResponse metadataDTOs.MultiDeviceServiceHealthResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &multiResponse))
+	require.Len(t, multiResponse.Healths, 1)
+	assert.Equal(t, TestDeviceServiceName, multiResponse.Healths[0].ServiceName)
+}
+
+func TestDeviceServiceHealth_Restart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"apiVersion":"v2","timestamp":"now"}`))
+	}))
+	defer server.Close()
+
+	// the device was marked DOWN by the health check before the restart, and the device service has recovered since
+	ds := models.DeviceService{Name: TestDeviceServiceName, BaseAddress: server.URL, AdminState: models.Unlocked}
+	devices := []models.Device{
+		{Name: TestDeviceName, ServiceName: TestDeviceServiceName, OperatingState: models.Down},
+		{Name: "already-down", ServiceName: TestDeviceServiceName, OperatingState: models.Down},
+	}
+	db, dbClientMock := newFakeHealthCheckDB(ds, devices, TestDeviceName)
+	startTestHealthMonitor(t, mockDic(), dbClientMock)
+
+	assert.Eventually(t, func() bool {
+		return db.stateOf(TestDeviceName) == models.Up && db.downedCount() == 0
+	}, time.Second, 5*time.Millisecond, "the device marked DOWN before the restart should be marked UP")
+	assert.Equal(t, models.OperatingState(models.Down), db.stateOf("already-down"))
+}
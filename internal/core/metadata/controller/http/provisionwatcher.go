@@ -6,13 +6,16 @@
 package http
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
 	metadataContainer "github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
 	"github.com/edgexfoundry/edgex-go/internal/io"
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/utils"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
@@ -21,6 +24,7 @@ import (
 	commonDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	requestDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	responseDTO "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/responses"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	"github.com/gorilla/mux"
 )
 
@@ -90,13 +94,14 @@ func (pwc *ProvisionWatcherController) ProvisionWatcherByName(w http.ResponseWri
 	vars := mux.Vars(r)
 	name := vars[common.Name]
 
-	provisionWatcher, err := application.ProvisionWatcherByName(name, pwc.dic)
+	provisionWatcher, revision, err := application.ProvisionWatcherByName(name, pwc.dic)
 	if err != nil {
 		utils.WriteErrorResponse(w, ctx, lc, err, "")
 		return
 	}
 
 	response := responseDTO.NewProvisionWatcherResponse("", "", http.StatusOK, provisionWatcher)
+	utils.WriteETagHeader(w, revision)
 	utils.WriteHttpHeader(w, ctx, http.StatusOK)
 	pkg.EncodeAndWriteResponse(response, w, lc)
 }
@@ -208,18 +213,28 @@ func (pwc *ProvisionWatcherController) PatchProvisionWatcher(w http.ResponseWrit
 		return
 	}
 
+	// the If-Match header is only allowed when a single entity is patched
+	expectedRevision, err := utils.ParseIfMatchRevision(r)
+	if err == nil && expectedRevision != nil && len(reqDTOs) != 1 {
+		err = errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%s header is not allowed when patching %d entities", pkgCommon.IfMatch, len(reqDTOs)), nil)
+	}
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
 	var updateResponses []interface{}
 	for _, dto := range reqDTOs {
 		var response interface{}
 		reqId := dto.RequestId
-		err := application.PatchProvisionWatcher(ctx, dto.ProvisionWatcher, pwc.dic)
+		err := application.PatchProvisionWatcher(ctx, dto.ProvisionWatcher, expectedRevision, pwc.dic)
 		if err != nil {
 			lc.Error(err.Error(), common.CorrelationHeader, correlationId)
 			lc.Debug(err.DebugMessages(), common.CorrelationHeader, correlationId)
 			response = commonDTO.NewBaseResponse(
 				reqId,
 				err.Message(),
-				err.Code())
+				utils.IfMatchStatusCode(err))
 		} else {
 			response = commonDTO.NewBaseResponse(
 				reqId,
@@ -232,3 +247,29 @@ func (pwc *ProvisionWatcherController) PatchProvisionWatcher(w http.ResponseWrit
 	utils.WriteHttpHeader(w, ctx, http.StatusMultiStatus)
 	pkg.EncodeAndWriteResponse(updateResponses, w, lc)
 }
+
+func (pwc *ProvisionWatcherController) MatchProvisionWatchers(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer func() { _ = r.Body.Close() }()
+	}
+
+	lc := container.LoggingClientFrom(pwc.dic.Get)
+	ctx := r.Context()
+
+	var reqDTO metadataDTOs.ProvisionWatcherMatchRequest
+	err := pwc.reader.Read(r.Body, &reqDTO)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	matches, err := application.MatchProvisionWatchers(reqDTO, pwc.dic)
+	if err != nil {
+		utils.WriteErrorResponse(w, ctx, lc, err, "")
+		return
+	}
+
+	response := metadataDTOs.NewProvisionWatcherMatchResponse(reqDTO.RequestId, "", http.StatusOK, matches)
+	utils.WriteHttpHeader(w, ctx, http.StatusOK)
+	pkg.EncodeAndWriteResponse(response, w, lc)
+}
@@ -27,7 +27,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	metadataDTOs "github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
 	"github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 )
 
 var testProvisionWatcherName = "TestProvisionWatcher"
@@ -169,6 +171,8 @@ func TestProvisionWatcherController_AddProvisionWatcher_BadRequest(t *testing.T)
 	notFoundProfile.ProvisionWatcher.ProfileName = notFountProfileName
 	dbClientMock.On("DeviceServiceNameExists", notFoundProfile.ProvisionWatcher.ServiceName).Return(true, nil)
 	dbClientMock.On("DeviceProfileNameExists", notFoundProfile.ProvisionWatcher.ProfileName).Return(false, nil)
+	invalidIdentifier := provisionWatcher
+	invalidIdentifier.ProvisionWatcher.Identifiers = map[string]string{"address": "localhost", "port": "3[0-9"}
 
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
@@ -188,6 +192,7 @@ func TestProvisionWatcherController_AddProvisionWatcher_BadRequest(t *testing.T)
 		{"Invalid - Bad name", []requests.AddProvisionWatcherRequest{noName}, http.StatusBadRequest, http.StatusBadRequest},
 		{"Invalid - not found service", []requests.AddProvisionWatcherRequest{notFoundService}, http.StatusMultiStatus, http.StatusNotFound},
 		{"Invalid - not found profile", []requests.AddProvisionWatcherRequest{notFoundProfile}, http.StatusMultiStatus, http.StatusNotFound},
+		{"Invalid - invalid identifier regular expression", []requests.AddProvisionWatcherRequest{invalidIdentifier}, http.StatusMultiStatus, http.StatusBadRequest},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -290,8 +295,8 @@ func TestProvisionWatcherController_ProvisionWatcherByName(t *testing.T) {
 
 	dic := mockDic()
 	dbClientMock := &mocks.DBClient{}
-	dbClientMock.On("ProvisionWatcherByName", provisionWatcher.Name).Return(provisionWatcher, nil)
-	dbClientMock.On("ProvisionWatcherByName", notFoundName).Return(models.ProvisionWatcher{}, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "provision watcher doesn't exist in the database", nil))
+	dbClientMock.On("ProvisionWatcherAndRevisionByName", provisionWatcher.Name).Return(provisionWatcher, TestRevision, nil)
+	dbClientMock.On("ProvisionWatcherAndRevisionByName", notFoundName).Return(models.ProvisionWatcher{}, uint64(0), errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, "provision watcher doesn't exist in the database", nil))
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
 			return dbClientMock
@@ -341,6 +346,7 @@ func TestProvisionWatcherController_ProvisionWatcherByName(t *testing.T) {
 				assert.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
 				assert.Equal(t, testCase.expectedStatusCode, int(res.StatusCode), "Response status code not as expected")
 				assert.Equal(t, testCase.provisionWatcherName, res.ProvisionWatcher.Name, "Name not as expected")
+				assert.Equal(t, `"2"`, recorder.Header().Get(pkgCommon.ETag), "ETag not as expected")
 				assert.Empty(t, res.Message, "Message should be empty when it is successful")
 			}
 		})
@@ -666,14 +672,13 @@ func TestProvisionWatcherController_PatchProvisionWatcher(t *testing.T) {
 	valid := testReq
 	dbClientMock.On("DeviceServiceNameExists", *valid.ProvisionWatcher.ServiceName).Return(true, nil)
 	dbClientMock.On("DeviceProfileNameExists", *valid.ProvisionWatcher.ProfileName).Return(true, nil)
-	dbClientMock.On("ProvisionWatcherByName", *valid.ProvisionWatcher.Name).Return(pwModels, nil)
-	dbClientMock.On("UpdateProvisionWatcher", mock.Anything).Return(nil)
+	dbClientMock.On("ProvisionWatcherAndRevisionByName", *valid.ProvisionWatcher.Name).Return(pwModels, TestRevision, nil)
+	dbClientMock.On("UpdateProvisionWatcherWithRevision", mock.Anything, TestRevision).Return(nil)
 	dbClientMock.On("DeviceServiceByName", *valid.ProvisionWatcher.ServiceName).Return(models.DeviceService{}, nil)
 	validWithNoReqID := testReq
 	validWithNoReqID.RequestId = ""
 	validWithNoId := testReq
 	validWithNoId.ProvisionWatcher.Id = nil
-	dbClientMock.On("ProvisionWatcherByName", *validWithNoId.ProvisionWatcher.Name).Return(pwModels, nil)
 	validWithNoName := testReq
 	validWithNoName.ProvisionWatcher.Name = nil
 	dbClientMock.On("ProvisionWatcherById", *validWithNoName.ProvisionWatcher.Id).Return(pwModels, nil)
@@ -706,7 +711,7 @@ func TestProvisionWatcherController_PatchProvisionWatcher(t *testing.T) {
 	invalidNotFoundName.ProvisionWatcher.Name = &notFoundName
 	invalidNotFoundName.ProvisionWatcher.Id = nil
 	notFoundNameError := errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("%s doesn't exist in the database", notFoundName), nil)
-	dbClientMock.On("ProvisionWatcherByName", *invalidNotFoundName.ProvisionWatcher.Name).Return(pwModels, notFoundNameError)
+	dbClientMock.On("ProvisionWatcherAndRevisionByName", *invalidNotFoundName.ProvisionWatcher.Name).Return(pwModels, uint64(0), notFoundNameError)
 
 	notFountServiceName := "notFoundService"
 	notFoundService := testReq
@@ -716,6 +721,8 @@ func TestProvisionWatcherController_PatchProvisionWatcher(t *testing.T) {
 	notFoundProfile := testReq
 	notFoundProfile.ProvisionWatcher.ProfileName = &notFountProfileName
 	dbClientMock.On("DeviceProfileNameExists", *notFoundProfile.ProvisionWatcher.ProfileName).Return(false, nil)
+	invalidIdentifier := testReq
+	invalidIdentifier.ProvisionWatcher.Identifiers = map[string]string{"port": "(3[0-9]"}
 
 	dic.Update(di.ServiceConstructorMap{
 		container.DBClientInterfaceName: func(get di.Get) interface{} {
@@ -742,6 +749,7 @@ func TestProvisionWatcherController_PatchProvisionWatcher(t *testing.T) {
 		{"Invalid - no id and name", []requests.UpdateProvisionWatcherRequest{invalidNoIdAndName}, http.StatusBadRequest, http.StatusBadRequest},
 		{"Invalid - not found service", []requests.UpdateProvisionWatcherRequest{notFoundService}, http.StatusMultiStatus, http.StatusNotFound},
 		{"Invalid - not found profile", []requests.UpdateProvisionWatcherRequest{notFoundProfile}, http.StatusMultiStatus, http.StatusNotFound},
+		{"Invalid - invalid identifier regular expression", []requests.UpdateProvisionWatcherRequest{invalidIdentifier}, http.StatusMultiStatus, http.StatusBadRequest},
 	}
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -788,3 +796,82 @@ func TestProvisionWatcherController_PatchProvisionWatcher(t *testing.T) {
 		})
 	}
 }
+
+func TestProvisionWatcherController_MatchProvisionWatchers(t *testing.T) {
+	dic := mockDic()
+	dbClientMock := &mocks.DBClient{}
+	pw := dtos.ToProvisionWatcherModel(buildTestAddProvisionWatcherRequest().ProvisionWatcher)
+	locked := pw
+	locked.Name = "LockedProvisionWatcher"
+	locked.AdminState = models.Locked
+	other := pw
+	other.Name = "OtherProvisionWatcher"
+	other.ServiceName = "OtherDeviceService"
+	other.Identifiers = map[string]string{"address": "^10\\."}
+	other.BlockingIdentifiers = nil
+
+	dbClientMock.On("AllProvisionWatchers", 0, -1, []string(nil)).Return([]models.ProvisionWatcher{pw, other, locked}, nil)
+	dbClientMock.On("DeviceServiceNameExists", TestDeviceServiceName).Return(true, nil)
+	dbClientMock.On("DeviceServiceNameExists", "notFoundService").Return(false, nil)
+	dbClientMock.On("ProvisionWatchersByServiceName", 0, -1, TestDeviceServiceName).Return([]models.ProvisionWatcher{pw}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+	})
+	controller := NewProvisionWatcherController(dic)
+
+	protocols := func(address string, port string) map[string]dtos.ProtocolProperties {
+		return map[string]dtos.ProtocolProperties{
+			"other": {"address": "unknown"},
+			"tcp":   {"address": address, "port": port},
+		}
+	}
+	tests := []struct {
+		name                string
+		serviceName         string
+		protocols           map[string]dtos.ProtocolProperties
+		expectedStatusCode  int
+		expectedProvisioned map[string]bool
+		expectedBlocked     map[string]bool
+	}{
+		{"Valid - provisioned", "", protocols("localhost", "300"), http.StatusOK,
+			map[string]bool{testProvisionWatcherName: true, "LockedProvisionWatcher": false, "OtherProvisionWatcher": false}, nil},
+		{"Valid - blocked", "", protocols("localhost", "398"), http.StatusOK,
+			map[string]bool{testProvisionWatcherName: false, "LockedProvisionWatcher": false, "OtherProvisionWatcher": false},
+			map[string]bool{testProvisionWatcherName: true, "LockedProvisionWatcher": true}},
+		{"Valid - by service name", TestDeviceServiceName, protocols("10.0.0.1", "300"), http.StatusOK,
+			map[string]bool{testProvisionWatcherName: false}, nil},
+		{"Invalid - no protocols", "", nil, http.StatusBadRequest, nil, nil},
+		{"Invalid - not found service", "notFoundService", protocols("localhost", "300"), http.StatusNotFound, nil, nil},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			reqDTO := metadataDTOs.ProvisionWatcherMatchRequest{
+				BaseRequest: commonDTO.NewBaseRequest(),
+				ServiceName: testCase.serviceName,
+				Protocols:   testCase.protocols,
+			}
+			jsonData, err := json.Marshal(reqDTO)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, pkgCommon.ApiProvisionWatcherMatchRoute, strings.NewReader(string(jsonData)))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			http.HandlerFunc(controller.MatchProvisionWatchers).ServeHTTP(recorder, req)
+			require.Equal(t, testCase.expectedStatusCode, recorder.Result().StatusCode, "HTTP status code not as expected")
+			if testCase.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var res metadataDTOs.ProvisionWatcherMatchResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+			require.Len(t, res.Matches, len(testCase.expectedProvisioned))
+			for _, match := range res.Matches {
+				assert.Equal(t, testCase.expectedProvisioned[match.Name], match.Provisioned, "provisioned of %s not as expected", match.Name)
+				assert.Equal(t, testCase.expectedBlocked[match.Name], match.Blocked, "blocked of %s not as expected", match.Name)
+				assert.NotEmpty(t, match.Reasons, "reasons of %s are empty", match.Name)
+			}
+		})
+	}
+}
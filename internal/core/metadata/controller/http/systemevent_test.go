@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/config"
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/container"
+	dbMock "github.com/edgexfoundry/edgex-go/internal/core/metadata/infrastructure/interfaces/mocks"
+	pkgDTOs "github.com/edgexfoundry/edgex-go/internal/pkg/dtos"
+
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	msgTypes "github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSystemEventTopicPrefix = "edgex/system-events/core-metadata"
+
+// publishRecorder is the message client recording the published messages
+type publishRecorder struct {
+	mutex    sync.Mutex
+	topics   []string
+	messages []msgTypes.MessageEnvelope
+}
+
+func (r *publishRecorder) Connect() error { return nil }
+
+func (r *publishRecorder) Publish(message msgTypes.MessageEnvelope, topic string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.topics = append(r.topics, topic)
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *publishRecorder) Subscribe(topics []msgTypes.TopicChannel, messageErrors chan error) error {
+	return nil
+}
+
+func (r *publishRecorder) Disconnect() error { return nil }
+
+func mockSystemEventDic(dbClientMock *dbMock.DBClient, recorder *publishRecorder) *di.Container {
+	dic := mockDic()
+	dic.Update(di.ServiceConstructorMap{
+		container.ConfigurationName: func(get di.Get) interface{} {
+			return &config.ConfigurationStruct{
+				Writable:     config.WritableInfo{LogLevel: "DEBUG"},
+				Service:      bootstrapConfig.ServiceInfo{MaxResultCount: 30},
+				MessageQueue: bootstrapConfig.MessageBusInfo{PublishTopicPrefix: testSystemEventTopicPrefix},
+				SystemEvents: config.SystemEventsInfo{Enabled: true},
+			}
+		},
+		container.DBClientInterfaceName: func(get di.Get) interface{} {
+			return dbClientMock
+		},
+		container.MessagingClientName: func(get di.Get) interface{} {
+			return recorder
+		},
+	})
+	return dic
+}
+
+func TestAddDeviceSystemEvent(t *testing.T) {
+	testDevice := buildTestDeviceRequest()
+	deviceModel := requests.AddDeviceReqToDeviceModels([]requests.AddDeviceRequest{testDevice})[0]
+	addedDevice := deviceModel
+	addedDevice.Id = ExampleUUID
+
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DeviceServiceNameExists", deviceModel.ServiceName).Return(true, nil)
+	dbClientMock.On("DeviceProfileNameExists", deviceModel.ProfileName).Return(true, nil)
+	dbClientMock.On("AddDevice", deviceModel).Return(addedDevice, nil)
+	dbClientMock.On("DeviceServiceByName", deviceModel.ServiceName).Return(models.DeviceService{BaseAddress: testBaseAddress}, nil)
+	recorder := &publishRecorder{}
+	controller := NewDeviceController(mockSystemEventDic(dbClientMock, recorder))
+
+	jsonData, err := json.Marshal([]requests.AddDeviceRequest{testDevice})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, common.ApiDeviceRoute, strings.NewReader(string(jsonData)))
+	require.NoError(t, err)
+	http.HandlerFunc(controller.AddDevice).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, []string{testSystemEventTopicPrefix + "/device/add/" + deviceModel.ServiceName}, recorder.topics)
+	assert.Equal(t, common.ContentTypeJSON, recorder.messages[0].ContentType)
+	var event pkgDTOs.SystemEvent
+	require.NoError(t, json.Unmarshal(recorder.messages[0].Payload, &event))
+	assert.Equal(t, pkgDTOs.DeviceSystemEventType, event.Type)
+	assert.Equal(t, pkgDTOs.SystemEventActionAdd, event.Action)
+	assert.Equal(t, common.CoreMetaDataServiceKey, event.Source)
+	assert.Equal(t, deviceModel.ServiceName, event.Owner)
+	assert.NotZero(t, event.Timestamp)
+	var device dtos.Device
+	require.NoError(t, event.DecodeDetails(&device))
+	assert.Equal(t, ExampleUUID, device.Id, "the details should be the added device")
+	assert.Equal(t, deviceModel.Name, device.Name)
+}
+
+func TestDeleteDeviceProfileSystemEvent(t *testing.T) {
+	deviceProfile := dtos.ToDeviceProfileModel(buildTestDeviceProfileRequest().Profile)
+	inUse := "inUse"
+
+	dbClientMock := &dbMock.DBClient{}
+	dbClientMock.On("DevicesByProfileName", 0, 1, deviceProfile.Name).Return([]models.Device{}, nil)
+	dbClientMock.On("ProvisionWatchersByProfileName", 0, 1, deviceProfile.Name).Return([]models.ProvisionWatcher{}, nil)
+	dbClientMock.On("DeviceProfileByName", deviceProfile.Name).Return(deviceProfile, nil)
+	dbClientMock.On("DeleteDeviceProfileByName", deviceProfile.Name).Return(nil)
+	dbClientMock.On("DevicesByProfileName", 0, 1, inUse).Return([]models.Device{{}}, nil)
+	recorder := &publishRecorder{}
+	controller := NewDeviceProfileController(mockSystemEventDic(dbClientMock, recorder))
+
+	for _, name := range []string{inUse, deviceProfile.Name} {
+		req, err := http.NewRequest(http.MethodDelete, common.ApiDeviceProfileByNameRoute, http.NoBody)
+		require.NoError(t, err)
+		req = mux.SetURLVars(req, map[string]string{common.Name: name})
+		http.HandlerFunc(controller.DeleteDeviceProfileByName).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.Equal(t, []string{testSystemEventTopicPrefix + "/deviceprofile/delete"}, recorder.topics,
+		"only the deleted device profile should be published, without the owner")
+	var event pkgDTOs.SystemEvent
+	require.NoError(t, json.Unmarshal(recorder.messages[0].Payload, &event))
+	var profile dtos.DeviceProfile
+	require.NoError(t, event.DecodeDetails(&profile))
+	assert.Equal(t, deviceProfile.Name, profile.Name)
+	assert.Len(t, profile.DeviceResources, len(deviceProfile.DeviceResources), "the details should be the whole deleted device profile")
+}
@@ -0,0 +1,104 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// MetadataArchiveVersion is the version of the archive format, which is increased when the format is changed
+// incompatibly, so that the archive of a newer format is rejected rather than imported partially
+const MetadataArchiveVersion = 1
+
+// Formats of the exported archive
+const (
+	ArchiveFormatJSON = "json"
+	ArchiveFormatYAML = "yaml"
+)
+
+// Modes of importing an entity whose name exists
+const (
+	// ImportModeOverwrite replaces the existing entity with the imported one
+	ImportModeOverwrite = "overwrite"
+	// ImportModeSkip keeps the existing entity
+	ImportModeSkip = "skip"
+	// ImportModeFail aborts the whole import without importing any entity
+	ImportModeFail = "fail"
+)
+
+// Actions taken, or to be taken by a dry-run, on an imported entity
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionSkip   = "skip"
+	ImportActionFail   = "fail"
+)
+
+// Types of the entities in the archive
+const (
+	ArchiveDeviceServiceType    = "deviceService"
+	ArchiveDeviceProfileType    = "deviceProfile"
+	ArchiveDeviceType           = "device"
+	ArchiveProvisionWatcherType = "provisionWatcher"
+)
+
+// MetadataArchive is the snapshot of all the entities of core-metadata, which are listed in dependency order
+type MetadataArchive struct {
+	common.Versionable `json:",inline"`
+	Version            int                     `json:"version"`
+	Created            int64                   `json:"created"`
+	DeviceServices     []dtos.DeviceService    `json:"deviceServices"`
+	DeviceProfiles     []dtos.DeviceProfile    `json:"deviceProfiles"`
+	Devices            []dtos.Device           `json:"devices"`
+	ProvisionWatchers  []dtos.ProvisionWatcher `json:"provisionWatchers"`
+}
+
+// ImportResult is the action on an imported entity, and why the entity fails to be imported
+type ImportResult struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+// ImportReport is the result of importing an archive in the dependency order of the entities
+type ImportReport struct {
+	Mode    string         `json:"mode"`
+	DryRun  bool           `json:"dryRun"`
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Skipped int            `json:"skipped"`
+	Failed  int            `json:"failed"`
+	Results []ImportResult `json:"results"`
+}
+
+// Add appends the result of an imported entity and counts its action
+func (r *ImportReport) Add(result ImportResult) {
+	switch result.Action {
+	case ImportActionCreate:
+		r.Created++
+	case ImportActionUpdate:
+		r.Updated++
+	case ImportActionSkip:
+		r.Skipped++
+	case ImportActionFail:
+		r.Failed++
+	}
+	r.Results = append(r.Results, result)
+}
+
+type ImportReportResponse struct {
+	common.BaseResponse `json:",inline"`
+	Report              ImportReport `json:"report"`
+}
+
+func NewImportReportResponse(requestId string, message string, statusCode int, report ImportReport) ImportReportResponse {
+	return ImportReportResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Report:       report,
+	}
+}
@@ -0,0 +1,74 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"encoding/json"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// Actions of the device service callbacks
+const (
+	CallbackAddDevice              = "AddDevice"
+	CallbackAddDevices             = "AddDevices"
+	CallbackUpdateDevice           = "UpdateDevice"
+	CallbackDeleteDevice           = "DeleteDevice"
+	CallbackUpdateDeviceProfile    = "UpdateDeviceProfile"
+	CallbackAddProvisionWatcher    = "AddProvisionWatcher"
+	CallbackUpdateProvisionWatcher = "UpdateProvisionWatcher"
+	CallbackDeleteProvisionWatcher = "DeleteProvisionWatcher"
+	CallbackUpdateDeviceService    = "UpdateDeviceService"
+)
+
+// Callback is a device service callback waiting to be delivered, or failed to be delivered after the maximum attempts
+type Callback struct {
+	Id          string `json:"id"`
+	ServiceName string `json:"serviceName"`
+	Action      string `json:"action"`
+	// Name is the name of the device, device profile, provision watcher or device service the callback is about, or
+	// the number of the devices the AddDevices callback is about
+	Name string `json:"name"`
+	// Payload is the JSON encoded DTO sent to the device service, which is empty for the delete callbacks, or the JSON
+	// encoded array of the devices not delivered yet for the AddDevices callback
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	CorrelationId string          `json:"correlationId,omitempty"`
+	Created       int64           `json:"created"`
+	Attempts      int             `json:"attempts"`
+	LastAttempt   int64           `json:"lastAttempt,omitempty"`
+	LastError     string          `json:"lastError,omitempty"`
+}
+
+// CallbackStatus is the state of the callbacks of a device service
+type CallbackStatus struct {
+	ServiceName string     `json:"serviceName"`
+	Pending     []Callback `json:"pending"`
+	Failed      []Callback `json:"failed"`
+}
+
+type CallbackStatusResponse struct {
+	common.BaseResponse `json:",inline"`
+	Status              CallbackStatus `json:"status"`
+}
+
+func NewCallbackStatusResponse(requestId string, message string, statusCode int, status CallbackStatus) CallbackStatusResponse {
+	return CallbackStatusResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Status:       status,
+	}
+}
+
+type MultiCallbackStatusResponse struct {
+	common.BaseResponse `json:",inline"`
+	Statuses            []CallbackStatus `json:"statuses"`
+}
+
+func NewMultiCallbackStatusResponse(requestId string, message string, statusCode int, statuses []CallbackStatus) MultiCallbackStatusResponse {
+	return MultiCallbackStatusResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Statuses:     statuses,
+	}
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// Formats of the uploaded device list
+const (
+	DeviceListFormatCSV  = "csv"
+	DeviceListFormatYAML = "yaml"
+)
+
+// DeviceList is the list of the devices to be added in bulk.  The protocols shared by the devices are defined once as
+// the protocol templates, whose property values refer to the variables of each device as {{variable}}.
+type DeviceList struct {
+	ProtocolTemplates map[string]map[string]dtos.ProtocolProperties `json:"protocolTemplates,omitempty"`
+	Devices           []DeviceListEntry                             `json:"devices"`
+}
+
+// DeviceListEntry is a device of the DeviceList, whose protocols are generated from the protocol template with the
+// variables, and then merged with the protocols of the device
+type DeviceListEntry struct {
+	dtos.Device      `json:",inline"`
+	ProtocolTemplate string            `json:"protocolTemplate,omitempty"`
+	Variables        map[string]string `json:"variables,omitempty"`
+	// Row is the 1-based row of the device in the uploaded file, i.e. the line of the CSV file including the header, or
+	// the position in the YAML devices list
+	Row int `json:"-"`
+}
+
+// DeviceRowError is why the device of a row fails to be added
+type DeviceRowError struct {
+	Row     int    `json:"row"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+type AddDevicesResponse struct {
+	common.BaseResponse `json:",inline"`
+	// Ids are the ids of the added devices in the order of the rows
+	Ids    []string         `json:"ids,omitempty"`
+	Errors []DeviceRowError `json:"errors,omitempty"`
+}
+
+func NewAddDevicesResponse(requestId string, message string, statusCode int, ids []string, rowErrors []DeviceRowError) AddDevicesResponse {
+	return AddDevicesResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Ids:          ids,
+		Errors:       rowErrors,
+	}
+}
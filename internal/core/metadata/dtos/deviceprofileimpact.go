@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// ValueTypeChange is the value type change of a device resource
+type ValueTypeChange struct {
+	ResourceName string `json:"resourceName"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+}
+
+// BrokenAutoEvent is the AutoEvent of a device whose source is removed from the device profile
+type BrokenAutoEvent struct {
+	DeviceName string `json:"deviceName"`
+	SourceName string `json:"sourceName"`
+}
+
+// DeviceProfileImpact is the impact of updating a device profile on the devices using it.  The update is breaking if
+// any device uses the device profile and a device resource or command is removed or a value type is changed.
+type DeviceProfileImpact struct {
+	ProfileName       string            `json:"profileName"`
+	Breaking          bool              `json:"breaking"`
+	RemovedResources  []string          `json:"removedResources,omitempty"`
+	RemovedCommands   []string          `json:"removedCommands,omitempty"`
+	ChangedValueTypes []ValueTypeChange `json:"changedValueTypes,omitempty"`
+	AffectedDevices   []string          `json:"affectedDevices,omitempty"`
+	BrokenAutoEvents  []BrokenAutoEvent `json:"brokenAutoEvents,omitempty"`
+}
+
+type DeviceProfileImpactResponse struct {
+	common.BaseResponse `json:",inline"`
+	Impact              DeviceProfileImpact `json:"impact"`
+}
+
+func NewDeviceProfileImpactResponse(requestId string, message string, statusCode int, impact DeviceProfileImpact) DeviceProfileImpactResponse {
+	return DeviceProfileImpactResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Impact:       impact,
+	}
+}
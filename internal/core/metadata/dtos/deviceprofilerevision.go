@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
+)
+
+// Kinds of the changes between two device profile revisions
+const (
+	DeviceProfileChangeAdded   = "added"
+	DeviceProfileChangeRemoved = "removed"
+	DeviceProfileChangeUpdated = "updated"
+)
+
+// DeviceProfileRevision is the device profile recorded when it was added or updated
+type DeviceProfileRevision struct {
+	Revision    uint32 `json:"revision"`
+	ProfileName string `json:"profileName"`
+	// CorrelationId is the correlation id of the request adding or updating the device profile
+	CorrelationId string             `json:"correlationId,omitempty"`
+	Created       int64              `json:"created"`
+	Profile       dtos.DeviceProfile `json:"profile"`
+}
+
+// FromDeviceProfileRevisionModelToDTO transforms the DeviceProfileRevision Model to the DeviceProfileRevision DTO
+func FromDeviceProfileRevisionModelToDTO(r pkgModels.DeviceProfileRevision) DeviceProfileRevision {
+	return DeviceProfileRevision{
+		Revision:      r.Revision,
+		ProfileName:   r.ProfileName,
+		CorrelationId: r.CorrelationId,
+		Created:       r.Created,
+		Profile:       dtos.FromDeviceProfileModelToDTO(r.Profile),
+	}
+}
+
+// DeviceProfileChange is a change of a device profile field.  The Path locates the field with the JSON field names, and
+// the elements of deviceResources and deviceCommands are located by name, e.g. deviceResources[temperature].properties.units
+type DeviceProfileChange struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// DeviceProfileDiff is the changes from one revision of a device profile to another
+type DeviceProfileDiff struct {
+	ProfileName  string                `json:"profileName"`
+	FromRevision uint32                `json:"fromRevision"`
+	ToRevision   uint32                `json:"toRevision"`
+	Changes      []DeviceProfileChange `json:"changes"`
+}
+
+type DeviceProfileRevisionResponse struct {
+	common.BaseResponse `json:",inline"`
+	Revision            DeviceProfileRevision `json:"revision"`
+}
+
+func NewDeviceProfileRevisionResponse(requestId string, message string, statusCode int, revision DeviceProfileRevision) DeviceProfileRevisionResponse {
+	return DeviceProfileRevisionResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Revision:     revision,
+	}
+}
+
+type MultiDeviceProfileRevisionsResponse struct {
+	common.BaseWithTotalCountResponse `json:",inline"`
+	Revisions                         []DeviceProfileRevision `json:"revisions"`
+}
+
+func NewMultiDeviceProfileRevisionsResponse(requestId string, message string, statusCode int, totalCount uint32, revisions []DeviceProfileRevision) MultiDeviceProfileRevisionsResponse {
+	return MultiDeviceProfileRevisionsResponse{
+		BaseWithTotalCountResponse: common.NewBaseWithTotalCountResponse(requestId, message, statusCode, totalCount),
+		Revisions:                  revisions,
+	}
+}
+
+type DeviceProfileDiffResponse struct {
+	common.BaseResponse `json:",inline"`
+	Diff                DeviceProfileDiff `json:"diff"`
+}
+
+func NewDeviceProfileDiffResponse(requestId string, message string, statusCode int, diff DeviceProfileDiff) DeviceProfileDiffResponse {
+	return DeviceProfileDiffResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Diff:         diff,
+	}
+}
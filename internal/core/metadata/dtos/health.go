@@ -0,0 +1,46 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// DeviceServiceHealth is the liveness of a device service tracked by the periodic health checks
+type DeviceServiceHealth struct {
+	ServiceName string `json:"serviceName"`
+	// OperatingState is UNKNOWN until the first successful check or until the device service fails the configured
+	// number of consecutive checks
+	OperatingState string `json:"operatingState"`
+	Failures       int    `json:"failures"`
+	LastChecked    int64  `json:"lastChecked,omitempty"`
+	LastConnected  int64  `json:"lastConnected,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+}
+
+type DeviceServiceHealthResponse struct {
+	common.BaseResponse `json:",inline"`
+	Health              DeviceServiceHealth `json:"health"`
+}
+
+func NewDeviceServiceHealthResponse(requestId string, message string, statusCode int, health DeviceServiceHealth) DeviceServiceHealthResponse {
+	return DeviceServiceHealthResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Health:       health,
+	}
+}
+
+type MultiDeviceServiceHealthResponse struct {
+	common.BaseResponse `json:",inline"`
+	Healths             []DeviceServiceHealth `json:"healths"`
+}
+
+func NewMultiDeviceServiceHealthResponse(requestId string, message string, statusCode int, healths []DeviceServiceHealth) MultiDeviceServiceHealthResponse {
+	return MultiDeviceServiceHealthResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Healths:      healths,
+	}
+}
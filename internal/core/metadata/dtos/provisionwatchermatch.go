@@ -0,0 +1,47 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+)
+
+// ProvisionWatcherMatchRequest is a candidate device discovered by a device service, which is matched against the
+// provision watchers of the device service, or of all the device services if ServiceName is empty
+type ProvisionWatcherMatchRequest struct {
+	common.BaseRequest `json:",inline"`
+	ServiceName        string                             `json:"serviceName,omitempty"`
+	Protocols          map[string]dtos.ProtocolProperties `json:"protocols" validate:"required,gt=0"`
+}
+
+// ProvisionWatcherMatch tells whether a provision watcher would provision the candidate device, and why.  The device
+// is provisioned if all the identifiers of the watcher match the properties of a protocol, none of the blocking
+// identifiers equals the properties of any protocol, and the watcher is UNLOCKED.
+type ProvisionWatcherMatch struct {
+	Name        string `json:"name"`
+	ServiceName string `json:"serviceName"`
+	ProfileName string `json:"profileName"`
+	AdminState  string `json:"adminState"`
+	Matched     bool   `json:"matched"`
+	Blocked     bool   `json:"blocked"`
+	Provisioned bool   `json:"provisioned"`
+	// MatchedProtocol is the protocol whose properties match all the identifiers
+	MatchedProtocol string   `json:"matchedProtocol,omitempty"`
+	Reasons         []string `json:"reasons,omitempty"`
+}
+
+type ProvisionWatcherMatchResponse struct {
+	common.BaseResponse `json:",inline"`
+	Matches             []ProvisionWatcherMatch `json:"matches"`
+}
+
+func NewProvisionWatcherMatchResponse(requestId string, message string, statusCode int, matches []ProvisionWatcherMatch) ProvisionWatcherMatchResponse {
+	return ProvisionWatcherMatchResponse{
+		BaseResponse: common.NewBaseResponse(requestId, message, statusCode),
+		Matches:      matches,
+	}
+}
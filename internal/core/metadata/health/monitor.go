@@ -0,0 +1,183 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+// ListFunc returns the device services to be checked
+type ListFunc func() ([]models.DeviceService, error)
+
+// CheckFunc checks whether the device service is alive
+type CheckFunc func(ctx context.Context, ds models.DeviceService) error
+
+// ChangeFunc is called when the operating state of the device service changes between UP and DOWN, or from UNKNOWN to
+// DOWN
+type ChangeFunc func(ctx context.Context, health dtos.DeviceServiceHealth, previous string)
+
+// Policy defines how the device services are checked
+type Policy struct {
+	// Interval is the wait between the checks of all the device services
+	Interval time.Duration
+	// FailureThreshold is the number of consecutive failed checks before a device service is DOWN
+	FailureThreshold int
+}
+
+// Monitor tracks the liveness of the device services by checking them periodically.  The device services are checked
+// concurrently, and the device service is UP once a check succeeds, or DOWN once FailureThreshold consecutive checks
+// fail.  The liveness is kept in memory, so every device service is UNKNOWN after the service restart unless it's
+// seeded by Seed.
+type Monitor struct {
+	lc      logger.LoggingClient
+	list    ListFunc
+	check   CheckFunc
+	changed ChangeFunc
+	policy  Policy
+	mutex   sync.Mutex
+	healths map[string]dtos.DeviceServiceHealth
+}
+
+// NewMonitor creates the Monitor, which doesn't check the device services until Start is called
+func NewMonitor(list ListFunc, check CheckFunc, changed ChangeFunc, policy Policy, lc logger.LoggingClient) *Monitor {
+	if policy.FailureThreshold < 1 {
+		policy.FailureThreshold = 1
+	}
+	return &Monitor{
+		lc:      lc,
+		list:    list,
+		check:   check,
+		changed: changed,
+		policy:  policy,
+		healths: make(map[string]dtos.DeviceServiceHealth),
+	}
+}
+
+// Seed sets the operating state of the device service known before it's checked, e.g. DOWN for the device service
+// whose devices were marked DOWN before the service restart, so that the ChangeFunc is called once it recovers.  A
+// device service seeded DOWN stays DOWN until a check succeeds.
+func (m *Monitor) Seed(name string, operatingState string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	health := dtos.DeviceServiceHealth{ServiceName: name, OperatingState: operatingState}
+	if operatingState == models.Down {
+		health.Failures = m.policy.FailureThreshold
+	}
+	m.healths[name] = health
+}
+
+// Start checks the device services every Interval until ctx is done
+func (m *Monitor) Start(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(m.policy.Interval)
+		defer ticker.Stop()
+		for {
+			m.CheckAll(ctx)
+			select {
+			case <-ctx.Done():
+				m.lc.Info("Exiting the device service health monitor")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CheckAll checks all the device services once, and forgets the device services which no longer exist
+func (m *Monitor) CheckAll(ctx context.Context) {
+	services, err := m.list()
+	if err != nil {
+		m.lc.Errorf("failed to list the device services for the health check, %v", err)
+		return
+	}
+
+	m.mutex.Lock()
+	names := make(map[string]bool, len(services))
+	for _, ds := range services {
+		names[ds.Name] = true
+	}
+	for name := range m.healths {
+		if !names[name] {
+			delete(m.healths, name)
+		}
+	}
+	m.mutex.Unlock()
+
+	var checks sync.WaitGroup
+	for _, ds := range services {
+		checks.Add(1)
+		go func(ds models.DeviceService) {
+			defer checks.Done()
+			m.record(ctx, ds.Name, m.check(ctx, ds))
+		}(ds)
+	}
+	checks.Wait()
+}
+
+// record records the result of the check, and calls the ChangeFunc if the operating state changes
+func (m *Monitor) record(ctx context.Context, name string, checkErr error) {
+	m.mutex.Lock()
+	health, ok := m.healths[name]
+	if !ok {
+		health = dtos.DeviceServiceHealth{ServiceName: name, OperatingState: models.Unknown}
+	}
+	previous := health.OperatingState
+	health.LastChecked = time.Now().UnixNano()
+	if checkErr == nil {
+		health.Failures = 0
+		health.LastError = ""
+		health.LastConnected = health.LastChecked
+		health.OperatingState = models.Up
+	} else {
+		health.Failures++
+		health.LastError = checkErr.Error()
+		if health.Failures >= m.policy.FailureThreshold {
+			health.OperatingState = models.Down
+		}
+	}
+	m.healths[name] = health
+	m.mutex.Unlock()
+
+	if health.OperatingState == previous || (previous == models.Unknown && health.OperatingState == models.Up) {
+		return
+	}
+	m.lc.Infof("Device service %s operating state changed from %s to %s", name, previous, health.OperatingState)
+	if m.changed != nil {
+		m.changed(ctx, health, previous)
+	}
+}
+
+// Health returns the liveness of the device service, which is false if the device service isn't checked yet
+func (m *Monitor) Health(name string) (dtos.DeviceServiceHealth, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	health, ok := m.healths[name]
+	return health, ok
+}
+
+// AllHealth returns the liveness of all the checked device services sorted by name
+func (m *Monitor) AllHealth() []dtos.DeviceServiceHealth {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	healths := make([]dtos.DeviceServiceHealth, 0, len(m.healths))
+	for _, health := range m.healths {
+		healths = append(healths, health)
+	}
+	sort.Slice(healths, func(i, j int) bool {
+		return healths[i].ServiceName < healths[j].ServiceName
+	})
+	return healths
+}
@@ -0,0 +1,192 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/dtos"
+)
+
+const (
+	testServiceName  = "test-service"
+	otherServiceName = "other-service"
+)
+
+// fakeServices checks the listed device services, and records the changes of the operating state
+type fakeServices struct {
+	mutex    sync.Mutex
+	services []models.DeviceService
+	down     map[string]bool
+	changes  []string
+}
+
+func newFakeServices(names ...string) *fakeServices {
+	f := &fakeServices{down: make(map[string]bool)}
+	for _, name := range names {
+		f.services = append(f.services, models.DeviceService{Name: name})
+	}
+	return f
+}
+
+func (f *fakeServices) list() ([]models.DeviceService, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]models.DeviceService{}, f.services...), nil
+}
+
+func (f *fakeServices) check(_ context.Context, ds models.DeviceService) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.down[ds.Name] {
+		return fmt.Errorf("device service %s unavailable", ds.Name)
+	}
+	return nil
+}
+
+func (f *fakeServices) changed(_ context.Context, health dtos.DeviceServiceHealth, previous string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.changes = append(f.changes, fmt.Sprintf("%s:%s->%s", health.ServiceName, previous, health.OperatingState))
+}
+
+func (f *fakeServices) setDown(name string, down bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.down[name] = down
+}
+
+func (f *fakeServices) result() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]string{}, f.changes...)
+}
+
+func newTestMonitor(f *fakeServices) *Monitor {
+	return NewMonitor(f.list, f.check, f.changed, Policy{Interval: time.Hour, FailureThreshold: 3}, logger.NewMockClient())
+}
+
+func TestMonitor_DownAfterThreshold(t *testing.T) {
+	f := newFakeServices(testServiceName, otherServiceName)
+	m := newTestMonitor(f)
+	ctx := context.Background()
+
+	m.CheckAll(ctx)
+	health, ok := m.Health(testServiceName)
+	require.True(t, ok)
+	assert.Equal(t, models.Up, health.OperatingState)
+	assert.NotZero(t, health.LastConnected)
+	assert.Empty(t, f.result(), "UNKNOWN to UP is not a change")
+
+	f.setDown(testServiceName, true)
+	m.CheckAll(ctx)
+	m.CheckAll(ctx)
+	health, _ = m.Health(testServiceName)
+	assert.Equal(t, models.Up, health.OperatingState, "the device service is UP before reaching the threshold")
+	assert.Equal(t, 2, health.Failures)
+	assert.Empty(t, f.result())
+
+	m.CheckAll(ctx)
+	m.CheckAll(ctx)
+	health, _ = m.Health(testServiceName)
+	assert.Equal(t, models.Down, health.OperatingState)
+	assert.Equal(t, 4, health.Failures)
+	assert.NotEmpty(t, health.LastError)
+	assert.Equal(t, []string{testServiceName + ":UP->DOWN"}, f.result(), "the change is reported only once")
+
+	other, _ := m.Health(otherServiceName)
+	assert.Equal(t, models.Up, other.OperatingState)
+
+	f.setDown(testServiceName, false)
+	m.CheckAll(ctx)
+	health, _ = m.Health(testServiceName)
+	assert.Equal(t, models.Up, health.OperatingState)
+	assert.Zero(t, health.Failures)
+	assert.Empty(t, health.LastError)
+	assert.Equal(t, []string{testServiceName + ":UP->DOWN", testServiceName + ":DOWN->UP"}, f.result())
+}
+
+func TestMonitor_UnknownToDown(t *testing.T) {
+	f := newFakeServices(testServiceName)
+	f.setDown(testServiceName, true)
+	m := newTestMonitor(f)
+
+	for i := 0; i < 3; i++ {
+		health, ok := m.Health(testServiceName)
+		if i > 0 {
+			require.True(t, ok)
+			assert.Equal(t, models.Unknown, health.OperatingState)
+		}
+		m.CheckAll(context.Background())
+	}
+	health, _ := m.Health(testServiceName)
+	assert.Equal(t, models.Down, health.OperatingState)
+	assert.Equal(t, []string{testServiceName + ":UNKNOWN->DOWN"}, f.result())
+}
+
+func TestMonitor_Seed(t *testing.T) {
+	f := newFakeServices(testServiceName, otherServiceName)
+	f.setDown(otherServiceName, true)
+	m := newTestMonitor(f)
+	// the device services whose devices were marked DOWN before the restart
+	m.Seed(testServiceName, models.Down)
+	m.Seed(otherServiceName, models.Down)
+
+	m.CheckAll(context.Background())
+	health, _ := m.Health(testServiceName)
+	assert.Equal(t, models.Up, health.OperatingState)
+	other, _ := m.Health(otherServiceName)
+	assert.Equal(t, models.Down, other.OperatingState, "the seeded DOWN device service stays DOWN while the checks fail")
+	assert.Equal(t, []string{testServiceName + ":DOWN->UP"}, f.result(), "the recovery of the seeded device service is a change")
+}
+
+func TestMonitor_AllHealth(t *testing.T) {
+	f := newFakeServices(testServiceName, otherServiceName)
+	m := newTestMonitor(f)
+	m.CheckAll(context.Background())
+
+	healths := m.AllHealth()
+	require.Len(t, healths, 2)
+	assert.Equal(t, otherServiceName, healths[0].ServiceName, "the healths are sorted by name")
+	assert.Equal(t, testServiceName, healths[1].ServiceName)
+
+	// the deleted device service is forgotten
+	f.mutex.Lock()
+	f.services = f.services[:1]
+	f.mutex.Unlock()
+	m.CheckAll(context.Background())
+	healths = m.AllHealth()
+	require.Len(t, healths, 1)
+	assert.Equal(t, testServiceName, healths[0].ServiceName)
+	_, ok := m.Health(otherServiceName)
+	assert.False(t, ok)
+}
+
+func TestMonitor_Start(t *testing.T) {
+	f := newFakeServices(testServiceName)
+	m := NewMonitor(f.list, f.check, f.changed, Policy{Interval: time.Millisecond, FailureThreshold: 1}, logger.NewMockClient())
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	m.Start(ctx, &wg)
+
+	f.setDown(testServiceName, true)
+	assert.Eventually(t, func() bool {
+		health, _ := m.Health(testServiceName)
+		return health.OperatingState == models.Down
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
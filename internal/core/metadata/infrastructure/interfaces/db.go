@@ -6,6 +6,8 @@
 package interfaces
 
 import (
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	model "github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 )
@@ -13,8 +15,10 @@ import (
 type DBClient interface {
 	CloseSession()
 
-	AddDeviceProfile(e model.DeviceProfile) (model.DeviceProfile, errors.EdgeX)
-	UpdateDeviceProfile(e model.DeviceProfile) errors.EdgeX
+	AddDeviceProfile(e model.DeviceProfile, correlationId string) (model.DeviceProfile, errors.EdgeX)
+	UpdateDeviceProfile(e model.DeviceProfile, correlationId string) errors.EdgeX
+	UpdateDeviceProfileWithRevision(e model.DeviceProfile, revision uint32, correlationId string) errors.EdgeX
+	DeviceProfileAndRevisionByName(name string) (model.DeviceProfile, uint32, errors.EdgeX)
 	DeviceProfileByName(name string) (model.DeviceProfile, errors.EdgeX)
 	DeleteDeviceProfileById(id string) errors.EdgeX
 	DeleteDeviceProfileByName(name string) errors.EdgeX
@@ -26,6 +30,9 @@ type DBClient interface {
 	DeviceProfileCountByLabels(labels []string) (uint32, errors.EdgeX)
 	DeviceProfileCountByManufacturer(manufacturer string) (uint32, errors.EdgeX)
 	DeviceProfileCountByModel(model string) (uint32, errors.EdgeX)
+	DeviceProfileRevision(name string, revision uint32) (pkgModels.DeviceProfileRevision, errors.EdgeX)
+	DeviceProfileRevisions(offset int, limit int, name string) ([]pkgModels.DeviceProfileRevision, errors.EdgeX)
+	DeviceProfileRevisionCount(name string) (uint32, errors.EdgeX)
 
 	AddDeviceService(ds model.DeviceService) (model.DeviceService, errors.EdgeX)
 	DeviceServiceById(id string) (model.DeviceService, errors.EdgeX)
@@ -35,6 +42,12 @@ type DBClient interface {
 	DeviceServiceNameExists(name string) (bool, errors.EdgeX)
 	AllDeviceServices(offset int, limit int, labels []string) ([]model.DeviceService, errors.EdgeX)
 	UpdateDeviceService(ds model.DeviceService) errors.EdgeX
+	UpdateDeviceServiceWithRevision(ds model.DeviceService, revision uint64) errors.EdgeX
+	DeviceServiceAndRevisionByName(name string) (model.DeviceService, uint64, errors.EdgeX)
+	UpdateDeviceServiceLastConnected(name string, lastConnected int64) errors.EdgeX
+	AddDevicesDownByHealthCheck(serviceName string, deviceNames []string) errors.EdgeX
+	DevicesDownByHealthCheck(serviceName string) ([]string, errors.EdgeX)
+	DeleteDevicesDownByHealthCheck(serviceName string) errors.EdgeX
 	DeviceServiceCountByLabels(labels []string) (uint32, errors.EdgeX)
 
 	AddDevice(d model.Device) (model.Device, errors.EdgeX)
@@ -47,7 +60,10 @@ type DBClient interface {
 	DeviceByName(name string) (model.Device, errors.EdgeX)
 	AllDevices(offset int, limit int, labels []string) ([]model.Device, errors.EdgeX)
 	DevicesByProfileName(offset int, limit int, profileName string) ([]model.Device, errors.EdgeX)
+	SearchDevices(offset int, limit int, filter pkgModels.DeviceSearchFilter) ([]model.Device, uint32, errors.EdgeX)
 	UpdateDevice(d model.Device) errors.EdgeX
+	UpdateDeviceWithRevision(d model.Device, revision uint64) errors.EdgeX
+	DeviceAndRevisionByName(name string) (model.Device, uint64, errors.EdgeX)
 	DeviceCountByLabels(labels []string) (uint32, errors.EdgeX)
 	DeviceCountByProfileName(profileName string) (uint32, errors.EdgeX)
 	DeviceCountByServiceName(serviceName string) (uint32, errors.EdgeX)
@@ -60,6 +76,8 @@ type DBClient interface {
 	AllProvisionWatchers(offset int, limit int, labels []string) ([]model.ProvisionWatcher, errors.EdgeX)
 	DeleteProvisionWatcherByName(name string) errors.EdgeX
 	UpdateProvisionWatcher(pw model.ProvisionWatcher) errors.EdgeX
+	UpdateProvisionWatcherWithRevision(pw model.ProvisionWatcher, revision uint64) errors.EdgeX
+	ProvisionWatcherAndRevisionByName(name string) (model.ProvisionWatcher, uint64, errors.EdgeX)
 	ProvisionWatcherCountByLabels(labels []string) (uint32, errors.EdgeX)
 	ProvisionWatcherCountByServiceName(name string) (uint32, errors.EdgeX)
 	ProvisionWatcherCountByProfileName(name string) (uint32, errors.EdgeX)
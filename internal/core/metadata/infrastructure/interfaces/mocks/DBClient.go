@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	pkgmodels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
 )
 
 // DBClient is an autogenerated mock type for the DBClient type
@@ -38,20 +40,20 @@ func (_m *DBClient) AddDevice(d models.Device) (models.Device, errors.EdgeX) {
 	return r0, r1
 }
 
-// AddDeviceProfile provides a mock function with given fields: e
-func (_m *DBClient) AddDeviceProfile(e models.DeviceProfile) (models.DeviceProfile, errors.EdgeX) {
-	ret := _m.Called(e)
+// AddDeviceProfile provides a mock function with given fields: e, correlationId
+func (_m *DBClient) AddDeviceProfile(e models.DeviceProfile, correlationId string) (models.DeviceProfile, errors.EdgeX) {
+	ret := _m.Called(e, correlationId)
 
 	var r0 models.DeviceProfile
-	if rf, ok := ret.Get(0).(func(models.DeviceProfile) models.DeviceProfile); ok {
-		r0 = rf(e)
+	if rf, ok := ret.Get(0).(func(models.DeviceProfile, string) models.DeviceProfile); ok {
+		r0 = rf(e, correlationId)
 	} else {
 		r0 = ret.Get(0).(models.DeviceProfile)
 	}
 
 	var r1 errors.EdgeX
-	if rf, ok := ret.Get(1).(func(models.DeviceProfile) errors.EdgeX); ok {
-		r1 = rf(e)
+	if rf, ok := ret.Get(1).(func(models.DeviceProfile, string) errors.EdgeX); ok {
+		r1 = rf(e, correlationId)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(errors.EdgeX)
@@ -84,6 +86,22 @@ func (_m *DBClient) AddDeviceService(ds models.DeviceService) (models.DeviceServ
 	return r0, r1
 }
 
+// AddDevicesDownByHealthCheck provides a mock function with given fields: serviceName, deviceNames
+func (_m *DBClient) AddDevicesDownByHealthCheck(serviceName string, deviceNames []string) errors.EdgeX {
+	ret := _m.Called(serviceName, deviceNames)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(string, []string) errors.EdgeX); ok {
+		r0 = rf(serviceName, deviceNames)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
+
 // AddProvisionWatcher provides a mock function with given fields: pw
 func (_m *DBClient) AddProvisionWatcher(pw models.ProvisionWatcher) (models.ProvisionWatcher, errors.EdgeX) {
 	ret := _m.Called(pw)
@@ -308,6 +326,22 @@ func (_m *DBClient) DeleteDeviceServiceByName(name string) errors.EdgeX {
 	return r0
 }
 
+// DeleteDevicesDownByHealthCheck provides a mock function with given fields: serviceName
+func (_m *DBClient) DeleteDevicesDownByHealthCheck(serviceName string) errors.EdgeX {
+	ret := _m.Called(serviceName)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(string) errors.EdgeX); ok {
+		r0 = rf(serviceName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
+
 // DeleteProvisionWatcherByName provides a mock function with given fields: name
 func (_m *DBClient) DeleteProvisionWatcherByName(name string) errors.EdgeX {
 	ret := _m.Called(name)
@@ -324,6 +358,36 @@ func (_m *DBClient) DeleteProvisionWatcherByName(name string) errors.EdgeX {
 	return r0
 }
 
+// DeviceAndRevisionByName provides a mock function with given fields: name
+func (_m *DBClient) DeviceAndRevisionByName(name string) (models.Device, uint64, errors.EdgeX) {
+	ret := _m.Called(name)
+
+	var r0 models.Device
+	if rf, ok := ret.Get(0).(func(string) models.Device); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(models.Device)
+	}
+
+	var r1 uint64
+	if rf, ok := ret.Get(1).(func(string) uint64); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	var r2 errors.EdgeX
+	if rf, ok := ret.Get(2).(func(string) errors.EdgeX); ok {
+		r2 = rf(name)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1, r2
+}
+
 // DeviceById provides a mock function with given fields: id
 func (_m *DBClient) DeviceById(id string) (models.Device, errors.EdgeX) {
 	ret := _m.Called(id)
@@ -485,6 +549,36 @@ func (_m *DBClient) DeviceNameExists(id string) (bool, errors.EdgeX) {
 	return r0, r1
 }
 
+// DeviceProfileAndRevisionByName provides a mock function with given fields: name
+func (_m *DBClient) DeviceProfileAndRevisionByName(name string) (models.DeviceProfile, uint32, errors.EdgeX) {
+	ret := _m.Called(name)
+
+	var r0 models.DeviceProfile
+	if rf, ok := ret.Get(0).(func(string) models.DeviceProfile); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(models.DeviceProfile)
+	}
+
+	var r1 uint32
+	if rf, ok := ret.Get(1).(func(string) uint32); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Get(1).(uint32)
+	}
+
+	var r2 errors.EdgeX
+	if rf, ok := ret.Get(2).(func(string) errors.EdgeX); ok {
+		r2 = rf(name)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1, r2
+}
+
 // DeviceProfileByName provides a mock function with given fields: name
 func (_m *DBClient) DeviceProfileByName(name string) (models.DeviceProfile, errors.EdgeX) {
 	ret := _m.Called(name)
@@ -600,6 +694,77 @@ func (_m *DBClient) DeviceProfileNameExists(name string) (bool, errors.EdgeX) {
 	return r0, r1
 }
 
+// DeviceProfileRevision provides a mock function with given fields: name, revision
+func (_m *DBClient) DeviceProfileRevision(name string, revision uint32) (pkgmodels.DeviceProfileRevision, errors.EdgeX) {
+	ret := _m.Called(name, revision)
+
+	var r0 pkgmodels.DeviceProfileRevision
+	if rf, ok := ret.Get(0).(func(string, uint32) pkgmodels.DeviceProfileRevision); ok {
+		r0 = rf(name, revision)
+	} else {
+		r0 = ret.Get(0).(pkgmodels.DeviceProfileRevision)
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(string, uint32) errors.EdgeX); ok {
+		r1 = rf(name, revision)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeviceProfileRevisionCount provides a mock function with given fields: name
+func (_m *DBClient) DeviceProfileRevisionCount(name string) (uint32, errors.EdgeX) {
+	ret := _m.Called(name)
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func(string) uint32); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(string) errors.EdgeX); ok {
+		r1 = rf(name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
+// DeviceProfileRevisions provides a mock function with given fields: offset, limit, name
+func (_m *DBClient) DeviceProfileRevisions(offset int, limit int, name string) ([]pkgmodels.DeviceProfileRevision, errors.EdgeX) {
+	ret := _m.Called(offset, limit, name)
+
+	var r0 []pkgmodels.DeviceProfileRevision
+	if rf, ok := ret.Get(0).(func(int, int, string) []pkgmodels.DeviceProfileRevision); ok {
+		r0 = rf(offset, limit, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pkgmodels.DeviceProfileRevision)
+		}
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(int, int, string) errors.EdgeX); ok {
+		r1 = rf(offset, limit, name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
 // DeviceProfilesByManufacturer provides a mock function with given fields: offset, limit, manufacturer
 func (_m *DBClient) DeviceProfilesByManufacturer(offset int, limit int, manufacturer string) ([]models.DeviceProfile, errors.EdgeX) {
 	ret := _m.Called(offset, limit, manufacturer)
@@ -682,6 +847,36 @@ func (_m *DBClient) DeviceProfilesByModel(offset int, limit int, model string) (
 	return r0, r1
 }
 
+// DeviceServiceAndRevisionByName provides a mock function with given fields: name
+func (_m *DBClient) DeviceServiceAndRevisionByName(name string) (models.DeviceService, uint64, errors.EdgeX) {
+	ret := _m.Called(name)
+
+	var r0 models.DeviceService
+	if rf, ok := ret.Get(0).(func(string) models.DeviceService); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(models.DeviceService)
+	}
+
+	var r1 uint64
+	if rf, ok := ret.Get(1).(func(string) uint64); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	var r2 errors.EdgeX
+	if rf, ok := ret.Get(2).(func(string) errors.EdgeX); ok {
+		r2 = rf(name)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1, r2
+}
+
 // DeviceServiceById provides a mock function with given fields: id
 func (_m *DBClient) DeviceServiceById(id string) (models.DeviceService, errors.EdgeX) {
 	ret := _m.Called(id)
@@ -824,6 +1019,61 @@ func (_m *DBClient) DevicesByServiceName(offset int, limit int, name string) ([]
 	return r0, r1
 }
 
+// DevicesDownByHealthCheck provides a mock function with given fields: serviceName
+func (_m *DBClient) DevicesDownByHealthCheck(serviceName string) ([]string, errors.EdgeX) {
+	ret := _m.Called(serviceName)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(serviceName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 errors.EdgeX
+	if rf, ok := ret.Get(1).(func(string) errors.EdgeX); ok {
+		r1 = rf(serviceName)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1
+}
+
+// ProvisionWatcherAndRevisionByName provides a mock function with given fields: name
+func (_m *DBClient) ProvisionWatcherAndRevisionByName(name string) (models.ProvisionWatcher, uint64, errors.EdgeX) {
+	ret := _m.Called(name)
+
+	var r0 models.ProvisionWatcher
+	if rf, ok := ret.Get(0).(func(string) models.ProvisionWatcher); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(models.ProvisionWatcher)
+	}
+
+	var r1 uint64
+	if rf, ok := ret.Get(1).(func(string) uint64); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	var r2 errors.EdgeX
+	if rf, ok := ret.Get(2).(func(string) errors.EdgeX); ok {
+		r2 = rf(name)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1, r2
+}
+
 // ProvisionWatcherById provides a mock function with given fields: id
 func (_m *DBClient) ProvisionWatcherById(id string) (models.ProvisionWatcher, errors.EdgeX) {
 	ret := _m.Called(id)
@@ -989,6 +1239,38 @@ func (_m *DBClient) ProvisionWatchersByServiceName(offset int, limit int, name s
 	return r0, r1
 }
 
+// SearchDevices provides a mock function with given fields: offset, limit, filter
+func (_m *DBClient) SearchDevices(offset int, limit int, filter pkgmodels.DeviceSearchFilter) ([]models.Device, uint32, errors.EdgeX) {
+	ret := _m.Called(offset, limit, filter)
+
+	var r0 []models.Device
+	if rf, ok := ret.Get(0).(func(int, int, pkgmodels.DeviceSearchFilter) []models.Device); ok {
+		r0 = rf(offset, limit, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Device)
+		}
+	}
+
+	var r1 uint32
+	if rf, ok := ret.Get(1).(func(int, int, pkgmodels.DeviceSearchFilter) uint32); ok {
+		r1 = rf(offset, limit, filter)
+	} else {
+		r1 = ret.Get(1).(uint32)
+	}
+
+	var r2 errors.EdgeX
+	if rf, ok := ret.Get(2).(func(int, int, pkgmodels.DeviceSearchFilter) errors.EdgeX); ok {
+		r2 = rf(offset, limit, filter)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(errors.EdgeX)
+		}
+	}
+
+	return r0, r1, r2
+}
+
 // UpdateDevice provides a mock function with given fields: d
 func (_m *DBClient) UpdateDevice(d models.Device) errors.EdgeX {
 	ret := _m.Called(d)
@@ -1005,13 +1287,29 @@ func (_m *DBClient) UpdateDevice(d models.Device) errors.EdgeX {
 	return r0
 }
 
-// UpdateDeviceProfile provides a mock function with given fields: e
-func (_m *DBClient) UpdateDeviceProfile(e models.DeviceProfile) errors.EdgeX {
-	ret := _m.Called(e)
+// UpdateDeviceProfile provides a mock function with given fields: e, correlationId
+func (_m *DBClient) UpdateDeviceProfile(e models.DeviceProfile, correlationId string) errors.EdgeX {
+	ret := _m.Called(e, correlationId)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(models.DeviceProfile, string) errors.EdgeX); ok {
+		r0 = rf(e, correlationId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
+
+// UpdateDeviceProfileWithRevision provides a mock function with given fields: e, revision, correlationId
+func (_m *DBClient) UpdateDeviceProfileWithRevision(e models.DeviceProfile, revision uint32, correlationId string) errors.EdgeX {
+	ret := _m.Called(e, revision, correlationId)
 
 	var r0 errors.EdgeX
-	if rf, ok := ret.Get(0).(func(models.DeviceProfile) errors.EdgeX); ok {
-		r0 = rf(e)
+	if rf, ok := ret.Get(0).(func(models.DeviceProfile, uint32, string) errors.EdgeX); ok {
+		r0 = rf(e, revision, correlationId)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(errors.EdgeX)
@@ -1037,6 +1335,54 @@ func (_m *DBClient) UpdateDeviceService(ds models.DeviceService) errors.EdgeX {
 	return r0
 }
 
+// UpdateDeviceServiceLastConnected provides a mock function with given fields: name, lastConnected
+func (_m *DBClient) UpdateDeviceServiceLastConnected(name string, lastConnected int64) errors.EdgeX {
+	ret := _m.Called(name, lastConnected)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(string, int64) errors.EdgeX); ok {
+		r0 = rf(name, lastConnected)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
+
+// UpdateDeviceServiceWithRevision provides a mock function with given fields: ds, revision
+func (_m *DBClient) UpdateDeviceServiceWithRevision(ds models.DeviceService, revision uint64) errors.EdgeX {
+	ret := _m.Called(ds, revision)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(models.DeviceService, uint64) errors.EdgeX); ok {
+		r0 = rf(ds, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
+
+// UpdateDeviceWithRevision provides a mock function with given fields: d, revision
+func (_m *DBClient) UpdateDeviceWithRevision(d models.Device, revision uint64) errors.EdgeX {
+	ret := _m.Called(d, revision)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(models.Device, uint64) errors.EdgeX); ok {
+		r0 = rf(d, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
+
 // UpdateProvisionWatcher provides a mock function with given fields: pw
 func (_m *DBClient) UpdateProvisionWatcher(pw models.ProvisionWatcher) errors.EdgeX {
 	ret := _m.Called(pw)
@@ -1052,3 +1398,19 @@ func (_m *DBClient) UpdateProvisionWatcher(pw models.ProvisionWatcher) errors.Ed
 
 	return r0
 }
+
+// UpdateProvisionWatcherWithRevision provides a mock function with given fields: pw, revision
+func (_m *DBClient) UpdateProvisionWatcherWithRevision(pw models.ProvisionWatcher, revision uint64) errors.EdgeX {
+	ret := _m.Called(pw, revision)
+
+	var r0 errors.EdgeX
+	if rf, ok := ret.Get(0).(func(models.ProvisionWatcher, uint64) errors.EdgeX); ok {
+		r0 = rf(pw, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(errors.EdgeX)
+		}
+	}
+
+	return r0
+}
@@ -19,6 +19,9 @@ import (
 
 	"sync"
 
+	"github.com/edgexfoundry/edgex-go/internal/core/metadata/application"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/gorilla/mux"
@@ -42,5 +45,14 @@ func NewBootstrap(router *mux.Router, serviceName string) *Bootstrap {
 func (b *Bootstrap) BootstrapHandler(ctx context.Context, wg *sync.WaitGroup, _ startup.Timer, dic *di.Container) bool {
 	LoadRestRoutes(b.router, dic, b.serviceName)
 
+	if err := application.StartCallbackQueue(ctx, wg, dic); err != nil {
+		container.LoggingClientFrom(dic.Get).Errorf("Failed to start the device service callback queue, %v", err)
+		return false
+	}
+	if err := application.StartHealthMonitor(ctx, wg, dic); err != nil {
+		container.LoggingClientFrom(dic.Get).Errorf("Failed to start the device service health check, %v", err)
+		return false
+	}
+
 	return true
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/handlers"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/interfaces"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
 	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/gorilla/mux"
@@ -56,6 +57,10 @@ func Main(ctx context.Context, cancel context.CancelFunc, router *mux.Router) {
 	})
 
 	httpServer := handlers.NewHttpServer(router, true)
+	// the message bus is only used to publish the system events
+	messageBusInfo := func(get di.Get) (bootstrapConfig.MessageBusInfo, bool) {
+		return configuration.MessageQueue, configuration.SystemEvents.Enabled
+	}
 
 	bootstrap.Run(
 		ctx,
@@ -69,6 +74,7 @@ func Main(ctx context.Context, cancel context.CancelFunc, router *mux.Router) {
 		true,
 		[]interfaces.BootstrapHandler{
 			pkgHandlers.NewDatabase(httpServer, configuration, container.DBClientInterfaceName).BootstrapHandler, // add v2 db client bootstrap handler
+			pkgHandlers.NewMessaging(messageBusInfo, container.MessagingClientName).BootstrapHandler,
 			NewBootstrap(router, common.CoreMetaDataServiceKey).BootstrapHandler,
 			telemetry.BootstrapHandler,
 			httpServer.BootstrapHandler,
@@ -14,6 +14,7 @@ import (
 	"github.com/gorilla/mux"
 
 	metadataController "github.com/edgexfoundry/edgex-go/internal/core/metadata/controller/http"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	commonController "github.com/edgexfoundry/edgex-go/internal/pkg/controller/http"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 )
@@ -32,12 +33,17 @@ func LoadRestRoutes(r *mux.Router, dic *di.Container, serviceName string) {
 	r.HandleFunc(common.ApiDeviceProfileRoute, dc.UpdateDeviceProfile).Methods(http.MethodPut)
 	r.HandleFunc(common.ApiDeviceProfileUploadFileRoute, dc.AddDeviceProfileByYaml).Methods(http.MethodPost)
 	r.HandleFunc(common.ApiDeviceProfileUploadFileRoute, dc.UpdateDeviceProfileByYaml).Methods(http.MethodPut)
+	r.HandleFunc(pkgCommon.ApiDeviceProfileImpactRoute, dc.DeviceProfileUpdateImpact).Methods(http.MethodPost)
 	r.HandleFunc(common.ApiDeviceProfileByNameRoute, dc.DeviceProfileByName).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceProfileByNameRoute, dc.DeleteDeviceProfileByName).Methods(http.MethodDelete)
 	r.HandleFunc(common.ApiAllDeviceProfileRoute, dc.AllDeviceProfiles).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceProfileByModelRoute, dc.DeviceProfilesByModel).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceProfileByManufacturerRoute, dc.DeviceProfilesByManufacturer).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceProfileByManufacturerAndModelRoute, dc.DeviceProfilesByManufacturerAndModel).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiAllDeviceProfileRevisionsRoute, dc.DeviceProfileRevisions).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiDeviceProfileRevisionByNumberRoute, dc.DeviceProfileRevision).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiDeviceProfileRevisionRollbackRoute, dc.RollbackDeviceProfile).Methods(http.MethodPost)
+	r.HandleFunc(pkgCommon.ApiDeviceProfileRevisionDiffRoute, dc.DiffDeviceProfileRevisions).Methods(http.MethodGet)
 
 	// Device Resource
 	dr := metadataController.NewDeviceResourceController(dic)
@@ -51,14 +57,32 @@ func LoadRestRoutes(r *mux.Router, dic *di.Container, serviceName string) {
 	r.HandleFunc(common.ApiDeviceServiceByNameRoute, ds.DeleteDeviceServiceByName).Methods(http.MethodDelete)
 	r.HandleFunc(common.ApiAllDeviceServiceRoute, ds.AllDeviceServices).Methods(http.MethodGet)
 
+	// Device Service Callback
+	cbc := metadataController.NewCallbackController(dic)
+	r.HandleFunc(pkgCommon.ApiAllCallbackRoute, cbc.AllCallbackStatus).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiCallbackByServiceNameRoute, cbc.CallbackStatusByServiceName).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiDeviceServiceResyncRoute, cbc.ResyncDeviceService).Methods(http.MethodPost)
+
+	// Device Service Health
+	hc := metadataController.NewHealthController(dic)
+	r.HandleFunc(pkgCommon.ApiAllDeviceServiceHealthRoute, hc.AllDeviceServiceHealth).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiDeviceServiceHealthByNameRoute, hc.DeviceServiceHealthByName).Methods(http.MethodGet)
+
+	// Metadata Archive
+	ac := metadataController.NewArchiveController(dic)
+	r.HandleFunc(pkgCommon.ApiMetadataExportRoute, ac.ExportMetadata).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiMetadataImportRoute, ac.ImportMetadata).Methods(http.MethodPost)
+
 	// Device
 	d := metadataController.NewDeviceController(dic)
 	r.HandleFunc(common.ApiDeviceRoute, d.AddDevice).Methods(http.MethodPost)
+	r.HandleFunc(pkgCommon.ApiDeviceUploadFileRoute, d.AddDevicesByFile).Methods(http.MethodPost)
 	r.HandleFunc(common.ApiDeviceByNameRoute, d.DeleteDeviceByName).Methods(http.MethodDelete)
 	r.HandleFunc(common.ApiDeviceByServiceNameRoute, d.DevicesByServiceName).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceNameExistsRoute, d.DeviceNameExists).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceRoute, d.PatchDevice).Methods(http.MethodPatch)
 	r.HandleFunc(common.ApiAllDeviceRoute, d.AllDevices).Methods(http.MethodGet)
+	r.HandleFunc(pkgCommon.ApiDeviceSearchRoute, d.SearchDevices).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceByNameRoute, d.DeviceByName).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiDeviceByProfileNameRoute, d.DevicesByProfileName).Methods(http.MethodGet)
 
@@ -71,6 +95,7 @@ func LoadRestRoutes(r *mux.Router, dic *di.Container, serviceName string) {
 	r.HandleFunc(common.ApiAllProvisionWatcherRoute, pwc.AllProvisionWatchers).Methods(http.MethodGet)
 	r.HandleFunc(common.ApiProvisionWatcherByNameRoute, pwc.DeleteProvisionWatcherByName).Methods(http.MethodDelete)
 	r.HandleFunc(common.ApiProvisionWatcherRoute, pwc.PatchProvisionWatcher).Methods(http.MethodPatch)
+	r.HandleFunc(pkgCommon.ApiProvisionWatcherMatchRoute, pwc.MatchProvisionWatchers).Methods(http.MethodPost)
 
 	r.Use(correlation.ManageHeader)
 	r.Use(correlation.LoggingMiddleware(container.LoggingClientFrom(dic.Get)))
@@ -0,0 +1,80 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package io
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// MemoryBudget bounds the total size of the data held by the requests processed concurrently, so that concurrent large
+// requests wait for the memory released by the others instead of exhausting the memory together
+type MemoryBudget struct {
+	mutex    sync.Mutex
+	capacity int64
+	used     int64
+	released chan struct{}
+}
+
+// NewMemoryBudget creates a MemoryBudget of capacity bytes
+func NewMemoryBudget(capacity int64) *MemoryBudget {
+	return &MemoryBudget{capacity: capacity, released: make(chan struct{})}
+}
+
+// Acquire reserves size bytes of the budget, waiting until enough bytes are released or ctx is done.  A size larger than
+// the capacity is reserved once nothing else is reserved.  The reservation is released after the returned release
+// function has been called by each of the holders, e.g. the request handler and the goroutine publishing the data.
+func (b *MemoryBudget) Acquire(ctx context.Context, size int64, holders int) (release func(), edgeXerr errors.EdgeX) {
+	if size > b.capacity {
+		// the whole budget is reserved for the request larger than the capacity
+		size = b.capacity
+	}
+	for {
+		b.mutex.Lock()
+		if b.used == 0 || b.used+size <= b.capacity {
+			b.used += size
+			b.mutex.Unlock()
+			break
+		}
+		released := b.released
+		b.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable,
+				fmt.Sprintf("timed out waiting for %d bytes of memory held by the concurrent requests", size), ctx.Err())
+		case <-released:
+		}
+	}
+
+	var mutex sync.Mutex
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if holders--; holders == 0 {
+			b.release(size)
+		}
+	}, nil
+}
+
+func (b *MemoryBudget) release(size int64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.used -= size
+	// wake up all waiting requests to try again
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// Used returns the reserved bytes of the budget
+func (b *MemoryBudget) Used() int64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.used
+}
@@ -0,0 +1,65 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package io
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	budget := NewMemoryBudget(10)
+	release, err := budget.Acquire(context.Background(), 6, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), budget.Used())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = budget.Acquire(ctx, 5, 1)
+	require.Error(t, err, "the request exceeding the budget should wait")
+	assert.Equal(t, errors.KindServiceUnavailable, errors.Kind(err))
+
+	acquired := make(chan func())
+	go func() {
+		waitingRelease, _ := budget.Acquire(context.Background(), 5, 1)
+		acquired <- waitingRelease
+	}()
+	release()
+	select {
+	case <-acquired:
+		t.Fatal("the reservation should be held until each holder releases it")
+	case <-time.After(10 * time.Millisecond):
+	}
+	release()
+	waitingRelease := <-acquired
+	assert.Equal(t, int64(5), budget.Used())
+	waitingRelease()
+
+	release, err = budget.Acquire(context.Background(), 20, 1)
+	require.NoError(t, err, "a request larger than the budget should be served alone")
+	release()
+	assert.Zero(t, budget.Used())
+}
+
+func TestMemoryBudgetUnlimitedSize(t *testing.T) {
+	budget := NewMemoryBudget(10)
+	release, err := budget.Acquire(context.Background(), math.MaxInt64, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), budget.Used(), "the whole budget should be reserved for the request of unlimited size")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = budget.Acquire(ctx, 1, 1)
+	require.Error(t, err, "the other requests should wait for the request of unlimited size")
+	release()
+	assert.Zero(t, budget.Used())
+}
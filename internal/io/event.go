@@ -6,23 +6,100 @@
 package io
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
+	"strings"
 
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/fxamacker/cbor/v2"
 )
 
-// To avoid large data causing unexpected memory exhaustion when decoding CBOR payload, defaultMaxEventSize was introduced as
-// a reasonable limit appropriate for handling CBOR payload in edgex-go.  More details could be found at
+// To avoid large data causing unexpected memory exhaustion when decoding the payload, DefaultMaxEventSize is the limit
+// applied when no maximum event size is configured.  More details could be found at
 // https://github.com/edgexfoundry/edgex-go/issues/2439
-// TODO Make MaxEventSize a service configuration setting, so that users could adjust the limit per systems requirements
-// https://github.com/edgexfoundry/edgex-go/issues/3237
-const defaultMaxEventSize = int64(25 * 1e6) // 25 MB
-
-func ReadAddEventRequestInBytes(reader io.Reader) ([]byte, errors.EdgeX) {
-	// use LimitReader with defaultMaxEventSize to avoid unexpected memory exhaustion
-	bytes, err := io.ReadAll(io.LimitReader(reader, defaultMaxEventSize))
-	if err != nil {
+const DefaultMaxEventSize = int64(25 * 1e6) // 25 MB
+
+// ValidateEventSize returns KindLimitExceeded error when the size of the encoded AddEventRequest exceeds maxSize, a
+// negative maxSize means no limit.  A negative size, e.g. the unknown Content-Length of the request, is always valid.
+func ValidateEventSize(size int64, maxSize int64) errors.EdgeX {
+	if maxSize >= 0 && size > maxSize {
+		return eventSizeExceeded(maxSize)
+	}
+	return nil
+}
+
+func eventSizeExceeded(maxSize int64) errors.EdgeX {
+	return errors.NewCommonEdgeX(errors.KindLimitExceeded, fmt.Sprintf("AddEventRequest size exceeds the limit of %d bytes", maxSize), nil)
+}
+
+// ReadAddEventRequestInBytes reads the encoded AddEventRequest of at most maxSize bytes, a negative maxSize means no
+// limit, to avoid unexpected memory exhaustion.  size is the expected size of the data, e.g. the Content-Length of the
+// request, or -1 when unknown.  A known size is validated before reading anything, and allows the buffer to be
+// allocated once instead of growing, and being copied, as the data arrives.
+func ReadAddEventRequestInBytes(reader io.Reader, size int64, maxSize int64) ([]byte, errors.EdgeX) {
+	if err := ValidateEventSize(size, maxSize); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if size > 0 {
+		// ReadFrom grows the buffer unless MinRead bytes are available to detect the end of data
+		buf.Grow(int(size) + bytes.MinRead)
+	}
+	if maxSize >= 0 {
+		// read one more byte to tell whether the data exceeds maxSize
+		reader = io.LimitReader(reader, maxSize+1)
+	}
+	if _, err := buf.ReadFrom(reader); err != nil {
 		return nil, errors.NewCommonEdgeX(errors.KindIOError, "AddEventRequest I/O reading failed", err)
 	}
-	return bytes, nil
+	if err := ValidateEventSize(int64(buf.Len()), maxSize); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeAddEventRequest decodes the JSON or CBOR encoded AddEventRequest directly from data, so that data isn't copied
+// into the buffer of a stream decoder
+func DecodeAddEventRequest(data []byte, contentType string, request *requests.AddEventRequest) errors.EdgeX {
+	switch strings.ToLower(contentType) {
+	case common.ContentTypeCBOR:
+		if err := cbor.Unmarshal(data, request); err != nil {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%T cbor decoding failed", request), err)
+		}
+	default:
+		if err := json.Unmarshal(data, request); err != nil {
+			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("%T json decoding failed", request), err)
+		}
+	}
+	return nil
+}
+
+// SplitAddEventRequests splits the JSON or CBOR encoded array of AddEventRequests into the encoded AddEventRequests, so
+// that each of them can be decoded and published individually without re-encoding
+func SplitAddEventRequests(data []byte, contentType string) ([][]byte, errors.EdgeX) {
+	var items [][]byte
+	switch strings.ToLower(contentType) {
+	case common.ContentTypeCBOR:
+		var rawItems []cbor.RawMessage
+		if err := cbor.NewDecoder(bytes.NewReader(data)).Decode(&rawItems); err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "AddEventRequest array cbor decoding failed", err)
+		}
+		for _, item := range rawItems {
+			items = append(items, item)
+		}
+	default:
+		var rawItems []json.RawMessage
+		if err := json.NewDecoder(bytes.NewReader(data)).Decode(&rawItems); err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "AddEventRequest array json decoding failed", err)
+		}
+		for _, item := range rawItems {
+			items = append(items, item)
+		}
+	}
+	return items, nil
 }
@@ -0,0 +1,121 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	dto "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/requests"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAddEventRequests(t *testing.T) {
+	requests := []dto.AddEventRequest{buildTestAddEvent(), buildTestAddEvent()}
+	jsonData, err := json.Marshal(requests)
+	require.NoError(t, err)
+	cborData, err := cbor.Marshal(requests)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+	}{
+		{"Valid - JSON array", jsonData, common.ContentTypeJSON},
+		{"Valid - CBOR array", cborData, common.ContentTypeCBOR},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			items, err := SplitAddEventRequests(testCase.data, testCase.contentType)
+			require.NoError(t, err)
+			require.Len(t, items, len(requests))
+			for i, item := range items {
+				var request dto.AddEventRequest
+				err = NewDtoReader(testCase.contentType).Read(bytes.NewReader(item), &request)
+				require.NoError(t, err)
+				assert.Equal(t, requests[i].Event.Id, request.Event.Id)
+			}
+		})
+	}
+
+	_, err = SplitAddEventRequests(jsonData, common.ContentTypeCBOR)
+	assert.Error(t, err, "the JSON array should not be split as CBOR")
+	_, err = SplitAddEventRequests([]byte(`{"apiVersion":"v2"}`), common.ContentTypeJSON)
+	assert.Error(t, err, "a single AddEventRequest should not be split")
+}
+
+func TestReadAddEventRequestInBytes(t *testing.T) {
+	data := []byte(`{"apiVersion":"v2"}`)
+	size := int64(len(data))
+	tests := []struct {
+		name         string
+		size         int64
+		maxSize      int64
+		expectedKind errors.ErrKind
+	}{
+		{"Valid - known size", size, size, ""},
+		{"Valid - unknown size", -1, size, ""},
+		{"Valid - no limit", -1, -1, ""},
+		{"Invalid - known size exceeds the limit", size, size - 1, errors.KindLimitExceeded},
+		{"Invalid - unknown size exceeds the limit", -1, size - 1, errors.KindLimitExceeded},
+		{"Invalid - zero limit", size, 0, errors.KindLimitExceeded},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := ReadAddEventRequestInBytes(bytes.NewReader(data), testCase.size, testCase.maxSize)
+			if testCase.expectedKind != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedKind, errors.Kind(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, data, result)
+		})
+	}
+}
+
+func TestDecodeAddEventRequest(t *testing.T) {
+	expected := buildTestAddEvent()
+	jsonData, err := json.Marshal(expected)
+	require.NoError(t, err)
+	cborData, err := cbor.Marshal(expected)
+	require.NoError(t, err)
+
+	var request dto.AddEventRequest
+	require.NoError(t, DecodeAddEventRequest(jsonData, common.ContentTypeJSON, &request))
+	assert.Equal(t, expected.Event.Id, request.Event.Id)
+	request = dto.AddEventRequest{}
+	require.NoError(t, DecodeAddEventRequest(cborData, common.ContentTypeCBOR, &request))
+	assert.Equal(t, expected.Event.Id, request.Event.Id)
+
+	err = DecodeAddEventRequest(cborData, common.ContentTypeJSON, &request)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+}
+
+func TestReadAddEventRequestInBytes_MemoryBounded(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 8*1024*1024)
+	size := int64(len(data))
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	result, err := ReadAddEventRequestInBytes(bytes.NewReader(data), size, size)
+	runtime.ReadMemStats(&after)
+	require.NoError(t, err)
+	require.Len(t, result, len(data))
+
+	// the data of known size is read into a single buffer, which is all the memory the budget reserves for the request
+	allocated := after.TotalAlloc - before.TotalAlloc
+	assert.Less(t, allocated, uint64(size+size/8), "the data should be read without being copied")
+}
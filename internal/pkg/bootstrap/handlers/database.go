@@ -7,12 +7,14 @@ package handlers
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
 	"sync"
 	"time"
 
 	bootstrapInterfaces "github.com/edgexfoundry/edgex-go/internal/pkg/bootstrap/interfaces"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/infrastructure/postgres"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/infrastructure/redis"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/interfaces"
 
@@ -25,6 +27,9 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 )
 
+// errPostgresUnsupported is returned for the services which don't implement their database on PostgreSQL
+var errPostgresUnsupported = stdErrors.New("postgres database type is only supported by core-data")
+
 // httpServer defines the contract used to determine whether or not the http httpServer is running.
 type httpServer interface {
 	IsRunning() bool
@@ -52,7 +57,7 @@ func (d Database) newDBClient(
 	credentials bootstrapConfig.Credentials) (interfaces.DBClient, error) {
 	databaseInfo := d.database.GetDatabaseInfo()[common.Primary]
 	switch databaseInfo.Type {
-	case "redisdb":
+	case db.RedisDB:
 		return redis.NewClient(
 			db.Configuration{
 				Host:     databaseInfo.Host,
@@ -60,6 +65,29 @@ func (d Database) newDBClient(
 				Password: credentials.Password,
 			},
 			lc)
+	case db.Postgres:
+		postgresInfo, ok := d.database.(bootstrapInterfaces.PostgresDatabase)
+		if !ok {
+			return nil, errPostgresUnsupported
+		}
+		return postgres.NewClient(
+			db.Configuration{
+				Host:         databaseInfo.Host,
+				Port:         databaseInfo.Port,
+				Timeout:      databaseInfo.Timeout,
+				DatabaseName: databaseInfo.Name,
+				Username:     credentials.Username,
+				Password:     credentials.Password,
+				SSLMode:      postgresInfo.GetPostgresSSLMode(),
+			},
+			lc)
+	case db.BoltDB:
+		return redis.NewEmbeddedClient(
+			db.Configuration{
+				Timeout:      databaseInfo.Timeout,
+				DatabaseName: databaseInfo.Name,
+			},
+			lc)
 	default:
 		return nil, db.ErrUnsupportedDatabase
 	}
@@ -75,9 +103,15 @@ func (d Database) BootstrapHandler(
 	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
 	secretProvider := bootstrapContainer.SecretProviderFrom(dic.Get)
 
-	// get database credentials.
+	// the unsupported database type is a configuration error, which retrying wouldn't fix
+	if _, ok := d.database.(bootstrapInterfaces.PostgresDatabase); !ok && d.database.GetDatabaseInfo()[common.Primary].Type == db.Postgres {
+		lc.Error(errPostgresUnsupported.Error())
+		return false
+	}
+
+	// get database credentials, the embedded database is a local file which requires none.
 	var credentials bootstrapConfig.Credentials
-	for startupTimer.HasNotElapsed() {
+	for d.database.GetDatabaseInfo()[common.Primary].Type != db.BoltDB && startupTimer.HasNotElapsed() {
 		var err error
 
 		secrets, err := secretProvider.GetSecret(d.database.GetDatabaseInfo()[common.Primary].Type)
@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/container"
+	bootstrapMessaging "github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/messaging"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/bootstrap/startup"
+	bootstrapConfig "github.com/edgexfoundry/go-mod-bootstrap/v2/config"
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/edgexfoundry/go-mod-messaging/v2/messaging"
+	"github.com/edgexfoundry/go-mod-messaging/v2/pkg/types"
+)
+
+// MessageBusInfoFunc returns the message bus configuration of the service, and whether the message bus is used
+type MessageBusInfoFunc func(get di.Get) (info bootstrapConfig.MessageBusInfo, enabled bool)
+
+// Messaging contains references to dependencies required by the message bus bootstrap implementation.
+type Messaging struct {
+	messageBusInfo      MessageBusInfoFunc
+	messagingClientName string
+}
+
+// NewMessaging is a factory method that returns an initialized Messaging receiver struct.
+func NewMessaging(messageBusInfo MessageBusInfoFunc, messagingClientName string) Messaging {
+	return Messaging{
+		messageBusInfo:      messageBusInfo,
+		messagingClientName: messagingClientName,
+	}
+}
+
+// BootstrapHandler fulfills the BootstrapHandler contract.  If the message bus is used, it creates and initializes the
+// Messaging client and adds it to the DIC
+func (m Messaging) BootstrapHandler(ctx context.Context, wg *sync.WaitGroup, startupTimer startup.Timer, dic *di.Container) bool {
+	lc := bootstrapContainer.LoggingClientFrom(dic.Get)
+	messageBusInfo, enabled := m.messageBusInfo(dic.Get)
+	if !enabled {
+		return true
+	}
+
+	messageBusInfo.AuthMode = strings.ToLower(strings.TrimSpace(messageBusInfo.AuthMode))
+	if len(messageBusInfo.AuthMode) > 0 && messageBusInfo.AuthMode != bootstrapMessaging.AuthModeNone {
+		if err := bootstrapMessaging.SetOptionsAuthData(&messageBusInfo, lc, dic); err != nil {
+			lc.Error(err.Error())
+			return false
+		}
+	}
+
+	msgClient, err := messaging.NewMessageClient(
+		types.MessageBusConfig{
+			PublishHost: types.HostInfo{
+				Host:     messageBusInfo.Host,
+				Port:     messageBusInfo.Port,
+				Protocol: messageBusInfo.Protocol,
+			},
+			SubscribeHost: types.HostInfo{
+				Host:     messageBusInfo.Host,
+				Port:     messageBusInfo.Port,
+				Protocol: messageBusInfo.Protocol,
+			},
+			Type:     messageBusInfo.Type,
+			Optional: messageBusInfo.Optional,
+		})
+
+	if err != nil {
+		lc.Errorf("Failed to create MessageClient: %v", err)
+		return false
+	}
+
+	for startupTimer.HasNotElapsed() {
+		select {
+		case <-ctx.Done():
+			return false
+		default:
+			err = msgClient.Connect()
+			if err != nil {
+				lc.Warnf("Unable to connect MessageBus: %v", err)
+				startupTimer.SleepForInterval()
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-ctx.Done()
+				if msgClient != nil {
+					_ = msgClient.Disconnect()
+				}
+				lc.Infof("Disconnected from MessageBus")
+			}()
+
+			dic.Update(di.ServiceConstructorMap{
+				m.messagingClientName: func(get di.Get) interface{} {
+					return msgClient
+				},
+			})
+
+			lc.Infof(
+				"Connected to %s Message Bus @ %s://%s:%d publishing on '%s' prefix topic with AuthMode='%s'",
+				messageBusInfo.Type,
+				messageBusInfo.Protocol,
+				messageBusInfo.Host,
+				messageBusInfo.Port,
+				messageBusInfo.PublishTopicPrefix,
+				messageBusInfo.AuthMode)
+
+			return true
+		}
+	}
+
+	lc.Error("Connecting to MessageBus time out")
+	return false
+}
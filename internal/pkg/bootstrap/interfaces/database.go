@@ -21,3 +21,10 @@ type Database interface {
 	// GetDatabaseInfo returns a database information map.
 	GetDatabaseInfo() map[string]config.Database
 }
+
+// PostgresDatabase is implemented by the configuration of the services which support PostgreSQL as their database, and
+// provides the settings of the connections specific to PostgreSQL.  PostgreSQL is rejected by the other services.
+type PostgresDatabase interface {
+	// GetPostgresSSLMode returns the sslmode of the connections, e.g. "require" or "verify-full"
+	GetPostgresSSLMode() string
+}
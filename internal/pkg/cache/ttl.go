@@ -0,0 +1,94 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value  interface{}
+	expiry time.Time
+}
+
+// TTLCache is a key-value cache whose entries expire after the time-to-live since they are set.  Expired entries are
+// removed when they are looked up.  It is safe for concurrent use.
+//
+// The generation of the cache is advanced by every eviction, i.e. Delete and Clear.  A value looked up from the source
+// of the cache is set with SetIfGeneration and the generation taken before the lookup, so a value which may have been
+// looked up before an eviction isn't cached after it.
+type TTLCache struct {
+	mutex      sync.Mutex
+	ttl        time.Duration
+	entries    map[string]entry
+	generation uint64
+}
+
+// NewTTLCache creates an empty TTLCache whose entries expire after ttl
+func NewTTLCache(ttl time.Duration) *TTLCache {
+	return &TTLCache{
+		ttl:     ttl,
+		entries: make(map[string]entry),
+	}
+}
+
+// Get returns the value of key, and false when key doesn't exist or has expired
+func (c *TTLCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiry) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set sets the value of key and restarts its time-to-live
+func (c *TTLCache) Set(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[key] = entry{value: value, expiry: time.Now().Add(c.ttl)}
+}
+
+// Generation returns the current generation of the cache
+func (c *TTLCache) Generation() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generation
+}
+
+// SetIfGeneration sets the value of key like Set unless the cache has been evicted since generation, and returns
+// whether the value is set
+func (c *TTLCache) SetIfGeneration(key string, value interface{}, generation uint64) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.generation != generation {
+		return false
+	}
+	c.entries[key] = entry{value: value, expiry: time.Now().Add(c.ttl)}
+	return true
+}
+
+// Delete removes key from the cache
+func (c *TTLCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+	c.generation++
+}
+
+// Clear removes all keys from the cache
+func (c *TTLCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]entry)
+	c.generation++
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache(t *testing.T) {
+	c := NewTTLCache(50 * time.Millisecond)
+
+	_, ok := c.Get("key")
+	assert.False(t, ok)
+
+	c.Set("key", "value")
+	c.Set("other", 1)
+	value, ok := c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	c.Delete("other")
+	_, ok = c.Get("other")
+	assert.False(t, ok)
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = c.Get("key")
+	assert.False(t, ok, "the entry should expire after the time-to-live")
+
+	c.Set("key", "value")
+	c.Clear()
+	_, ok = c.Get("key")
+	assert.False(t, ok)
+}
+
+func TestTTLCache_SetIfGeneration(t *testing.T) {
+	c := NewTTLCache(time.Minute)
+	generation := c.Generation()
+	assert.True(t, c.SetIfGeneration("key", "value", generation))
+	value, ok := c.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	generation = c.Generation()
+	c.Delete("other")
+	assert.False(t, c.SetIfGeneration("key", "stale", generation), "the value looked up before the eviction should not be set")
+	value, _ = c.Get("key")
+	assert.Equal(t, "value", value)
+	assert.True(t, c.SetIfGeneration("key", "new", c.Generation()))
+	value, _ = c.Get("key")
+	assert.Equal(t, "new", value)
+}
@@ -0,0 +1,103 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+)
+
+// Routes of the APIs which are provided by the EdgeX services in addition to the ones defined by go-mod-core-contracts
+const (
+	ApiEventBatchRoute       = common.ApiEventRoute + "/" + Batch
+	ApiEventStreamRoute      = common.ApiEventRoute + "/" + Stream
+	ApiReadingExportRoute    = common.ApiReadingRoute + "/" + Export + "/" + common.Device + "/" + common.Name + "/{" + common.Name + "}/" + common.ResourceName + "/{" + common.ResourceName + "}/" + common.Start + "/{" + common.Start + "}/" + common.End + "/{" + common.End + "}"
+	ApiReadingAggregateRoute = common.ApiReadingRoute + "/" + Aggregate + "/" + common.Device + "/" + common.Name + "/{" + common.Name + "}/" + common.ResourceName + "/{" + common.ResourceName + "}/" + common.Start + "/{" + common.Start + "}/" + common.End + "/{" + common.End + "}"
+
+	ApiCallbackRoute              = common.ApiBase + "/" + Callback
+	ApiAllCallbackRoute           = ApiCallbackRoute + "/" + common.All
+	ApiCallbackByServiceNameRoute = ApiCallbackRoute + "/" + common.Service + "/" + common.Name + "/{" + common.Name + "}"
+	ApiDeviceServiceResyncRoute   = common.ApiDeviceServiceByNameRoute + "/" + Resync
+	ApiDeviceUploadFileRoute      = common.ApiDeviceRoute + "/uploadfile"
+	ApiDeviceSearchRoute          = common.ApiDeviceRoute + "/" + Search
+	ApiDeviceCommandBatchRoute    = common.ApiDeviceRoute + "/" + common.Command + "/" + Batch
+
+	ApiCommandAuditRoute                         = common.ApiBase + "/" + Audit
+	ApiAllCommandAuditRoute                      = ApiCommandAuditRoute + "/" + common.All
+	ApiCommandAuditByDeviceNameRoute             = ApiCommandAuditRoute + "/" + common.Device + "/" + common.Name + "/{" + common.Name + "}"
+	ApiCommandAuditByTimeRangeRoute              = ApiCommandAuditRoute + "/" + common.Start + "/{" + common.Start + "}/" + common.End + "/{" + common.End + "}"
+	ApiCommandAuditByDeviceNameAndTimeRangeRoute = ApiCommandAuditByDeviceNameRoute + "/" + common.Start + "/{" + common.Start + "}/" + common.End + "/{" + common.End + "}"
+
+	ApiAllDeviceServiceHealthRoute    = common.ApiDeviceServiceRoute + "/" + Health + "/" + common.All
+	ApiDeviceServiceHealthByNameRoute = common.ApiDeviceServiceByNameRoute + "/" + Health
+
+	ApiDeviceProfileImpactRoute           = common.ApiDeviceProfileRoute + "/" + Impact
+	ApiDeviceProfileRevisionRoute         = common.ApiDeviceProfileByNameRoute + "/" + Revision
+	ApiAllDeviceProfileRevisionsRoute     = ApiDeviceProfileRevisionRoute + "/" + common.All
+	ApiDeviceProfileRevisionByNumberRoute = ApiDeviceProfileRevisionRoute + "/{" + Revision + "}"
+	ApiDeviceProfileRevisionRollbackRoute = ApiDeviceProfileRevisionByNumberRoute + "/" + Rollback
+	ApiDeviceProfileRevisionDiffRoute     = common.ApiDeviceProfileByNameRoute + "/" + Diff + "/" + From + "/{" + From + "}/" + To + "/{" + To + "}"
+
+	ApiProvisionWatcherMatchRoute = common.ApiProvisionWatcherRoute + "/" + Match
+
+	ApiMetadataExportRoute = common.ApiBase + "/" + Metadata + "/" + Export
+	ApiMetadataImportRoute = common.ApiBase + "/" + Metadata + "/" + Import
+)
+
+// Constants related to defined routes and query parameters
+const (
+	Aggregate = "aggregate"
+	Audit     = "audit"
+	Batch     = "batch"
+	Callback  = "callback"
+	Diff      = "diff"
+	DryRun    = "dryRun"
+	Export    = "export"
+	Force     = "force"
+	Format    = "format"
+	From      = "from"
+	Functions = "functions"
+	Health    = "health"
+	Impact    = "impact"
+	Import    = "import"
+	Metadata  = "metadata"
+	Match     = "match"
+	Mode      = "mode"
+	Resync    = "resync"
+	Revision  = "revision"
+	Rollback  = "rollback"
+	Search    = "search"
+	Stream    = "stream"
+	To        = "to"
+)
+
+// Query parameters of the device search
+const (
+	AdminState     = "adminState"
+	OperatingState = "operatingState"
+	Property       = "property"
+	SortBy         = "sortBy"
+	Order          = "order"
+
+	OrderAscending  = "asc"
+	OrderDescending = "desc"
+)
+
+// Constants related to the optimistic concurrency control of the entity updates
+const (
+	ETag    = "ETag"
+	IfMatch = "If-Match"
+)
+
+// Aggregation functions supported by the reading aggregation API
+const (
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+	AggregateAvg   = "avg"
+	AggregateSum   = "sum"
+	AggregateCount = "count"
+	AggregateFirst = "first"
+	AggregateLast  = "last"
+)
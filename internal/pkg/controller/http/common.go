@@ -21,6 +21,13 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 )
 
+// MetricsResponse extends the Metrics Response Content defined by go-mod-core-contracts with the service specific
+// metrics, which are keyed by metric name
+type MetricsResponse struct {
+	commonDTO.MetricsResponse `json:",inline"`
+	ServiceMetrics            map[string]interface{} `json:"serviceMetrics,omitempty"`
+}
+
 // CommonController controller for V2 REST APIs
 type CommonController struct {
 	dic         *di.Container
@@ -70,7 +77,10 @@ func (c *CommonController) Metrics(writer http.ResponseWriter, request *http.Req
 		CpuBusyAvg:     uint8(telem.CpuBusyAvg),
 	}
 
-	response := commonDTO.NewMetricsResponse(metrics, c.serviceName)
+	response := MetricsResponse{MetricsResponse: commonDTO.NewMetricsResponse(metrics, c.serviceName)}
+	if serviceMetrics := telemetry.ServiceMetricsFrom(c.dic.Get); serviceMetrics != nil {
+		response.ServiceMetrics = serviceMetrics.All()
+	}
 	c.sendResponse(writer, request, common.ApiMetricsRoute, response, http.StatusOK)
 }
 
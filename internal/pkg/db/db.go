@@ -18,6 +18,13 @@ import (
 	"errors"
 )
 
+// Supported values of the Databases.Primary.Type configuration
+const (
+	RedisDB  = "redisdb"
+	Postgres = "postgres"
+	BoltDB   = "boltdb"
+)
+
 var (
 	ErrNotFound            = errors.New("Item not found")
 	ErrUnsupportedDatabase = errors.New("Unsupported database type")
@@ -37,4 +44,6 @@ type Configuration struct {
 	Username     string
 	Password     string
 	BatchSize    int
+	// SSLMode is the sslmode of the connections to PostgreSQL
+	SSLMode string
 }
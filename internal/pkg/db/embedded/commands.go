@@ -0,0 +1,284 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	okReply     = "OK"
+	pongReply   = "PONG"
+	queuedReply = "QUEUED"
+)
+
+var errConnClosed = errors.New("embedded: connection closed")
+
+type handler func(tx *bolt.Tx, args [][]byte) (interface{}, error)
+
+type commandInfo struct {
+	readOnly bool
+	minArgs  int
+	handler  handler
+}
+
+// commands lists the Redis commands supported by the embedded store.  The Redis client relies on nothing else.
+var commands map[string]commandInfo
+
+func init() {
+	commands = map[string]commandInfo{
+		"SET":              {false, 2, set},
+		"GET":              {true, 1, get},
+		"MGET":             {true, 1, mget},
+		"EXISTS":           {true, 1, exists},
+		"DEL":              {false, 1, del},
+		"UNLINK":           {false, 1, del},
+		"HSET":             {false, 3, hset},
+		"HGET":             {true, 2, hget},
+		"HEXISTS":          {true, 2, hexists},
+		"HDEL":             {false, 2, hdel},
+		"ZADD":             {false, 3, zadd},
+		"ZREM":             {false, 2, zrem},
+		"ZCARD":            {true, 1, zcard},
+		"ZCOUNT":           {true, 3, zcount},
+		"ZRANGE":           {true, 3, zrange},
+		"ZREVRANGE":        {true, 3, zrevrange},
+		"ZRANGEBYSCORE":    {true, 3, zrangebyscore},
+		"ZREVRANGEBYSCORE": {true, 3, zrevrangebyscore},
+		"ZRANGEBYLEX":      {true, 3, zrangebylex},
+		"ZUNIONSTORE":      {false, 3, zunionstore},
+		"ZINTERSTORE":      {false, 3, zinterstore},
+	}
+}
+
+// execute runs the command within the transaction.  Invalid arguments are reported as redis.Error replies.  The keys
+// written by the command are reported to touch, which is nil for the read-only transaction.
+func execute(tx *bolt.Tx, cmd command, touch func(key []byte)) (interface{}, error) {
+	info, ok := commands[cmd.name]
+	if !ok {
+		return redis.Error(fmt.Sprintf("ERR unknown command '%s'", cmd.name)), nil
+	}
+	args := make([][]byte, len(cmd.args))
+	for i, arg := range cmd.args {
+		args[i] = argBytes(arg)
+	}
+	if len(args) < info.minArgs {
+		return wrongArgs(cmd.name), nil
+	}
+	reply, err := info.handler(tx, args)
+	if err != nil {
+		return nil, err
+	}
+	if _, failed := reply.(redis.Error); !failed && !info.readOnly && touch != nil {
+		for _, key := range writtenKeys(cmd.name, args) {
+			touch(key)
+		}
+	}
+	return reply, nil
+}
+
+// writtenKeys returns the keys written by the write command, which are all the arguments of DEL and UNLINK, and the
+// first argument of the others, e.g. the destination of ZUNIONSTORE
+func writtenKeys(name string, args [][]byte) [][]byte {
+	switch name {
+	case "DEL", "UNLINK":
+		return args
+	default:
+		return args[:1]
+	}
+}
+
+// argBytes converts a command argument the same way as the redigo connection writes it to a Redis server
+func argBytes(arg interface{}) []byte {
+	switch a := arg.(type) {
+	case []byte:
+		return a
+	case string:
+		return []byte(a)
+	case int:
+		return []byte(strconv.FormatInt(int64(a), 10))
+	case int64:
+		return []byte(strconv.FormatInt(a, 10))
+	case uint32:
+		return []byte(strconv.FormatUint(uint64(a), 10))
+	case uint64:
+		return []byte(strconv.FormatUint(a, 10))
+	case float64:
+		return []byte(strconv.FormatFloat(a, 'g', -1, 64))
+	case bool:
+		if a {
+			return []byte("1")
+		}
+		return []byte("0")
+	case nil:
+		return []byte{}
+	case redis.Argument:
+		return argBytes(a.RedisArg())
+	default:
+		return []byte(fmt.Sprint(a))
+	}
+}
+
+func wrongArgs(name string) redis.Error {
+	return redis.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+// ****************************** STRINGS ******************************
+
+func set(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	if err := deleteKey(tx, args[0]); err != nil {
+		return nil, err
+	}
+	if err := tx.Bucket(stringsBucket).Put(args[0], copyBytes(args[1])); err != nil {
+		return nil, err
+	}
+	return okReply, nil
+}
+
+// bulkReply returns the value as a bulk string reply, a missing value is a nil reply
+func bulkReply(v []byte) interface{} {
+	if v == nil {
+		return nil
+	}
+	return copyBytes(v)
+}
+
+func get(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return bulkReply(tx.Bucket(stringsBucket).Get(args[0])), nil
+}
+
+func mget(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	b := tx.Bucket(stringsBucket)
+	values := make([]interface{}, len(args))
+	for i, key := range args {
+		values[i] = bulkReply(b.Get(key))
+	}
+	return values, nil
+}
+
+// ****************************** KEYS ******************************
+
+func keyExists(tx *bolt.Tx, key []byte) bool {
+	return tx.Bucket(stringsBucket).Get(key) != nil ||
+		tx.Bucket(hashesBucket).Bucket(key) != nil ||
+		tx.Bucket(sortedSetsBucket).Bucket(key) != nil
+}
+
+// deleteKey removes the key regardless of its type
+func deleteKey(tx *bolt.Tx, key []byte) error {
+	if err := tx.Bucket(stringsBucket).Delete(key); err != nil {
+		return err
+	}
+	for _, name := range [][]byte{hashesBucket, sortedSetsBucket} {
+		err := tx.Bucket(name).DeleteBucket(key)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func exists(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	var count int64
+	for _, key := range args {
+		if keyExists(tx, key) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func del(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	var count int64
+	for _, key := range args {
+		if !keyExists(tx, key) {
+			continue
+		}
+		if err := deleteKey(tx, key); err != nil {
+			return nil, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// ****************************** HASHES ******************************
+
+func hset(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	if len(args)%2 != 1 {
+		return wrongArgs("HSET"), nil
+	}
+	b, err := tx.Bucket(hashesBucket).CreateBucketIfNotExists(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var added int64
+	for i := 1; i < len(args); i += 2 {
+		if b.Get(args[i]) == nil {
+			added++
+		}
+		if err = b.Put(args[i], copyBytes(args[i+1])); err != nil {
+			return nil, err
+		}
+	}
+	return added, nil
+}
+
+func hget(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	b := tx.Bucket(hashesBucket).Bucket(args[0])
+	if b == nil {
+		return nil, nil
+	}
+	return bulkReply(b.Get(args[1])), nil
+}
+
+func hexists(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	b := tx.Bucket(hashesBucket).Bucket(args[0])
+	if b == nil || b.Get(args[1]) == nil {
+		return int64(0), nil
+	}
+	return int64(1), nil
+}
+
+func hdel(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	hashes := tx.Bucket(hashesBucket)
+	b := hashes.Bucket(args[0])
+	if b == nil {
+		return int64(0), nil
+	}
+	var removed int64
+	for _, field := range args[1:] {
+		if b.Get(field) == nil {
+			continue
+		}
+		if err := b.Delete(field); err != nil {
+			return nil, err
+		}
+		removed++
+	}
+	// Redis removes the key once the hash becomes empty
+	if k, _ := b.Cursor().First(); k == nil {
+		if err := hashes.DeleteBucket(args[0]); err != nil {
+			return nil, err
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,275 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package embedded
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+	bolt "go.etcd.io/bbolt"
+)
+
+type command struct {
+	name string
+	args []interface{}
+}
+
+// conn implements redis.Conn.  Commands passed to Send are buffered until Flush or Do, like a pipelined connection
+// to a Redis server, and the buffered commands outside MULTI are applied within one write transaction, so that a
+// pipeline is synced to the file once rather than once per command.  Commands queued between MULTI and EXEC are
+// applied atomically within one write transaction, which is aborted if any key watched by WATCH, of any type, has been
+// written since it was watched.
+type conn struct {
+	store   *Store
+	mutex   sync.Mutex
+	pending []command
+	replies []interface{}
+	multi   bool
+	queued  []command
+	closed  bool
+	// watched and dirty are guarded by the writeMutex of the store
+	watched []string
+	dirty   bool
+}
+
+func (c *conn) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	c.pending = nil
+	c.replies = nil
+	c.multi = false
+	c.queued = nil
+	c.store.unwatch(c)
+	return nil
+}
+
+func (c *conn) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return errConnClosed
+	}
+	return nil
+}
+
+func (c *conn) Send(commandName string, args ...interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return errConnClosed
+	}
+	c.pending = append(c.pending, command{name: strings.ToUpper(commandName), args: args})
+	return nil
+}
+
+func (c *conn) Flush() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return errConnClosed
+	}
+	pending := c.pending
+	c.pending = nil
+	for len(pending) > 0 {
+		// the consecutive data commands outside MULTI are executed in a batch
+		n := 0
+		for !c.multi && n < len(pending) && isDataCommand(pending[n].name) {
+			n++
+		}
+		if n == 0 {
+			reply, err := c.process(pending[0])
+			if err != nil {
+				return err
+			}
+			c.replies = append(c.replies, reply)
+			pending = pending[1:]
+			continue
+		}
+		replies, err := c.executeBatch(pending[:n])
+		if err != nil {
+			return err
+		}
+		c.replies = append(c.replies, replies...)
+		pending = pending[n:]
+	}
+	return nil
+}
+
+func (c *conn) Receive() (interface{}, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.replies) == 0 {
+		return nil, fmt.Errorf("embedded: no pending reply to receive")
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	if e, ok := reply.(redis.Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// Do follows the semantics of the redigo connection: all the pending replies are consumed, the reply of the specified
+// command is returned, and the first error reply, if any, is returned as the error.  Do("") returns all pending
+// replies.
+func (c *conn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	replies := c.replies
+	c.replies = nil
+	if commandName == "" {
+		return replies, nil
+	}
+
+	reply, err := c.process(command{name: strings.ToUpper(commandName), args: args})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range append(replies, reply) {
+		if e, ok := r.(redis.Error); ok {
+			return reply, e
+		}
+	}
+	return reply, nil
+}
+
+// process executes the command, or queues it when a transaction is started by MULTI.  The returned error is only used
+// for storage failures; command errors are returned as redis.Error replies like a Redis server does.
+func (c *conn) process(cmd command) (interface{}, error) {
+	switch cmd.name {
+	case "MULTI":
+		if c.multi {
+			return redis.Error("ERR MULTI calls can not be nested"), nil
+		}
+		c.multi = true
+		c.queued = nil
+		return okReply, nil
+	case "EXEC":
+		if !c.multi {
+			return redis.Error("ERR EXEC without MULTI"), nil
+		}
+		queued := c.queued
+		c.multi = false
+		c.queued = nil
+		defer c.store.unwatch(c)
+		replies := make([]interface{}, len(queued))
+		aborted := false
+		err := c.store.update(func(tx *bolt.Tx, touch func(key []byte)) error {
+			if aborted = c.dirty; aborted {
+				return nil
+			}
+			for i, q := range queued {
+				reply, err := execute(tx, q, touch)
+				if err != nil {
+					return err
+				}
+				replies[i] = reply
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if aborted {
+			// like a Redis server, the aborted transaction replies with a null array
+			return nil, nil
+		}
+		return replies, nil
+	case "DISCARD":
+		if !c.multi {
+			return redis.Error("ERR DISCARD without MULTI"), nil
+		}
+		c.multi = false
+		c.queued = nil
+		c.store.unwatch(c)
+		return okReply, nil
+	case "WATCH":
+		if c.multi {
+			return redis.Error("ERR WATCH inside MULTI is not allowed"), nil
+		}
+		if len(cmd.args) == 0 {
+			return wrongArgs(cmd.name), nil
+		}
+		keys := make([][]byte, len(cmd.args))
+		for i, arg := range cmd.args {
+			keys[i] = argBytes(arg)
+		}
+		c.store.watch(c, keys)
+		return okReply, nil
+	case "UNWATCH":
+		c.store.unwatch(c)
+		return okReply, nil
+	case "PING":
+		return pongReply, nil
+	}
+
+	if _, ok := commands[cmd.name]; !ok {
+		return redis.Error(fmt.Sprintf("ERR unknown command '%s'", cmd.name)), nil
+	}
+	if c.multi {
+		c.queued = append(c.queued, cmd)
+		return queuedReply, nil
+	}
+	replies, err := c.executeBatch([]command{cmd})
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// executeBatch executes the data commands outside MULTI within one transaction, which is read-only unless any command
+// writes
+func (c *conn) executeBatch(cmds []command) ([]interface{}, error) {
+	replies := make([]interface{}, len(cmds))
+	readOnly := true
+	for _, cmd := range cmds {
+		readOnly = readOnly && commands[cmd.name].readOnly
+	}
+	var err error
+	if readOnly {
+		err = c.store.db.View(func(tx *bolt.Tx) error {
+			for i, cmd := range cmds {
+				reply, err := execute(tx, cmd, nil)
+				if err != nil {
+					return err
+				}
+				replies[i] = reply
+			}
+			return nil
+		})
+	} else {
+		err = c.store.update(func(tx *bolt.Tx, touch func(key []byte)) error {
+			for i, cmd := range cmds {
+				reply, err := execute(tx, cmd, touch)
+				if err != nil {
+					return err
+				}
+				replies[i] = reply
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// isDataCommand tells whether the command reads or writes the data, rather than controls the connection
+func isDataCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
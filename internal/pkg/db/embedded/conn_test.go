@@ -0,0 +1,325 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package embedded
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func newTestConn(t *testing.T) redis.Conn {
+	store, err := Open(filepath.Join(t.TempDir(), "test.db"), 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	conn, err := store.Dial()
+	require.NoError(t, err)
+	return conn
+}
+
+func TestEncodeScoreOrder(t *testing.T) {
+	scores := []float64{math.Inf(1), 1.5, -2, 0, 1616728256236000000, -1616728256236000000, math.Inf(-1), 1}
+	encoded := make([]string, len(scores))
+	for i, s := range scores {
+		encoded[i] = string(encodeScore(s))
+		assert.Equal(t, s, decodeScore([]byte(encoded[i])))
+	}
+	sort.Strings(encoded)
+	sort.Float64s(scores)
+	for i, s := range scores {
+		assert.Equal(t, s, decodeScore([]byte(encoded[i])))
+	}
+}
+
+func TestStringsAndHashes(t *testing.T) {
+	conn := newTestConn(t)
+
+	_, err := conn.Do("SET", "k1", []byte("v1"))
+	require.NoError(t, err)
+	v, err := redis.String(conn.Do("GET", "k1"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", v)
+	_, err = redis.Bytes(conn.Do("GET", "missing"))
+	assert.Equal(t, redis.ErrNil, err)
+
+	values, err := redis.ByteSlices(conn.Do("MGET", "k1", "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("v1"), nil}, values)
+
+	_, err = conn.Do("HSET", "h", "f", "k1")
+	require.NoError(t, err)
+	field, err := redis.String(conn.Do("HGET", "h", "f"))
+	require.NoError(t, err)
+	assert.Equal(t, "k1", field)
+	exists, err := redis.Bool(conn.Do("HEXISTS", "h", "f"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = conn.Do("HDEL", "h", "f")
+	require.NoError(t, err)
+	exists, err = redis.Bool(conn.Do("EXISTS", "h"))
+	require.NoError(t, err)
+	assert.False(t, exists, "empty hash should be removed")
+
+	deleted, err := redis.Int(conn.Do("DEL", "k1", "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+}
+
+func TestSortedSets(t *testing.T) {
+	conn := newTestConn(t)
+
+	_, err := conn.Do("ZADD", "z1", 3, "c", 1, "a", 2, "b")
+	require.NoError(t, err)
+	_, err = conn.Do("ZADD", "z2", 10, "b", 20, "d")
+	require.NoError(t, err)
+	_, err = conn.Do("ZADD", "lex", 0, "band", 0, "apple", 0, "banana", 0, "cherry")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		command  string
+		args     []interface{}
+		expected []string
+	}{
+		{"ZRANGE all", "ZRANGE", []interface{}{"z1", 0, -1}, []string{"a", "b", "c"}},
+		{"ZREVRANGE first two", "ZREVRANGE", []interface{}{"z1", 0, 1}, []string{"c", "b"}},
+		{"ZRANGE out of range", "ZRANGE", []interface{}{"z1", 5, 10}, []string{}},
+		{"ZRANGEBYSCORE", "ZRANGEBYSCORE", []interface{}{"z1", 2, "+inf"}, []string{"b", "c"}},
+		{"ZRANGEBYSCORE exclusive", "ZRANGEBYSCORE", []interface{}{"z1", "(1", 3}, []string{"b", "c"}},
+		{"ZREVRANGEBYSCORE with limit", "ZREVRANGEBYSCORE", []interface{}{"z1", 3, 1, "LIMIT", 1, 1}, []string{"b"}},
+		{"ZREVRANGEBYSCORE all", "ZREVRANGEBYSCORE", []interface{}{"z1", "+inf", "-inf", "LIMIT", 0, -1}, []string{"c", "b", "a"}},
+		{"ZRANGE missing key", "ZRANGE", []interface{}{"missing", 0, -1}, []string{}},
+		{"ZRANGEBYLEX all", "ZRANGEBYLEX", []interface{}{"lex", "-", "+"}, []string{"apple", "banana", "band", "cherry"}},
+		{"ZRANGEBYLEX prefix", "ZRANGEBYLEX", []interface{}{"lex", "[ban", "(ban\xff"}, []string{"banana", "band"}},
+		{"ZRANGEBYLEX exclusive", "ZRANGEBYLEX", []interface{}{"lex", "(apple", "[band"}, []string{"banana", "band"}},
+		{"ZRANGEBYLEX with limit", "ZRANGEBYLEX", []interface{}{"lex", "-", "+", "LIMIT", 1, 2}, []string{"banana", "band"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := redis.Strings(conn.Do(tt.command, tt.args...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	count, err := redis.Int(conn.Do("ZCOUNT", "z1", "(0", "+inf"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = redis.Int(conn.Do("ZUNIONSTORE", "union", "2", "z1", "z2"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	members, err := redis.Strings(conn.Do("ZRANGE", "union", 0, -1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "b", "d"}, members, "scores of common members should be summed")
+
+	count, err = redis.Int(conn.Do("ZINTERSTORE", "inter", "2", "z1", "z2"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = redis.Int(conn.Do("ZUNIONSTORE", "weighted", "2", "z1", "z2", "WEIGHTS", "-1", "0"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	members, err = redis.Strings(conn.Do("ZRANGE", "weighted", 0, -1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b", "a", "d"}, members, "scores should be multiplied by the weights of their sets")
+	_, err = conn.Do("ZINTERSTORE", "inter", "2", "z1", "z2", "WEIGHTS", "1")
+	assert.Error(t, err, "a weight is required for each set")
+
+	_, err = conn.Do("ZREM", "z2", "b", "d")
+	require.NoError(t, err)
+	count, err = redis.Int(conn.Do("ZCARD", "z2"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	exists, err := redis.Bool(conn.Do("EXISTS", "z2"))
+	require.NoError(t, err)
+	assert.False(t, exists, "empty sorted set should be removed")
+}
+
+func TestMultiExec(t *testing.T) {
+	conn := newTestConn(t)
+
+	_ = conn.Send("MULTI")
+	_ = conn.Send("SET", "k", "v")
+	_ = conn.Send("ZADD", "z", 1, "k")
+	replies, err := redis.Values(conn.Do("EXEC"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"OK", int64(1)}, replies)
+
+	_ = conn.Send("MULTI")
+	_ = conn.Send("DEL", "k")
+	_, err = conn.Do("DISCARD")
+	require.NoError(t, err)
+	exists, err := redis.Bool(conn.Do("EXISTS", "k"))
+	require.NoError(t, err)
+	assert.True(t, exists, "discarded transaction should not be applied")
+
+	_, err = conn.Do("UNKNOWN")
+	assert.Error(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "test.db"), 0)
+	require.NoError(t, err)
+	defer store.Close()
+	conn, _ := store.Dial()
+	other, _ := store.Dial()
+
+	_, err = conn.Do("SET", "k", "v1")
+	require.NoError(t, err)
+	_, err = conn.Do("WATCH", "k", "missing")
+	require.NoError(t, err)
+	_ = conn.Send("MULTI")
+	_ = conn.Send("SET", "k", "v2")
+	replies, err := redis.Values(conn.Do("EXEC"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"OK"}, replies, "the transaction is applied when the watched keys are unchanged")
+
+	_, err = conn.Do("WATCH", "k")
+	require.NoError(t, err)
+	_, err = other.Do("SET", "k", "v3")
+	require.NoError(t, err)
+	_ = conn.Send("MULTI")
+	_ = conn.Send("SET", "k", "v4")
+	reply, err := conn.Do("EXEC")
+	require.NoError(t, err)
+	assert.Nil(t, reply, "the transaction is aborted when the watched key is changed")
+	v, err := redis.String(conn.Do("GET", "k"))
+	require.NoError(t, err)
+	assert.Equal(t, "v3", v)
+
+	_, err = conn.Do("WATCH", "missing")
+	require.NoError(t, err)
+	_, err = other.Do("SET", "missing", "v")
+	require.NoError(t, err)
+	_, err = conn.Do("UNWATCH")
+	require.NoError(t, err)
+	_ = conn.Send("MULTI")
+	_ = conn.Send("DEL", "missing")
+	_, err = redis.Values(conn.Do("EXEC"))
+	require.NoError(t, err, "the keys are no longer watched after UNWATCH")
+
+	_ = conn.Send("MULTI")
+	_, err = conn.Do("WATCH", "k")
+	assert.Error(t, err)
+	_, _ = conn.Do("DISCARD")
+}
+
+func TestWatchAllKeyTypes(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "test.db"), 0)
+	require.NoError(t, err)
+	defer store.Close()
+	conn, _ := store.Dial()
+	other, _ := store.Dial()
+
+	_, err = conn.Do("HSET", "h", "f", "v1")
+	require.NoError(t, err)
+	_, err = conn.Do("ZADD", "z", 1, "m")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     string
+		command string
+		args    []interface{}
+	}{
+		{"hash field set", "h", "HSET", []interface{}{"h", "f", "v2"}},
+		{"hash field removed", "h", "HDEL", []interface{}{"h", "f"}},
+		{"sorted set member added", "z", "ZADD", []interface{}{"z", 2, "n"}},
+		{"sorted set member removed", "z", "ZREM", []interface{}{"z", "n"}},
+		{"sorted set stored", "z", "ZUNIONSTORE", []interface{}{"z", 1, "z"}},
+		{"key deleted", "z", "DEL", []interface{}{"missing", "z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := conn.Do("WATCH", tt.key)
+			require.NoError(t, err)
+			_, err = other.Do(tt.command, tt.args...)
+			require.NoError(t, err)
+			_ = conn.Send("MULTI")
+			_ = conn.Send("SET", "k", "v")
+			reply, err := conn.Do("EXEC")
+			require.NoError(t, err)
+			assert.Nil(t, reply, "the transaction is aborted when the watched key is written")
+		})
+	}
+
+	_, err = conn.Do("WATCH", "h")
+	require.NoError(t, err)
+	_, err = other.Do("HGET", "h", "f")
+	require.NoError(t, err)
+	_, err = other.Do("SET", "unwatched", "v")
+	require.NoError(t, err)
+	_ = conn.Send("MULTI")
+	_ = conn.Send("SET", "k", "v")
+	replies, err := redis.Values(conn.Do("EXEC"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"OK"}, replies, "reading the watched key or writing the others shouldn't abort the transaction")
+	assert.Empty(t, store.watchers, "the keys should be unwatched after EXEC")
+}
+
+func TestPipeline(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "test.db"), 0)
+	require.NoError(t, err)
+	defer store.Close()
+	conn, _ := store.Dial()
+
+	// each committed write transaction increases the transaction id of the file
+	txId := func() (id int) {
+		_ = store.db.View(func(tx *bolt.Tx) error {
+			id = tx.ID()
+			return nil
+		})
+		return id
+	}
+	before := txId()
+	_ = conn.Send("SET", "k1", "v1")
+	_ = conn.Send("SET", "k2", "v2")
+	_ = conn.Send("HSET", "h", "f", "k1")
+	_ = conn.Send("ZADD", "z", 1, "k1")
+	_ = conn.Send("GET", "k1")
+	replies, err := redis.Values(conn.Do(""))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"OK", "OK", int64(1), int64(1), []byte("v1")}, replies)
+	assert.Equal(t, before+1, txId(), "the pipelined commands should be written in one transaction")
+
+	before = txId()
+	_ = conn.Send("SET", "k3", "v3")
+	_ = conn.Send("MULTI")
+	_ = conn.Send("SET", "k4", "v4")
+	_ = conn.Send("EXEC")
+	_ = conn.Send("DEL", "k1")
+	_ = conn.Send("GET", "k4")
+	replies, err = redis.Values(conn.Do(""))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"OK", "OK", "QUEUED", []interface{}{"OK"}, int64(1), []byte("v4")}, replies)
+	assert.Equal(t, before+3, txId(), "the transaction should separate the pipelined commands before and after it")
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	store, err := Open(path, 0)
+	require.NoError(t, err)
+	conn, _ := store.Dial()
+	_, err = conn.Do("SET", "k", "v")
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	store, err = Open(path, 0)
+	require.NoError(t, err)
+	defer store.Close()
+	conn, _ = store.Dial()
+	v, err := redis.String(conn.Do("GET", "k"))
+	require.NoError(t, err)
+	assert.Equal(t, "v", v)
+}
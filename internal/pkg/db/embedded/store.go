@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package embedded provides a single-file, in-process storage engine which understands the subset of Redis commands
+// used by the infrastructure/redis client.  It allows a one-box deployment to run every service on top of the
+// existing Redis client implementation without running an external Redis server.
+package embedded
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Top-level buckets, one per Redis data type used by the Redis client
+var (
+	stringsBucket    = []byte("strings")
+	hashesBucket     = []byte("hashes")
+	sortedSetsBucket = []byte("zsets")
+)
+
+const defaultOpenTimeout = 5 * time.Second
+
+// Store is an embedded database stored in a single file.  A Store is safe for concurrent use, and every connection
+// returned by Dial shares the same underlying file.
+type Store struct {
+	db *bolt.DB
+	// writeMutex serializes the write transactions with WATCH, so that a key is either watched before a write
+	// transaction modifies it, or watched after the modification is committed
+	writeMutex sync.Mutex
+	// watchers lists the connections watching each key, guarded by writeMutex
+	watchers map[string]map[*conn]bool
+}
+
+// Open opens or creates the database file at path.  The file is locked exclusively by the process, and timeout
+// bounds how long Open waits for the lock held by another process.
+func Open(path string, timeout time.Duration) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("embedded database file path is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultOpenTimeout
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("unable to create the directory of embedded database file %s: %v", path, err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open embedded database file %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{stringsBucket, hashesBucket, sortedSetsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("unable to initialize embedded database file %s: %v", path, err)
+	}
+
+	return &Store{db: db, watchers: make(map[string]map[*conn]bool)}, nil
+}
+
+// update runs fn within a write transaction.  fn reports the keys written by the commands through touch, which makes
+// the transactions of the connections watching the keys abort, like a Redis server does for any key type.
+func (s *Store) update(fn func(tx *bolt.Tx, touch func(key []byte)) error) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx, s.touch)
+	})
+}
+
+// touch marks the connections watching the key dirty, which must be called with writeMutex held
+func (s *Store) touch(key []byte) {
+	for c := range s.watchers[string(key)] {
+		c.dirty = true
+	}
+}
+
+// watch makes the connection watch the keys
+func (s *Store) watch(c *conn, keys [][]byte) {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	for _, key := range keys {
+		watchers, ok := s.watchers[string(key)]
+		if !ok {
+			watchers = make(map[*conn]bool)
+			s.watchers[string(key)] = watchers
+		}
+		watchers[c] = true
+		c.watched = append(c.watched, string(key))
+	}
+}
+
+// unwatch makes the connection forget all the watched keys
+func (s *Store) unwatch(c *conn) {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	for _, key := range c.watched {
+		delete(s.watchers[key], c)
+		if len(s.watchers[key]) == 0 {
+			delete(s.watchers, key)
+		}
+	}
+	c.watched = nil
+	c.dirty = false
+}
+
+// Dial returns a new connection to the store, it has the same signature as redis.Pool.Dial
+func (s *Store) Dial() (redis.Conn, error) {
+	return &conn{store: s}, nil
+}
+
+// Close releases the database file
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,539 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package embedded
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Every sorted set is a nested bucket of the zsets bucket which contains two buckets, members maps each member to its
+// score, and scores is the ordered index made of the encoded score followed by the member.  Like Redis, members with
+// the same score are ordered lexicographically.
+var (
+	membersBucket = []byte("m")
+	scoresBucket  = []byte("s")
+	countKey      = []byte("n")
+)
+
+const scoreSize = 8
+
+// encodeScore encodes the score so that the byte-wise order of the encoded values is the numeric order of the scores
+func encodeScore(score float64) []byte {
+	bits := math.Float64bits(score)
+	if score >= 0 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	b := make([]byte, scoreSize)
+	binary.BigEndian.PutUint64(b, bits)
+	return b
+}
+
+func decodeScore(b []byte) float64 {
+	bits := binary.BigEndian.Uint64(b)
+	if bits&(1<<63) != 0 {
+		bits ^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits)
+}
+
+func indexKey(score float64, member []byte) []byte {
+	return append(encodeScore(score), member...)
+}
+
+// scoreBound is the min or max argument of a score range command, such as -inf, +inf, 10 or (10
+type scoreBound struct {
+	value     float64
+	exclusive bool
+}
+
+func parseScoreBound(b []byte) (scoreBound, bool) {
+	s := string(b)
+	bound := scoreBound{}
+	if strings.HasPrefix(s, "(") {
+		bound.exclusive = true
+		s = s[1:]
+	}
+	switch strings.ToLower(s) {
+	case "-inf":
+		bound.value = math.Inf(-1)
+	case "+inf", "inf":
+		bound.value = math.Inf(1)
+	default:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(v) {
+			return bound, false
+		}
+		bound.value = v
+	}
+	return bound, true
+}
+
+func (b scoreBound) aboveMin(score float64) bool {
+	if b.exclusive {
+		return score > b.value
+	}
+	return score >= b.value
+}
+
+func (b scoreBound) belowMax(score float64) bool {
+	if b.exclusive {
+		return score < b.value
+	}
+	return score <= b.value
+}
+
+type sortedSet struct {
+	bucket *bolt.Bucket
+}
+
+func (z sortedSet) members() *bolt.Bucket {
+	return z.bucket.Bucket(membersBucket)
+}
+
+func (z sortedSet) scores() *bolt.Bucket {
+	return z.bucket.Bucket(scoresBucket)
+}
+
+// readSortedSet returns the sorted set of the key, the returned bucket is nil if the key does not exist
+func readSortedSet(tx *bolt.Tx, key []byte) sortedSet {
+	return sortedSet{bucket: tx.Bucket(sortedSetsBucket).Bucket(key)}
+}
+
+func writeSortedSet(tx *bolt.Tx, key []byte) (sortedSet, error) {
+	if tx.Bucket(stringsBucket).Get(key) != nil || tx.Bucket(hashesBucket).Bucket(key) != nil {
+		return sortedSet{}, redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+	}
+	b, err := tx.Bucket(sortedSetsBucket).CreateBucketIfNotExists(key)
+	if err != nil {
+		return sortedSet{}, err
+	}
+	for _, name := range [][]byte{membersBucket, scoresBucket} {
+		if _, err = b.CreateBucketIfNotExists(name); err != nil {
+			return sortedSet{}, err
+		}
+	}
+	return sortedSet{bucket: b}, nil
+}
+
+func (z sortedSet) add(member []byte, score float64) (added bool, err error) {
+	if old := z.members().Get(member); old != nil {
+		if err = z.scores().Delete(indexKey(decodeScore(old), member)); err != nil {
+			return false, err
+		}
+	} else {
+		added = true
+	}
+	if err = z.members().Put(member, encodeScore(score)); err != nil {
+		return false, err
+	}
+	if added {
+		if err = z.setCard(z.card() + 1); err != nil {
+			return false, err
+		}
+	}
+	return added, z.scores().Put(indexKey(score, member), []byte{})
+}
+
+func (z sortedSet) remove(member []byte) (removed bool, err error) {
+	old := z.members().Get(member)
+	if old == nil {
+		return false, nil
+	}
+	if err = z.scores().Delete(indexKey(decodeScore(old), member)); err != nil {
+		return false, err
+	}
+	if err = z.setCard(z.card() - 1); err != nil {
+		return false, err
+	}
+	return true, z.members().Delete(member)
+}
+
+// card returns the number of members, which is counted as they are added and removed, since the bucket statistics
+// are a full scan that misses the changes of the current transaction
+func (z sortedSet) card() int {
+	if z.bucket == nil {
+		return 0
+	}
+	count := z.bucket.Get(countKey)
+	if count == nil {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(count))
+}
+
+func (z sortedSet) setCard(card int) error {
+	count := make([]byte, 8)
+	binary.BigEndian.PutUint64(count, uint64(card))
+	return z.bucket.Put(countKey, count)
+}
+
+// entry is a member of a sorted set and its score
+type entry struct {
+	member []byte
+	score  float64
+}
+
+// entries returns all the members ordered by score, or in reverse order when rev is true
+func (z sortedSet) entries(rev bool) []entry {
+	if z.bucket == nil {
+		return nil
+	}
+	var result []entry
+	c := z.scores().Cursor()
+	first, next := c.First, c.Next
+	if rev {
+		first, next = c.Last, c.Prev
+	}
+	for k, _ := first(); k != nil; k, _ = next() {
+		result = append(result, entry{member: copyBytes(k[scoreSize:]), score: decodeScore(k[:scoreSize])})
+	}
+	return result
+}
+
+// entriesByScore returns the members within the score range, ordered by score or in reverse order when rev is true
+func (z sortedSet) entriesByScore(min, max scoreBound, rev bool) []entry {
+	if z.bucket == nil {
+		return nil
+	}
+	var result []entry
+	c := z.scores().Cursor()
+	if !rev {
+		for k, _ := c.Seek(encodeScore(min.value)); k != nil; k, _ = c.Next() {
+			score := decodeScore(k[:scoreSize])
+			if !max.belowMax(score) {
+				break
+			}
+			if min.aboveMin(score) {
+				result = append(result, entry{member: copyBytes(k[scoreSize:]), score: score})
+			}
+		}
+		return result
+	}
+
+	var k []byte
+	if math.IsInf(max.value, 1) {
+		k, _ = c.Last()
+	} else {
+		// position the cursor at the first key beyond the max score, then walk backward
+		k, _ = c.Seek(encodeScore(math.Nextafter(max.value, math.Inf(1))))
+		if k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+	}
+	for ; k != nil; k, _ = c.Prev() {
+		score := decodeScore(k[:scoreSize])
+		if !min.aboveMin(score) {
+			break
+		}
+		if max.belowMax(score) {
+			result = append(result, entry{member: copyBytes(k[scoreSize:]), score: score})
+		}
+	}
+	return result
+}
+
+// deleteIfEmpty removes the key once the sorted set becomes empty like Redis does
+func deleteIfEmpty(tx *bolt.Tx, key []byte, z sortedSet) error {
+	if k, _ := z.members().Cursor().First(); k == nil {
+		return tx.Bucket(sortedSetsBucket).DeleteBucket(key)
+	}
+	return nil
+}
+
+func memberReplies(entries []entry) []interface{} {
+	replies := make([]interface{}, len(entries))
+	for i, e := range entries {
+		replies[i] = e.member
+	}
+	return replies
+}
+
+func zadd(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	if len(args)%2 != 1 {
+		return redis.Error("ERR syntax error"), nil
+	}
+	z, err := writeSortedSet(tx, args[0])
+	if err != nil {
+		if e, ok := err.(redis.Error); ok {
+			return e, nil
+		}
+		return nil, err
+	}
+	var added int64
+	for i := 1; i < len(args); i += 2 {
+		score, err := strconv.ParseFloat(string(args[i]), 64)
+		if err != nil || math.IsNaN(score) {
+			return redis.Error("ERR value is not a valid float"), nil
+		}
+		ok, err := z.add(args[i+1], score)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, nil
+}
+
+func zrem(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	z := readSortedSet(tx, args[0])
+	if z.bucket == nil {
+		return int64(0), nil
+	}
+	var removed int64
+	for _, member := range args[1:] {
+		ok, err := z.remove(member)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			removed++
+		}
+	}
+	return removed, deleteIfEmpty(tx, args[0], z)
+}
+
+func zcard(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return int64(readSortedSet(tx, args[0]).card()), nil
+}
+
+func zcount(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	min, ok1 := parseScoreBound(args[1])
+	max, ok2 := parseScoreBound(args[2])
+	if !ok1 || !ok2 {
+		return redis.Error("ERR min or max is not a float"), nil
+	}
+	return int64(len(readSortedSet(tx, args[0]).entriesByScore(min, max, false))), nil
+}
+
+// rangeByIndex implements ZRANGE and ZREVRANGE, negative indexes count from the end of the sorted set
+func rangeByIndex(tx *bolt.Tx, args [][]byte, rev bool) (interface{}, error) {
+	start, err1 := strconv.Atoi(string(args[1]))
+	stop, err2 := strconv.Atoi(string(args[2]))
+	if err1 != nil || err2 != nil {
+		return redis.Error("ERR value is not an integer or out of range"), nil
+	}
+	entries := readSortedSet(tx, args[0]).entries(rev)
+	count := len(entries)
+	if start < 0 {
+		start += count
+	}
+	if stop < 0 {
+		stop += count
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= count {
+		stop = count - 1
+	}
+	if start > stop || start >= count {
+		return []interface{}{}, nil
+	}
+	return memberReplies(entries[start : stop+1]), nil
+}
+
+func zrange(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return rangeByIndex(tx, args, false)
+}
+
+func zrevrange(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return rangeByIndex(tx, args, true)
+}
+
+// rangeByScore implements ZRANGEBYSCORE key min max [LIMIT offset count] and ZREVRANGEBYSCORE key max min [LIMIT
+// offset count]
+func rangeByScore(tx *bolt.Tx, args [][]byte, rev bool) (interface{}, error) {
+	minArg, maxArg := args[1], args[2]
+	if rev {
+		minArg, maxArg = args[2], args[1]
+	}
+	min, ok1 := parseScoreBound(minArg)
+	max, ok2 := parseScoreBound(maxArg)
+	if !ok1 || !ok2 {
+		return redis.Error("ERR min or max is not a float"), nil
+	}
+	offset, count := 0, -1
+	if len(args) > 3 {
+		if len(args) != 6 || !bytes.EqualFold(args[3], []byte("LIMIT")) {
+			return redis.Error("ERR syntax error"), nil
+		}
+		var err1, err2 error
+		offset, err1 = strconv.Atoi(string(args[4]))
+		count, err2 = strconv.Atoi(string(args[5]))
+		if err1 != nil || err2 != nil {
+			return redis.Error("ERR value is not an integer or out of range"), nil
+		}
+	}
+
+	entries := readSortedSet(tx, args[0]).entriesByScore(min, max, rev)
+	if offset < 0 || offset >= len(entries) {
+		return []interface{}{}, nil
+	}
+	entries = entries[offset:]
+	if count >= 0 && count < len(entries) {
+		entries = entries[:count]
+	}
+	return memberReplies(entries), nil
+}
+
+func zrangebyscore(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return rangeByScore(tx, args, false)
+}
+
+func zrevrangebyscore(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return rangeByScore(tx, args, true)
+}
+
+// lexBound is the min or max argument of ZRANGEBYLEX, such as -, +, [abc or (abc
+type lexBound struct {
+	value     []byte
+	exclusive bool
+	infinite  bool
+}
+
+func parseLexBound(b []byte) (lexBound, bool) {
+	switch {
+	case string(b) == "-" || string(b) == "+":
+		return lexBound{infinite: true}, true
+	case len(b) > 0 && b[0] == '[':
+		return lexBound{value: b[1:]}, true
+	case len(b) > 0 && b[0] == '(':
+		return lexBound{value: b[1:], exclusive: true}, true
+	}
+	return lexBound{}, false
+}
+
+func (b lexBound) aboveMin(member []byte) bool {
+	if b.infinite {
+		return true
+	}
+	c := bytes.Compare(member, b.value)
+	return c > 0 || (c == 0 && !b.exclusive)
+}
+
+func (b lexBound) belowMax(member []byte) bool {
+	if b.infinite {
+		return true
+	}
+	c := bytes.Compare(member, b.value)
+	return c < 0 || (c == 0 && !b.exclusive)
+}
+
+// zrangebylex implements ZRANGEBYLEX key min max [LIMIT offset count].  Like Redis, the members are expected to have
+// the same score, otherwise the result is unspecified.
+func zrangebylex(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	min, ok1 := parseLexBound(args[1])
+	max, ok2 := parseLexBound(args[2])
+	if !ok1 || !ok2 || string(args[1]) == "+" || string(args[2]) == "-" {
+		return redis.Error("ERR min or max not valid string range item"), nil
+	}
+	offset, count := 0, -1
+	if len(args) > 3 {
+		if len(args) != 6 || !bytes.EqualFold(args[3], []byte("LIMIT")) {
+			return redis.Error("ERR syntax error"), nil
+		}
+		var err1, err2 error
+		offset, err1 = strconv.Atoi(string(args[4]))
+		count, err2 = strconv.Atoi(string(args[5]))
+		if err1 != nil || err2 != nil {
+			return redis.Error("ERR value is not an integer or out of range"), nil
+		}
+	}
+
+	var entries []entry
+	for _, e := range readSortedSet(tx, args[0]).entries(false) {
+		if min.aboveMin(e.member) && max.belowMax(e.member) {
+			entries = append(entries, e)
+		}
+	}
+	if offset < 0 || offset >= len(entries) {
+		return []interface{}{}, nil
+	}
+	entries = entries[offset:]
+	if count >= 0 && count < len(entries) {
+		entries = entries[:count]
+	}
+	return memberReplies(entries), nil
+}
+
+// storeSetOperation implements ZUNIONSTORE and ZINTERSTORE destination numkeys key [key ...] [WEIGHTS weight
+// [weight ...]] with the default SUM aggregation, the destination is overwritten
+func storeSetOperation(tx *bolt.Tx, args [][]byte, intersect bool) (interface{}, error) {
+	numKeys, err := strconv.Atoi(string(args[1]))
+	if err != nil || numKeys < 1 {
+		return redis.Error("ERR at least 1 input key is needed"), nil
+	}
+	if len(args) < numKeys+2 {
+		return redis.Error("ERR syntax error"), nil
+	}
+	weights := make([]float64, numKeys)
+	for i := range weights {
+		weights[i] = 1
+	}
+	if options := args[numKeys+2:]; len(options) > 0 {
+		if len(options) != numKeys+1 || !strings.EqualFold(string(options[0]), "WEIGHTS") {
+			return redis.Error("ERR syntax error"), nil
+		}
+		for i, option := range options[1:] {
+			if weights[i], err = strconv.ParseFloat(string(option), 64); err != nil {
+				return redis.Error("ERR weight value is not a float"), nil
+			}
+		}
+	}
+
+	scores := make(map[string]float64)
+	occurrences := make(map[string]int)
+	for i, key := range args[2 : numKeys+2] {
+		for _, e := range readSortedSet(tx, key).entries(false) {
+			scores[string(e.member)] += e.score * weights[i]
+			occurrences[string(e.member)]++
+		}
+	}
+
+	if err = deleteKey(tx, args[0]); err != nil {
+		return nil, err
+	}
+	var stored int64
+	var z sortedSet
+	for member, score := range scores {
+		if intersect && occurrences[member] != numKeys {
+			continue
+		}
+		if z.bucket == nil {
+			if z, err = writeSortedSet(tx, args[0]); err != nil {
+				return nil, err
+			}
+		}
+		if _, err = z.add([]byte(member), score); err != nil {
+			return nil, err
+		}
+		stored++
+	}
+	return stored, nil
+}
+
+func zunionstore(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return storeSetOperation(tx, args, false)
+}
+
+func zinterstore(tx *bolt.Tx, args [][]byte) (interface{}, error) {
+	return storeSetOperation(tx, args, true)
+}
@@ -15,6 +15,7 @@ package redis
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/gomodule/redigo/redis"
 
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db/embedded"
 )
 
 var currClient *Client // a singleton so Readings can be de-referenced
@@ -34,6 +36,7 @@ type Client struct {
 	Pool          *redis.Pool // A thread-safe pool of connections to Redis
 	BatchSize     int
 	loggingClient logger.LoggingClient
+	store         io.Closer // The embedded store backing the pool, nil when connected to a Redis server
 }
 
 type CoreDataClient struct {
@@ -95,6 +98,41 @@ func NewClient(config db.Configuration, lc logger.LoggingClient) (*Client, error
 	return currClient, nil
 }
 
+// NewEmbeddedClient returns a pointer to a client backed by the embedded single-file store instead of a Redis server.
+// The DatabaseName of the configuration is the path of the database file.
+func NewEmbeddedClient(config db.Configuration, lc logger.LoggingClient) (*Client, error) {
+	var err error
+	once.Do(func() {
+		var store *embedded.Store
+		store, err = embedded.Open(config.DatabaseName, time.Duration(config.Timeout)*time.Millisecond)
+		if err != nil {
+			return
+		}
+		// Default the batch size to 1,000 if not set
+		batchSize := 1000
+		if config.BatchSize != 0 {
+			batchSize = config.BatchSize
+		}
+		currClient = &Client{
+			Pool: &redis.Pool{
+				IdleTimeout: 0,
+				MaxIdle:     10,
+				Dial:        store.Dial,
+			},
+			BatchSize:     batchSize,
+			loggingClient: lc,
+			store:         store,
+		}
+	})
+	if err != nil {
+		// allow the next attempt to open the database file again
+		once = sync.Once{}
+		return nil, err
+	}
+
+	return currClient, nil
+}
+
 // Connect connects to Redis
 func (c *Client) Connect() error {
 	return nil
@@ -103,6 +141,9 @@ func (c *Client) Connect() error {
 // CloseSession closes the connections to Redis
 func (c *Client) CloseSession() {
 	_ = c.Pool.Close()
+	if c.store != nil {
+		_ = c.store.Close()
+	}
 	currClient = nil
 	once = sync.Once{}
 }
@@ -0,0 +1,68 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// Types of the system events
+const (
+	DeviceSystemEventType           = "device"
+	DeviceProfileSystemEventType    = "deviceprofile"
+	DeviceServiceSystemEventType    = "deviceservice"
+	ProvisionWatcherSystemEventType = "provisionwatcher"
+)
+
+// Actions of the system events
+const (
+	SystemEventActionAdd    = "add"
+	SystemEventActionUpdate = "update"
+	SystemEventActionDelete = "delete"
+)
+
+// SystemEvent is published to the message bus by an EdgeX service when its data, e.g. the metadata, changes
+type SystemEvent struct {
+	common.Versionable `json:",inline"`
+	Type               string `json:"type"`
+	Action             string `json:"action"`
+	// Source is the service key of the service publishing the event
+	Source string `json:"source"`
+	// Owner is the name of the device service the changed data belongs to, if any
+	Owner string `json:"owner,omitempty"`
+	// Details is the DTO of the changed data, e.g. dtos.Device of the device system event
+	Details   json.RawMessage `json:"details"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+// NewSystemEvent creates the SystemEvent with the JSON encoded details
+func NewSystemEvent(eventType string, action string, source string, owner string, details interface{}) (SystemEvent, errors.EdgeX) {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return SystemEvent{}, errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to encode the system event details", err)
+	}
+	return SystemEvent{
+		Versionable: common.NewVersionable(),
+		Type:        eventType,
+		Action:      action,
+		Source:      source,
+		Owner:       owner,
+		Details:     data,
+		Timestamp:   time.Now().UnixNano(),
+	}, nil
+}
+
+// DecodeDetails decodes the details of the system event into v, e.g. *dtos.Device of the device system event
+func (s SystemEvent) DecodeDetails(v interface{}) errors.EdgeX {
+	if err := json.Unmarshal(s.Details, v); err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "failed to decode the system event details", err)
+	}
+	return nil
+}
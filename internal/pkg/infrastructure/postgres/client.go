@@ -0,0 +1,404 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	model "github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultConnectTimeout = 5 * time.Second
+	defaultSSLMode        = "require"
+)
+
+type Client struct {
+	db            *sql.DB
+	timeout       time.Duration
+	loggingClient logger.LoggingClient
+}
+
+// NewClient opens a connection pool to PostgreSQL and creates the core-data tables and indexes when needed
+func NewClient(config db.Configuration, lc logger.LoggingClient) (*Client, errors.EdgeX) {
+	timeout := defaultConnectTimeout
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Millisecond
+	}
+	sqlDB, err := sql.Open("postgres", connectionString(config, timeout))
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "postgres client creation failed", err)
+	}
+
+	c := &Client{
+		db:            sqlDB,
+		timeout:       timeout,
+		loggingClient: lc,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err = sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "could not connect to postgres", err)
+	}
+	for _, statement := range schemaStatements {
+		if _, err = sqlDB.ExecContext(ctx, statement); err != nil {
+			_ = sqlDB.Close()
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "postgres schema initialization failed", err)
+		}
+	}
+
+	return c, nil
+}
+
+// connectionString builds the postgres:// URL of the configuration, in which the credentials and the other values are
+// escaped, so that they can't break the URL or add options to it whatever characters they contain
+func connectionString(config db.Configuration, timeout time.Duration) string {
+	sslMode := config.SSLMode
+	if sslMode == "" {
+		sslMode = defaultSSLMode
+	}
+	// connect_timeout is expressed in seconds, so round up to avoid a zero value which means wait indefinitely
+	connectTimeout := int((timeout + time.Second - 1) / time.Second)
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(config.Username, config.Password),
+		Host:   net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		Path:   "/" + config.DatabaseName,
+		RawQuery: url.Values{
+			"sslmode":         []string{sslMode},
+			"connect_timeout": []string{strconv.Itoa(connectTimeout)},
+		}.Encode(),
+	}
+	return u.String()
+}
+
+// CloseSession closes the connections to PostgreSQL
+func (c *Client) CloseSession() {
+	_ = c.db.Close()
+}
+
+// AddEvent adds a new event
+func (c *Client) AddEvent(e model.Event) (model.Event, errors.EdgeX) {
+	if e.Id != "" {
+		_, err := uuid.Parse(e.Id)
+		if err != nil {
+			return model.Event{}, errors.NewCommonEdgeX(errors.KindInvalidId, "uuid parsing failed", err)
+		}
+	} else {
+		e.Id = uuid.New().String()
+	}
+
+	return c.addEvent(e)
+}
+
+// AddEvents adds the events in a single transaction and returns the added events along with the error of each event,
+// which is nil when the event is added
+func (c *Client) AddEvents(events []model.Event) ([]model.Event, []errors.EdgeX) {
+	var validEvents []model.Event
+	var validIndexes []int
+	addedEvents := make([]model.Event, len(events))
+	errs := make([]errors.EdgeX, len(events))
+	for i, e := range events {
+		if e.Id != "" {
+			_, err := uuid.Parse(e.Id)
+			if err != nil {
+				errs[i] = errors.NewCommonEdgeX(errors.KindInvalidId, "uuid parsing failed", err)
+				continue
+			}
+		} else {
+			e.Id = uuid.New().String()
+		}
+		validEvents = append(validEvents, e)
+		validIndexes = append(validIndexes, i)
+	}
+	if len(validEvents) == 0 {
+		return addedEvents, errs
+	}
+
+	added, addErrs := c.addEvents(validEvents)
+	for i, index := range validIndexes {
+		addedEvents[index] = added[i]
+		errs[index] = addErrs[i]
+	}
+	return addedEvents, errs
+}
+
+// EventById gets an event by id
+func (c *Client) EventById(id string) (event model.Event, edgeXerr errors.EdgeX) {
+	event, edgeXerr = c.eventById(id)
+	if edgeXerr != nil {
+		return event, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+
+	return
+}
+
+// DeleteEventById removes an event by id
+func (c *Client) DeleteEventById(id string) (edgeXerr errors.EdgeX) {
+	edgeXerr = c.deleteEventById(id)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+
+	return
+}
+
+// EventTotalCount returns the total count of Event from the database
+func (c *Client) EventTotalCount() (uint32, errors.EdgeX) {
+	return c.countByQuery(eventCountSQL)
+}
+
+// EventCountByDeviceName returns the count of Event associated a specific Device from the database
+func (c *Client) EventCountByDeviceName(deviceName string) (uint32, errors.EdgeX) {
+	return c.countByQuery(eventCountByDeviceSQL, deviceName)
+}
+
+// EventCountByTimeRange returns the count of Event by time range
+func (c *Client) EventCountByTimeRange(startTime int, endTime int) (uint32, errors.EdgeX) {
+	return c.countByQuery(eventCountByTimeSQL, startTime, endTime)
+}
+
+// AllEvents query events by offset and limit
+func (c *Client) AllEvents(offset int, limit int) ([]model.Event, errors.EdgeX) {
+	events, edgeXerr := c.eventsByQuery(eventCountSQL, allEventsSQL, offset, limit)
+	if edgeXerr != nil {
+		return events, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query events by offset %d and limit %d", offset, limit), edgeXerr)
+	}
+	return events, nil
+}
+
+// EventsByDeviceName query events by offset, limit and device name
+func (c *Client) EventsByDeviceName(offset int, limit int, name string) (events []model.Event, edgeXerr errors.EdgeX) {
+	events, edgeXerr = c.eventsByQuery(eventCountByDeviceSQL, eventsByDeviceNameSQL, offset, limit, name)
+	if edgeXerr != nil {
+		return events, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query events by offset %d, limit %d and name %s", offset, limit, name), edgeXerr)
+	}
+	return events, nil
+}
+
+// EventsByTimeRange query events by time range, offset, and limit
+func (c *Client) EventsByTimeRange(startTime int, endTime int, offset int, limit int) (events []model.Event, edgeXerr errors.EdgeX) {
+	events, edgeXerr = c.eventsByQuery(eventCountByTimeSQL, eventsByTimeRangeSQL, offset, limit, startTime, endTime)
+	if edgeXerr != nil {
+		return events, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query events by time range %v ~ %v, offset %d, and limit %d", startTime, endTime, offset, limit), edgeXerr)
+	}
+	return events, nil
+}
+
+// DeleteEventsByDeviceName deletes specific device's events and corresponding readings.  The deletion runs in the
+// background to keep the same asynchronous behavior as the Redis implementation.
+func (c *Client) DeleteEventsByDeviceName(deviceName string) errors.EdgeX {
+	go c.asyncDeleteEvents(deleteEventsByDeviceSQL, deviceName)
+	return nil
+}
+
+// DeleteEventsByAge deletes events and their corresponding readings that are older than age.  The deletion runs in
+// the background to keep the same asynchronous behavior as the Redis implementation.
+func (c *Client) DeleteEventsByAge(age int64) errors.EdgeX {
+	expireTimestamp := time.Now().UnixNano() - age
+	go c.asyncDeleteEvents(deleteEventsByOriginSQL, expireTimestamp)
+	return nil
+}
+
+// PruneEvents deletes the events, except the ones of the excluded devices, which are older than age or are not among
+// the newest maxCount events.  A zero age or maxCount means no limit.  The deletion runs in the background, and the
+// number of events to be deleted is returned.
+func (c *Client) PruneEvents(age int64, maxCount uint32, excludedDeviceNames []string) (uint32, errors.EdgeX) {
+	if excludedDeviceNames == nil {
+		excludedDeviceNames = []string{}
+	}
+	count, edgeXerr := c.pruneEvents(eventIdsByOriginExcludingDevicesSQL, eventIdsBeyondCountExcludingDevicesSQL, pq.Array(excludedDeviceNames), age, maxCount)
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeX(errors.Kind(edgeXerr), "fail to prune events", edgeXerr)
+	}
+	return count, nil
+}
+
+// PruneEventsByDeviceName deletes specific device's events which are older than age or are not among the newest
+// maxCount events of the device.  A zero age or maxCount means no limit.  The deletion runs in the background, and the
+// number of events to be deleted is returned.
+func (c *Client) PruneEventsByDeviceName(deviceName string, age int64, maxCount uint32) (uint32, errors.EdgeX) {
+	count, edgeXerr := c.pruneEvents(eventIdsByDeviceNameAndOriginSQL, eventIdsBeyondCountByDeviceNameAndOriginSQL, deviceName, age, maxCount)
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeX(errors.Kind(edgeXerr), fmt.Sprintf("fail to prune events of device %s", deviceName), edgeXerr)
+	}
+	return count, nil
+}
+
+// ReadingTotalCount returns the total count of Reading from the database
+func (c *Client) ReadingTotalCount() (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountSQL)
+}
+
+// AllReadings query readings by offset and limit
+func (c *Client) AllReadings(offset int, limit int) ([]model.Reading, errors.EdgeX) {
+	readings, edgeXerr := c.readingsByQuery(readingCountSQL, allReadingsSQL, offset, limit)
+	if edgeXerr != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query readings by offset %d, and limit %d", offset, limit), edgeXerr)
+	}
+	return readings, nil
+}
+
+// ReadingsByTimeRange query readings by time range, offset, and limit
+func (c *Client) ReadingsByTimeRange(start int, end int, offset int, limit int) (readings []model.Reading, edgeXerr errors.EdgeX) {
+	readings, edgeXerr = c.readingsByQuery(readingCountByTimeRangeSQL, readingsByTimeRangeSQL, offset, limit, start, end)
+	if edgeXerr != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query readings by time range %v ~ %v, offset %d, and limit %d", start, end, offset, limit), edgeXerr)
+	}
+	return readings, nil
+}
+
+// ReadingsByResourceName query readings by offset, limit and resource name
+func (c *Client) ReadingsByResourceName(offset int, limit int, resourceName string) (readings []model.Reading, edgeXerr errors.EdgeX) {
+	readings, edgeXerr = c.readingsByQuery(readingCountByResourceNameSQL, readingsByResourceNameSQL, offset, limit, resourceName)
+	if edgeXerr != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query readings by offset %d, limit %d and resourceName %s", offset, limit, resourceName), edgeXerr)
+	}
+	return readings, nil
+}
+
+// ReadingsByDeviceName query readings by offset, limit and device name
+func (c *Client) ReadingsByDeviceName(offset int, limit int, name string) (readings []model.Reading, edgeXerr errors.EdgeX) {
+	readings, edgeXerr = c.readingsByQuery(readingCountByDeviceNameSQL, readingsByDeviceNameSQL, offset, limit, name)
+	if edgeXerr != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to query readings by offset %d, limit %d and name %s", offset, limit, name), edgeXerr)
+	}
+	return readings, nil
+}
+
+// ReadingsByDeviceNameAndResourceName query readings by device name and resource name, offset, and limit
+func (c *Client) ReadingsByDeviceNameAndResourceName(deviceName string, resourceName string, offset int, limit int) (readings []model.Reading, err errors.EdgeX) {
+	readings, err = c.readingsByQuery(readingCountByDeviceNameAndResourceNameSQL, readingsByDeviceNameAndResourceNameSQL, offset, limit, deviceName, resourceName)
+	if err != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to query readings by deviceName %s and resourceName %s", deviceName, resourceName), err)
+	}
+	return readings, nil
+}
+
+// ReadingsByDeviceNameAndResourceNameAndTimeRange query readings by device name, resource name and specified time range
+func (c *Client) ReadingsByDeviceNameAndResourceNameAndTimeRange(deviceName string, resourceName string, start int, end int, offset int, limit int) (readings []model.Reading, err errors.EdgeX) {
+	readings, err = c.readingsByQuery(readingCountByDeviceNameAndResourceNameTimeSQL, readingsByDeviceNameAndResourceNameTimeSQL, offset, limit, deviceName, resourceName, start, end)
+	if err != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to query readings by deviceName %s, resourceName %s and time range %v ~ %v", deviceName, resourceName, start, end), err)
+	}
+	return readings, nil
+}
+
+// ReadingsByDeviceNameAndResourceNameBeforeCursor query readings by device name and resource name whose origin is not
+// less than start, and which are ordered after the cursor of origin and id in the descending order of origin and id.
+// The cursor includes all the readings of cursorOrigin when cursorId is empty.
+func (c *Client) ReadingsByDeviceNameAndResourceNameBeforeCursor(deviceName string, resourceName string, start int, cursorOrigin int, cursorId string, limit int) (readings []model.Reading, err errors.EdgeX) {
+	query := readingsByDeviceNameAndResourceNameUntilSQL
+	args := []interface{}{deviceName, resourceName, start, cursorOrigin}
+	if cursorId != "" {
+		if _, parseErr := uuid.Parse(cursorId); parseErr != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindInvalidId, "uuid parsing failed", parseErr)
+		}
+		query = readingsByDeviceNameAndResourceNameBeforeIdSQL
+		args = append(args, cursorId)
+	}
+	rows, queryErr := c.db.Query(query, append(args, limitArg(limit))...)
+	if queryErr != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError,
+			fmt.Sprintf("fail to query readings by deviceName %s, resourceName %s before origin %v and id %s", deviceName, resourceName, cursorOrigin, cursorId), queryErr)
+	}
+	return scanReadings(rows)
+}
+
+// ReadingsByDeviceNameAndResourceNamesAndTimeRange query readings by device name, multiple resource names and specified
+// time range, and return the total count of readings matching the same criteria
+func (c *Client) ReadingsByDeviceNameAndResourceNamesAndTimeRange(deviceName string, resourceNames []string, start, end, offset, limit int) (readings []model.Reading, totalCount uint32, err errors.EdgeX) {
+	names := pq.Array(resourceNames)
+	totalCount, err = c.countByQuery(readingCountByDeviceNameAndResourceNamesTimeSQL, deviceName, names, start, end)
+	if err == nil {
+		readings, err = c.readingsByQuery(readingCountByDeviceNameAndResourceNamesTimeSQL, readingsByDeviceNameAndResourceNamesTimeSQL, offset, limit, deviceName, names, start, end)
+	}
+	if err != nil {
+		return readings, totalCount, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to query readings by deviceName %s, resourceNames %v and time range %v ~ %v", deviceName, resourceNames, start, end), err)
+	}
+	return readings, totalCount, nil
+}
+
+// ReadingsByResourceNameAndTimeRange query readings by resourceName and specified time range. Readings are sorted in descending order of origin time.
+func (c *Client) ReadingsByResourceNameAndTimeRange(resourceName string, start int, end int, offset int, limit int) (readings []model.Reading, err errors.EdgeX) {
+	readings, err = c.readingsByQuery(readingCountByResourceNameAndTimeRangeSQL, readingsByResourceNameAndTimeRangeSQL, offset, limit, resourceName, start, end)
+	if err != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to query readings by resourceName %s and time range %v ~ %v, offset %d, and limit %d", resourceName, start, end, offset, limit), err)
+	}
+	return readings, nil
+}
+
+// ReadingsByDeviceNameAndTimeRange query readings by device name and specified time range
+func (c *Client) ReadingsByDeviceNameAndTimeRange(deviceName string, start int, end int, offset int, limit int) (readings []model.Reading, err errors.EdgeX) {
+	readings, err = c.readingsByQuery(readingCountByDeviceNameAndTimeRangeSQL, readingsByDeviceNameAndTimeRangeSQL, offset, limit, deviceName, start, end)
+	if err != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to query readings by deviceName %s, and time range %v ~ %v", deviceName, start, end), err)
+	}
+	return readings, nil
+}
+
+// ReadingCountByDeviceName returns the count of Readings associated a specific Device from the database
+func (c *Client) ReadingCountByDeviceName(deviceName string) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByDeviceNameSQL, deviceName)
+}
+
+// ReadingCountByResourceName returns the count of Readings associated a specific Resource from the database
+func (c *Client) ReadingCountByResourceName(resourceName string) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByResourceNameSQL, resourceName)
+}
+
+// ReadingCountByResourceNameAndTimeRange returns the count of Readings associated a specific Resource from the database within specified time range
+func (c *Client) ReadingCountByResourceNameAndTimeRange(resourceName string, start int, end int) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByResourceNameAndTimeRangeSQL, resourceName, start, end)
+}
+
+// ReadingCountByDeviceNameAndResourceName returns the count of Readings associated with specified Resource and Device from the database
+func (c *Client) ReadingCountByDeviceNameAndResourceName(deviceName string, resourceName string) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByDeviceNameAndResourceNameSQL, deviceName, resourceName)
+}
+
+// ReadingCountByDeviceNameAndResourceNameAndTimeRange returns the count of Readings associated with specified Resource and Device from the database within specified time range
+func (c *Client) ReadingCountByDeviceNameAndResourceNameAndTimeRange(deviceName string, resourceName string, start int, end int) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByDeviceNameAndResourceNameTimeSQL, deviceName, resourceName, start, end)
+}
+
+// ReadingCountByTimeRange returns the count of Readings from the database within specified time range
+func (c *Client) ReadingCountByTimeRange(start int, end int) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByTimeRangeSQL, start, end)
+}
+
+// ReadingCountByDeviceNameAndTimeRange returns the count of Readings associated with specified Device from the database within specified time range
+func (c *Client) ReadingCountByDeviceNameAndTimeRange(deviceName string, start int, end int) (uint32, errors.EdgeX) {
+	return c.countByQuery(readingCountByDeviceNameAndTimeRangeSQL, deviceName, start, end)
+}
@@ -0,0 +1,288 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	dataInterfaces "github.com/edgexfoundry/edgex-go/internal/core/data/infrastructure/interfaces"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Check the implementation of PostgreSQL satisfies the DB client
+var _ dataInterfaces.DBClient = &Client{}
+
+// The environment variables to run the integration tests against a real PostgreSQL, e.g.
+//
+//	docker run -d -p 5432:5432 -e POSTGRES_PASSWORD=postgres postgres:13-alpine
+//	POSTGRES_TEST_HOST=localhost go test ./internal/pkg/infrastructure/postgres/...
+//
+// The integration tests are skipped when POSTGRES_TEST_HOST is not set, while the SQL paths are covered without a
+// database by the tests of sql_test.go.
+const (
+	testHostEnv     = "POSTGRES_TEST_HOST"
+	testPortEnv     = "POSTGRES_TEST_PORT"
+	testUserEnv     = "POSTGRES_TEST_USER"
+	testPasswordEnv = "POSTGRES_TEST_PASSWORD"
+	testDatabaseEnv = "POSTGRES_TEST_DB"
+	testSSLModeEnv  = "POSTGRES_TEST_SSLMODE"
+)
+
+func envOrDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// newTestClient connects to the PostgreSQL of the test environment, and returns the client along with a device name
+// unique to the test, whose events are removed when the test is done
+func newTestClient(t *testing.T) (*Client, string) {
+	host := os.Getenv(testHostEnv)
+	if host == "" {
+		t.Skipf("%s is not set, skip the PostgreSQL integration test", testHostEnv)
+	}
+	port, err := strconv.Atoi(envOrDefault(testPortEnv, "5432"))
+	require.NoError(t, err)
+
+	c, edgeXerr := NewClient(db.Configuration{
+		Host:         host,
+		Port:         port,
+		Timeout:      5000,
+		DatabaseName: envOrDefault(testDatabaseEnv, "postgres"),
+		Username:     envOrDefault(testUserEnv, "postgres"),
+		Password:     envOrDefault(testPasswordEnv, "postgres"),
+		SSLMode:      envOrDefault(testSSLModeEnv, "disable"),
+	}, logger.NewMockClient())
+	require.NoError(t, edgeXerr)
+
+	deviceName := testDeviceName + "-" + uuid.NewString()
+	t.Cleanup(func() {
+		_, err := c.db.Exec(deleteEventsByDeviceSQL, deviceName)
+		assert.NoError(t, err)
+		c.CloseSession()
+	})
+	return c, deviceName
+}
+
+func testEvent(deviceName string, origin int64, values ...string) models.Event {
+	e := models.Event{
+		Id:          uuid.NewString(),
+		DeviceName:  deviceName,
+		ProfileName: testProfileName,
+		SourceName:  testResourceName,
+		Origin:      origin,
+		Tags:        map[string]interface{}{"floor": "1"},
+	}
+	for _, value := range values {
+		e.Readings = append(e.Readings, models.SimpleReading{
+			BaseReading: models.BaseReading{
+				DeviceName:   deviceName,
+				ProfileName:  testProfileName,
+				ResourceName: testResourceName,
+				Origin:       origin,
+				ValueType:    common.ValueTypeString,
+			},
+			Value: value,
+		})
+	}
+	return e
+}
+
+// eventually polls condition until it holds, since the deletions are run in the background
+func eventually(t *testing.T, condition func() bool, msg string) {
+	assert.Eventually(t, condition, 5*time.Second, 50*time.Millisecond, msg)
+}
+
+func TestIntegrationAddAndQueryEvents(t *testing.T) {
+	c, deviceName := newTestClient(t)
+
+	event := testEvent(deviceName, 100, "1", "2")
+	added, err := c.AddEvent(event)
+	require.NoError(t, err)
+	require.Len(t, added.Readings, 2)
+	for _, r := range added.Readings {
+		_, parseErr := uuid.Parse(r.GetBaseReading().Id)
+		assert.NoError(t, parseErr, "the reading id should be generated")
+	}
+
+	found, err := c.EventById(event.Id)
+	require.NoError(t, err)
+	assert.Equal(t, event.Id, found.Id)
+	assert.Equal(t, deviceName, found.DeviceName)
+	assert.Equal(t, event.Tags, found.Tags)
+	require.Len(t, found.Readings, 2)
+	assert.Equal(t, "1", found.Readings[0].(models.SimpleReading).Value, "the readings should keep their order")
+	assert.Equal(t, "2", found.Readings[1].(models.SimpleReading).Value, "the readings should keep their order")
+
+	events := []models.Event{testEvent(deviceName, 200, "3"), testEvent(deviceName, 300, "4"), {Id: "invalid"}, event}
+	_, errs := c.AddEvents(events)
+	require.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Equal(t, errors.KindInvalidId, errors.Kind(errs[2]), "the event with invalid id should be rejected alone")
+	assert.Equal(t, errors.KindDuplicateName, errors.Kind(errs[3]), "the existing event should be rejected alone")
+
+	count, err := c.EventCountByDeviceName(deviceName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), count)
+	count, err = c.ReadingCountByDeviceName(deviceName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), count)
+	count, err = c.ReadingCountByDeviceNameAndResourceNameAndTimeRange(deviceName, testResourceName, 150, 300)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count)
+
+	found2, err := c.EventsByDeviceName(1, 1, deviceName)
+	require.NoError(t, err)
+	require.Len(t, found2, 1)
+	assert.Equal(t, events[0].Id, found2[0].Id, "the events should be ordered by origin descending")
+
+	foundAll, err := c.EventsByDeviceName(0, -1, deviceName)
+	require.NoError(t, err)
+	assert.Len(t, foundAll, 3, "-1 limit should return all the events")
+
+	readings, err := c.ReadingsByDeviceNameAndResourceNameAndTimeRange(deviceName, testResourceName, 0, 250, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, readings, 3)
+	assert.Equal(t, "3", readings[0].(models.SimpleReading).Value)
+
+	readings, totalCount, err := c.ReadingsByDeviceNameAndResourceNamesAndTimeRange(deviceName, []string{testResourceName}, 0, 300, 0, 2)
+	require.NoError(t, err)
+	assert.Len(t, readings, 2)
+	assert.Equal(t, uint32(4), totalCount)
+
+	var values []string
+	cursorOrigin, cursorId := 250, ""
+	for {
+		readings, err = c.ReadingsByDeviceNameAndResourceNameBeforeCursor(deviceName, testResourceName, 0, cursorOrigin, cursorId, 1)
+		require.NoError(t, err)
+		if len(readings) == 0 {
+			break
+		}
+		values = append(values, readings[0].(models.SimpleReading).Value)
+		last := readings[0].GetBaseReading()
+		cursorOrigin, cursorId = int(last.Origin), last.Id
+	}
+	assert.ElementsMatch(t, []string{"3", "1", "2"}, values, "each reading should be returned once by the cursor")
+	assert.Equal(t, "3", values[0], "the readings should be returned the newest first")
+}
+
+func TestIntegrationErrorMapping(t *testing.T) {
+	c, deviceName := newTestClient(t)
+
+	_, err := c.AddEvent(testEvent(deviceName, 100, "1"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		call         func() errors.EdgeX
+		expectedKind errors.ErrKind
+	}{
+		{"invalid event id", func() errors.EdgeX {
+			_, err := c.AddEvent(models.Event{Id: "invalid", DeviceName: deviceName})
+			return err
+		}, errors.KindInvalidId},
+		{"invalid reading id", func() errors.EdgeX {
+			e := testEvent(deviceName, 100, "1")
+			r := e.Readings[0].(models.SimpleReading)
+			r.Id = "invalid"
+			e.Readings[0] = r
+			_, err := c.AddEvent(e)
+			return err
+		}, errors.KindInvalidId},
+		{"event not found", func() errors.EdgeX {
+			_, err := c.EventById(uuid.NewString())
+			return err
+		}, errors.KindEntityDoesNotExist},
+		{"delete event not found", func() errors.EdgeX {
+			return c.DeleteEventById(uuid.NewString())
+		}, errors.KindEntityDoesNotExist},
+		{"offset out of range", func() errors.EdgeX {
+			_, err := c.EventsByDeviceName(1, 10, deviceName)
+			return err
+		}, errors.KindRangeNotSatisfiable},
+		{"reading offset out of range", func() errors.EdgeX {
+			_, err := c.ReadingsByDeviceName(5, 10, deviceName)
+			return err
+		}, errors.KindRangeNotSatisfiable},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.call()
+			require.Error(t, err)
+			assert.Equal(t, testCase.expectedKind, errors.Kind(err))
+		})
+	}
+
+	count, err := c.EventCountByDeviceName(deviceName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), count, "the rejected events should not be added")
+}
+
+func TestIntegrationDeleteEvents(t *testing.T) {
+	c, deviceName := newTestClient(t)
+
+	event := testEvent(deviceName, 100, "1")
+	_, err := c.AddEvent(event)
+	require.NoError(t, err)
+	err = c.DeleteEventById(event.Id)
+	require.NoError(t, err)
+	_, err = c.EventById(event.Id)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+	count, err := c.ReadingCountByDeviceName(deviceName)
+	require.NoError(t, err)
+	assert.Zero(t, count, "the readings should be removed along with the event")
+
+	for i := 1; i <= 3; i++ {
+		_, err = c.AddEvent(testEvent(deviceName, int64(i), strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+	err = c.DeleteEventsByDeviceName(deviceName)
+	require.NoError(t, err)
+	eventually(t, func() bool {
+		count, err := c.EventCountByDeviceName(deviceName)
+		return err == nil && count == 0
+	}, "the events of the device should be deleted")
+}
+
+func TestIntegrationPruneEvents(t *testing.T) {
+	c, deviceName := newTestClient(t)
+
+	now := time.Now().UnixNano()
+	origins := []int64{now - int64(2*time.Hour), now - int64(time.Minute), now - int64(time.Second), now}
+	for _, origin := range origins {
+		_, err := c.AddEvent(testEvent(deviceName, origin, "1"))
+		require.NoError(t, err)
+	}
+
+	pruned, err := c.PruneEventsByDeviceName(deviceName, int64(time.Hour), 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), pruned, "the aged event and the event beyond the count should be pruned")
+	eventually(t, func() bool {
+		count, err := c.EventCountByDeviceName(deviceName)
+		return err == nil && count == 2
+	}, "the pruned events should be deleted")
+
+	events, err := c.EventsByDeviceName(0, -1, deviceName)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, now, events[0].Origin, "the newest events should be kept")
+	assert.Equal(t, origins[2], events[1].Origin, "the newest events should be kept")
+
+}
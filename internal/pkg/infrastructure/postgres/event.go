@@ -0,0 +1,265 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/lib/pq"
+)
+
+// asyncDeleteEvents deletes all events matching the specified DELETE statement, and the corresponding readings are
+// removed by the ON DELETE CASCADE constraint.  This function is implemented to be run as a separate goroutine in the
+// background, so this function return nothing.  When encountering any errors during deletion, this function will
+// simply log the error.
+func (c *Client) asyncDeleteEvents(query string, args ...interface{}) {
+	result, err := c.db.Exec(query, args...)
+	if err != nil {
+		c.loggingClient.Error(fmt.Sprintf("unable to execute event deletion.  Err: %s", err.Error()))
+		return
+	}
+	deleted, _ := result.RowsAffected()
+	c.loggingClient.Debug(fmt.Sprintf("%v events and their readings deleted", deleted))
+}
+
+// pruneEvents selects the ids of the events to prune by the age and count queries, both of which take the device
+// filter, the expire timestamp and the count limit as arguments, and then deletes these events in the background
+func (c *Client) pruneEvents(ageQuery string, countQuery string, deviceFilter interface{}, age int64, maxCount uint32) (uint32, errors.EdgeX) {
+	var expireTimestamp int64 = math.MinInt64
+	var ids []string
+	if age > 0 {
+		expireTimestamp = time.Now().UnixNano() - age
+		agedIds, edgeXerr := c.idsByQuery(ageQuery, deviceFilter, expireTimestamp)
+		if edgeXerr != nil {
+			return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		ids = append(ids, agedIds...)
+	}
+	if maxCount > 0 {
+		excessIds, edgeXerr := c.idsByQuery(countQuery, deviceFilter, expireTimestamp, maxCount)
+		if edgeXerr != nil {
+			return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		ids = append(ids, excessIds...)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	go c.asyncDeleteEvents(deleteEventsByIdsSQL, pq.Array(ids))
+	return uint32(len(ids)), nil
+}
+
+func (c *Client) idsByQuery(query string, args ...interface{}) (ids []string, edgeXerr errors.EdgeX) {
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "event id query failed", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "event id scan failed", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "event id query failed", err)
+	}
+	return ids, nil
+}
+
+func (c *Client) addEvent(e models.Event) (addedEvent models.Event, edgeXerr errors.EdgeX) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return addedEvent, errors.NewCommonEdgeX(errors.KindDatabaseError, "event creation failed", err)
+	}
+	defer func() {
+		if edgeXerr != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	e, edgeXerr = insertEvent(tx, e)
+	if edgeXerr != nil {
+		return addedEvent, edgeXerr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return addedEvent, errors.NewCommonEdgeX(errors.KindDatabaseError, "event creation failed", err)
+	}
+
+	return e, nil
+}
+
+// addEvents adds the events in a single transaction and returns the added events along with the error of each event.
+// Each event is inserted under a savepoint, so an event is rejected alone when its Id exists or its readings fail the
+// parsing, while all the other events are rejected when the transaction fails.
+func (c *Client) addEvents(events []models.Event) (addedEvents []models.Event, errs []errors.EdgeX) {
+	addedEvents = make([]models.Event, len(events))
+	errs = make([]errors.EdgeX, len(events))
+	rejectAll := func(edgeXerr errors.EdgeX) ([]models.Event, []errors.EdgeX) {
+		for i := range events {
+			if errs[i] == nil {
+				addedEvents[i] = models.Event{}
+				errs[i] = edgeXerr
+			}
+		}
+		return addedEvents, errs
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return rejectAll(errors.NewCommonEdgeX(errors.KindDatabaseError, "events creation failed", err))
+	}
+	for i, e := range events {
+		if _, err = tx.Exec(savepointEventSQL); err != nil {
+			_ = tx.Rollback()
+			return rejectAll(errors.NewCommonEdgeX(errors.KindDatabaseError, "events creation failed", err))
+		}
+		addedEvents[i], errs[i] = insertEvent(tx, e)
+		if errs[i] == nil {
+			_, err = tx.Exec(releaseSavepointEventSQL)
+		} else {
+			_, err = tx.Exec(rollbackToSavepointEventSQL)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return rejectAll(errors.NewCommonEdgeX(errors.KindDatabaseError, "events creation failed", err))
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return rejectAll(errors.NewCommonEdgeX(errors.KindDatabaseError, "events creation failed", err))
+	}
+
+	return addedEvents, errs
+}
+
+// insertEvent inserts the event and its readings in the transaction
+func insertEvent(tx *sql.Tx, e models.Event) (models.Event, errors.EdgeX) {
+	// query Event by Id first to avoid the Id conflict
+	var exists bool
+	if err := tx.QueryRow(existsEventByIdSQL, e.Id).Scan(&exists); err != nil {
+		return models.Event{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "event existence check failed", err)
+	}
+	if exists {
+		return models.Event{}, errors.NewCommonEdgeX(errors.KindDuplicateName, "Event Id exists", nil)
+	}
+
+	tags, err := marshalTags(e.Tags)
+	if err != nil {
+		return models.Event{}, errors.NewCommonEdgeX(errors.KindContractInvalid, "event parsing failed", err)
+	}
+	if _, err = tx.Exec(insertEventSQL, e.Id, e.DeviceName, e.ProfileName, e.SourceName, e.Origin, tags); err != nil {
+		return models.Event{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "event creation failed", err)
+	}
+
+	// keep the order of the readings provided by device service
+	var newReadings []models.Reading
+	for i, r := range e.Readings {
+		newReading, edgeXerr := addReading(tx, e.Id, i, r)
+		if edgeXerr != nil {
+			return models.Event{}, edgeXerr
+		}
+		newReadings = append(newReadings, newReading)
+	}
+	e.Readings = newReadings
+
+	return e, nil
+}
+
+func (c *Client) deleteEventById(id string) errors.EdgeX {
+	// query Event by Id first to ensure there is an corresponding event
+	_, edgeXerr := c.eventById(id)
+	if edgeXerr != nil {
+		return edgeXerr
+	}
+
+	if _, err := c.db.Exec(deleteEventByIdSQL, id); err != nil {
+		return errors.NewCommonEdgeX(errors.KindDatabaseError, "event delete failed", err)
+	}
+	return nil
+}
+
+func (c *Client) eventById(id string) (event models.Event, edgeXerr errors.EdgeX) {
+	event, err := scanEvent(c.db.QueryRow(eventByIdSQL, id))
+	if err == sql.ErrNoRows {
+		return event, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("fail to query event, because id: %s doesn't exist in the database", id), err)
+	} else if err != nil {
+		return event, errors.NewCommonEdgeX(errors.KindDatabaseError, "query event by id from the database failed", err)
+	}
+
+	event.Readings, edgeXerr = c.readingsByEventId(id)
+	if edgeXerr != nil {
+		return event, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+
+	return event, nil
+}
+
+// eventsByQuery executes the query with offset and limit, and then loads the readings of each event
+func (c *Client) eventsByQuery(countQuery string, query string, offset int, limit int, args ...interface{}) (events []models.Event, edgeXerr errors.EdgeX) {
+	rows, edgeXerr := c.queryWithOffsetLimit(countQuery, query, offset, limit, args...)
+	if edgeXerr != nil || rows == nil {
+		return events, edgeXerr
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return []models.Event{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "event format parsing failed from the database", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return []models.Event{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "query events from database failed", err)
+	}
+
+	for i := range events {
+		events[i].Readings, edgeXerr = c.readingsByEventId(events[i].Id)
+		if edgeXerr != nil {
+			return events, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+	}
+	return events, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEvent(row rowScanner) (models.Event, error) {
+	var e models.Event
+	var tags []byte
+	err := row.Scan(&e.Id, &e.DeviceName, &e.ProfileName, &e.SourceName, &e.Origin, &tags)
+	if err != nil {
+		return e, err
+	}
+	if len(tags) > 0 {
+		if err = json.Unmarshal(tags, &e.Tags); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// marshalTags converts the event tags into the JSONB column value, and nil tags are stored as NULL
+func marshalTags(tags map[string]interface{}) (interface{}, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	return json.Marshal(tags)
+}
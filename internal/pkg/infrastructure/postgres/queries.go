@@ -0,0 +1,57 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// countByQuery executes the specified COUNT query and returns the result
+func (c *Client) countByQuery(query string, args ...interface{}) (uint32, errors.EdgeX) {
+	var count uint32
+	err := c.db.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "failed to query the record count", err)
+	}
+	return count, nil
+}
+
+// queryWithOffsetLimit executes the query with the offset and limit appended to args.  When the query returns no row
+// but offset is positive, countQuery is used to determine whether the offset is out of range so that the same
+// RangeNotSatisfiable error as the Redis implementation could be returned.  A nil *sql.Rows along with nil error is
+// returned when limit is zero.
+func (c *Client) queryWithOffsetLimit(countQuery string, query string, offset int, limit int, args ...interface{}) (*sql.Rows, errors.EdgeX) {
+	if limit == 0 {
+		return nil, nil
+	}
+	if offset > 0 {
+		count, edgeXerr := c.countByQuery(countQuery, args...)
+		if edgeXerr != nil {
+			return nil, edgeXerr
+		}
+		if count > 0 && offset >= int(count) {
+			return nil, errors.NewCommonEdgeX(errors.KindRangeNotSatisfiable, fmt.Sprintf("query objects bounds out of range. length:%v offset:%v", count, offset), nil)
+		}
+	}
+
+	rows, err := c.db.Query(query, append(args, offset, limitArg(limit))...)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "query objects from database failed", err)
+	}
+	return rows, nil
+}
+
+// limitArg converts the limit into the LIMIT argument.  -1 limit means that clients want to retrieve all remaining
+// records after offset, which is expressed by LIMIT NULL in PostgreSQL.
+func limitArg(limit int) interface{} {
+	if limit < 0 {
+		return nil
+	}
+	return limit
+}
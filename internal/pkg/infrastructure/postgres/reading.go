@@ -0,0 +1,154 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/google/uuid"
+)
+
+var emptyBinaryValue = make([]byte, 0)
+
+// addReading inserts a reading of the specified event within the transaction, seq keeps the order of readings provided
+// by device service
+func addReading(tx *sql.Tx, eventId string, seq int, r models.Reading) (reading models.Reading, edgeXerr errors.EdgeX) {
+	var m []byte
+	var err error
+	var baseReading *models.BaseReading
+	switch newReading := r.(type) {
+	case models.BinaryReading:
+		// Clear the binary data since we do not want to persist binary data to save on storage.
+		newReading.BinaryValue = emptyBinaryValue
+
+		baseReading = &newReading.BaseReading
+		if edgeXerr = checkReadingValue(baseReading); edgeXerr != nil {
+			return nil, edgeXerr
+		}
+		m, err = json.Marshal(newReading)
+		reading = newReading
+	case models.SimpleReading:
+		baseReading = &newReading.BaseReading
+		if edgeXerr = checkReadingValue(baseReading); edgeXerr != nil {
+			return nil, edgeXerr
+		}
+		m, err = json.Marshal(newReading)
+		reading = newReading
+	case models.ObjectReading:
+		baseReading = &newReading.BaseReading
+		if edgeXerr = checkReadingValue(baseReading); edgeXerr != nil {
+			return nil, edgeXerr
+		}
+		m, err = json.Marshal(newReading)
+		reading = newReading
+	default:
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "unsupported reading type", nil)
+	}
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "reading parsing failed", err)
+	}
+
+	_, err = tx.Exec(insertReadingSQL, baseReading.Id, eventId, seq, baseReading.DeviceName, baseReading.ProfileName,
+		baseReading.ResourceName, baseReading.Origin, baseReading.ValueType, m)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "reading creation failed", err)
+	}
+
+	return reading, nil
+}
+
+func checkReadingValue(b *models.BaseReading) errors.EdgeX {
+	// check if id is a valid uuid
+	if b.Id == "" {
+		b.Id = uuid.New().String()
+	} else {
+		_, err := uuid.Parse(b.Id)
+		if err != nil {
+			return errors.NewCommonEdgeX(errors.KindInvalidId, "uuid parsing failed", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) readingsByEventId(eventId string) (readings []models.Reading, edgeXerr errors.EdgeX) {
+	rows, err := c.db.Query(readingsByEventIdSQL, eventId)
+	if err != nil {
+		return readings, errors.NewCommonEdgeX(errors.KindDatabaseError, "query readings by event id from database failed", err)
+	}
+	return scanReadings(rows)
+}
+
+// readingsByQuery executes the query with offset and limit, and converts the returned rows into readings
+func (c *Client) readingsByQuery(countQuery string, query string, offset int, limit int, args ...interface{}) (readings []models.Reading, edgeXerr errors.EdgeX) {
+	rows, edgeXerr := c.queryWithOffsetLimit(countQuery, query, offset, limit, args...)
+	if edgeXerr != nil || rows == nil {
+		return readings, edgeXerr
+	}
+	return scanReadings(rows)
+}
+
+// scanReadings reads the content column of each row and closes rows afterward
+func scanReadings(rows *sql.Rows) (readings []models.Reading, edgeXerr errors.EdgeX) {
+	defer rows.Close()
+
+	var objects [][]byte
+	for rows.Next() {
+		var content []byte
+		if err := rows.Scan(&content); err != nil {
+			return []models.Reading{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "reading format parsing failed from the database", err)
+		}
+		objects = append(objects, content)
+	}
+	if err := rows.Err(); err != nil {
+		return []models.Reading{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "query readings from database failed", err)
+	}
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	return convertObjectsToReadings(objects)
+}
+
+func convertObjectsToReadings(objects [][]byte) (readings []models.Reading, edgeXerr errors.EdgeX) {
+	readings = make([]models.Reading, len(objects))
+	var alias struct {
+		ValueType string
+	}
+	for i, in := range objects {
+		err := json.Unmarshal(in, &alias)
+		if err != nil {
+			return []models.Reading{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "reading format parsing failed from the database", err)
+		}
+		if alias.ValueType == common.ValueTypeBinary {
+			var binaryReading models.BinaryReading
+			err = json.Unmarshal(in, &binaryReading)
+			if err != nil {
+				return []models.Reading{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "binary reading format parsing failed from the database", err)
+			}
+			readings[i] = binaryReading
+		} else if alias.ValueType == common.ValueTypeObject {
+			var objectReading models.ObjectReading
+			err = json.Unmarshal(in, &objectReading)
+			if err != nil {
+				return []models.Reading{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "object reading format parsing failed from the database", err)
+			}
+			readings[i] = objectReading
+		} else {
+			var simpleReading models.SimpleReading
+			err = json.Unmarshal(in, &simpleReading)
+			if err != nil {
+				return []models.Reading{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "simple reading format parsing failed from the database", err)
+			}
+			readings[i] = simpleReading
+		}
+	}
+	return readings, nil
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	exampleUUID      = "82eb2e26-0f24-48aa-ae4c-de9dac3fb9bc"
+	testDeviceName   = "testDeviceName"
+	testProfileName  = "testProfileName"
+	testResourceName = "testResourceName"
+)
+
+func baseReadingData(valueType string) models.BaseReading {
+	return models.BaseReading{
+		Id:           exampleUUID,
+		Origin:       1616728256236000000,
+		DeviceName:   testDeviceName,
+		ProfileName:  testProfileName,
+		ResourceName: testResourceName,
+		ValueType:    valueType,
+	}
+}
+
+func TestConvertObjectsToReadings(t *testing.T) {
+	simpleReading := models.SimpleReading{BaseReading: baseReadingData(common.ValueTypeString), Value: "123"}
+	binaryReading := models.BinaryReading{BaseReading: baseReadingData(common.ValueTypeBinary), BinaryValue: make([]byte, 0), MediaType: "image"}
+	objectReading := models.ObjectReading{BaseReading: baseReadingData(common.ValueTypeObject), ObjectValue: map[string]interface{}{"f1": "ABC", "f2": float64(123)}}
+
+	var readingsData [][]byte
+	for _, r := range []models.Reading{simpleReading, binaryReading, objectReading} {
+		data, err := json.Marshal(r)
+		require.NoError(t, err)
+		readingsData = append(readingsData, data)
+	}
+
+	readings, err := convertObjectsToReadings(readingsData)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Reading{simpleReading, binaryReading, objectReading}, readings)
+}
+
+func TestCheckReadingValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		errorExpected bool
+	}{
+		{"valid id", exampleUUID, false},
+		{"empty id is generated", "", false},
+		{"invalid id", "not-a-uuid", true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			reading := baseReadingData(common.ValueTypeInt32)
+			reading.Id = testCase.id
+			err := checkReadingValue(&reading)
+			if testCase.errorExpected {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, reading.Id)
+		})
+	}
+}
+
+func TestLimitArg(t *testing.T) {
+	assert.Nil(t, limitArg(-1))
+	assert.Equal(t, 20, limitArg(20))
+}
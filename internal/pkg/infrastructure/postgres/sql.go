@@ -0,0 +1,107 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+// Table names used by core-data.  All tables live in the core_data schema so that the service can share a database
+// instance with other applications.
+const (
+	coreDataSchema = "core_data"
+	eventTable     = coreDataSchema + ".event"
+	readingTable   = coreDataSchema + ".reading"
+)
+
+// schemaStatements creates the core-data schema, tables and indexes when they do not exist yet.  Readings reference
+// their parent event with ON DELETE CASCADE, so removing an event always removes its readings as well.
+var schemaStatements = []string{
+	`CREATE SCHEMA IF NOT EXISTS ` + coreDataSchema,
+	`CREATE TABLE IF NOT EXISTS ` + eventTable + ` (
+		id           UUID PRIMARY KEY,
+		device_name  TEXT NOT NULL,
+		profile_name TEXT NOT NULL,
+		source_name  TEXT NOT NULL,
+		origin       BIGINT NOT NULL,
+		tags         JSONB
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_origin ON ` + eventTable + ` (origin)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_device_name_origin ON ` + eventTable + ` (device_name, origin)`,
+	`CREATE TABLE IF NOT EXISTS ` + readingTable + ` (
+		id            UUID PRIMARY KEY,
+		event_id      UUID NOT NULL REFERENCES ` + eventTable + ` (id) ON DELETE CASCADE,
+		seq           INTEGER NOT NULL,
+		device_name   TEXT NOT NULL,
+		profile_name  TEXT NOT NULL,
+		resource_name TEXT NOT NULL,
+		origin        BIGINT NOT NULL,
+		value_type    TEXT NOT NULL,
+		content       JSONB NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_event_id_seq ON ` + readingTable + ` (event_id, seq)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_origin ON ` + readingTable + ` (origin)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_device_name_origin ON ` + readingTable + ` (device_name, origin)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_resource_name_origin ON ` + readingTable + ` (resource_name, origin)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_device_resource_origin ON ` + readingTable + ` (device_name, resource_name, origin)`,
+}
+
+// Event queries
+const (
+	insertEventSQL = `INSERT INTO ` + eventTable + ` (id, device_name, profile_name, source_name, origin, tags) VALUES ($1, $2, $3, $4, $5, $6)`
+	selectEventSQL = `SELECT id, device_name, profile_name, source_name, origin, tags FROM ` + eventTable
+
+	// the savepoint to reject a single event of the batch insertion
+	savepointEventSQL           = `SAVEPOINT add_event`
+	releaseSavepointEventSQL    = `RELEASE SAVEPOINT add_event`
+	rollbackToSavepointEventSQL = `ROLLBACK TO SAVEPOINT add_event`
+
+	eventByIdSQL            = selectEventSQL + ` WHERE id = $1`
+	existsEventByIdSQL      = `SELECT EXISTS(SELECT 1 FROM ` + eventTable + ` WHERE id = $1)`
+	deleteEventByIdSQL      = `DELETE FROM ` + eventTable + ` WHERE id = $1`
+	allEventsSQL            = selectEventSQL + ` ORDER BY origin DESC OFFSET $1 LIMIT $2`
+	eventsByDeviceNameSQL   = selectEventSQL + ` WHERE device_name = $1 ORDER BY origin DESC OFFSET $2 LIMIT $3`
+	eventsByTimeRangeSQL    = selectEventSQL + ` WHERE origin >= $1 AND origin <= $2 ORDER BY origin DESC OFFSET $3 LIMIT $4`
+	eventCountSQL           = `SELECT COUNT(*) FROM ` + eventTable
+	eventCountByDeviceSQL   = eventCountSQL + ` WHERE device_name = $1`
+	eventCountByTimeSQL     = eventCountSQL + ` WHERE origin >= $1 AND origin <= $2`
+	deleteEventsByDeviceSQL = `DELETE FROM ` + eventTable + ` WHERE device_name = $1`
+	deleteEventsByOriginSQL = `DELETE FROM ` + eventTable + ` WHERE origin < $1`
+	deleteEventsByIdsSQL    = `DELETE FROM ` + eventTable + ` WHERE id = ANY($1)`
+
+	// The prune queries select the ids of the events which are older than $2, or which are not among the newest $3
+	// events that are not older than $2, either excluding the devices of $1 or for the device of $1 only.
+	eventIdsByOriginExcludingDevicesSQL         = `SELECT id FROM ` + eventTable + ` WHERE NOT (device_name = ANY($1)) AND origin < $2`
+	eventIdsBeyondCountExcludingDevicesSQL      = `SELECT id FROM ` + eventTable + ` WHERE NOT (device_name = ANY($1)) AND origin >= $2 ORDER BY origin DESC OFFSET $3`
+	eventIdsByDeviceNameAndOriginSQL            = `SELECT id FROM ` + eventTable + ` WHERE device_name = $1 AND origin < $2`
+	eventIdsBeyondCountByDeviceNameAndOriginSQL = `SELECT id FROM ` + eventTable + ` WHERE device_name = $1 AND origin >= $2 ORDER BY origin DESC OFFSET $3`
+)
+
+// Reading queries
+const (
+	insertReadingSQL = `INSERT INTO ` + readingTable + ` (id, event_id, seq, device_name, profile_name, resource_name, origin, value_type, content) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	selectReadingSQL = `SELECT content FROM ` + readingTable
+
+	readingsByEventIdSQL                       = selectReadingSQL + ` WHERE event_id = $1 ORDER BY seq`
+	allReadingsSQL                             = selectReadingSQL + ` ORDER BY origin DESC OFFSET $1 LIMIT $2`
+	readingsByTimeRangeSQL                     = selectReadingSQL + ` WHERE origin >= $1 AND origin <= $2 ORDER BY origin DESC OFFSET $3 LIMIT $4`
+	readingsByResourceNameSQL                  = selectReadingSQL + ` WHERE resource_name = $1 ORDER BY origin DESC OFFSET $2 LIMIT $3`
+	readingsByDeviceNameSQL                    = selectReadingSQL + ` WHERE device_name = $1 ORDER BY origin DESC OFFSET $2 LIMIT $3`
+	readingsByDeviceNameAndResourceNameSQL     = selectReadingSQL + ` WHERE device_name = $1 AND resource_name = $2 ORDER BY origin DESC OFFSET $3 LIMIT $4`
+	readingsByDeviceNameAndResourceNameTimeSQL = selectReadingSQL + ` WHERE device_name = $1 AND resource_name = $2 AND origin >= $3 AND origin <= $4 ORDER BY origin DESC OFFSET $5 LIMIT $6`
+	// The cursor queries order the readings by id as well as origin, so that the readings of the same origin are never
+	// skipped or repeated across the pages however the readings are added or removed meanwhile.
+	readingsByDeviceNameAndResourceNameUntilSQL     = selectReadingSQL + ` WHERE device_name = $1 AND resource_name = $2 AND origin >= $3 AND origin <= $4 ORDER BY origin DESC, id DESC LIMIT $5`
+	readingsByDeviceNameAndResourceNameBeforeIdSQL  = selectReadingSQL + ` WHERE device_name = $1 AND resource_name = $2 AND origin >= $3 AND (origin, id) < ($4, $5) ORDER BY origin DESC, id DESC LIMIT $6`
+	readingsByDeviceNameAndResourceNamesTimeSQL     = selectReadingSQL + ` WHERE device_name = $1 AND resource_name = ANY($2) AND origin >= $3 AND origin <= $4 ORDER BY origin DESC OFFSET $5 LIMIT $6`
+	readingsByResourceNameAndTimeRangeSQL           = selectReadingSQL + ` WHERE resource_name = $1 AND origin >= $2 AND origin <= $3 ORDER BY origin DESC OFFSET $4 LIMIT $5`
+	readingsByDeviceNameAndTimeRangeSQL             = selectReadingSQL + ` WHERE device_name = $1 AND origin >= $2 AND origin <= $3 ORDER BY origin DESC OFFSET $4 LIMIT $5`
+	readingCountSQL                                 = `SELECT COUNT(*) FROM ` + readingTable
+	readingCountByDeviceNameSQL                     = readingCountSQL + ` WHERE device_name = $1`
+	readingCountByResourceNameSQL                   = readingCountSQL + ` WHERE resource_name = $1`
+	readingCountByResourceNameAndTimeRangeSQL       = readingCountSQL + ` WHERE resource_name = $1 AND origin >= $2 AND origin <= $3`
+	readingCountByDeviceNameAndResourceNameSQL      = readingCountSQL + ` WHERE device_name = $1 AND resource_name = $2`
+	readingCountByDeviceNameAndResourceNameTimeSQL  = readingCountSQL + ` WHERE device_name = $1 AND resource_name = $2 AND origin >= $3 AND origin <= $4`
+	readingCountByDeviceNameAndResourceNamesTimeSQL = readingCountSQL + ` WHERE device_name = $1 AND resource_name = ANY($2) AND origin >= $3 AND origin <= $4`
+	readingCountByTimeRangeSQL                      = readingCountSQL + ` WHERE origin >= $1 AND origin <= $2`
+	readingCountByDeviceNameAndTimeRangeSQL         = readingCountSQL + ` WHERE device_name = $1 AND origin >= $2 AND origin <= $3`
+)
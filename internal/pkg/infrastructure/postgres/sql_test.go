@@ -0,0 +1,386 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	stdErrors "errors"
+	"io"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	beginSQL    = "BEGIN"
+	commitSQL   = "COMMIT"
+	rollbackSQL = "ROLLBACK"
+)
+
+var errTestDatabase = stdErrors.New("connection reset")
+
+// statement is a statement the fake database expects to be executed next, along with its result.  The arguments are
+// only checked when args isn't nil.
+type statement struct {
+	query   string
+	args    []driver.Value
+	columns []string
+	rows    [][]driver.Value
+	err     error
+}
+
+// fakeDatabase is a database/sql driver which executes the expected statements in order, so that the SQL paths of the
+// client are tested without PostgreSQL
+type fakeDatabase struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	expected []statement
+}
+
+func (d *fakeDatabase) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{database: d}, nil
+}
+
+func (d *fakeDatabase) Driver() driver.Driver {
+	return nil
+}
+
+// execute checks the query and the arguments against the next expected statement, and returns the statement
+func (d *fakeDatabase) execute(query string, args []driver.NamedValue) statement {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if !assert.NotEmpty(d.t, d.expected, "unexpected statement %s", query) {
+		return statement{err: stdErrors.New("unexpected statement")}
+	}
+	expected := d.expected[0]
+	d.expected = d.expected[1:]
+	assert.Equal(d.t, expected.query, query)
+	if expected.args != nil {
+		values := make([]driver.Value, len(args))
+		for i, arg := range args {
+			values[i] = arg.Value
+		}
+		assert.Equal(d.t, expected.args, values, "the arguments of %s", query)
+	}
+	return expected
+}
+
+func (d *fakeDatabase) remaining() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return len(d.expected)
+}
+
+type fakeConn struct {
+	database *fakeDatabase
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, stdErrors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	if err := c.database.execute(beginSQL, nil).err; err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	return c.database.execute(commitSQL, nil).err
+}
+
+func (c *fakeConn) Rollback() error {
+	return c.database.execute(rollbackSQL, nil).err
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	s := c.database.execute(query, args)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return driver.RowsAffected(len(s.rows)), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	s := c.database.execute(query, args)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &fakeRows{columns: s.columns, rows: s.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// newFakeClient returns the client of the fake database expecting the statements, all of which are expected to be
+// executed by the end of the test
+func newFakeClient(t *testing.T, expected ...statement) *Client {
+	database := &fakeDatabase{t: t, expected: expected}
+	c := &Client{db: sql.OpenDB(database), timeout: time.Second, loggingClient: logger.NewMockClient()}
+	t.Cleanup(func() {
+		// the deletions run in the background
+		assert.Eventually(t, func() bool { return database.remaining() == 0 }, time.Second, time.Millisecond,
+			"all the expected statements should be executed")
+		c.CloseSession()
+	})
+	return c
+}
+
+// query expects a query returning the rows of the columns
+func query(sql string, args []driver.Value, columns []string, rows ...[]driver.Value) statement {
+	return statement{query: sql, args: args, columns: columns, rows: rows}
+}
+
+func exec(sql string, args ...driver.Value) statement {
+	return statement{query: sql, args: args}
+}
+
+func countQuery(sql string, count int64, args ...driver.Value) statement {
+	return query(sql, args, []string{"count"}, []driver.Value{count})
+}
+
+var eventColumns = []string{"id", "device_name", "profile_name", "source_name", "origin", "tags"}
+
+func testReadingContent(t *testing.T, r models.SimpleReading) []byte {
+	content, err := json.Marshal(r)
+	require.NoError(t, err)
+	return content
+}
+
+func TestConnectionString(t *testing.T) {
+	config := db.Configuration{
+		Host:         "localhost",
+		Port:         5432,
+		DatabaseName: "edgex",
+		Username:     "core data",
+		Password:     "p@ss word' sslmode=disable",
+	}
+	u, err := url.Parse(connectionString(config, 1500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", u.Scheme)
+	assert.Equal(t, "localhost:5432", u.Host)
+	assert.Equal(t, "/edgex", u.Path)
+	password, _ := u.User.Password()
+	assert.Equal(t, config.Username, u.User.Username())
+	assert.Equal(t, config.Password, password, "the password should be escaped rather than add an option")
+	assert.Equal(t, url.Values{"sslmode": {defaultSSLMode}, "connect_timeout": {"2"}}, u.Query())
+
+	config.SSLMode = "verify-full"
+	u, err = url.Parse(connectionString(config, time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "verify-full", u.Query().Get("sslmode"))
+
+	_, err = pq.ParseURL(connectionString(config, time.Second))
+	assert.NoError(t, err, "the URL should be accepted by the driver")
+}
+
+func TestAddEventSQL(t *testing.T) {
+	reading := models.SimpleReading{BaseReading: baseReadingData(common.ValueTypeString), Value: "1"}
+	event := models.Event{Id: exampleUUID, DeviceName: testDeviceName, ProfileName: testProfileName, SourceName: testResourceName, Origin: 100, Readings: []models.Reading{reading}}
+
+	c := newFakeClient(t,
+		exec(beginSQL),
+		query(existsEventByIdSQL, []driver.Value{exampleUUID}, []string{"exists"}, []driver.Value{false}),
+		exec(insertEventSQL, exampleUUID, testDeviceName, testProfileName, testResourceName, int64(100), nil),
+		exec(insertReadingSQL, exampleUUID, exampleUUID, int64(0), testDeviceName, testProfileName, testResourceName, reading.Origin, common.ValueTypeString, testReadingContent(t, reading)),
+		exec(commitSQL),
+		exec(beginSQL),
+		query(existsEventByIdSQL, []driver.Value{exampleUUID}, []string{"exists"}, []driver.Value{true}),
+		exec(rollbackSQL),
+	)
+
+	added, err := c.AddEvent(event)
+	require.NoError(t, err)
+	assert.Equal(t, event, added)
+
+	_, err = c.AddEvent(event)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindDuplicateName, errors.Kind(err), "the existing event should be rejected")
+}
+
+func TestAddEventsSQL(t *testing.T) {
+	existing := models.Event{Id: exampleUUID, DeviceName: testDeviceName, Origin: 100}
+	added := models.Event{Id: "0d8f4c8a-9b6f-4d8e-9d5c-3f0e8c1c2a11", DeviceName: testDeviceName, Origin: 200}
+
+	c := newFakeClient(t,
+		exec(beginSQL),
+		exec(savepointEventSQL),
+		query(existsEventByIdSQL, []driver.Value{existing.Id}, []string{"exists"}, []driver.Value{true}),
+		exec(rollbackToSavepointEventSQL),
+		exec(savepointEventSQL),
+		query(existsEventByIdSQL, []driver.Value{added.Id}, []string{"exists"}, []driver.Value{false}),
+		exec(insertEventSQL, added.Id, testDeviceName, "", "", int64(200), nil),
+		exec(releaseSavepointEventSQL),
+		exec(commitSQL),
+		exec(beginSQL),
+		statement{query: savepointEventSQL, err: errTestDatabase},
+		exec(rollbackSQL),
+	)
+
+	events, errs := c.AddEvents([]models.Event{existing, {Id: "invalid"}, added})
+	require.Len(t, errs, 3)
+	assert.Equal(t, errors.KindDuplicateName, errors.Kind(errs[0]), "the existing event should be rejected alone")
+	assert.Equal(t, errors.KindInvalidId, errors.Kind(errs[1]), "the invalid id should be rejected before the transaction")
+	assert.NoError(t, errs[2])
+	assert.Equal(t, added, events[2])
+
+	_, errs = c.AddEvents([]models.Event{added})
+	require.Len(t, errs, 1)
+	assert.Equal(t, errors.KindDatabaseError, errors.Kind(errs[0]), "the events should be rejected when the transaction fails")
+}
+
+func TestEventByIdSQL(t *testing.T) {
+	reading := models.SimpleReading{BaseReading: baseReadingData(common.ValueTypeString), Value: "1"}
+	c := newFakeClient(t,
+		query(eventByIdSQL, []driver.Value{exampleUUID}, eventColumns,
+			[]driver.Value{exampleUUID, testDeviceName, testProfileName, testResourceName, int64(100), []byte(`{"floor":"1"}`)}),
+		query(readingsByEventIdSQL, []driver.Value{exampleUUID}, []string{"content"}, []driver.Value{testReadingContent(t, reading)}),
+		query(eventByIdSQL, []driver.Value{exampleUUID}, eventColumns),
+		statement{query: eventByIdSQL, err: errTestDatabase},
+	)
+
+	event, err := c.EventById(exampleUUID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Event{Id: exampleUUID, DeviceName: testDeviceName, ProfileName: testProfileName, SourceName: testResourceName,
+		Origin: 100, Tags: map[string]interface{}{"floor": "1"}, Readings: []models.Reading{reading}}, event)
+
+	_, err = c.EventById(exampleUUID)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+	_, err = c.EventById(exampleUUID)
+	assert.Equal(t, errors.KindDatabaseError, errors.Kind(err))
+}
+
+func TestQueryWithOffsetLimitSQL(t *testing.T) {
+	c := newFakeClient(t,
+		countQuery(eventCountByDeviceSQL, 2, testDeviceName),
+		countQuery(eventCountByDeviceSQL, 3, testDeviceName),
+		query(eventsByDeviceNameSQL, []driver.Value{testDeviceName, int64(2), nil}, eventColumns,
+			[]driver.Value{exampleUUID, testDeviceName, testProfileName, testResourceName, int64(100), nil}),
+		query(readingsByEventIdSQL, []driver.Value{exampleUUID}, []string{"content"}),
+		query(readingsByDeviceNameSQL, []driver.Value{testDeviceName, int64(0), int64(10)}, []string{"content"}),
+	)
+
+	_, err := c.EventsByDeviceName(2, 10, testDeviceName)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindRangeNotSatisfiable, errors.Kind(err), "the offset beyond the count should be rejected")
+
+	events, err := c.EventsByDeviceName(2, -1, testDeviceName)
+	require.NoError(t, err)
+	require.Len(t, events, 1, "-1 limit should be passed as LIMIT NULL")
+	assert.Nil(t, events[0].Tags)
+
+	events, err = c.EventsByDeviceName(0, 0, testDeviceName)
+	require.NoError(t, err)
+	assert.Empty(t, events, "0 limit should query nothing")
+
+	readings, err := c.ReadingsByDeviceName(0, 10, testDeviceName)
+	require.NoError(t, err)
+	assert.Empty(t, readings)
+}
+
+func TestReadingsBeforeCursorSQL(t *testing.T) {
+	reading := models.SimpleReading{BaseReading: baseReadingData(common.ValueTypeString), Value: "1"}
+	c := newFakeClient(t,
+		query(readingsByDeviceNameAndResourceNameUntilSQL, []driver.Value{testDeviceName, testResourceName, int64(0), int64(300), int64(1)},
+			[]string{"content"}, []driver.Value{testReadingContent(t, reading)}),
+		query(readingsByDeviceNameAndResourceNameBeforeIdSQL, []driver.Value{testDeviceName, testResourceName, int64(0), int64(300), exampleUUID, int64(1)},
+			[]string{"content"}),
+	)
+
+	readings, err := c.ReadingsByDeviceNameAndResourceNameBeforeCursor(testDeviceName, testResourceName, 0, 300, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Reading{reading}, readings)
+	readings, err = c.ReadingsByDeviceNameAndResourceNameBeforeCursor(testDeviceName, testResourceName, 0, 300, exampleUUID, 1)
+	require.NoError(t, err)
+	assert.Empty(t, readings)
+
+	_, err = c.ReadingsByDeviceNameAndResourceNameBeforeCursor(testDeviceName, testResourceName, 0, 300, "invalid", 1)
+	assert.Equal(t, errors.KindInvalidId, errors.Kind(err), "the invalid cursor id should be rejected without a query")
+}
+
+func TestPruneEventsSQL(t *testing.T) {
+	ids := []string{exampleUUID, "0d8f4c8a-9b6f-4d8e-9d5c-3f0e8c1c2a11"}
+	idsValue, err := pq.Array(ids).Value()
+	require.NoError(t, err)
+
+	c := newFakeClient(t,
+		query(eventIdsBeyondCountExcludingDevicesSQL, nil, []string{"id"}),
+		statement{query: eventIdsByOriginExcludingDevicesSQL, err: errTestDatabase},
+		query(eventIdsByDeviceNameAndOriginSQL, nil, []string{"id"}, []driver.Value{ids[0]}),
+		query(eventIdsBeyondCountByDeviceNameAndOriginSQL, nil, []string{"id"}, []driver.Value{ids[1]}),
+		exec(deleteEventsByIdsSQL, idsValue),
+	)
+
+	pruned, err := c.PruneEvents(0, 2, nil)
+	require.NoError(t, err)
+	assert.Zero(t, pruned, "nothing should be deleted when no event is selected")
+
+	_, err = c.PruneEvents(int64(time.Hour), 0, []string{testDeviceName})
+	assert.Equal(t, errors.KindDatabaseError, errors.Kind(err))
+
+	pruned, err = c.PruneEventsByDeviceName(testDeviceName, int64(time.Hour), 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), pruned, "the aged events and the events beyond the count should be deleted together")
+}
+
+func TestCountByQuerySQL(t *testing.T) {
+	reading := models.SimpleReading{BaseReading: baseReadingData(common.ValueTypeString), Value: "1"}
+	resourceNames, err := pq.Array([]string{testResourceName}).Value()
+	require.NoError(t, err)
+	c := newFakeClient(t,
+		countQuery(readingCountByDeviceNameAndResourceNamesTimeSQL, 1, testDeviceName, resourceNames, int64(0), int64(100)),
+		query(readingsByDeviceNameAndResourceNamesTimeSQL, []driver.Value{testDeviceName, resourceNames, int64(0), int64(100), int64(0), int64(10)},
+			[]string{"content"}, []driver.Value{testReadingContent(t, reading)}),
+		statement{query: readingCountSQL, err: errTestDatabase},
+	)
+
+	readings, count, err := c.ReadingsByDeviceNameAndResourceNamesAndTimeRange(testDeviceName, []string{testResourceName}, 0, 100, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), count)
+	assert.Equal(t, []models.Reading{reading}, readings)
+
+	_, err = c.ReadingTotalCount()
+	assert.Equal(t, errors.KindDatabaseError, errors.Kind(err))
+}
@@ -7,9 +7,11 @@ package redis
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
 	redisClient "github.com/edgexfoundry/edgex-go/internal/pkg/db/redis"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	model "github.com/edgexfoundry/go-mod-core-contracts/v2/models"
@@ -20,6 +22,11 @@ import (
 type Client struct {
 	*redisClient.Client
 	loggingClient logger.LoggingClient
+
+	// deviceSearchIndexed tells whether the devices stored before the device search was introduced have been indexed,
+	// which is done once by the first search under deviceSearchIndexMutex
+	deviceSearchIndexMutex sync.Mutex
+	deviceSearchIndexed    bool
 }
 
 func NewClient(config db.Configuration, logger logger.LoggingClient) (*Client, errors.EdgeX) {
@@ -34,6 +41,20 @@ func NewClient(config db.Configuration, logger logger.LoggingClient) (*Client, e
 	return dc, nil
 }
 
+// NewEmbeddedClient returns a client which stores data in the embedded single-file database rather than a Redis
+// server.  The DatabaseName of the configuration is the path of the database file.
+func NewEmbeddedClient(config db.Configuration, logger logger.LoggingClient) (*Client, errors.EdgeX) {
+	var err error
+	dc := &Client{}
+	dc.Client, err = redisClient.NewEmbeddedClient(config, logger)
+	dc.loggingClient = logger
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "embedded database client creation failed", err)
+	}
+
+	return dc, nil
+}
+
 // AddEvent adds a new event
 func (c *Client) AddEvent(e model.Event) (model.Event, errors.EdgeX) {
 	conn := c.Pool.Get()
@@ -49,6 +70,39 @@ func (c *Client) AddEvent(e model.Event) (model.Event, errors.EdgeX) {
 	return addEvent(conn, e)
 }
 
+// AddEvents adds the events in a single transaction and returns the added events along with the error of each event,
+// which is nil when the event is added
+func (c *Client) AddEvents(events []model.Event) ([]model.Event, []errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	var validEvents []model.Event
+	var validIndexes []int
+	addedEvents := make([]model.Event, len(events))
+	errs := make([]errors.EdgeX, len(events))
+	for i, e := range events {
+		if e.Id != "" {
+			_, err := uuid.Parse(e.Id)
+			if err != nil {
+				errs[i] = errors.NewCommonEdgeX(errors.KindInvalidId, "uuid parsing failed", err)
+				continue
+			}
+		}
+		validEvents = append(validEvents, e)
+		validIndexes = append(validIndexes, i)
+	}
+	if len(validEvents) == 0 {
+		return addedEvents, errs
+	}
+
+	added, addErrs := addEvents(conn, validEvents)
+	for i, index := range validIndexes {
+		addedEvents[index] = added[i]
+		errs[index] = addErrs[i]
+	}
+	return addedEvents, errs
+}
+
 // EventById gets an event by id
 func (c *Client) EventById(id string) (event model.Event, edgeXerr errors.EdgeX) {
 	conn := c.Pool.Get()
@@ -75,8 +129,8 @@ func (c *Client) DeleteEventById(id string) (edgeXerr errors.EdgeX) {
 	return
 }
 
-// Add a new device profle
-func (c *Client) AddDeviceProfile(dp model.DeviceProfile) (model.DeviceProfile, errors.EdgeX) {
+// Add a new device profle, and record it as the first revision of the device profile
+func (c *Client) AddDeviceProfile(dp model.DeviceProfile, correlationId string) (model.DeviceProfile, errors.EdgeX) {
 	conn := c.Pool.Get()
 	defer conn.Close()
 
@@ -89,14 +143,70 @@ func (c *Client) AddDeviceProfile(dp model.DeviceProfile) (model.DeviceProfile,
 		dp.Id = uuid.New().String()
 	}
 
-	return addDeviceProfile(conn, dp)
+	return addDeviceProfile(conn, dp, correlationId)
+}
+
+// UpdateDeviceProfile updates a new device profile, and records it as the next revision of the device profile
+func (c *Client) UpdateDeviceProfile(dp model.DeviceProfile, correlationId string) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return updateDeviceProfile(conn, dp, correlationId, nil)
+}
+
+// UpdateDeviceProfileWithRevision updates a device profile only if its latest revision is the specified one, and
+// records it as the next revision of the device profile
+func (c *Client) UpdateDeviceProfileWithRevision(dp model.DeviceProfile, revision uint32, correlationId string) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return updateDeviceProfile(conn, dp, correlationId, &revision)
 }
 
-// UpdateDeviceProfile updates a new device profile
-func (c *Client) UpdateDeviceProfile(dp model.DeviceProfile) errors.EdgeX {
+// DeviceProfileAndRevisionByName gets a device profile and its latest revision number by name
+func (c *Client) DeviceProfileAndRevisionByName(name string) (model.DeviceProfile, uint32, errors.EdgeX) {
 	conn := c.Pool.Get()
 	defer conn.Close()
-	return updateDeviceProfile(conn, dp)
+
+	dp, revision, edgeXerr := deviceProfileAndRevisionByName(conn, name)
+	if edgeXerr != nil {
+		return dp, revision, errors.NewCommonEdgeX(errors.Kind(edgeXerr), fmt.Sprintf("fail to query device profile and its revision by name %s", name), edgeXerr)
+	}
+	return dp, revision, nil
+}
+
+// DeviceProfileRevision gets the revision of the device profile by name and revision number
+func (c *Client) DeviceProfileRevision(name string, revision uint32) (pkgModels.DeviceProfileRevision, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	deviceProfileRevision, edgeXerr := deviceProfileRevision(conn, name, revision)
+	if edgeXerr != nil {
+		return deviceProfileRevision, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return deviceProfileRevision, nil
+}
+
+// DeviceProfileRevisions query the revisions of the device profile from the latest with offset and limit
+func (c *Client) DeviceProfileRevisions(offset int, limit int, name string) ([]pkgModels.DeviceProfileRevision, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	revisions, edgeXerr := deviceProfileRevisions(conn, offset, limit, name)
+	if edgeXerr != nil {
+		return revisions, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return revisions, nil
+}
+
+// DeviceProfileRevisionCount returns the count of the revisions of the device profile
+func (c *Client) DeviceProfileRevisionCount(name string) (uint32, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	count, edgeXerr := getMemberNumber(conn, ZCARD, deviceProfileRevisionsKey(name))
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return count, nil
 }
 
 // DeviceProfileNameExists checks the device profile exists by name
@@ -181,7 +291,54 @@ func (c *Client) DeviceServiceNameExists(name string) (bool, errors.EdgeX) {
 func (c *Client) UpdateDeviceService(ds model.DeviceService) errors.EdgeX {
 	conn := c.Pool.Get()
 	defer conn.Close()
-	return updateDeviceService(conn, ds)
+	return updateDeviceService(conn, ds, nil)
+}
+
+// UpdateDeviceServiceWithRevision updates a device service only if it is at the specified revision
+func (c *Client) UpdateDeviceServiceWithRevision(ds model.DeviceService, revision uint64) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return updateDeviceService(conn, ds, &revision)
+}
+
+// UpdateDeviceServiceLastConnected updates the LastConnected of the device service without changing its revision
+func (c *Client) UpdateDeviceServiceLastConnected(name string, lastConnected int64) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return updateDeviceServiceLastConnected(conn, name, lastConnected)
+}
+
+// AddDevicesDownByHealthCheck records the devices marked DOWN by the health check of the device service
+func (c *Client) AddDevicesDownByHealthCheck(serviceName string, deviceNames []string) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return addDevicesDownByHealthCheck(conn, serviceName, deviceNames)
+}
+
+// DevicesDownByHealthCheck returns the names of the devices marked DOWN by the health check of the device service
+func (c *Client) DevicesDownByHealthCheck(serviceName string) ([]string, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return devicesDownByHealthCheck(conn, serviceName)
+}
+
+// DeleteDevicesDownByHealthCheck forgets the devices marked DOWN by the health check of the device service
+func (c *Client) DeleteDevicesDownByHealthCheck(serviceName string) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+	return deleteDevicesDownByHealthCheck(conn, serviceName)
+}
+
+// DeviceServiceAndRevisionByName gets a device service and its revision by name
+func (c *Client) DeviceServiceAndRevisionByName(name string) (model.DeviceService, uint64, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	ds, revision, edgeXerr := deviceServiceAndRevisionByName(conn, name)
+	if edgeXerr != nil {
+		return ds, revision, errors.NewCommonEdgeX(errors.Kind(edgeXerr), fmt.Sprintf("fail to query device service and its revision by name %s", name), edgeXerr)
+	}
+	return ds, revision, nil
 }
 
 // DeviceProfileByName gets a device profile by name
@@ -437,12 +594,56 @@ func (c *Client) DevicesByProfileName(offset int, limit int, profileName string)
 	return devices, nil
 }
 
+// SearchDevices query the devices which match the filter with offset and limit, and returns the total count of the
+// matching devices
+func (c *Client) SearchDevices(offset int, limit int, filter pkgModels.DeviceSearchFilter) (devices []model.Device, totalCount uint32, edgeXerr errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	c.deviceSearchIndexMutex.Lock()
+	if !c.deviceSearchIndexed {
+		edgeXerr = ensureDeviceSearchIndexes(conn)
+		c.deviceSearchIndexed = edgeXerr == nil
+	}
+	c.deviceSearchIndexMutex.Unlock()
+	if edgeXerr != nil {
+		return devices, totalCount, errors.NewCommonEdgeX(errors.Kind(edgeXerr), "fail to index the devices for the device search", edgeXerr)
+	}
+
+	devices, totalCount, edgeXerr = searchDevices(conn, offset, limit, filter)
+	if edgeXerr != nil {
+		return devices, totalCount, errors.NewCommonEdgeX(errors.Kind(edgeXerr),
+			fmt.Sprintf("fail to search devices by offset %d and limit %d", offset, limit), edgeXerr)
+	}
+	return devices, totalCount, nil
+}
+
 // Update a device
 func (c *Client) UpdateDevice(d model.Device) errors.EdgeX {
 	conn := c.Pool.Get()
 	defer conn.Close()
 
-	return updateDevice(conn, d)
+	return updateDevice(conn, d, nil)
+}
+
+// UpdateDeviceWithRevision updates a device only if it is at the specified revision
+func (c *Client) UpdateDeviceWithRevision(d model.Device, revision uint64) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	return updateDevice(conn, d, &revision)
+}
+
+// DeviceAndRevisionByName gets a device and its revision by name
+func (c *Client) DeviceAndRevisionByName(name string) (model.Device, uint64, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	d, revision, edgeXerr := deviceAndRevisionByName(conn, name)
+	if edgeXerr != nil {
+		return d, revision, errors.NewCommonEdgeX(errors.Kind(edgeXerr), fmt.Sprintf("fail to query device and its revision by name %s", name), edgeXerr)
+	}
+	return d, revision, nil
 }
 
 // AllEvents query events by offset and limit
@@ -639,6 +840,21 @@ func (c *Client) ReadingCountByTimeRange(start int, end int) (uint32, errors.Edg
 	return count, nil
 }
 
+// ReadingsByDeviceNameAndResourceNameBeforeCursor query readings by device name and resource name whose origin is not
+// less than start, and which are ordered after the cursor of origin and id in the descending order
+func (c *Client) ReadingsByDeviceNameAndResourceNameBeforeCursor(deviceName string, resourceName string, start int, cursorOrigin int, cursorId string, limit int) (readings []model.Reading, err errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	readings, err = readingsByDeviceNameAndResourceNameBeforeCursor(conn, deviceName, resourceName, start, cursorOrigin, cursorId, limit)
+	if err != nil {
+		return readings, errors.NewCommonEdgeX(errors.Kind(err),
+			fmt.Sprintf("fail to query readings by deviceName %s, resourceName %s before origin %v and id %s", deviceName, resourceName, cursorOrigin, cursorId), err)
+	}
+
+	return readings, nil
+}
+
 // ReadingsByResourceNameAndTimeRange query readings by resourceName and specified time range. Readings are sorted in descending order of origin time.
 func (c *Client) ReadingsByResourceNameAndTimeRange(resourceName string, start int, end int, offset int, limit int) (readings []model.Reading, err errors.EdgeX) {
 	conn := c.Pool.Get()
@@ -813,7 +1029,27 @@ func (c *Client) UpdateProvisionWatcher(pw model.ProvisionWatcher) errors.EdgeX
 	conn := c.Pool.Get()
 	defer conn.Close()
 
-	return updateProvisionWatcher(conn, pw)
+	return updateProvisionWatcher(conn, pw, nil)
+}
+
+// UpdateProvisionWatcherWithRevision updates a provision watcher only if it is at the specified revision
+func (c *Client) UpdateProvisionWatcherWithRevision(pw model.ProvisionWatcher, revision uint64) errors.EdgeX {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	return updateProvisionWatcher(conn, pw, &revision)
+}
+
+// ProvisionWatcherAndRevisionByName gets a provision watcher and its revision by name
+func (c *Client) ProvisionWatcherAndRevisionByName(name string) (model.ProvisionWatcher, uint64, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	pw, revision, edgeXerr := provisionWatcherAndRevisionByName(conn, name)
+	if edgeXerr != nil {
+		return pw, revision, errors.NewCommonEdgeX(errors.Kind(edgeXerr), fmt.Sprintf("fail to query provision watcher and its revision by name %s", name), edgeXerr)
+	}
+	return pw, revision, nil
 }
 
 // DeviceProfileCountByLabels returns the total count of Device Profiles with labels specified.  If no label is specified, the total count of all device profiles will be returned.
@@ -22,6 +22,7 @@ const (
 	ZADD             = "ZADD"
 	ZREM             = "ZREM"
 	EXEC             = "EXEC"
+	DISCARD          = "DISCARD"
 	ZRANGE           = "ZRANGE"
 	ZREVRANGE        = "ZREVRANGE"
 	MGET             = "MGET"
@@ -30,9 +31,12 @@ const (
 	UNLINK           = "UNLINK"
 	ZRANGEBYSCORE    = "ZRANGEBYSCORE"
 	ZREVRANGEBYSCORE = "ZREVRANGEBYSCORE"
+	ZRANGEBYLEX      = "ZRANGEBYLEX"
 	LIMIT            = "LIMIT"
 	ZUNIONSTORE      = "ZUNIONSTORE"
 	ZINTERSTORE      = "ZINTERSTORE"
+	WATCH            = "WATCH"
+	UNWATCH          = "UNWATCH"
 )
 
 const (
@@ -63,6 +63,7 @@ func sendAddDeviceCmd(conn redis.Conn, storedKey string, d models.Device) errors
 	for _, label := range d.Labels {
 		_ = conn.Send(ZADD, CreateKey(DeviceCollectionLabel, label), d.Modified, storedKey)
 	}
+	sendAddDeviceSearchIndexCmd(conn, storedKey, d)
 	return nil
 }
 
@@ -156,6 +157,7 @@ func sendDeleteDeviceCmd(conn redis.Conn, storedKey string, device models.Device
 	for _, label := range device.Labels {
 		_ = conn.Send(ZREM, CreateKey(DeviceCollectionLabel, label), storedKey)
 	}
+	sendDeleteDeviceSearchIndexCmd(conn, storedKey, device)
 }
 
 // deleteDevice deletes a device
@@ -163,6 +165,7 @@ func deleteDevice(conn redis.Conn, device models.Device) errors.EdgeX {
 	storedKey := deviceStoredKey(device.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteDeviceCmd(conn, storedKey, device)
+	_ = conn.Send(DEL, revisionKey(storedKey))
 	_, err := conn.Do(EXEC)
 	if err != nil {
 		return errors.NewCommonEdgeX(errors.KindDatabaseError, "device deletion failed", err)
@@ -227,26 +230,44 @@ func devicesByProfileName(conn redis.Conn, offset int, limit int, profileName st
 	return devices, nil
 }
 
-func updateDevice(conn redis.Conn, d models.Device) errors.EdgeX {
+// deviceAndRevisionByName query device and its revision by name from DB
+func deviceAndRevisionByName(conn redis.Conn, name string) (device models.Device, revision uint64, edgeXerr errors.EdgeX) {
+	revision, edgeXerr = getObjectAndRevisionByHash(conn, DeviceCollectionName, name, &device)
+	if edgeXerr != nil {
+		return device, revision, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return
+}
+
+// updateDevice updates the device and increases its revision, the update is rejected if the device is at a revision
+// other than the expected one, or if the device is modified by another client at the same time
+func updateDevice(conn redis.Conn, d models.Device, expectedRevision *uint64) errors.EdgeX {
+	storedKey := deviceStoredKey(d.Id)
+	revision, edgexErr := watchRevision(conn, storedKey)
+	if edgexErr != nil {
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
 	oldDevice, edgexErr := deviceByName(conn, d.Name)
 	if edgexErr != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeXWrapper(edgexErr)
+	}
+	edgexErr = checkRevision("device", d.Name, revision, expectedRevision)
+	if edgexErr != nil {
+		unwatch(conn)
 		return errors.NewCommonEdgeXWrapper(edgexErr)
 	}
 
 	ts := pkgCommon.MakeTimestamp()
 	d.Modified = ts
 
-	storedKey := deviceStoredKey(d.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteDeviceCmd(conn, storedKey, oldDevice)
 	edgexErr = sendAddDeviceCmd(conn, storedKey, d)
 	if edgexErr != nil {
+		_, _ = conn.Do(DISCARD)
 		return errors.NewCommonEdgeXWrapper(edgexErr)
 	}
-	_, err := conn.Do(EXEC)
-	if err != nil {
-		return errors.NewCommonEdgeX(errors.KindDatabaseError, "device update failed", err)
-	}
-
-	return nil
+	_ = conn.Send(SET, revisionKey(storedKey), revision+1)
+	return execWatched(conn, "device", d.Name)
 }
@@ -66,8 +66,9 @@ func sendAddDeviceProfileCmd(conn redis.Conn, storedKey string, dp models.Device
 	return nil
 }
 
-// addDeviceProfile adds a device profile to DB
-func addDeviceProfile(conn redis.Conn, dp models.DeviceProfile) (models.DeviceProfile, errors.EdgeX) {
+// addDeviceProfile adds a device profile to DB, and records it as the next revision of the device profile within the
+// same transaction
+func addDeviceProfile(conn redis.Conn, dp models.DeviceProfile, correlationId string) (models.DeviceProfile, errors.EdgeX) {
 	// query device profile name and id to avoid the conflict
 	exists, edgeXerr := deviceProfileIdExists(conn, dp.Id)
 	if edgeXerr != nil {
@@ -92,17 +93,25 @@ func addDeviceProfile(conn redis.Conn, dp models.DeviceProfile) (models.DevicePr
 	dp.Modified = ts
 
 	storedKey := deviceProfileStoredKey(dp.Id)
+	latest, edgeXerr := watchDeviceProfileRevisions(conn, storedKey, dp.Name)
+	if edgeXerr != nil {
+		return dp, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
 	_ = conn.Send(MULTI)
 	edgeXerr = sendAddDeviceProfileCmd(conn, storedKey, dp)
+	if edgeXerr == nil {
+		edgeXerr = sendAddDeviceProfileRevisionCmd(conn, dp, latest+1, correlationId)
+	}
 	if edgeXerr != nil {
+		_, _ = conn.Do(DISCARD)
 		return dp, errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
-	_, err := conn.Do(EXEC)
-	if err != nil {
-		edgeXerr = errors.NewCommonEdgeX(errors.KindDatabaseError, "device profile creation failed", err)
+	edgeXerr = execWatched(conn, "device profile", dp.Name)
+	if edgeXerr != nil {
+		return dp, errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
 
-	return dp, edgeXerr
+	return dp, nil
 }
 
 // deviceProfileById query device profile by id from DB
@@ -136,9 +145,14 @@ func sendDeleteDeviceProfileCmd(conn redis.Conn, storedKey string, dp models.Dev
 }
 
 func deleteDeviceProfile(conn redis.Conn, dp models.DeviceProfile) errors.EdgeX {
+	revisionKeys, edgeXerr := deviceProfileRevisionStoredKeys(conn, dp.Name)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
 	storedKey := deviceProfileStoredKey(dp.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteDeviceProfileCmd(conn, storedKey, dp)
+	sendDeleteDeviceProfileRevisionsCmd(conn, dp.Name, revisionKeys)
 	_, err := conn.Do(EXEC)
 	if err != nil {
 		return errors.NewCommonEdgeX(errors.KindDatabaseError, "device profile deletion failed", err)
@@ -146,35 +160,63 @@ func deleteDeviceProfile(conn redis.Conn, dp models.DeviceProfile) errors.EdgeX
 	return nil
 }
 
-// updateDeviceProfile updates a device profile to DB
-func updateDeviceProfile(conn redis.Conn, dp models.DeviceProfile) (edgeXerr errors.EdgeX) {
+// updateDeviceProfile updates a device profile to DB, and records it as the next revision of the device profile within
+// the same transaction.  The stored device profile is recorded as the first revision beforehand if the device profile
+// has no revision yet, e.g. it was added before the revisions were recorded, so that its definition isn't lost.  The
+// update is rejected if the latest revision of the device profile is other than the expected one, e.g. the one checked
+// for the impact of the update, or if the device profile is updated by another client at the same time.
+func updateDeviceProfile(conn redis.Conn, dp models.DeviceProfile, correlationId string, expectedRevision *uint32) (edgeXerr errors.EdgeX) {
 	var oldDeviceProfile models.DeviceProfile
 	oldDeviceProfile, edgeXerr = deviceProfileById(conn, dp.Id)
-	if edgeXerr == nil {
-		if dp.Name != oldDeviceProfile.Name {
-			return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("device profile name '%s' not match the exsting '%s' ", dp.Name, oldDeviceProfile.Name), nil)
-		}
-	} else {
+	if edgeXerr != nil {
 		oldDeviceProfile, edgeXerr = deviceProfileByName(conn, dp.Name)
 		if edgeXerr != nil {
 			return errors.NewCommonEdgeXWrapper(edgeXerr)
 		}
 	}
 
+	storedKey := deviceProfileStoredKey(oldDeviceProfile.Id)
+	latest, edgeXerr := watchDeviceProfileRevisions(conn, storedKey, oldDeviceProfile.Name)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	// read the device profile again now that it's watched, as it may be updated by another client before
+	oldDeviceProfile, edgeXerr = deviceProfileById(conn, oldDeviceProfile.Id)
+	if edgeXerr != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	if dp.Name != oldDeviceProfile.Name {
+		unwatch(conn)
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("device profile name '%s' not match the exsting '%s' ", dp.Name, oldDeviceProfile.Name), nil)
+	}
+	if expectedRevision != nil && *expectedRevision != latest {
+		unwatch(conn)
+		return errors.NewCommonEdgeX(errors.KindStatusConflict,
+			fmt.Sprintf("device profile %s is at revision %d rather than the expected revision %d", dp.Name, latest, *expectedRevision), nil)
+	}
+
 	dp.Id = oldDeviceProfile.Id
 	dp.Created = oldDeviceProfile.Created
 	dp.Modified = pkgCommon.MakeTimestamp()
 
-	storedKey := deviceProfileStoredKey(dp.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteDeviceProfileCmd(conn, storedKey, oldDeviceProfile)
 	edgeXerr = sendAddDeviceProfileCmd(conn, storedKey, dp)
+	if edgeXerr == nil && latest == 0 {
+		latest++
+		edgeXerr = sendAddDeviceProfileRevisionCmd(conn, oldDeviceProfile, latest, "")
+	}
+	if edgeXerr == nil {
+		edgeXerr = sendAddDeviceProfileRevisionCmd(conn, dp, latest+1, correlationId)
+	}
 	if edgeXerr != nil {
+		_, _ = conn.Do(DISCARD)
 		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
-	_, err := conn.Do(EXEC)
-	if err != nil {
-		return errors.NewCommonEdgeX(errors.KindDatabaseError, "device profile update failed", err)
+	edgeXerr = execWatched(conn, "device profile", dp.Name)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
 
 	return nil
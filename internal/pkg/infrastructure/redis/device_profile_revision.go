@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	DeviceProfileRevisionCollection        = "md|dp|rev"
+	DeviceProfileRevisionCollectionProfile = DeviceProfileRevisionCollection + "|profile"
+	DeviceProfileRevisionCollectionLatest  = DeviceProfileRevisionCollection + "|latest"
+)
+
+// deviceProfileRevisionsKey returns the key of the sorted set of the device profile's revisions scored by the revision
+// number
+func deviceProfileRevisionsKey(profileName string) string {
+	return CreateKey(DeviceProfileRevisionCollectionProfile, profileName)
+}
+
+// deviceProfileRevisionStoredKey returns the stored key of the device profile revision
+func deviceProfileRevisionStoredKey(profileName string, revision uint32) string {
+	return CreateKey(DeviceProfileRevisionCollection, profileName, strconv.FormatUint(uint64(revision), 10))
+}
+
+// deviceProfileLatestRevisionKey returns the key of the latest revision number of the device profile, which is watched
+// by the transactions adding the revisions so that the concurrent updates can't record the same revision number
+func deviceProfileLatestRevisionKey(profileName string) string {
+	return CreateKey(DeviceProfileRevisionCollectionLatest, profileName)
+}
+
+// watchDeviceProfileRevisions watches the device profile and its latest revision number, so that the following
+// transaction is aborted if another client updates the device profile in the meantime, and returns the latest
+// revision number, which is 0 if the device profile has no revision yet
+func watchDeviceProfileRevisions(conn redis.Conn, storedKey string, name string) (uint32, errors.EdgeX) {
+	_, err := conn.Do(WATCH, storedKey, deviceProfileLatestRevisionKey(name))
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "failed to watch the device profile", err)
+	}
+	latest, err := redis.Uint64(conn.Do(GET, deviceProfileLatestRevisionKey(name)))
+	if err == redis.ErrNil {
+		return 0, nil
+	} else if err != nil {
+		unwatch(conn)
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query the latest revision of device profile %s from the database failed", name), err)
+	}
+	return uint32(latest), nil
+}
+
+// deviceProfileAndRevisionByName queries the device profile by name and its latest revision number atomically, which is
+// 0 if the device profile has no revision yet
+func deviceProfileAndRevisionByName(conn redis.Conn, name string) (dp models.DeviceProfile, revision uint32, edgeXerr errors.EdgeX) {
+	storedKey, err := redis.String(conn.Do(HGET, DeviceProfileCollectionName, name))
+	if err == redis.ErrNil {
+		return dp, 0, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("device profile %s doesn't exist in the database", name), err)
+	} else if err != nil {
+		return dp, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query device profile %s from the database failed", name), err)
+	}
+	latest, edgeXerr := getObjectAndRevision(conn, storedKey, deviceProfileLatestRevisionKey(name), &dp)
+	if edgeXerr != nil {
+		return dp, 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return dp, uint32(latest), nil
+}
+
+// sendAddDeviceProfileRevisionCmd send redis command for recording the device profile as the revision
+func sendAddDeviceProfileRevisionCmd(conn redis.Conn, dp models.DeviceProfile, revision uint32, correlationId string) errors.EdgeX {
+	r := pkgModels.DeviceProfileRevision{
+		Revision:      revision,
+		ProfileName:   dp.Name,
+		CorrelationId: correlationId,
+		Created:       pkgCommon.MakeTimestamp(),
+		Profile:       dp,
+	}
+	m, err := json.Marshal(r)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "unable to JSON marshal device profile revision for Redis persistence", err)
+	}
+	storedKey := deviceProfileRevisionStoredKey(dp.Name, revision)
+	_ = conn.Send(SET, storedKey, m)
+	_ = conn.Send(ZADD, deviceProfileRevisionsKey(dp.Name), revision, storedKey)
+	_ = conn.Send(SET, deviceProfileLatestRevisionKey(dp.Name), revision)
+	return nil
+}
+
+// deviceProfileRevision queries the device profile revision by the device profile name and revision number
+func deviceProfileRevision(conn redis.Conn, name string, revision uint32) (deviceProfileRevision pkgModels.DeviceProfileRevision, edgeXerr errors.EdgeX) {
+	edgeXerr = getObjectById(conn, deviceProfileRevisionStoredKey(name, revision), &deviceProfileRevision)
+	if errors.Kind(edgeXerr) == errors.KindEntityDoesNotExist {
+		return deviceProfileRevision, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("revision %d of device profile %s doesn't exist", revision, name), edgeXerr)
+	} else if edgeXerr != nil {
+		return deviceProfileRevision, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return deviceProfileRevision, nil
+}
+
+// deviceProfileRevisions queries the revisions of the device profile from the latest with offset and limit
+func deviceProfileRevisions(conn redis.Conn, offset int, limit int, name string) (revisions []pkgModels.DeviceProfileRevision, edgeXerr errors.EdgeX) {
+	objects, edgeXerr := getObjectsByRevRange(conn, deviceProfileRevisionsKey(name), offset, limit)
+	if edgeXerr != nil {
+		return revisions, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+
+	revisions = make([]pkgModels.DeviceProfileRevision, len(objects))
+	for i, in := range objects {
+		err := json.Unmarshal(in, &revisions[i])
+		if err != nil {
+			return []pkgModels.DeviceProfileRevision{}, errors.NewCommonEdgeX(errors.KindDatabaseError, "device profile revision format parsing failed from the database", err)
+		}
+	}
+	return revisions, nil
+}
+
+// deviceProfileRevisionStoredKeys returns the stored keys of the revisions of the device profile
+func deviceProfileRevisionStoredKeys(conn redis.Conn, name string) ([]string, errors.EdgeX) {
+	storedKeys, err := redis.Strings(conn.Do(ZRANGE, deviceProfileRevisionsKey(name), 0, -1))
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query revisions of device profile %s from the database failed", name), err)
+	}
+	return storedKeys, nil
+}
+
+// sendDeleteDeviceProfileRevisionsCmd send redis command for deleting the revisions of the device profile
+func sendDeleteDeviceProfileRevisionsCmd(conn redis.Conn, name string, storedKeys []string) {
+	for _, storedKey := range storedKeys {
+		_ = conn.Send(DEL, storedKey)
+	}
+	_ = conn.Send(DEL, deviceProfileRevisionsKey(name))
+	_ = conn.Send(DEL, deviceProfileLatestRevisionKey(name))
+}
@@ -0,0 +1,141 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceProfileRevisions(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	dp, err := client.AddDeviceProfile(models.DeviceProfile{Id: exampleUUID, Name: testProfileName, Model: "m1"}, "correlation-1")
+	require.NoError(t, err)
+
+	dp.Model = "m2"
+	require.NoError(t, client.UpdateDeviceProfile(dp, "correlation-2"))
+
+	count, err := client.DeviceProfileRevisionCount(testProfileName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count, "the revisions should be recorded along with the device profile")
+	revisions, err := client.DeviceProfileRevisions(0, -1, testProfileName)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, uint32(2), revisions[0].Revision, "the revisions should be sorted from the latest")
+	assert.Equal(t, "m2", revisions[0].Profile.Model)
+	assert.Equal(t, "correlation-2", revisions[0].CorrelationId)
+
+	first, err := client.DeviceProfileRevision(testProfileName, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "m1", first.Profile.Model, "the revision shouldn't change with the device profile")
+	assert.Equal(t, "correlation-1", first.CorrelationId)
+	_, err = client.DeviceProfileRevision(testProfileName, 3)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+
+	require.NoError(t, client.DeleteDeviceProfileByName(testProfileName))
+	count, err = client.DeviceProfileRevisionCount(testProfileName)
+	require.NoError(t, err)
+	assert.Zero(t, count, "the revisions should be deleted with the device profile")
+	_, err = client.DeviceProfileRevision(testProfileName, 1)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+
+	_, err = client.AddDeviceProfile(models.DeviceProfile{Id: exampleUUID, Name: testProfileName, Model: "m3"}, "correlation-3")
+	require.NoError(t, err)
+	r, err := client.DeviceProfileRevision(testProfileName, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "m3", r.Profile.Model, "the revisions of the re-added device profile should be numbered from 1")
+}
+
+func TestDeviceProfileBaseRevision(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	// the device profile added before the revisions were recorded
+	dp := models.DeviceProfile{Id: exampleUUID, Name: testProfileName, Model: "m1"}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_ = conn.Send(MULTI)
+	require.NoError(t, sendAddDeviceProfileCmd(conn, deviceProfileStoredKey(dp.Id), dp))
+	_, err := conn.Do(EXEC)
+	require.NoError(t, err)
+
+	dp.Model = "m2"
+	require.NoError(t, client.UpdateDeviceProfile(dp, "correlation-1"))
+	revisions, err := client.DeviceProfileRevisions(0, -1, testProfileName)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "m1", revisions[1].Profile.Model, "the stored device profile should be recorded as the first revision")
+	assert.Equal(t, "m2", revisions[0].Profile.Model)
+	assert.Equal(t, "correlation-1", revisions[0].CorrelationId)
+}
+
+func TestUpdateDeviceProfileConcurrently(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+	dp, err := client.AddDeviceProfile(models.DeviceProfile{Id: exampleUUID, Name: testProfileName, Model: "m1"}, "")
+	require.NoError(t, err)
+
+	// another client updates the device profile after it's watched by the update
+	conn := client.Pool.Get()
+	defer conn.Close()
+	latest, err := watchDeviceProfileRevisions(conn, deviceProfileStoredKey(dp.Id), dp.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), latest)
+	dp.Model = "m2"
+	require.NoError(t, client.UpdateDeviceProfile(dp, ""))
+
+	_ = conn.Send(MULTI)
+	require.NoError(t, sendAddDeviceProfileRevisionCmd(conn, dp, latest+1, ""))
+	err = execWatched(conn, "device profile", dp.Name)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindStatusConflict, errors.Kind(err), "the revision number taken by the other update shouldn't be recorded again")
+
+	dp.Model = "m3"
+	require.NoError(t, client.UpdateDeviceProfile(dp, ""))
+	revisions, err := client.DeviceProfileRevisions(0, -1, testProfileName)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, uint32(3), revisions[0].Revision)
+	assert.Equal(t, "m3", revisions[0].Profile.Model)
+	assert.Equal(t, "m2", revisions[1].Profile.Model)
+}
+
+func TestUpdateDeviceProfileWithRevision(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+	_, err := client.AddDeviceProfile(models.DeviceProfile{Id: exampleUUID, Name: testProfileName, Model: "m1"}, "")
+	require.NoError(t, err)
+
+	dp, revision, err := client.DeviceProfileAndRevisionByName(testProfileName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), revision)
+	assert.Equal(t, "m1", dp.Model)
+
+	// another client updates the device profile after its revision is read
+	dp.Model = "m2"
+	require.NoError(t, client.UpdateDeviceProfile(dp, ""))
+
+	dp.Model = "m3"
+	err = client.UpdateDeviceProfileWithRevision(dp, revision, "")
+	require.Error(t, err)
+	assert.Equal(t, errors.KindStatusConflict, errors.Kind(err), "the update of the outdated revision should be rejected")
+
+	dp, revision, err = client.DeviceProfileAndRevisionByName(testProfileName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), revision)
+	assert.Equal(t, "m2", dp.Model)
+	dp.Model = "m3"
+	require.NoError(t, client.UpdateDeviceProfileWithRevision(dp, revision, ""))
+	_, revision, err = client.DeviceProfileAndRevisionByName(testProfileName)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), revision)
+
+	_, _, err = client.DeviceProfileAndRevisionByName("unknown")
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+}
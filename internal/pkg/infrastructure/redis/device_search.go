@@ -0,0 +1,406 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+)
+
+// The indexes of the device search, in addition to the service, profile and label indexes of the devices
+const (
+	DeviceCollectionAdminState       = DeviceCollection + DBKeySeparator + "adminState"
+	DeviceCollectionOperatingState   = DeviceCollection + DBKeySeparator + "operatingState"
+	DeviceCollectionProtocolProperty = DeviceCollection + DBKeySeparator + "protocolProperty"
+	DeviceCollectionCreated          = DeviceCollection + DBKeySeparator + "created"
+	DeviceCollectionModified         = DeviceCollection + DBKeySeparator + "modified"
+	DeviceCollectionSortedName       = DeviceCollection + DBKeySeparator + "sortedName"
+)
+
+// deviceNameMemberSeparator separates the device name from the stored key in the members of DeviceCollectionSortedName.
+// It sorts before any character allowed in a device name, so the members are ordered by device name.
+const deviceNameMemberSeparator = "\x00"
+
+func deviceNameMember(name string, storedKey string) string {
+	return name + deviceNameMemberSeparator + storedKey
+}
+
+// deviceProtocolPropertyKey returns the key of the index of the protocol property.  The property name is prefixed with
+// its length, so the names and values containing DBKeySeparator can't make the same key, e.g. a|b=c and a=b|c.
+func deviceProtocolPropertyKey(key string, value string) string {
+	return CreateKey(DeviceCollectionProtocolProperty, strconv.Itoa(len(key)), key, value)
+}
+
+// sendAddDeviceSearchIndexCmd send redis command for adding the device to the indexes of the device search
+func sendAddDeviceSearchIndexCmd(conn redis.Conn, storedKey string, d models.Device) {
+	_ = conn.Send(ZADD, CreateKey(DeviceCollectionAdminState, string(d.AdminState)), d.Modified, storedKey)
+	_ = conn.Send(ZADD, CreateKey(DeviceCollectionOperatingState, string(d.OperatingState)), d.Modified, storedKey)
+	for _, properties := range d.Protocols {
+		for key, value := range properties {
+			_ = conn.Send(ZADD, deviceProtocolPropertyKey(key, value), d.Modified, storedKey)
+		}
+	}
+	_ = conn.Send(ZADD, DeviceCollectionCreated, d.Created, storedKey)
+	_ = conn.Send(ZADD, DeviceCollectionModified, d.Modified, storedKey)
+	_ = conn.Send(ZADD, DeviceCollectionSortedName, 0, deviceNameMember(d.Name, storedKey))
+}
+
+// sendDeleteDeviceSearchIndexCmd send redis command for removing the device from the indexes of the device search
+func sendDeleteDeviceSearchIndexCmd(conn redis.Conn, storedKey string, d models.Device) {
+	_ = conn.Send(ZREM, CreateKey(DeviceCollectionAdminState, string(d.AdminState)), storedKey)
+	_ = conn.Send(ZREM, CreateKey(DeviceCollectionOperatingState, string(d.OperatingState)), storedKey)
+	for _, properties := range d.Protocols {
+		for key, value := range properties {
+			_ = conn.Send(ZREM, deviceProtocolPropertyKey(key, value), storedKey)
+		}
+	}
+	_ = conn.Send(ZREM, DeviceCollectionCreated, storedKey)
+	_ = conn.Send(ZREM, DeviceCollectionModified, storedKey)
+	_ = conn.Send(ZREM, DeviceCollectionSortedName, deviceNameMember(d.Name, storedKey))
+}
+
+// deviceSearchIndexAttempts is the number of times the devices are indexed before giving up, when they keep being
+// modified by other clients during the indexing
+const deviceSearchIndexAttempts = 3
+
+// ensureDeviceSearchIndexes indexes the devices which were added before the device search was introduced, the devices
+// added since are indexed as they are written
+func ensureDeviceSearchIndexes(conn redis.Conn) errors.EdgeX {
+	for attempt := 0; attempt < deviceSearchIndexAttempts; attempt++ {
+		indexed, edgeXerr := indexDevices(conn)
+		if edgeXerr != nil {
+			return errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		if indexed {
+			return nil
+		}
+	}
+	return errors.NewCommonEdgeX(errors.KindStatusConflict, "devices were modified by other clients during the device search indexing", nil)
+}
+
+// indexDevices adds all the devices to the indexes of the device search unless they are indexed already.  The devices
+// are watched while they are indexed, and false is returned without indexing any device when one of them is modified
+// by another client meanwhile, so its indexes aren't overwritten with its stale values.
+func indexDevices(conn redis.Conn) (bool, errors.EdgeX) {
+	deviceCount, edgeXerr := getMemberNumber(conn, ZCARD, DeviceCollection)
+	if edgeXerr != nil {
+		return false, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	indexedCount, edgeXerr := getMemberNumber(conn, ZCARD, DeviceCollectionSortedName)
+	if edgeXerr != nil {
+		return false, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	if deviceCount == indexedCount {
+		return true, nil
+	}
+
+	storedKeys, err := redis.Values(conn.Do(ZRANGE, DeviceCollection, 0, -1))
+	if err != nil {
+		return false, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids from database failed", err)
+	}
+	if len(storedKeys) == 0 {
+		return true, nil
+	}
+	_, err = conn.Do(WATCH, storedKeys...)
+	if err != nil {
+		return false, errors.NewCommonEdgeX(errors.KindDatabaseError, "failed to watch the devices", err)
+	}
+	objects, edgeXerr := getObjectsByIds(conn, storedKeys)
+	if edgeXerr != nil {
+		unwatch(conn)
+		return false, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	_ = conn.Send(MULTI)
+	for _, in := range objects {
+		d := models.Device{}
+		err := json.Unmarshal(in, &d)
+		if err != nil {
+			_, _ = conn.Do(DISCARD)
+			return false, errors.NewCommonEdgeX(errors.KindDatabaseError, "device format parsing failed from the database", err)
+		}
+		sendAddDeviceSearchIndexCmd(conn, deviceStoredKey(d.Id), d)
+	}
+	reply, err := conn.Do(EXEC)
+	if err != nil {
+		return false, errors.NewCommonEdgeX(errors.KindDatabaseError, "device search indexing failed", err)
+	}
+	return reply != nil, nil
+}
+
+// deviceSearchIndexKeys returns the keys of the indexes holding the devices which match the filter, except the name
+func deviceSearchIndexKeys(filter pkgModels.DeviceSearchFilter) []string {
+	var keys []string
+	if filter.ServiceName != "" {
+		keys = append(keys, CreateKey(DeviceCollectionServiceName, filter.ServiceName))
+	}
+	if filter.ProfileName != "" {
+		keys = append(keys, CreateKey(DeviceCollectionProfileName, filter.ProfileName))
+	}
+	for _, label := range filter.Labels {
+		keys = append(keys, CreateKey(DeviceCollectionLabel, label))
+	}
+	if filter.AdminState != "" {
+		keys = append(keys, CreateKey(DeviceCollectionAdminState, string(filter.AdminState)))
+	}
+	if filter.OperatingState != "" {
+		keys = append(keys, CreateKey(DeviceCollectionOperatingState, string(filter.OperatingState)))
+	}
+	for key, value := range filter.ProtocolProperties {
+		keys = append(keys, deviceProtocolPropertyKey(key, value))
+	}
+	return keys
+}
+
+// globPrefix returns the literal prefix of the glob pattern, which is matched with a range of the sorted names
+func globPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
+// deviceIdsByNamePattern returns the stored keys of the devices whose names match the glob pattern, ordered by name
+func deviceIdsByNamePattern(conn redis.Conn, pattern string) ([]string, errors.EdgeX) {
+	var members []string
+	var err error
+	if prefix := globPrefix(pattern); prefix != "" {
+		members, err = redis.Strings(conn.Do(ZRANGEBYLEX, DeviceCollectionSortedName, "["+prefix, "("+prefix+"\xff"))
+	} else {
+		members, err = redis.Strings(conn.Do(ZRANGE, DeviceCollectionSortedName, 0, -1))
+	}
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids by name from database failed", err)
+	}
+
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		parts := strings.SplitN(member, deviceNameMemberSeparator, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if pattern != "" {
+			matched, err := path.Match(pattern, parts[0])
+			if err != nil {
+				return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("invalid device name pattern %s", pattern), err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		ids = append(ids, parts[1])
+	}
+	return ids, nil
+}
+
+// pageRange returns the inclusive start and end indexes of the page of a sorted set, where -1 end means the last member
+func pageRange(offset int, limit int) (int, int) {
+	switch limit {
+	case 0:
+		// the start after the end makes an empty range
+		return 1, 0
+	case -1:
+		//-1 limit means that clients want to retrieve all remaining records after offset from DB, so specifying -1 for end
+		return offset, limit
+	}
+	return offset, offset + limit - 1
+}
+
+// deviceIdsByScore returns the stored keys of the page of devices which are in all the filter indexes and match the
+// name pattern, sorted by their scores in sortKey, along with the total count of the matching devices.  The indexes are
+// intersected into a temporary sorted set by Redis, in which only the scores of sortKey are kept, and the page is
+// ranged from it within the same transaction.
+func deviceIdsByScore(conn redis.Conn, offset int, limit int, sortKey string, filterKeys []string, namePattern string, descending bool) ([]string, uint32, errors.EdgeX) {
+	keys := append([]string{sortKey}, filterKeys...)
+	var nameIds []interface{}
+	nameSet := uuid.New().String()
+	if namePattern != "" {
+		ids, edgeXerr := deviceIdsByNamePattern(conn, namePattern)
+		if edgeXerr != nil {
+			return nil, 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+		}
+		if len(ids) == 0 {
+			return []string{}, 0, nil
+		}
+		for _, id := range ids {
+			nameIds = append(nameIds, 0, id)
+		}
+		keys = append(keys, nameSet)
+	}
+
+	args := redis.Args{}
+	cacheSet := uuid.New().String()
+	args = append(args, cacheSet, len(keys))
+	args = args.AddFlat(keys)
+	args = append(args, "WEIGHTS", 1)
+	for range keys[1:] {
+		args = append(args, 0)
+	}
+	command := ZRANGE
+	if descending {
+		command = ZREVRANGE
+	}
+	start, end := pageRange(offset, limit)
+
+	_ = conn.Send(MULTI)
+	if len(nameIds) > 0 {
+		_ = conn.Send(ZADD, append([]interface{}{nameSet}, nameIds...)...)
+	}
+	_ = conn.Send(ZINTERSTORE, args...)
+	_ = conn.Send(ZCARD, cacheSet)
+	_ = conn.Send(command, cacheSet, start, end)
+	_ = conn.Send(DEL, cacheSet, nameSet)
+	replies, err := redis.Values(conn.Do(EXEC))
+	if err != nil {
+		return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids from database failed", err)
+	}
+	replies = replies[len(replies)-3:]
+	count, err := redis.Int(replies[0], nil)
+	if err != nil {
+		return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device count from database failed", err)
+	}
+	ids, err := redis.Strings(replies[1], nil)
+	if err != nil {
+		return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids from database failed", err)
+	}
+	return ids, uint32(count), nil
+}
+
+// deviceIdsByName returns the stored keys of the page of devices which are in all the filter indexes and match the name
+// pattern, sorted by name, along with the total count of the matching devices.  The sorted names can't be intersected
+// by Redis, since their scores are all 0 to be ordered lexicographically.  Without any filter, the page is ranged from
+// the sorted names directly, otherwise the filter indexes are intersected by Redis and the devices are matched with
+// the sorted names in the range of the name pattern.
+func deviceIdsByName(conn redis.Conn, offset int, limit int, filterKeys []string, namePattern string, descending bool) ([]string, uint32, errors.EdgeX) {
+	if len(filterKeys) == 0 && namePattern == "" {
+		command := ZRANGE
+		if descending {
+			command = ZREVRANGE
+		}
+		start, end := pageRange(offset, limit)
+		_ = conn.Send(MULTI)
+		_ = conn.Send(ZCARD, DeviceCollectionSortedName)
+		_ = conn.Send(command, DeviceCollectionSortedName, start, end)
+		replies, err := redis.Values(conn.Do(EXEC))
+		if err != nil {
+			return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids by name from database failed", err)
+		}
+		count, err := redis.Int(replies[0], nil)
+		if err != nil {
+			return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device count from database failed", err)
+		}
+		members, err := redis.Strings(replies[1], nil)
+		if err != nil {
+			return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids by name from database failed", err)
+		}
+		ids := make([]string, 0, len(members))
+		for _, member := range members {
+			if parts := strings.SplitN(member, deviceNameMemberSeparator, 2); len(parts) == 2 {
+				ids = append(ids, parts[1])
+			}
+		}
+		return ids, uint32(count), nil
+	}
+
+	ids, edgeXerr := deviceIdsByNamePattern(conn, namePattern)
+	if edgeXerr != nil {
+		return nil, 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	if len(filterKeys) > 0 {
+		args := redis.Args{}
+		cacheSet := uuid.New().String()
+		args = append(args, cacheSet, len(filterKeys))
+		args = args.AddFlat(filterKeys)
+		_ = conn.Send(MULTI)
+		_ = conn.Send(ZINTERSTORE, args...)
+		_ = conn.Send(ZRANGE, cacheSet, 0, -1)
+		_ = conn.Send(DEL, cacheSet)
+		replies, err := redis.Values(conn.Do(EXEC))
+		if err != nil {
+			return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids from database failed", err)
+		}
+		filtered, err := redis.Strings(replies[1], nil)
+		if err != nil {
+			return nil, 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query device ids from database failed", err)
+		}
+		ids = pkgCommon.FindCommonStrings(filtered, ids)
+	}
+	if descending {
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	}
+
+	count := len(ids)
+	start, end := pageRange(offset, limit)
+	if start >= count || (end != -1 && end < start) {
+		ids = []string{}
+	} else if end >= count || end == -1 {
+		ids = ids[start:]
+	} else { // as end index in golang re-slice is exclusive, increment the end index to ensure the end could be inclusive
+		ids = ids[start : end+1]
+	}
+	return ids, uint32(count), nil
+}
+
+// searchDevices query the devices which match the filter with offset and limit, and returns the total count of the
+// matching devices.  The devices are selected and sorted with the indexes, only the requested page of devices is read.
+func searchDevices(conn redis.Conn, offset int, limit int, filter pkgModels.DeviceSearchFilter) (devices []models.Device, totalCount uint32, edgeXerr errors.EdgeX) {
+	var sortKey string
+	switch filter.SortBy {
+	case pkgModels.DeviceSortByName, "":
+	case pkgModels.DeviceSortByCreated:
+		sortKey = DeviceCollectionCreated
+	case pkgModels.DeviceSortByModified:
+		sortKey = DeviceCollectionModified
+	default:
+		return nil, totalCount, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("devices can't be sorted by %s", filter.SortBy), nil)
+	}
+	var ids []string
+	if sortKey == "" {
+		ids, totalCount, edgeXerr = deviceIdsByName(conn, offset, limit, deviceSearchIndexKeys(filter), filter.NamePattern, filter.Descending)
+	} else {
+		ids, totalCount, edgeXerr = deviceIdsByScore(conn, offset, limit, sortKey, deviceSearchIndexKeys(filter), filter.NamePattern, filter.Descending)
+	}
+	if edgeXerr != nil {
+		return nil, totalCount, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	if limit == 0 {
+		return []models.Device{}, totalCount, nil
+	}
+	if offset > int(totalCount) {
+		return nil, totalCount, errors.NewCommonEdgeX(errors.KindRangeNotSatisfiable, fmt.Sprintf("query objects bounds out of range. length:%v", totalCount), nil)
+	}
+	if len(ids) == 0 {
+		return []models.Device{}, totalCount, nil
+	}
+
+	objects, edgeXerr := getObjectsByIds(conn, pkgCommon.ConvertStringsToInterfaces(ids))
+	if edgeXerr != nil {
+		return nil, totalCount, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	devices = make([]models.Device, len(objects))
+	for i, in := range objects {
+		d := models.Device{}
+		err := json.Unmarshal(in, &d)
+		if err != nil {
+			return []models.Device{}, totalCount, errors.NewCommonEdgeX(errors.KindDatabaseError, "device format parsing failed from the database", err)
+		}
+		devices[i] = d
+	}
+	return devices, totalCount, nil
+}
@@ -131,6 +131,8 @@ func deleteDeviceService(conn redis.Conn, ds models.DeviceService) errors.EdgeX
 	storedKey := deviceServiceStoredKey(ds.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteDeviceServiceCmd(conn, storedKey, ds)
+	_ = conn.Send(DEL, revisionKey(storedKey))
+	_ = conn.Send(DEL, devicesDownByHealthCheckKey(ds.Name))
 	_, err := conn.Do(EXEC)
 	if err != nil {
 		return errors.NewCommonEdgeX(errors.KindDatabaseError, "device service deletion failed", err)
@@ -183,24 +185,74 @@ func deviceServicesByLabels(conn redis.Conn, offset int, limit int, labels []str
 	return deviceServices, nil
 }
 
-func updateDeviceService(conn redis.Conn, ds models.DeviceService) errors.EdgeX {
+// deviceServiceAndRevisionByName query device service and its revision by name from DB
+func deviceServiceAndRevisionByName(conn redis.Conn, name string) (ds models.DeviceService, revision uint64, edgeXerr errors.EdgeX) {
+	revision, edgeXerr = getObjectAndRevisionByHash(conn, DeviceServiceCollectionName, name, &ds)
+	if edgeXerr != nil {
+		return ds, revision, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return
+}
+
+// updateDeviceService updates the device service and increases its revision, the update is rejected if the
+// device service is at a revision other than the expected one, or if the device service is modified by another client at
+// the same time
+func updateDeviceService(conn redis.Conn, ds models.DeviceService, expectedRevision *uint64) errors.EdgeX {
+	storedKey := deviceServiceStoredKey(ds.Id)
+	revision, edgeXerr := watchRevision(conn, storedKey)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
 	oldDeviceService, edgeXerr := deviceServiceByName(conn, ds.Name)
 	if edgeXerr != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	edgeXerr = checkRevision("device service", ds.Name, revision, expectedRevision)
+	if edgeXerr != nil {
+		unwatch(conn)
 		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
 
 	ds.Modified = pkgCommon.MakeTimestamp()
-	storedKey := deviceServiceStoredKey(ds.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteDeviceServiceCmd(conn, storedKey, oldDeviceService)
 	edgeXerr = sendAddDeviceServiceCmd(conn, storedKey, ds)
 	if edgeXerr != nil {
+		_, _ = conn.Do(DISCARD)
 		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
-	_, err := conn.Do(EXEC)
+	_ = conn.Send(SET, revisionKey(storedKey), revision+1)
+	return execWatched(conn, "device service", ds.Name)
+}
+
+// updateDeviceServiceLastConnected updates the LastConnected of the device service alone, which neither changes the
+// Modified nor increases the revision of the device service, as it's maintained by the health check rather than the
+// user.  The update is rejected if the device service is modified by another client at the same time.
+func updateDeviceServiceLastConnected(conn redis.Conn, name string, lastConnected int64) errors.EdgeX {
+	ds, edgeXerr := deviceServiceByName(conn, name)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	storedKey := deviceServiceStoredKey(ds.Id)
+	_, err := conn.Do(WATCH, storedKey)
 	if err != nil {
-		return errors.NewCommonEdgeX(errors.KindDatabaseError, "device service update failed", err)
+		return errors.NewCommonEdgeX(errors.KindDatabaseError, "failed to watch the device service", err)
+	}
+	// read the device service again now that it's watched, as it may be updated by another client before
+	ds, edgeXerr = deviceServiceById(conn, ds.Id)
+	if edgeXerr != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
 
-	return nil
+	ds.LastConnected = lastConnected
+	m, err := json.Marshal(ds)
+	if err != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeX(errors.KindContractInvalid, "unable to JSON marshal device service for Redis persistence", err)
+	}
+	_ = conn.Send(MULTI)
+	_ = conn.Send(SET, storedKey, m)
+	return execWatched(conn, "device service", name)
 }
@@ -0,0 +1,56 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const DeviceServiceHealthCollectionDown = DeviceServiceCollection + DBKeySeparator + "health" + DBKeySeparator + "down"
+
+// devicesDownByHealthCheckKey returns the key of the sorted set of the devices marked DOWN by the health check of the
+// device service
+func devicesDownByHealthCheckKey(serviceName string) string {
+	return CreateKey(DeviceServiceHealthCollectionDown, serviceName)
+}
+
+// addDevicesDownByHealthCheck records the devices marked DOWN by the health check of the device service
+func addDevicesDownByHealthCheck(conn redis.Conn, serviceName string, deviceNames []string) errors.EdgeX {
+	if len(deviceNames) == 0 {
+		return nil
+	}
+	args := redis.Args{}.Add(devicesDownByHealthCheckKey(serviceName))
+	for _, name := range deviceNames {
+		args = args.Add(0, name)
+	}
+	_, err := conn.Do(ZADD, args...)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("record the devices marked DOWN by the health check of device service %s failed", serviceName), err)
+	}
+	return nil
+}
+
+// devicesDownByHealthCheck returns the names of the devices marked DOWN by the health check of the device service
+func devicesDownByHealthCheck(conn redis.Conn, serviceName string) ([]string, errors.EdgeX) {
+	names, err := redis.Strings(conn.Do(ZRANGE, devicesDownByHealthCheckKey(serviceName), 0, -1))
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query the devices marked DOWN by the health check of device service %s failed", serviceName), err)
+	}
+	return names, nil
+}
+
+// deleteDevicesDownByHealthCheck forgets the devices marked DOWN by the health check of the device service
+func deleteDevicesDownByHealthCheck(conn redis.Conn, serviceName string) errors.EdgeX {
+	_, err := conn.Do(DEL, devicesDownByHealthCheckKey(serviceName))
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("delete the devices marked DOWN by the health check of device service %s failed", serviceName), err)
+	}
+	return nil
+}
@@ -0,0 +1,484 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	pkgModels "github.com/edgexfoundry/edgex-go/internal/pkg/models"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEmbeddedTestClient(t *testing.T) *Client {
+	client, err := NewEmbeddedClient(db.Configuration{DatabaseName: filepath.Join(t.TempDir(), "test.db")}, logger.NewMockClient())
+	require.NoError(t, err)
+	t.Cleanup(client.CloseSession)
+	return client
+}
+
+func testEvent(deviceName string, origin int64) models.Event {
+	reading := simpleReadingData()
+	reading.Id = uuid.New().String()
+	reading.DeviceName = deviceName
+	reading.Origin = origin
+	return models.Event{
+		Id:          uuid.New().String(),
+		DeviceName:  deviceName,
+		ProfileName: testProfileName,
+		SourceName:  testResourceName,
+		Origin:      origin,
+		Readings:    []models.Reading{reading},
+	}
+}
+
+func TestEmbeddedClientEvents(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	var events []models.Event
+	for i, deviceName := range []string{"device1", "device1", "device2"} {
+		e, err := client.AddEvent(testEvent(deviceName, int64(i+1)))
+		require.NoError(t, err)
+		events = append(events, e)
+	}
+
+	e, err := client.EventById(events[0].Id)
+	require.NoError(t, err)
+	assert.Equal(t, events[0].Id, e.Id)
+	require.Len(t, e.Readings, 1)
+
+	all, err := client.AllEvents(0, -1)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, events[2].Id, all[0].Id, "events should be sorted by origin in descending order")
+
+	byDevice, err := client.EventsByDeviceName(0, 10, "device1")
+	require.NoError(t, err)
+	assert.Len(t, byDevice, 2)
+
+	byTime, err := client.EventsByTimeRange(2, 3, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, byTime, 2)
+
+	_, err = client.AllEvents(5, 10)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindRangeNotSatisfiable, errors.Kind(err))
+
+	count, err := client.ReadingCountByDeviceName("device1")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count)
+
+	readings, totalCount, err := client.ReadingsByDeviceNameAndResourceNamesAndTimeRange("device1", []string{testResourceName}, 0, 10, 0, 1)
+	require.NoError(t, err)
+	assert.Len(t, readings, 1)
+	assert.Equal(t, uint32(2), totalCount)
+
+	err = client.DeleteEventById(events[0].Id)
+	require.NoError(t, err)
+	_, err = client.EventById(events[0].Id)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+
+	count, err = client.EventTotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count)
+	count, err = client.ReadingTotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count)
+}
+
+func TestEmbeddedClientAddEvents(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	existing, err := client.AddEvent(testEvent("device1", 1))
+	require.NoError(t, err)
+
+	valid := testEvent("device1", 2)
+	invalidId := testEvent("device1", 3)
+	invalidId.Id = "invalid"
+	unsupportedReading := testEvent("device2", 4)
+	unsupportedReading.Readings = []models.Reading{nil}
+	inBatchDuplicate := testEvent("device2", 5)
+	inBatchDuplicate.Id = valid.Id
+
+	added, errs := client.AddEvents([]models.Event{valid, existing, invalidId, unsupportedReading, inBatchDuplicate})
+	require.Len(t, errs, 5)
+	require.NoError(t, errs[0])
+	assert.Equal(t, valid.Id, added[0].Id)
+	assert.Equal(t, errors.KindDuplicateName, errors.Kind(errs[1]), "the existing event should be rejected")
+	assert.Equal(t, errors.KindInvalidId, errors.Kind(errs[2]))
+	assert.Equal(t, errors.KindContractInvalid, errors.Kind(errs[3]))
+	assert.Equal(t, errors.KindDuplicateName, errors.Kind(errs[4]), "the duplicate in the batch should be rejected")
+
+	count, err := client.EventTotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), count, "only the valid event should be added along with the existing one")
+	e, err := client.EventById(valid.Id)
+	require.NoError(t, err)
+	assert.Len(t, e.Readings, 1)
+	readingCount, err := client.ReadingCountByDeviceName("device2")
+	require.NoError(t, err)
+	assert.Zero(t, readingCount, "nothing of the rejected events should be added")
+}
+
+func TestEmbeddedClientReadingsBeforeCursor(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	for _, origin := range []int64{1, 2, 2, 2, 3, 4} {
+		_, err := client.AddEvent(testEvent("device1", origin))
+		require.NoError(t, err)
+	}
+
+	var origins []int64
+	seen := make(map[string]bool)
+	cursorOrigin, cursorId := 3, ""
+	for {
+		readings, err := client.ReadingsByDeviceNameAndResourceNameBeforeCursor("device1", testResourceName, 2, cursorOrigin, cursorId, 2)
+		require.NoError(t, err)
+		if len(readings) == 0 {
+			break
+		}
+		for _, r := range readings {
+			base := r.GetBaseReading()
+			assert.False(t, seen[base.Id], "the reading should not be repeated across the pages")
+			seen[base.Id] = true
+			origins = append(origins, base.Origin)
+		}
+		// the readings added after the cursor should not affect the following pages
+		_, err = client.AddEvent(testEvent("device1", 5))
+		require.NoError(t, err)
+		last := readings[len(readings)-1].GetBaseReading()
+		cursorOrigin, cursorId = int(last.Origin), last.Id
+	}
+	assert.Equal(t, []int64{3, 2, 2, 2}, origins, "the readings within the time range should be returned the newest first")
+}
+
+func TestEmbeddedClientMetadata(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	ds, err := client.AddDeviceService(models.DeviceService{Name: "service", BaseAddress: "http://localhost:59900", Labels: []string{"label"}})
+	require.NoError(t, err)
+	_, err = client.AddDeviceService(models.DeviceService{Name: "service", BaseAddress: "http://localhost:59900"})
+	require.Error(t, err)
+	assert.Equal(t, errors.KindDuplicateName, errors.Kind(err))
+
+	dp, err := client.AddDeviceProfile(models.DeviceProfile{Name: testProfileName, Manufacturer: "IOTech", Model: "m1", Labels: []string{"label"}}, "")
+	require.NoError(t, err)
+
+	for _, name := range []string{"device1", "device2"} {
+		_, err = client.AddDevice(models.Device{
+			Name:           name,
+			ServiceName:    ds.Name,
+			ProfileName:    dp.Name,
+			AdminState:     models.Unlocked,
+			OperatingState: models.Up,
+			Labels:         []string{"label", name},
+		})
+		require.NoError(t, err)
+	}
+
+	devices, err := client.DevicesByServiceName(0, -1, ds.Name)
+	require.NoError(t, err)
+	assert.Len(t, devices, 2)
+
+	devices, err = client.AllDevices(0, 10, []string{"label", "device2"})
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "device2", devices[0].Name)
+
+	profiles, count, err := client.DeviceProfilesByManufacturerAndModel(0, -1, "IOTech", "m1")
+	require.NoError(t, err)
+	assert.Len(t, profiles, 1)
+	assert.Equal(t, uint32(1), count)
+
+	device, err := client.DeviceByName("device1")
+	require.NoError(t, err)
+	device.Labels = []string{"updated"}
+	require.NoError(t, client.UpdateDevice(device))
+	count, err = client.DeviceCountByLabels([]string{"label"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), count)
+
+	require.NoError(t, client.DeleteDeviceByName("device1"))
+	exists, err := client.DeviceNameExists("device1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestEmbeddedClientSearchDevices(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	for _, d := range []models.Device{
+		{Name: "sensor-01", ServiceName: "service-a", ProfileName: "profile-p", AdminState: models.Unlocked, OperatingState: models.Up, DBTimestamp: models.DBTimestamp{Created: 3},
+			Labels: []string{"floor1"}, Protocols: map[string]models.ProtocolProperties{"modbus": {"Address": "10.0.0.1", "Port": "502"}}},
+		{Name: "sensor-02", ServiceName: "service-a", ProfileName: "profile-p", AdminState: models.Locked, OperatingState: models.Up, DBTimestamp: models.DBTimestamp{Created: 1},
+			Labels: []string{"floor1", "hot"}, Protocols: map[string]models.ProtocolProperties{"modbus": {"Address": "10.0.0.2", "Port": "502"}}},
+		{Name: "sensor-10", ServiceName: "service-b", ProfileName: "profile-p", AdminState: models.Unlocked, OperatingState: models.Down, DBTimestamp: models.DBTimestamp{Created: 2},
+			Labels: []string{"floor2"}, Protocols: map[string]models.ProtocolProperties{"mqtt": {"Topic": "t"}, "modbus": {"Port": "502"}}},
+		{Name: "camera-01", ServiceName: "service-b", ProfileName: "profile-q", AdminState: models.Unlocked, OperatingState: models.Up, DBTimestamp: models.DBTimestamp{Created: 4},
+			Protocols: map[string]models.ProtocolProperties{"onvif": {"Address": "10.0.0.1"}}},
+	} {
+		_, err := client.AddDevice(d)
+		require.NoError(t, err)
+	}
+	names := func(filter pkgModels.DeviceSearchFilter) []string {
+		devices, count, err := client.SearchDevices(0, -1, filter)
+		require.NoError(t, err)
+		result := make([]string, len(devices))
+		for i, d := range devices {
+			result[i] = d.Name
+		}
+		assert.Equal(t, uint32(len(devices)), count)
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		filter   pkgModels.DeviceSearchFilter
+		expected []string
+	}{
+		{"all devices", pkgModels.DeviceSearchFilter{}, []string{"camera-01", "sensor-01", "sensor-02", "sensor-10"}},
+		{"name prefix", pkgModels.DeviceSearchFilter{NamePattern: "sensor-0*"}, []string{"sensor-01", "sensor-02"}},
+		{"name suffix", pkgModels.DeviceSearchFilter{NamePattern: "*-01"}, []string{"camera-01", "sensor-01"}},
+		{"name glob", pkgModels.DeviceSearchFilter{NamePattern: "sensor-?0"}, []string{"sensor-10"}},
+		{"exact name", pkgModels.DeviceSearchFilter{NamePattern: "sensor-1"}, []string{}},
+		{"service and admin state", pkgModels.DeviceSearchFilter{ServiceName: "service-a", AdminState: models.Unlocked}, []string{"sensor-01"}},
+		{"profile and operating state", pkgModels.DeviceSearchFilter{ProfileName: "profile-p", OperatingState: models.Up}, []string{"sensor-01", "sensor-02"}},
+		{"labels", pkgModels.DeviceSearchFilter{Labels: []string{"floor1", "hot"}}, []string{"sensor-02"}},
+		{"protocol property", pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"Port": "502"}}, []string{"sensor-01", "sensor-02", "sensor-10"}},
+		{"protocol property of any protocol", pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"Address": "10.0.0.1"}}, []string{"camera-01", "sensor-01"}},
+		{"protocol properties", pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"Address": "10.0.0.1", "Port": "502"}}, []string{"sensor-01"}},
+		{"unknown service", pkgModels.DeviceSearchFilter{ServiceName: "unknown"}, []string{}},
+		{"sort by created", pkgModels.DeviceSearchFilter{SortBy: pkgModels.DeviceSortByCreated}, []string{"sensor-02", "sensor-10", "sensor-01", "camera-01"}},
+		{"name sorted by created descending", pkgModels.DeviceSearchFilter{NamePattern: "sensor*", SortBy: pkgModels.DeviceSortByCreated, Descending: true}, []string{"sensor-01", "sensor-10", "sensor-02"}},
+		{"sort by name descending", pkgModels.DeviceSearchFilter{Descending: true}, []string{"sensor-10", "sensor-02", "sensor-01", "camera-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(tt.filter))
+		})
+	}
+
+	devices, count, err := client.SearchDevices(1, 2, pkgModels.DeviceSearchFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), count)
+	require.Len(t, devices, 2)
+	assert.Equal(t, "sensor-01", devices[0].Name)
+	assert.Equal(t, "sensor-02", devices[1].Name)
+	_, _, err = client.SearchDevices(5, 2, pkgModels.DeviceSearchFilter{})
+	require.Error(t, err)
+	assert.Equal(t, errors.KindRangeNotSatisfiable, errors.Kind(err))
+
+	// the indexes follow the updates and deletions
+	time.Sleep(2 * time.Millisecond)
+	device, err := client.DeviceByName("sensor-02")
+	require.NoError(t, err)
+	device.AdminState = models.Unlocked
+	device.Protocols = map[string]models.ProtocolProperties{"modbus": {"Address": "10.0.0.3", "Port": "502"}}
+	require.NoError(t, client.UpdateDevice(device))
+	assert.Empty(t, names(pkgModels.DeviceSearchFilter{AdminState: models.Locked}))
+	assert.Empty(t, names(pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"Address": "10.0.0.2"}}))
+	assert.Equal(t, []string{"sensor-02"}, names(pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"Address": "10.0.0.3"}}))
+	assert.Equal(t, "sensor-02", names(pkgModels.DeviceSearchFilter{SortBy: pkgModels.DeviceSortByModified, Descending: true})[0])
+	require.NoError(t, client.DeleteDeviceByName("camera-01"))
+	assert.Equal(t, []string{"sensor-01", "sensor-02", "sensor-10"}, names(pkgModels.DeviceSearchFilter{}))
+
+	devices, count, err = client.SearchDevices(1, 0, pkgModels.DeviceSearchFilter{ServiceName: "service-a"})
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+	assert.Equal(t, uint32(2), count, "the total count should be returned without any device")
+}
+
+func TestEmbeddedClientSearchDevicesByProtocolProperties(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	for name, properties := range map[string]models.ProtocolProperties{"device-1": {"a|b": "c"}, "device-2": {"a": "b|c"}} {
+		_, err := client.AddDevice(models.Device{Name: name, Protocols: map[string]models.ProtocolProperties{"other": properties}})
+		require.NoError(t, err)
+	}
+	devices, _, err := client.SearchDevices(0, -1, pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"a|b": "c"}})
+	require.NoError(t, err)
+	require.Len(t, devices, 1, "the property names and values containing the key separator shouldn't collide")
+	assert.Equal(t, "device-1", devices[0].Name)
+	devices, _, err = client.SearchDevices(0, -1, pkgModels.DeviceSearchFilter{ProtocolProperties: map[string]string{"a": "b|c"}})
+	require.NoError(t, err)
+	require.Len(t, devices, 1, "the property names and values containing the key separator shouldn't collide")
+	assert.Equal(t, "device-2", devices[0].Name)
+}
+
+func TestEmbeddedClientSearchDevicesIndexing(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	for i, name := range []string{"device-b", "device-c", "device-a"} {
+		_, err := client.AddDevice(models.Device{Name: name, AdminState: models.Unlocked, DBTimestamp: models.DBTimestamp{Created: int64(i + 1)}})
+		require.NoError(t, err)
+	}
+	conn := client.Pool.Get()
+	defer conn.Close()
+	_, err := conn.Do(DEL, DeviceCollectionSortedName, DeviceCollectionCreated, CreateKey(DeviceCollectionAdminState, string(models.Unlocked)))
+	require.NoError(t, err)
+
+	// the devices stored without the search indexes are indexed by the first search
+	devices, count, edgeXerr := client.SearchDevices(0, -1, pkgModels.DeviceSearchFilter{AdminState: models.Unlocked})
+	require.NoError(t, edgeXerr)
+	assert.Equal(t, uint32(3), count)
+	require.Len(t, devices, 3)
+	assert.Equal(t, "device-a", devices[0].Name)
+	devices, _, edgeXerr = client.SearchDevices(0, 1, pkgModels.DeviceSearchFilter{SortBy: pkgModels.DeviceSortByCreated})
+	require.NoError(t, edgeXerr)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "device-b", devices[0].Name)
+	assert.True(t, client.deviceSearchIndexed, "the devices should be indexed once")
+}
+
+func TestEmbeddedClientRevisions(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	ds, err := client.AddDeviceService(models.DeviceService{Name: "service", BaseAddress: "http://localhost:59900"})
+	require.NoError(t, err)
+	_, revision, err := client.DeviceServiceAndRevisionByName(ds.Name)
+	require.NoError(t, err)
+	assert.Zero(t, revision, "the device service is at revision 0 until updated")
+
+	require.NoError(t, client.UpdateDeviceService(ds))
+	require.NoError(t, client.UpdateDeviceServiceWithRevision(ds, 1))
+	_, revision, err = client.DeviceServiceAndRevisionByName(ds.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), revision)
+
+	err = client.UpdateDeviceServiceWithRevision(ds, 1)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindStatusConflict, errors.Kind(err), "the stale revision is rejected")
+
+	// another client updates the device service between the watch and the transaction
+	conn := client.Pool.Get()
+	defer conn.Close()
+	revision, err = watchRevision(conn, deviceServiceStoredKey(ds.Id))
+	require.NoError(t, err)
+	require.NoError(t, client.UpdateDeviceService(ds))
+	_ = conn.Send(MULTI)
+	_ = conn.Send(SET, revisionKey(deviceServiceStoredKey(ds.Id)), revision+1)
+	err = execWatched(conn, "device service", ds.Name)
+	require.Error(t, err)
+	assert.Equal(t, errors.KindStatusConflict, errors.Kind(err), "the concurrent update aborts the transaction")
+	_, revision, err = client.DeviceServiceAndRevisionByName(ds.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), revision)
+
+	require.NoError(t, client.DeleteDeviceServiceByName(ds.Name))
+	_, err = client.AddDeviceService(ds)
+	require.NoError(t, err)
+	_, revision, err = client.DeviceServiceAndRevisionByName(ds.Name)
+	require.NoError(t, err)
+	assert.Zero(t, revision, "the revision is removed with the device service")
+}
+
+func TestEmbeddedClientDeviceServiceHealth(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	ds, err := client.AddDeviceService(models.DeviceService{Name: "service", BaseAddress: "http://localhost:59900"})
+	require.NoError(t, err)
+	require.NoError(t, client.UpdateDeviceServiceLastConnected(ds.Name, 100))
+	stored, revision, err := client.DeviceServiceAndRevisionByName(ds.Name)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), stored.LastConnected)
+	assert.Equal(t, ds.Modified, stored.Modified, "the last connected time isn't a modification by the user")
+	assert.Zero(t, revision, "the last connected time shouldn't change the revision")
+
+	err = client.UpdateDeviceServiceLastConnected("unknown", 100)
+	assert.Equal(t, errors.KindEntityDoesNotExist, errors.Kind(err))
+
+	require.NoError(t, client.AddDevicesDownByHealthCheck(ds.Name, []string{"device1", "device2"}))
+	require.NoError(t, client.AddDevicesDownByHealthCheck(ds.Name, []string{"device2"}))
+	names, err := client.DevicesDownByHealthCheck(ds.Name)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"device1", "device2"}, names)
+	require.NoError(t, client.DeleteDevicesDownByHealthCheck(ds.Name))
+	names, err = client.DevicesDownByHealthCheck(ds.Name)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	require.NoError(t, client.AddDevicesDownByHealthCheck(ds.Name, []string{"device1"}))
+	require.NoError(t, client.DeleteDeviceServiceByName(ds.Name))
+	names, err = client.DevicesDownByHealthCheck(ds.Name)
+	require.NoError(t, err)
+	assert.Empty(t, names, "the devices marked DOWN are forgotten with the device service")
+}
+
+func TestEmbeddedClientSupport(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	interval, err := client.AddInterval(models.Interval{Name: "midnight", Start: "20000101T000000", Interval: "24h"})
+	require.NoError(t, err)
+	_, err = client.AddIntervalAction(models.IntervalAction{Name: "action", IntervalName: interval.Name, Address: models.RESTAddress{
+		BaseAddress: models.BaseAddress{Type: common.REST, Host: "localhost", Port: 59880},
+		HTTPMethod:  "DELETE",
+	}})
+	require.NoError(t, err)
+	actions, err := client.IntervalActionsByIntervalName(0, -1, interval.Name)
+	require.NoError(t, err)
+	assert.Len(t, actions, 1)
+
+	_, err = client.AddSubscription(models.Subscription{Name: "subscription", Categories: []string{"category"}, Labels: []string{"label"}, Receiver: "receiver"})
+	require.NoError(t, err)
+	notification, err := client.AddNotification(models.Notification{Category: "category", Labels: []string{"label"}, Content: "content", Sender: "sender", Severity: models.Normal, Status: models.New})
+	require.NoError(t, err)
+
+	subscriptions, err := client.SubscriptionsByCategoriesAndLabels(0, 10, []string{"category"}, []string{"label"})
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	notifications, err := client.NotificationsByCategoriesAndLabels(0, 10, []string{"category"}, []string{"label"})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, notification.Id, notifications[0].Id)
+}
+
+func TestEmbeddedClientPruneEvents(t *testing.T) {
+	client := newEmbeddedTestClient(t)
+
+	now := time.Now().UnixNano()
+	for i, deviceName := range []string{"device1", "device1", "device2", "device2", "device2", "device3"} {
+		_, err := client.AddEvent(testEvent(deviceName, now-int64(6-i)*int64(time.Hour)))
+		require.NoError(t, err)
+	}
+	// the newest events of device1, device2 and device3 are 5h, 2h and 1h old respectively
+
+	purged, err := client.PruneEvents(int64(5*time.Hour+30*time.Minute), 3, []string{"device3"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), purged, "the 6h old event should be purged by age and the 5h old one by count")
+	require.Eventually(t, func() bool {
+		count, err := client.EventTotalCount()
+		return err == nil && count == 4
+	}, time.Second, 10*time.Millisecond)
+	count, err := client.EventCountByDeviceName("device1")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), count)
+	count, err = client.ReadingTotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), count)
+
+	purged, err = client.PruneEventsByDeviceName("device2", 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), purged)
+	require.Eventually(t, func() bool {
+		count, err := client.EventCountByDeviceName("device2")
+		return err == nil && count == 1
+	}, time.Second, 10*time.Millisecond)
+
+	purged, err = client.PruneEvents(0, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), purged, "nothing should be purged without any limit")
+}
@@ -118,6 +118,64 @@ func (c *Client) DeleteEventsByAge(age int64) (edgeXerr errors.EdgeX) {
 	return nil
 }
 
+// PruneEvents deletes the events, except the ones of the excluded devices, which are older than age or are not among
+// the newest maxCount events.  A zero age or maxCount means no limit.  Like DeleteEventsByAge, the events and their
+// readings are deleted in the background, and the number of events to be deleted is returned.
+func (c *Client) PruneEvents(age int64, maxCount uint32, excludedDeviceNames []string) (uint32, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	excludedIds := make(map[string]bool)
+	for _, deviceName := range excludedDeviceNames {
+		key := CreateKey(EventsCollectionDeviceName, deviceName)
+		eventIds, err := redis.Strings(conn.Do(ZRANGE, key, 0, -1))
+		if err != nil {
+			return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("retrieve event ids by key %s failed", key), err)
+		}
+		for _, id := range eventIds {
+			excludedIds[id] = true
+		}
+	}
+
+	eventIds, edgeXerr := c.eventIdsToPrune(conn, EventsCollectionOrigin, age, maxCount, excludedIds)
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return c.asyncDeleteEventsAndReadings(conn, eventIds)
+}
+
+// PruneEventsByDeviceName deletes specific device's events which are older than age or are not among the newest
+// maxCount events of the device.  A zero age or maxCount means no limit.  Like DeleteEventsByDeviceName, the events and
+// their readings are deleted in the background, and the number of events to be deleted is returned.
+func (c *Client) PruneEventsByDeviceName(deviceName string, age int64, maxCount uint32) (uint32, errors.EdgeX) {
+	conn := c.Pool.Get()
+	defer conn.Close()
+
+	eventIds, edgeXerr := c.eventIdsToPrune(conn, CreateKey(EventsCollectionDeviceName, deviceName), age, maxCount, nil)
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return c.asyncDeleteEventsAndReadings(conn, eventIds)
+}
+
+// asyncDeleteEventsAndReadings starts up two goroutines to delete the events with given event Ids and their readings in
+// the background, and returns the number of events to be deleted
+func (c *Client) asyncDeleteEventsAndReadings(conn redis.Conn, eventIds []string) (uint32, errors.EdgeX) {
+	if len(eventIds) == 0 {
+		return 0, nil
+	}
+	readingIds, edgeXerr := readingIdsByEventIds(conn, eventIds)
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	c.loggingClient.Debug(fmt.Sprintf("Prepare to delete %v readings", len(readingIds)))
+	go c.asyncDeleteReadingsByIds(readingIds)
+	c.loggingClient.Debug(fmt.Sprintf("Prepare to delete %v events", len(eventIds)))
+	go c.asyncDeleteEventsByIds(eventIds)
+
+	return uint32(len(eventIds)), nil
+}
+
 // ************************** DB HELPER FUNCTIONS ***************************
 // eventStoredKey return the event's stored key which combines the collection name and object id
 func eventStoredKey(id string) string {
@@ -130,8 +188,84 @@ func addEvent(conn redis.Conn, e models.Event) (addedEvent models.Event, edgeXer
 	if errors.Kind(edgeXerr) != errors.KindEntityDoesNotExist {
 		return addedEvent, errors.NewCommonEdgeX(errors.KindDuplicateName, "Event Id exists", nil)
 	}
-	edgeXerr = nil
 
+	_ = conn.Send(MULTI)
+	e, edgeXerr = sendAddEvent(conn, e)
+	if edgeXerr != nil {
+		return addedEvent, edgeXerr
+	}
+
+	_, err := conn.Do(EXEC)
+	if err != nil {
+		edgeXerr = errors.NewCommonEdgeX(errors.KindDatabaseError, "event creation failed", err)
+	}
+
+	return e, edgeXerr
+}
+
+// addEvents adds the events in a single transaction and returns the added events along with the error of each event.
+// An event is rejected alone when its Id exists or its readings fail the parsing, while all the other events are
+// rejected when the transaction fails.
+func addEvents(conn redis.Conn, events []models.Event) (addedEvents []models.Event, errs []errors.EdgeX) {
+	addedEvents = make([]models.Event, len(events))
+	errs = make([]errors.EdgeX, len(events))
+
+	// pipeline the existence check of all event Ids to avoid the Id conflict
+	for _, e := range events {
+		_ = conn.Send(EXISTS, eventStoredKey(e.Id))
+	}
+	err := conn.Flush()
+	if err != nil {
+		edgeXerr := errors.NewCommonEdgeX(errors.KindDatabaseError, "event existence check failed", err)
+		for i := range errs {
+			errs[i] = edgeXerr
+		}
+		return addedEvents, errs
+	}
+	ids := make(map[string]bool, len(events))
+	for i, e := range events {
+		exists, err := redis.Bool(conn.Receive())
+		if err != nil {
+			errs[i] = errors.NewCommonEdgeX(errors.KindDatabaseError, "event existence check failed", err)
+		} else if exists || ids[e.Id] {
+			errs[i] = errors.NewCommonEdgeX(errors.KindDuplicateName, "Event Id exists", nil)
+		}
+		ids[e.Id] = true
+	}
+
+	_ = conn.Send(MULTI)
+	queued := 0
+	for i, e := range events {
+		if errs[i] != nil {
+			continue
+		}
+		addedEvents[i], errs[i] = sendAddEvent(conn, e)
+		if errs[i] == nil {
+			queued++
+		}
+	}
+	if queued == 0 {
+		_, _ = conn.Do(DISCARD)
+		return addedEvents, errs
+	}
+
+	_, err = conn.Do(EXEC)
+	if err != nil {
+		edgeXerr := errors.NewCommonEdgeX(errors.KindDatabaseError, "events creation failed", err)
+		for i := range events {
+			if errs[i] == nil {
+				addedEvents[i] = models.Event{}
+				errs[i] = edgeXerr
+			}
+		}
+	}
+
+	return addedEvents, errs
+}
+
+// sendAddEvent sends the commands to add the event and its readings, which must be sent in a transaction.  The event
+// and readings are all parsed before sending any command, so nothing is sent when the parsing fails.
+func sendAddEvent(conn redis.Conn, e models.Event) (models.Event, errors.EdgeX) {
 	event := models.Event{
 		Id:          e.Id,
 		DeviceName:  e.DeviceName,
@@ -143,11 +277,21 @@ func addEvent(conn redis.Conn, e models.Event) (addedEvent models.Event, edgeXer
 
 	m, err := json.Marshal(event)
 	if err != nil {
-		return addedEvent, errors.NewCommonEdgeX(errors.KindContractInvalid, "event parsing failed", err)
+		return models.Event{}, errors.NewCommonEdgeX(errors.KindContractInvalid, "event parsing failed", err)
+	}
+
+	var newReadings []models.Reading
+	readingBlobs := make([][]byte, len(e.Readings))
+	for i, r := range e.Readings {
+		newReading, rm, edgeXerr := marshalReading(r)
+		if edgeXerr != nil {
+			return models.Event{}, edgeXerr
+		}
+		newReadings = append(newReadings, newReading)
+		readingBlobs[i] = rm
 	}
 
 	storedKey := eventStoredKey(e.Id)
-	_ = conn.Send(MULTI)
 	// use the SET command to save event as blob
 	_ = conn.Send(SET, storedKey, m)
 	_ = conn.Send(ZADD, EventsCollection, e.Origin, storedKey)
@@ -158,13 +302,8 @@ func addEvent(conn redis.Conn, e models.Event) (addedEvent models.Event, edgeXer
 	// sort by the order provided by device service
 	rids := make([]interface{}, len(e.Readings)*2+1)
 	rids[0] = CreateKey(EventsCollectionReadings, e.Id)
-	var newReadings []models.Reading
-	for i, r := range e.Readings {
-		newReading, err := addReading(conn, r)
-		if err != nil {
-			return models.Event{}, err
-		}
-		newReadings = append(newReadings, newReading)
+	for i, newReading := range newReadings {
+		sendAddReading(conn, newReading.GetBaseReading(), readingBlobs[i])
 
 		// set the sorted set score to the index of the reading
 		rids[i*2+1] = i
@@ -175,12 +314,7 @@ func addEvent(conn redis.Conn, e models.Event) (addedEvent models.Event, edgeXer
 		_ = conn.Send(ZADD, rids...)
 	}
 
-	_, err = conn.Do(EXEC)
-	if err != nil {
-		edgeXerr = errors.NewCommonEdgeX(errors.KindDatabaseError, "event creation failed", err)
-	}
-
-	return e, edgeXerr
+	return e, nil
 }
 
 func deleteEventById(conn redis.Conn, id string) (edgeXerr errors.EdgeX) {
@@ -223,15 +357,73 @@ func getEventReadingIdsByKeyScoreRange(conn redis.Conn, key string, min string,
 	if err != nil {
 		return nil, nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("retrieve event ids by key %s failed", key), err)
 	}
+	readingIds, edgeXerr = readingIdsByEventIds(conn, eventIds)
+	if edgeXerr != nil {
+		return nil, nil, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return eventIds, readingIds, nil
+}
+
+func readingIdsByEventIds(conn redis.Conn, eventIds []string) (readingIds []string, edgeXerr errors.EdgeX) {
 	for _, storeKey := range eventIds {
 		eId := idFromStoredKey(storeKey)
 		rIds, err := redis.Strings(conn.Do(ZRANGE, CreateKey(EventsCollectionReadings, eId), 0, -1))
 		if err != nil {
-			return nil, nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("retrieve all reading Ids of event %s failed", eId), err)
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("retrieve all reading Ids of event %s failed", eId), err)
 		}
 		readingIds = append(readingIds, rIds...)
 	}
-	return eventIds, readingIds, nil
+	return readingIds, nil
+}
+
+// eventIdsToPrune returns the ids of the events in the sorted set of key, except the excluded ones, which are older than
+// age or are not among the newest maxCount events.  The newer events are walked through in batches from the oldest one.
+func (c *Client) eventIdsToPrune(conn redis.Conn, key string, age int64, maxCount uint32, excludedIds map[string]bool) (eventIds []string, edgeXerr errors.EdgeX) {
+	agedCount := 0
+	if age > 0 {
+		expireTimestamp := time.Now().UnixNano() - age
+		agedIds, err := redis.Strings(conn.Do(ZRANGEBYSCORE, key, "0", strconv.FormatInt(expireTimestamp, 10)))
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("retrieve event ids by key %s failed", key), err)
+		}
+		agedCount = len(agedIds)
+		for _, id := range agedIds {
+			if !excludedIds[id] {
+				eventIds = append(eventIds, id)
+			}
+		}
+	}
+	if maxCount == 0 {
+		return eventIds, nil
+	}
+
+	totalCount, edgeXerr := getMemberNumber(conn, ZCARD, key)
+	if edgeXerr != nil {
+		return nil, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	// the excluded events which are not aged remain in the sorted set as well
+	agedExcludedCount := agedCount - len(eventIds)
+	excess := int(totalCount) - agedCount - (len(excludedIds) - agedExcludedCount) - int(maxCount)
+	for start := agedCount; excess > 0; start += c.BatchSize {
+		ids, err := redis.Strings(conn.Do(ZRANGE, key, start, start+c.BatchSize-1))
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("retrieve event ids by key %s failed", key), err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+		for _, id := range ids {
+			if excludedIds[id] {
+				continue
+			}
+			eventIds = append(eventIds, id)
+			excess--
+			if excess == 0 {
+				break
+			}
+		}
+	}
+	return eventIds, nil
 }
 
 func eventById(conn redis.Conn, id string) (event models.Event, edgeXerr errors.EdgeX) {
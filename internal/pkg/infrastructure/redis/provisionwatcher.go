@@ -193,6 +193,7 @@ func deleteProvisionWatcher(conn redis.Conn, pw models.ProvisionWatcher) errors.
 	storedKey := provisionWatcherStoredKey(pw.Id)
 	_ = conn.Send(MULTI)
 	sendDeleteProvisionWatcherCmd(conn, storedKey, pw)
+	_ = conn.Send(DEL, revisionKey(storedKey))
 	_, err := conn.Do(EXEC)
 	if err != nil {
 		return errors.NewCommonEdgeX(errors.KindDatabaseError, "provision watcher deletion failed", err)
@@ -201,24 +202,43 @@ func deleteProvisionWatcher(conn redis.Conn, pw models.ProvisionWatcher) errors.
 	return nil
 }
 
-func updateProvisionWatcher(conn redis.Conn, pw models.ProvisionWatcher) errors.EdgeX {
-	oldProvisionWatcher, edgexErr := provisionWatcherByName(conn, pw.Name)
-	if edgexErr != nil {
-		return errors.NewCommonEdgeXWrapper(edgexErr)
+// provisionWatcherAndRevisionByName query provision watcher and its revision by name from DB
+func provisionWatcherAndRevisionByName(conn redis.Conn, name string) (pw models.ProvisionWatcher, revision uint64, edgeXerr errors.EdgeX) {
+	revision, edgeXerr = getObjectAndRevisionByHash(conn, ProvisionWatcherCollectionName, name, &pw)
+	if edgeXerr != nil {
+		return pw, revision, errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
+	return
+}
 
-	pw.Modified = pkgCommon.MakeTimestamp()
+// updateProvisionWatcher updates the provision watcher and increases its revision, the update is rejected if the
+// provision watcher is at a revision other than the expected one, or if the provision watcher is modified by another client at
+// the same time
+func updateProvisionWatcher(conn redis.Conn, pw models.ProvisionWatcher, expectedRevision *uint64) errors.EdgeX {
 	storedKey := provisionWatcherStoredKey(pw.Id)
-	_ = conn.Send(MULTI)
-	sendDeleteProvisionWatcherCmd(conn, storedKey, oldProvisionWatcher)
-	edgexErr = sendAddProvisionWatcherCmd(conn, storedKey, pw)
-	if edgexErr != nil {
-		return errors.NewCommonEdgeXWrapper(edgexErr)
+	revision, edgeXerr := watchRevision(conn, storedKey)
+	if edgeXerr != nil {
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
-	_, err := conn.Do(EXEC)
-	if err != nil {
-		return errors.NewCommonEdgeX(errors.KindDatabaseError, "provision watcher update failed", err)
+	oldProvisionWatcher, edgeXerr := provisionWatcherByName(conn, pw.Name)
+	if edgeXerr != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	edgeXerr = checkRevision("provision watcher", pw.Name, revision, expectedRevision)
+	if edgeXerr != nil {
+		unwatch(conn)
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
 	}
 
-	return nil
+	pw.Modified = pkgCommon.MakeTimestamp()
+	_ = conn.Send(MULTI)
+	sendDeleteProvisionWatcherCmd(conn, storedKey, oldProvisionWatcher)
+	edgeXerr = sendAddProvisionWatcherCmd(conn, storedKey, pw)
+	if edgeXerr != nil {
+		_, _ = conn.Do(DISCARD)
+		return errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	_ = conn.Send(SET, revisionKey(storedKey), revision+1)
+	return execWatched(conn, "provision watcher", pw.Name)
 }
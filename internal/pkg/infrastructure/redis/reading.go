@@ -8,6 +8,7 @@ package redis
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
@@ -91,43 +92,43 @@ func readingStoredKey(id string) string {
 	return CreateKey(ReadingsCollection, id)
 }
 
-// Add a reading to the database
-func addReading(conn redis.Conn, r models.Reading) (reading models.Reading, edgeXerr errors.EdgeX) {
-	var m []byte
+// marshalReading returns the reading to be stored and its marshaled blob
+func marshalReading(r models.Reading) (reading models.Reading, m []byte, edgeXerr errors.EdgeX) {
 	var err error
-	var baseReading *models.BaseReading
 	switch newReading := r.(type) {
 	case models.BinaryReading:
 		// Clear the binary data since we do not want to persist binary data to save on memory.
 		newReading.BinaryValue = emptyBinaryValue
 
-		baseReading = &newReading.BaseReading
-		if err = checkReadingValue(baseReading); err != nil {
-			return nil, errors.NewCommonEdgeXWrapper(err)
+		if err = checkReadingValue(&newReading.BaseReading); err != nil {
+			return nil, nil, errors.NewCommonEdgeXWrapper(err)
 		}
 		m, err = json.Marshal(newReading)
 		reading = newReading
 	case models.SimpleReading:
-		baseReading = &newReading.BaseReading
-		if err = checkReadingValue(baseReading); err != nil {
-			return nil, errors.NewCommonEdgeXWrapper(err)
+		if err = checkReadingValue(&newReading.BaseReading); err != nil {
+			return nil, nil, errors.NewCommonEdgeXWrapper(err)
 		}
 		m, err = json.Marshal(newReading)
 		reading = newReading
 	case models.ObjectReading:
-		baseReading = &newReading.BaseReading
-		if err = checkReadingValue(baseReading); err != nil {
-			return nil, errors.NewCommonEdgeXWrapper(err)
+		if err = checkReadingValue(&newReading.BaseReading); err != nil {
+			return nil, nil, errors.NewCommonEdgeXWrapper(err)
 		}
 		m, err = json.Marshal(newReading)
 		reading = newReading
 	default:
-		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "unsupported reading type", nil)
+		return nil, nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "unsupported reading type", nil)
 	}
 
 	if err != nil {
-		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "reading parsing failed", err)
+		return nil, nil, errors.NewCommonEdgeX(errors.KindContractInvalid, "reading parsing failed", err)
 	}
+	return reading, m, nil
+}
+
+// sendAddReading sends the commands to add the marshaled reading to the database
+func sendAddReading(conn redis.Conn, baseReading models.BaseReading, m []byte) {
 	storedKey := readingStoredKey(baseReading.Id)
 	// use the SET command to save reading as blob
 	_ = conn.Send(SET, storedKey, m)
@@ -136,8 +137,6 @@ func addReading(conn redis.Conn, r models.Reading) (reading models.Reading, edge
 	_ = conn.Send(ZADD, CreateKey(ReadingsCollectionDeviceName, baseReading.DeviceName), baseReading.Origin, storedKey)
 	_ = conn.Send(ZADD, CreateKey(ReadingsCollectionResourceName, baseReading.ResourceName), baseReading.Origin, storedKey)
 	_ = conn.Send(ZADD, CreateKey(ReadingsCollectionDeviceNameResourceName, baseReading.DeviceName, baseReading.ResourceName), baseReading.Origin, storedKey)
-
-	return reading, nil
 }
 
 // Remove a reading out of the database
@@ -235,6 +234,56 @@ func readingsByDeviceNameAndResourceNameAndTimeRange(conn redis.Conn, deviceName
 	return convertObjectsToReadings(objects)
 }
 
+// readingsByDeviceNameAndResourceNameBeforeCursor queries the readings of the device resource whose origin is not less
+// than startTime, and which are ordered after the cursor of origin and id.  The sorted set orders the members of the
+// same score by the stored key, which contains the reading id, so the cursor is stable however the readings are added
+// or removed between the queries.  All the readings of cursorOrigin are included when cursorId is empty.
+func readingsByDeviceNameAndResourceNameBeforeCursor(conn redis.Conn, deviceName string, resourceName string, startTime int, cursorOrigin int, cursorId string, limit int) (readings []models.Reading, edgeXerr errors.EdgeX) {
+	if limit == 0 {
+		return
+	}
+	key := CreateKey(ReadingsCollectionDeviceNameResourceName, deviceName, resourceName)
+	var ids []string
+	max := strconv.Itoa(cursorOrigin)
+	if cursorId != "" {
+		// ZREVRANGEBYSCORE returns the members of the same score in the descending order of the member, so the readings
+		// of the cursor origin are kept when their stored key is less than the one of the cursor
+		sameOriginIds, err := redis.Strings(conn.Do(ZREVRANGEBYSCORE, key, max, max))
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "query readings by cursor failed", err)
+		}
+		cursorKey := readingStoredKey(cursorId)
+		for _, id := range sameOriginIds {
+			if id < cursorKey {
+				ids = append(ids, id)
+			}
+		}
+		max = "(" + max
+	}
+	if limit < 0 || len(ids) < limit {
+		remaining := -1
+		if limit > 0 {
+			remaining = limit - len(ids)
+		}
+		olderIds, err := redis.Strings(conn.Do(ZREVRANGEBYSCORE, key, max, startTime, LIMIT, 0, remaining))
+		if err != nil {
+			return nil, errors.NewCommonEdgeX(errors.KindDatabaseError, "query readings by cursor failed", err)
+		}
+		ids = append(ids, olderIds...)
+	} else {
+		ids = ids[:limit]
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	objects, edgeXerr := getObjectsByIds(conn, pkgCommon.ConvertStringsToInterfaces(ids))
+	if edgeXerr != nil {
+		return nil, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return convertObjectsToReadings(objects)
+}
+
 func readingsByDeviceNameAndResourceNamesAndTimeRange(conn redis.Conn, deviceName string, resourceNames []string, startTime int, endTime int, offset int, limit int) (readings []models.Reading, totalCount uint32, err errors.EdgeX) {
 	var redisKeys []string
 	for _, resourceName := range resourceNames {
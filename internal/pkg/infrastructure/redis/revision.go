@@ -0,0 +1,118 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// RevisionKeyword is appended to the stored key of an object to make the key of its revision, which counts the updates
+// of the object.  An object which has never been updated has no revision key, and its revision is 0.
+const RevisionKeyword = "revision"
+
+// revisionKey returns the key of the revision of the object stored with storedKey
+func revisionKey(storedKey string) string {
+	return CreateKey(storedKey, RevisionKeyword)
+}
+
+// getObjectAndRevisionByHash retrieves the stored key with associated field from the hash, and then retrieves the object
+// and its revision atomically
+func getObjectAndRevisionByHash(conn redis.Conn, hash string, field string, out interface{}) (uint64, errors.EdgeX) {
+	storedKey, err := redis.String(conn.Do(HGET, hash, field))
+	if err == redis.ErrNil {
+		return 0, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("fail to query object %T, because %s: %s doesn't exist in the database", out, field, hash), err)
+	} else if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query %s from the database failed", field), err)
+	}
+	return getObjectAndRevision(conn, storedKey, revisionKey(storedKey), out)
+}
+
+// getObjectAndRevision retrieves the object of the stored key and the revision stored at revKey atomically
+func getObjectAndRevision(conn redis.Conn, storedKey string, revKey string, out interface{}) (uint64, errors.EdgeX) {
+	_ = conn.Send(MULTI)
+	_ = conn.Send(GET, storedKey)
+	_ = conn.Send(GET, revKey)
+	replies, err := redis.Values(conn.Do(EXEC))
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query object %T and its revision from the database failed", out), err)
+	}
+	obj, err := redis.Bytes(replies[0], nil)
+	if err == redis.ErrNil {
+		return 0, errors.NewCommonEdgeX(errors.KindEntityDoesNotExist, fmt.Sprintf("fail to query object %T, because id: %s doesn't exist in the database", out, storedKey), err)
+	} else if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("query object %T by id from the database failed", out), err)
+	}
+	revision, edgeXerr := revisionFromReply(replies[1], nil)
+	if edgeXerr != nil {
+		return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+
+	err = json.Unmarshal(obj, out)
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("object %T format parsing failed from the database", out), err)
+	}
+	return revision, nil
+}
+
+// watchRevision watches the object and its revision, so that the following transaction is aborted if another client
+// modifies the object in the meantime, and returns the current revision of the object
+func watchRevision(conn redis.Conn, storedKey string) (uint64, errors.EdgeX) {
+	_, err := conn.Do(WATCH, storedKey, revisionKey(storedKey))
+	if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "failed to watch the object", err)
+	}
+	revision, edgeXerr := revisionFromReply(conn.Do(GET, revisionKey(storedKey)))
+	if edgeXerr != nil {
+		unwatch(conn)
+		return 0, errors.NewCommonEdgeXWrapper(edgeXerr)
+	}
+	return revision, nil
+}
+
+// unwatch forgets the watched keys when the transaction is given up
+func unwatch(conn redis.Conn) {
+	_, _ = conn.Do(UNWATCH)
+}
+
+// checkRevision checks the current revision of the object against the expected one, if any.  The expected revision is
+// the one read by core-metadata before the update, which has been checked against the If-Match header already, so the
+// mismatch means another client updated the object in the meantime, i.e. a conflict rather than a failed precondition.
+func checkRevision(objectType string, name string, current uint64, expected *uint64) errors.EdgeX {
+	if expected != nil && *expected != current {
+		return errors.NewCommonEdgeX(errors.KindStatusConflict,
+			fmt.Sprintf("%s %s is at revision %d rather than the expected revision %d", objectType, name, current, *expected), nil)
+	}
+	return nil
+}
+
+// execWatched executes the transaction started after watchRevision.  The transaction is aborted by Redis, without
+// applying any command, if the watched object was modified by another client.
+func execWatched(conn redis.Conn, objectType string, name string) errors.EdgeX {
+	reply, err := conn.Do(EXEC)
+	if err != nil {
+		return errors.NewCommonEdgeX(errors.KindDatabaseError, fmt.Sprintf("%s update failed", objectType), err)
+	}
+	if reply == nil {
+		return errors.NewCommonEdgeX(errors.KindStatusConflict,
+			fmt.Sprintf("%s %s was modified by another update at the same time", objectType, name), nil)
+	}
+	return nil
+}
+
+func revisionFromReply(reply interface{}, err error) (uint64, errors.EdgeX) {
+	revision, err := redis.Uint64(reply, err)
+	if err == redis.ErrNil {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.NewCommonEdgeX(errors.KindDatabaseError, "query the revision from the database failed", err)
+	}
+	return revision, nil
+}
@@ -0,0 +1,20 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+// DeviceProfileRevision is the immutable snapshot of a device profile recorded when the device profile is added or
+// updated.  The revisions of a device profile are numbered from 1 in the order they are recorded.
+type DeviceProfileRevision struct {
+	Revision      uint32
+	ProfileName   string
+	CorrelationId string
+	Created       int64
+	Profile       models.DeviceProfile
+}
@@ -0,0 +1,34 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+// The fields the device search results can be sorted by
+const (
+	DeviceSortByName     = "name"
+	DeviceSortByCreated  = "created"
+	DeviceSortByModified = "modified"
+)
+
+// DeviceSearchFilter selects the devices which match all the specified criteria, the empty fields match any device
+type DeviceSearchFilter struct {
+	// NamePattern is a glob pattern such as "sensor-*" matched against the whole device name
+	NamePattern    string
+	ServiceName    string
+	ProfileName    string
+	AdminState     models.AdminState
+	OperatingState models.OperatingState
+	// Labels must all be labels of the device
+	Labels []string
+	// ProtocolProperties must all be properties of the device, possibly from different protocols
+	ProtocolProperties map[string]string
+	// SortBy is one of DeviceSortByName, DeviceSortByCreated and DeviceSortByModified, and defaults to the name
+	SortBy     string
+	Descending bool
+}
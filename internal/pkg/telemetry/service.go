@@ -0,0 +1,55 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+)
+
+// ServiceMetrics holds the service specific metrics which are reported by the metrics endpoint in addition to the
+// system usage.  It is safe for concurrent use.
+type ServiceMetrics struct {
+	mutex   sync.RWMutex
+	metrics map[string]interface{}
+}
+
+// NewServiceMetrics creates an empty ServiceMetrics
+func NewServiceMetrics() *ServiceMetrics {
+	return &ServiceMetrics{metrics: make(map[string]interface{})}
+}
+
+// Set sets the metric of name to value, which must be serializable to JSON and must not be modified afterwards
+func (s *ServiceMetrics) Set(name string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.metrics[name] = value
+}
+
+// All returns a copy of all metrics keyed by name
+func (s *ServiceMetrics) All() map[string]interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	metrics := make(map[string]interface{}, len(s.metrics))
+	for name, value := range s.metrics {
+		metrics[name] = value
+	}
+	return metrics
+}
+
+// ServiceMetricsName contains the name of the ServiceMetrics implementation in the DIC.
+var ServiceMetricsName = di.TypeInstanceToName((*ServiceMetrics)(nil))
+
+// ServiceMetricsFrom helper function queries the DIC and returns the ServiceMetrics implementation, or nil when the
+// service does not report any service specific metrics.
+func ServiceMetricsFrom(get di.Get) *ServiceMetrics {
+	metrics, ok := get(ServiceMetricsName).(*ServiceMetrics)
+	if !ok {
+		return nil
+	}
+	return metrics
+}
@@ -0,0 +1,33 @@
+//
+// Copyright (C) 2021 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-bootstrap/v2/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceMetrics(t *testing.T) {
+	dic := di.NewContainer(di.ServiceConstructorMap{})
+	assert.Nil(t, ServiceMetricsFrom(dic.Get), "nil should be returned when no ServiceMetrics is registered")
+
+	dic.Update(di.ServiceConstructorMap{
+		ServiceMetricsName: func(get di.Get) interface{} {
+			return NewServiceMetrics()
+		},
+	})
+	metrics := ServiceMetricsFrom(dic.Get)
+	require.NotNil(t, metrics)
+
+	metrics.Set("purged", 1)
+	all := metrics.All()
+	metrics.Set("purged", 2)
+	assert.Equal(t, map[string]interface{}{"purged": 1}, all, "a copy of the metrics should be returned")
+	assert.Equal(t, 2, metrics.All()["purged"])
+}
@@ -6,8 +6,10 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	stdErrors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/edgexfoundry/edgex-go/internal/pkg"
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/correlation"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
@@ -52,6 +55,42 @@ func WriteErrorResponse(w http.ResponseWriter, ctx context.Context, lc logger.Lo
 	pkg.EncodeAndWriteResponse(errResponses, w, lc)
 }
 
+// WriteETagHeader sets the ETag header of the response to the revision of the entity, it must be called before
+// WriteHttpHeader
+func WriteETagHeader(w http.ResponseWriter, revision uint64) {
+	w.Header().Set(pkgCommon.ETag, strconv.Quote(strconv.FormatUint(revision, 10)))
+}
+
+// ParseIfMatchRevision parses the revision of the entity expected by the If-Match header, which is the ETag returned by
+// WriteETagHeader.  nil is returned if the header is absent or is "*", which matches any revision.
+func ParseIfMatchRevision(r *http.Request) (*uint64, errors.EdgeX) {
+	value := strings.TrimSpace(r.Header.Get(pkgCommon.IfMatch))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	if strings.HasPrefix(value, "W/") {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("weak entity tag %s is not allowed by %s", value, pkgCommon.IfMatch), nil)
+	}
+	revision, err := strconv.ParseUint(strings.Trim(value, "\""), 10, 64)
+	if err != nil {
+		return nil, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("failed to parse %s header's value %s into revision", pkgCommon.IfMatch, value), err)
+	}
+	return &revision, nil
+}
+
+// ErrRevisionMismatch is wrapped by the error rejecting the update of the entity which isn't at the revision expected by
+// the If-Match header
+var ErrRevisionMismatch = stdErrors.New("revision mismatch")
+
+// IfMatchStatusCode returns the status code of the error, which is 412 Precondition Failed if the error wraps
+// ErrRevisionMismatch, as errors.EdgeX has no kind for it
+func IfMatchStatusCode(err errors.EdgeX) int {
+	if stdErrors.Is(err, ErrRevisionMismatch) {
+		return http.StatusPreconditionFailed
+	}
+	return err.Code()
+}
+
 // ParseGetAllObjectsRequestQueryString parses offset, limit and labels from the query parameters. And use maximum and minimum to check whether the offset and limit are valid.
 func ParseGetAllObjectsRequestQueryString(r *http.Request, minOffset int, maxOffset int, minLimit int, maxLimit int) (offset int, limit int, labels []string, err errors.EdgeX) {
 	offset, err = ParseQueryStringToInt(r, common.Offset, common.DefaultOffset, minOffset, maxOffset)
@@ -127,6 +166,22 @@ func ParseQueryStringToString(r *http.Request, queryStringKey string, defaultVal
 	return value[0]
 }
 
+// Parse the specified query string key to a boolean.  If specified query string key is found more than once in the
+// http request, only the first specified query string will be parsed and converted to a boolean.  If no specified query
+// string key could be found in the http request, specified default value will be returned.  EdgeX error will be
+// returned if any parsing error occurs.
+func ParseQueryStringToBool(r *http.Request, queryStringKey string, defaultValue bool) (bool, errors.EdgeX) {
+	values, ok := r.URL.Query()[queryStringKey]
+	if !ok || len(values) == 0 {
+		return defaultValue, nil
+	}
+	result, parsingErr := strconv.ParseBool(strings.TrimSpace(values[0]))
+	if parsingErr != nil {
+		return false, errors.NewCommonEdgeX(errors.KindContractInvalid, fmt.Sprintf("failed to parse querystring %s's value %s into boolean. Error:%s", queryStringKey, values[0], parsingErr.Error()), nil)
+	}
+	return result, nil
+}
+
 func ParseTimeRangeOffsetLimit(r *http.Request, minOffset int, maxOffset int, minLimit int, maxLimit int) (start int, end int, offset int, limit int, edgexErr errors.EdgeX) {
 	start, edgexErr = ParsePathParamToInt(r, common.Start)
 	if edgexErr != nil {
@@ -171,6 +226,7 @@ func ParsePathParamToInt(r *http.Request, pathKey string) (int, errors.EdgeX) {
 }
 
 // ParseBodyToMap parses the body of http request to a map[string]interfaces{}.  EdgeX error will be returned if any parsing error occurs.
+// The numbers are parsed as json.Number, so they keep the precision they are written with.
 func ParseBodyToMap(r *http.Request) (map[string]interface{}, errors.EdgeX) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
@@ -179,7 +235,9 @@ func ParseBodyToMap(r *http.Request) (map[string]interface{}, errors.EdgeX) {
 	}
 
 	var result map[string]interface{}
-	if err = json.Unmarshal(body, &result); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err = decoder.Decode(&result); err != nil {
 		return nil, errors.NewCommonEdgeX(errors.KindServerError, "failed to parse request body", err)
 	}
 
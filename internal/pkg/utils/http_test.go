@@ -8,9 +8,12 @@ package utils
 import (
 	"math"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 
+	pkgCommon "github.com/edgexfoundry/edgex-go/internal/pkg/common"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 
@@ -69,3 +72,94 @@ func TestParseGetAllObjectsRequestQueryString(t *testing.T) {
 	}
 
 }
+
+func TestParseQueryStringToBool(t *testing.T) {
+	key := "force"
+	tests := []struct {
+		name              string
+		value             string
+		expected          bool
+		expectedErrorKind errors.ErrKind
+	}{
+		{"valid - absent", "", false, ""},
+		{"valid - true", "true", true, ""},
+		{"valid - false", "false", false, ""},
+		{"valid - 1", "1", true, ""},
+		{"invalid - not a boolean", "yes", false, errors.KindContractInvalid},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, common.ApiDeviceProfileRoute, http.NoBody)
+			require.NoError(t, err)
+			if testCase.value != "" {
+				query := req.URL.Query()
+				query.Add(key, testCase.value)
+				req.URL.RawQuery = query.Encode()
+			}
+
+			result, err := ParseQueryStringToBool(req, key, false)
+			if testCase.expectedErrorKind != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedErrorKind, errors.Kind(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
+
+func TestParseIfMatchRevision(t *testing.T) {
+	tests := []struct {
+		name              string
+		value             string
+		expected          *uint64
+		expectedErrorKind errors.ErrKind
+	}{
+		{"valid - absent", "", nil, ""},
+		{"valid - any", "*", nil, ""},
+		{"valid - quoted", `"3"`, uint64Ptr(3), ""},
+		{"valid - unquoted", "0", uint64Ptr(0), ""},
+		{"invalid - weak", `W/"3"`, nil, errors.KindContractInvalid},
+		{"invalid - not a revision", `"abc"`, nil, errors.KindContractInvalid},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPatch, common.ApiDeviceRoute, http.NoBody)
+			require.NoError(t, err)
+			if testCase.value != "" {
+				req.Header.Set(pkgCommon.IfMatch, testCase.value)
+			}
+
+			result, err := ParseIfMatchRevision(req)
+			if testCase.expectedErrorKind != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedErrorKind, errors.Kind(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	WriteETagHeader(recorder, 3)
+	req, err := http.NewRequest(http.MethodPatch, common.ApiDeviceRoute, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set(pkgCommon.IfMatch, recorder.Header().Get(pkgCommon.ETag))
+	result, err := ParseIfMatchRevision(req)
+	require.NoError(t, err)
+	assert.Equal(t, uint64Ptr(3), result, "the ETag is accepted by If-Match")
+}
+
+func TestIfMatchStatusCode(t *testing.T) {
+	mismatch := errors.NewCommonEdgeX(errors.KindStatusConflict, "revision mismatch", ErrRevisionMismatch)
+	assert.Equal(t, http.StatusPreconditionFailed, IfMatchStatusCode(mismatch))
+	assert.Equal(t, http.StatusPreconditionFailed, IfMatchStatusCode(errors.NewCommonEdgeXWrapper(mismatch)), "the wrapped mismatch should be found")
+	conflict := errors.NewCommonEdgeX(errors.KindStatusConflict, "modified at the same time", nil)
+	assert.Equal(t, http.StatusConflict, IfMatchStatusCode(conflict))
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}